@@ -30,6 +30,7 @@ import (
 	"runtime/debug"
 	"time"
 
+	"github.com/annchain/OG/metrics"
 	"github.com/annchain/OG/mylog"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -92,6 +93,8 @@ func init() {
 	rootCmd.PersistentFlags().BoolP("log_line_number", "n", false, "log_line_number")
 	rootCmd.PersistentFlags().BoolP("multifile_by_level", "m", false, "multifile_by_level")
 	rootCmd.PersistentFlags().BoolP("multifile_by_module", "M", false, "multifile_by_module")
+	// read by the metrics package on init, before the flags are parsed.
+	rootCmd.PersistentFlags().Bool(metrics.MetricsEnabledFlag, false, "Enable the collection of the metrics exported at the /metrics rpc")
 
 	_ = viper.BindPFlag("datadir", rootCmd.PersistentFlags().Lookup("datadir"))
 	_ = viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
@@ -114,6 +117,13 @@ func init() {
 	viper.SetDefault("max_mined_hash", "0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF")
 
 	viper.SetDefault("debug.node_id", 0)
+
+	viper.SetDefault("dag.parallel_workers", 1)
+
+	viper.SetDefault("health.min_peers", 1)
+	viper.SetDefault("health.max_sequencer_age_s", 60)
+	viper.SetDefault("health.max_bft_idle_s", 60)
+	viper.SetDefault("health.db_error_window_s", 60)
 }
 
 func panicIfError(err error, message string) {
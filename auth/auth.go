@@ -0,0 +1,243 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth authenticates the clients of the rpc and websocket servers
+// by api key or jwt and authorizes them by permission group and quota.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Group is a permission group of routes.
+type Group string
+
+const (
+	// GroupPublic is the queries of the chain.
+	GroupPublic Group = "public"
+	// GroupTx is the submission of txs.
+	GroupTx Group = "tx"
+	// GroupAdmin is the node management and debugging, it grants the
+	// other groups.
+	GroupAdmin Group = "admin"
+)
+
+var (
+	ErrUnauthorized  = errors.New("auth: invalid credential")
+	ErrForbidden     = errors.New("auth: permission denied")
+	ErrQuotaExceeded = errors.New("auth: request quota exceeded")
+)
+
+// StatusCode returns the http status of an error of Authorize.
+func StatusCode(err error) int {
+	switch err {
+	case nil:
+		return http.StatusOK
+	case ErrForbidden:
+		return http.StatusForbidden
+	case ErrQuotaExceeded:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusUnauthorized
+}
+
+// ParseGroups checks the names of groups.
+func ParseGroups(names []string) ([]Group, error) {
+	var groups []Group
+	for _, name := range names {
+		group := Group(strings.ToLower(name))
+		switch group {
+		case GroupPublic, GroupTx, GroupAdmin:
+			groups = append(groups, group)
+		default:
+			return nil, fmt.Errorf("auth: unknown group %q", name)
+		}
+	}
+	return groups, nil
+}
+
+// Key is an api key.
+type Key struct {
+	Name   string
+	Key    string
+	Groups []Group
+	// Quota is the number of requests per minute, 0 for unlimited.
+	Quota int
+}
+
+type Config struct {
+	Keys []Key
+	// JWTSecret verifies the HS256 jwts, they are not accepted if empty.
+	JWTSecret string
+	// JWTQuota is the number of requests per minute of each jwt subject.
+	JWTQuota int
+	// AnonymousGroups are granted to the requests without credential.
+	AnonymousGroups []Group
+	// AnonymousQuota is the number of requests per minute of each client
+	// ip without credential.
+	AnonymousQuota int
+	// AllowedOrigins are the origins of the browser requests accepted,
+	// "*" accepts any.
+	AllowedOrigins []string
+}
+
+// Identity is the client of a request.
+type Identity struct {
+	// Name identifies the client for its quota.
+	Name   string
+	Groups []Group
+	Quota  int
+}
+
+// Allowed tells if the identity is granted the group.
+func (id *Identity) Allowed(group Group) bool {
+	for _, g := range id.Groups {
+		if g == group || g == GroupAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type Authenticator struct {
+	conf   Config
+	keys   map[string]*Key
+	quotas *quotas
+}
+
+func NewAuthenticator(conf Config) (*Authenticator, error) {
+	a := &Authenticator{
+		conf:   conf,
+		keys:   make(map[string]*Key),
+		quotas: newQuotas(time.Minute),
+	}
+	for i, key := range conf.Keys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("auth: empty key %s", key.Name)
+		}
+		if _, ok := a.keys[key.Key]; ok {
+			return nil, fmt.Errorf("auth: duplicated key %s", key.Name)
+		}
+		a.keys[key.Key] = &conf.Keys[i]
+	}
+	return a, nil
+}
+
+// credential returns the api key or jwt of the request, given in the
+// X-API-Key or Authorization: Bearer header.
+func credential(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(h, "Bearer "))
+	}
+	return ""
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Authenticate returns the identity of the request, anonymous if it has no
+// credential.
+func (a *Authenticator) Authenticate(r *http.Request) (*Identity, error) {
+	return a.authenticate(r, credential(r))
+}
+
+func (a *Authenticator) authenticate(r *http.Request, cred string) (*Identity, error) {
+	if cred == "" {
+		return &Identity{
+			Name:   "ip:" + clientIP(r),
+			Groups: a.conf.AnonymousGroups,
+			Quota:  a.conf.AnonymousQuota,
+		}, nil
+	}
+	if key, ok := a.keys[cred]; ok {
+		return &Identity{Name: "key:" + key.Name, Groups: key.Groups, Quota: key.Quota}, nil
+	}
+	if a.conf.JWTSecret != "" && strings.Count(cred, ".") == 2 {
+		claims, err := VerifyJWT(cred, []byte(a.conf.JWTSecret), time.Now())
+		if err != nil {
+			return nil, ErrUnauthorized
+		}
+		groups, err := ParseGroups(claims.Groups)
+		if err != nil {
+			return nil, ErrUnauthorized
+		}
+		return &Identity{Name: "jwt:" + claims.Subject, Groups: groups, Quota: a.conf.JWTQuota}, nil
+	}
+	return nil, ErrUnauthorized
+}
+
+// Authorize checks that the client of the request is granted the group
+// and has not exceeded its quota.
+func (a *Authenticator) Authorize(r *http.Request, group Group) (*Identity, error) {
+	return a.authorize(r, credential(r), group)
+}
+
+// AuthorizeWebsocket is Authorize for the websocket upgrade requests, whose
+// credential may also be given in the api_key query since the browsers
+// can't set the headers of websocket requests.
+func (a *Authenticator) AuthorizeWebsocket(r *http.Request, group Group) (*Identity, error) {
+	cred := credential(r)
+	if cred == "" {
+		cred = r.URL.Query().Get("api_key")
+	}
+	return a.authorize(r, cred, group)
+}
+
+func (a *Authenticator) authorize(r *http.Request, cred string, group Group) (*Identity, error) {
+	id, err := a.authenticate(r, cred)
+	if err != nil {
+		return nil, err
+	}
+	if !id.Allowed(group) {
+		return id, ErrForbidden
+	}
+	if !a.quotas.take(id.Name, id.Quota, time.Now()) {
+		return id, ErrQuotaExceeded
+	}
+	return id, nil
+}
+
+// AllowedOrigin returns the value of the Access-Control-Allow-Origin header
+// for a request from origin, empty if the origin is not allowed.
+func (a *Authenticator) AllowedOrigin(origin string) string {
+	for _, allowed := range a.conf.AllowedOrigins {
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// CheckOrigin accepts the websocket requests from the allowed origins, and
+// those without origin which are not sent by browsers.
+func (a *Authenticator) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || a.AllowedOrigin(origin) != ""
+}
@@ -0,0 +1,137 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	a, err := NewAuthenticator(Config{
+		Keys: []Key{
+			{Name: "wallet", Key: "wallet-key", Groups: []Group{GroupTx}, Quota: 2},
+			{Name: "ops", Key: "ops-key", Groups: []Group{GroupAdmin}},
+		},
+		JWTSecret:       "secret",
+		AnonymousGroups: []Group{GroupPublic},
+		AllowedOrigins:  []string{"https://explorer.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func newTestRequest(header, value string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/status", nil)
+	if header != "" {
+		r.Header.Set(header, value)
+	}
+	return r
+}
+
+func TestAuthorize(t *testing.T) {
+	a := newTestAuthenticator(t)
+	token, err := SignJWT(&Claims{Subject: "bob", Groups: []string{"public", "tx"}}, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, _ := SignJWT(&Claims{Subject: "bob", Groups: []string{"admin"}}, []byte("other"))
+
+	for i, c := range []struct {
+		r     *http.Request
+		group Group
+		err   error
+	}{
+		{newTestRequest("", ""), GroupPublic, nil},
+		{newTestRequest("", ""), GroupTx, ErrForbidden},
+		{newTestRequest("X-API-Key", "wallet-key"), GroupTx, nil},
+		{newTestRequest("X-API-Key", "wallet-key"), GroupAdmin, ErrForbidden},
+		{newTestRequest("X-API-Key", "unknown"), GroupPublic, ErrUnauthorized},
+		{newTestRequest("Authorization", "Bearer ops-key"), GroupAdmin, nil},
+		{newTestRequest("Authorization", "Bearer ops-key"), GroupTx, nil},
+		{newTestRequest("Authorization", "Bearer "+token), GroupTx, nil},
+		{newTestRequest("Authorization", "Bearer "+token), GroupAdmin, ErrForbidden},
+		{newTestRequest("Authorization", "Bearer "+forged), GroupPublic, ErrUnauthorized},
+		{httptest.NewRequest(http.MethodGet, "/debug?api_key=ops-key", nil), GroupAdmin, ErrForbidden},
+	} {
+		if _, err := a.Authorize(c.r, c.group); err != c.err {
+			t.Fatalf("case %d: expected %v, got %v", i, c.err, err)
+		}
+	}
+	// the query credential is only taken for the websocket upgrades.
+	if _, err := a.AuthorizeWebsocket(httptest.NewRequest(http.MethodGet, "/ws?api_key=ops-key", nil), GroupAdmin); err != nil {
+		t.Fatalf("websocket query credential rejected: %v", err)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	a := newTestAuthenticator(t)
+	// the wallet key makes 2 requests a minute.
+	for i := 0; i < 2; i++ {
+		if _, err := a.Authorize(newTestRequest("X-API-Key", "wallet-key"), GroupTx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := a.Authorize(newTestRequest("X-API-Key", "wallet-key"), GroupTx); err != ErrQuotaExceeded {
+		t.Fatalf("expected quota exceeded, got %v", err)
+	}
+	if StatusCode(ErrQuotaExceeded) != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status")
+	}
+
+	q := newQuotas(time.Minute)
+	now := time.Now()
+	if !q.take("a", 1, now) || q.take("a", 1, now) || !q.take("b", 1, now) {
+		t.Fatalf("unexpected quota in window")
+	}
+	if !q.take("a", 1, now.Add(time.Minute)) {
+		t.Fatalf("quota not reset in next window")
+	}
+}
+
+func TestJWT(t *testing.T) {
+	now := time.Now()
+	token, err := SignJWT(&Claims{Subject: "bob", ExpiresAt: now.Unix() + 60}, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := VerifyJWT(token, []byte("secret"), now)
+	if err != nil || claims.Subject != "bob" {
+		t.Fatalf("unexpected claims %v, %v", claims, err)
+	}
+	if _, err := VerifyJWT(token, []byte("secret"), now.Add(time.Minute)); err == nil {
+		t.Fatalf("expired jwt verified")
+	}
+	if _, err := VerifyJWT(token+"x", []byte("secret"), now); err == nil {
+		t.Fatalf("tampered jwt verified")
+	}
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	a := newTestAuthenticator(t)
+	if a.AllowedOrigin("https://explorer.example") != "https://explorer.example" {
+		t.Fatalf("allowed origin rejected")
+	}
+	if a.AllowedOrigin("https://evil.example") != "" {
+		t.Fatalf("unknown origin allowed")
+	}
+	r := newTestRequest("Origin", "https://evil.example")
+	if a.CheckOrigin(r) || !a.CheckOrigin(newTestRequest("", "")) {
+		t.Fatalf("unexpected websocket origin check")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// quotas counts the requests of each client in fixed windows.
+type quotas struct {
+	window time.Duration
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+func newQuotas(window time.Duration) *quotas {
+	return &quotas{
+		window: window,
+		counts: make(map[string]int),
+	}
+}
+
+// take counts a request of name at now, it returns false if name already
+// made limit requests in the window. limit 0 is unlimited.
+func (q *quotas) take(name string, limit int, now time.Time) bool {
+	if limit <= 0 {
+		return true
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// the counts of the previous window are dropped, so that the clients
+	// gone are not kept.
+	if now.Sub(q.windowStart) >= q.window {
+		q.windowStart = now
+		q.counts = make(map[string]int)
+	}
+	if q.counts[name] >= limit {
+		return false
+	}
+	q.counts[name]++
+	return true
+}
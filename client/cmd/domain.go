@@ -0,0 +1,138 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"fmt"
+	"github.com/annchain/OG/client/tx_client"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	domainCmd = &cobra.Command{
+		Use:   "domain",
+		Short: "domain name processing",
+	}
+	domainRegisterCmd = &cobra.Command{
+		Use:   "register",
+		Short: "register a domain name",
+		Run:   domainRegister,
+	}
+	domainRenewCmd = &cobra.Command{
+		Use:   "renew",
+		Short: "renew a domain name",
+		Run:   domainRenew,
+	}
+	domainTransferCmd = &cobra.Command{
+		Use:   "transfer",
+		Short: "transfer a domain name",
+		Run:   domainTransfer,
+	}
+	domainResolveCmd = &cobra.Command{
+		Use:   "resolve",
+		Short: "resolve a domain name to address",
+		Run:   domainResolve,
+	}
+	domainName   string
+	domainPeriod uint64
+)
+
+func domainInit() {
+	domainCmd.AddCommand(domainRegisterCmd, domainRenewCmd, domainTransferCmd, domainResolveCmd)
+	domainCmd.PersistentFlags().StringVarP(&domainName, "name", "d", "", "name alice")
+	domainRegisterCmd.PersistentFlags().StringVarP(&priv_key, "priv_key", "k", "", "priv_key ***")
+	domainRenewCmd.PersistentFlags().StringVarP(&priv_key, "priv_key", "k", "", "priv_key ***")
+	domainTransferCmd.PersistentFlags().StringVarP(&priv_key, "priv_key", "k", "", "priv_key ***")
+	domainRegisterCmd.PersistentFlags().Uint64VarP(&nonce, "nonce", "n", 0, "nonce 1")
+	domainRenewCmd.PersistentFlags().Uint64VarP(&nonce, "nonce", "n", 0, "nonce 1")
+	domainTransferCmd.PersistentFlags().Uint64VarP(&nonce, "nonce", "n", 0, "nonce 1")
+	domainRegisterCmd.PersistentFlags().Uint64VarP(&domainPeriod, "period", "p", 0, "sequencer heights 100000")
+	domainRenewCmd.PersistentFlags().Uint64VarP(&domainPeriod, "period", "p", 0, "sequencer heights 100000")
+	domainTransferCmd.PersistentFlags().StringVarP(&to, "to", "t", "", "to 0x*** or domain name")
+}
+
+func domainRegister(cmd *cobra.Command, args []string) {
+	sendDomainTx(cmd, tx_types.ActionRequestDomainName)
+}
+
+func domainRenew(cmd *cobra.Command, args []string) {
+	sendDomainTx(cmd, tx_types.ActionRenewDomainName)
+}
+
+func domainTransfer(cmd *cobra.Command, args []string) {
+	sendDomainTx(cmd, tx_types.ActionTransferDomainName)
+}
+
+func sendDomainTx(cmd *cobra.Command, action uint8) {
+	if priv_key == "" || domainName == "" {
+		cmd.HelpFunc()
+	}
+	privKey, err := crypto.PrivateKeyFromString(priv_key)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	txClient := tx_client.NewTxClient(Host, true)
+	requester := tx_client.NewRequestGenerator(privKey)
+
+	domain := tx_types.RequestDomain{
+		DomainName: domainName,
+		Period:     domainPeriod,
+	}
+	if action == tx_types.ActionTransferDomainName {
+		domain.To, err = txClient.ResolveAddress(to)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if err = domain.Validate(action); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if nonce <= 0 {
+		nonce, err = txClient.GetNonce(requester.Address())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	data := requester.Domain(action, nonce+1, domain)
+	var resp string
+	switch action {
+	case tx_types.ActionRequestDomainName:
+		resp, err = txClient.SendDomainRegister(&data)
+	case tx_types.ActionRenewDomainName:
+		resp, err = txClient.SendDomainRenew(&data)
+	default:
+		resp, err = txClient.SendDomainTransfer(&data)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(resp)
+}
+
+func domainResolve(cmd *cobra.Command, args []string) {
+	txClient := tx_client.NewTxClient(Host, true)
+	addr, err := txClient.ResolveAddress(domainName)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(addr.Hex())
+}
@@ -48,6 +48,8 @@ func init() {
 	rootCmd.AddCommand(accountCmd)
 	rootCmd.AddCommand(tokenCmd)
 	tokenInit()
+	rootCmd.AddCommand(domainCmd)
+	domainInit()
 	rootCmd.AddCommand(tpsCmd)
 	tpsInit()
 
@@ -16,9 +16,9 @@ package cmd
 import (
 	"fmt"
 	"github.com/annchain/OG/client/tx_client"
-	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/types/tx_types"
 	"github.com/spf13/cobra"
 )
 
@@ -49,33 +49,125 @@ var (
 		Short: "token transfer",
 		Run:   tokenTransfer,
 	}
-	tokenName = "btc"
-	tokenId   = int32(0)
-	enableSPO bool
+	tokenApproveCmd = &cobra.Command{
+		Use:   "approve",
+		Short: "allow a spender to transfer your token",
+		Run:   tokenApprove,
+	}
+	tokenRevokeCmd = &cobra.Command{
+		Use:   "revoke",
+		Short: "remove the allowance of a spender",
+		Run:   tokenRevoke,
+	}
+	tokenTransferFromCmd = &cobra.Command{
+		Use:   "transfer_from",
+		Short: "transfer the token of an owner who approved you",
+		Run:   tokenTransferFrom,
+	}
+	tokenAllowanceCmd = &cobra.Command{
+		Use:   "allowance",
+		Short: "query the allowance of a spender",
+		Run:   tokenAllowance,
+	}
+	tokenFreezeCmd = &cobra.Command{
+		Use:   "freeze",
+		Short: "freeze a holder of your token",
+		Run:   tokenControl(tx_types.ActionTxActionFreeze),
+	}
+	tokenUnfreezeCmd = &cobra.Command{
+		Use:   "unfreeze",
+		Short: "unfreeze a holder of your token",
+		Run:   tokenControl(tx_types.ActionTxActionUnfreeze),
+	}
+	tokenWhitelistAddCmd = &cobra.Command{
+		Use:   "whitelist_add",
+		Short: "add a holder to the whitelist of your token",
+		Run:   tokenControl(tx_types.ActionTxActionWhitelistAdd),
+	}
+	tokenWhitelistRemoveCmd = &cobra.Command{
+		Use:   "whitelist_remove",
+		Short: "remove a holder from the whitelist of your token",
+		Run:   tokenControl(tx_types.ActionTxActionWhitelistRemove),
+	}
+	tokenPauseCmd = &cobra.Command{
+		Use:   "pause",
+		Short: "pause the transfers of your token",
+		Run:   tokenControl(tx_types.ActionTxActionPause),
+	}
+	tokenUnpauseCmd = &cobra.Command{
+		Use:   "unpause",
+		Short: "unpause the transfers of your token",
+		Run:   tokenControl(tx_types.ActionTxActionUnpause),
+	}
+	tokenName     = "btc"
+	tokenId       = int32(0)
+	enableSPO     bool
+	tokenSymbol   string
+	tokenDecimals uint8
+	metadataURI   string
+	tokenControls uint8
+	// tokenValue is the amount in units of 10^decimals of the token.
+	tokenValue string
+	spender    string
+	owner      string
+	holder     string
 )
 
 func tokenInit() {
-	tokenCmd.AddCommand(tokenIPOCmd, tokenSPOCmd, tokenDestroyCmd, tokenTransferCmd)
+	tokenCmd.AddCommand(tokenIPOCmd, tokenSPOCmd, tokenDestroyCmd, tokenTransferCmd,
+		tokenApproveCmd, tokenRevokeCmd, tokenTransferFromCmd, tokenAllowanceCmd,
+		tokenFreezeCmd, tokenUnfreezeCmd, tokenWhitelistAddCmd, tokenWhitelistRemoveCmd, tokenPauseCmd, tokenUnpauseCmd)
 	tokenCmd.PersistentFlags().StringVarP(&priv_key, "priv_key", "k", "", "priv_key ***")
-	tokenIPOCmd.PersistentFlags().Int64VarP(&value, "value", "v", 0, "value 1")
+	tokenIPOCmd.PersistentFlags().StringVarP(&tokenValue, "value", "v", "", "value 1.5")
 
-	tokenSPOCmd.PersistentFlags().Int64VarP(&value, "value", "v", 0, "value 1")
-	tokenTransferCmd.PersistentFlags().Int64VarP(&value, "value", "v", 0, "value 1")
+	tokenSPOCmd.PersistentFlags().StringVarP(&tokenValue, "value", "v", "", "value 1.5")
+	tokenTransferCmd.PersistentFlags().StringVarP(&tokenValue, "value", "v", "", "value 1.5")
 	tokenSPOCmd.PersistentFlags().Int32VarP(&tokenId, "token_id", "i", 0, "token_id 1")
 	tokenDestroyCmd.PersistentFlags().Int32VarP(&tokenId, "token_id", "i", 0, "token_id 1")
 	tokenTransferCmd.PersistentFlags().Int32VarP(&tokenId, "token_id", "i", 0, "token_id 1")
 	tokenCmd.PersistentFlags().Uint64VarP(&nonce, "nonce", "n", 0, "nonce 1")
-	tokenTransferCmd.PersistentFlags().StringVarP(&to, "to", "t", "", "to 0x***")
+	tokenTransferCmd.PersistentFlags().StringVarP(&to, "to", "t", "", "to 0x*** or domain name")
 	tokenIPOCmd.PersistentFlags().StringVarP(&tokenName, "toke_name", "t", "test_token", "toke_name btc")
 	tokenIPOCmd.PersistentFlags().BoolVarP(&enableSPO, "enable_spo", "e", false, "enable_spo true")
+	tokenIPOCmd.PersistentFlags().StringVarP(&tokenSymbol, "symbol", "y", "", "symbol BTC, leave empty for a legacy IPO")
+	tokenIPOCmd.PersistentFlags().Uint8VarP(&tokenDecimals, "decimals", "d", 0, "decimals 8")
+	tokenIPOCmd.PersistentFlags().StringVarP(&metadataURI, "metadata_uri", "u", "", "metadata_uri https://***")
+	tokenIPOCmd.PersistentFlags().Uint8VarP(&tokenControls, "controls", "c", 0, "controls 7 (1 freeze, 2 whitelist, 4 pause)")
+	for _, c := range []*cobra.Command{tokenApproveCmd, tokenRevokeCmd, tokenTransferFromCmd, tokenAllowanceCmd} {
+		c.PersistentFlags().Int32VarP(&tokenId, "token_id", "i", 0, "token_id 1")
+	}
+	tokenApproveCmd.PersistentFlags().StringVarP(&spender, "spender", "p", "", "spender 0x*** or domain name")
+	tokenApproveCmd.PersistentFlags().StringVarP(&tokenValue, "value", "v", "", "value 1.5")
+	tokenRevokeCmd.PersistentFlags().StringVarP(&spender, "spender", "p", "", "spender 0x*** or domain name")
+	tokenTransferFromCmd.PersistentFlags().StringVarP(&owner, "owner", "o", "", "owner 0x*** or domain name")
+	tokenTransferFromCmd.PersistentFlags().StringVarP(&to, "to", "t", "", "to 0x*** or domain name")
+	tokenTransferFromCmd.PersistentFlags().StringVarP(&tokenValue, "value", "v", "", "value 1.5")
+	tokenAllowanceCmd.PersistentFlags().StringVarP(&owner, "owner", "o", "", "owner 0x*** or domain name")
+	tokenAllowanceCmd.PersistentFlags().StringVarP(&spender, "spender", "p", "", "spender 0x*** or domain name")
+	for _, c := range []*cobra.Command{tokenFreezeCmd, tokenUnfreezeCmd, tokenWhitelistAddCmd, tokenWhitelistRemoveCmd} {
+		c.PersistentFlags().StringVarP(&holder, "holder", "a", "", "holder 0x*** or domain name")
+	}
+	for _, c := range []*cobra.Command{tokenFreezeCmd, tokenUnfreezeCmd, tokenWhitelistAddCmd, tokenWhitelistRemoveCmd, tokenPauseCmd, tokenUnpauseCmd} {
+		c.PersistentFlags().Int32VarP(&tokenId, "token_id", "i", 0, "token_id 1")
+	}
 
 }
 
 func tokenIPO(cmd *cobra.Command, args []string) {
 
-	if priv_key == "" || value < 1 || tokenName == "" {
+	if priv_key == "" || tokenValue == "" || tokenName == "" {
 		cmd.HelpFunc()
 	}
+	// without a symbol the IPO is a legacy one, which carries no metadata.
+	if tokenSymbol == "" && (tokenDecimals != 0 || metadataURI != "" || tokenControls != 0) {
+		fmt.Println("symbol is required with decimals, metadata_uri or controls")
+		return
+	}
+	amount, ok := math.NewBigIntFromDecimal(tokenValue, tokenDecimals)
+	if !ok {
+		fmt.Println("value format error")
+		return
+	}
 	privKey, err := crypto.PrivateKeyFromString(priv_key)
 	if err != nil {
 		fmt.Println(err)
@@ -91,7 +183,7 @@ func tokenIPO(cmd *cobra.Command, args []string) {
 			return
 		}
 	}
-	data := requester.TokenPublishing(nonce+1, enableSPO, tokenName, math.NewBigInt(value))
+	data := requester.TokenPublishing(nonce+1, enableSPO, tokenName, tokenSymbol, tokenDecimals, metadataURI, tokenControls, amount)
 	resp, err := txClient.SendTokenIPO(&data)
 	if err != nil {
 		fmt.Println(err)
@@ -101,7 +193,7 @@ func tokenIPO(cmd *cobra.Command, args []string) {
 }
 
 func tokenSPO(cmd *cobra.Command, args []string) {
-	if priv_key == "" || value < 1 {
+	if priv_key == "" || tokenValue == "" {
 		cmd.HelpFunc()
 	}
 	privKey, err := crypto.PrivateKeyFromString(priv_key)
@@ -111,6 +203,11 @@ func tokenSPO(cmd *cobra.Command, args []string) {
 	}
 	txClient := tx_client.NewTxClient(Host, true)
 	requester := tx_client.NewRequestGenerator(privKey)
+	decimals, amount, err := parseTokenValue(&txClient, tokenId, tokenValue)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	if nonce <= 0 {
 		nonce, err = txClient.GetNonce(requester.Address())
@@ -119,7 +216,7 @@ func tokenSPO(cmd *cobra.Command, args []string) {
 			return
 		}
 	}
-	data := requester.SecondPublicOffering(tokenId, nonce+1, math.NewBigInt(value))
+	data := requester.SecondPublicOffering(tokenId, decimals, nonce+1, amount)
 	resp, err := txClient.SendTokenSPO(&data)
 	if err != nil {
 		fmt.Println(err)
@@ -157,16 +254,25 @@ func tokenDestroy(cmd *cobra.Command, args []string) {
 }
 
 func tokenTransfer(cmd *cobra.Command, args []string) {
-	if to == "" || value < 1 || priv_key == "" {
+	if to == "" || tokenValue == "" || priv_key == "" {
 		cmd.HelpFunc()
 	}
-	toAddr := common.HexToAddress(to)
 	privKey, err := crypto.PrivateKeyFromString(priv_key)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 	txClient := tx_client.NewTxClient(Host, true)
+	toAddr, err := txClient.ResolveAddress(to)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	decimals, amount, err := parseTokenValue(&txClient, tokenId, tokenValue)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	requester := tx_client.NewRequestGenerator(privKey)
 
 	if nonce <= 0 {
@@ -176,7 +282,7 @@ func tokenTransfer(cmd *cobra.Command, args []string) {
 			return
 		}
 	}
-	data := requester.NormalTx(tokenId, nonce+1, toAddr, math.NewBigInt(value))
+	data := requester.NormalTx(tokenId, decimals, nonce+1, toAddr, amount)
 	resp, err := txClient.SendNormalTx(&data)
 	if err != nil {
 		fmt.Println(err)
@@ -185,6 +291,171 @@ func tokenTransfer(cmd *cobra.Command, args []string) {
 	fmt.Println(resp)
 }
 
+func tokenApprove(cmd *cobra.Command, args []string) {
+	if spender == "" || tokenValue == "" || priv_key == "" {
+		cmd.HelpFunc()
+	}
+	tokenSendApproval(tx_types.ActionTxActionApprove)
+}
+
+func tokenRevoke(cmd *cobra.Command, args []string) {
+	if spender == "" || priv_key == "" {
+		cmd.HelpFunc()
+	}
+	tokenSendApproval(tx_types.ActionTxActionRevoke)
+}
+
+func tokenSendApproval(action uint8) {
+	privKey, err := crypto.PrivateKeyFromString(priv_key)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	txClient := tx_client.NewTxClient(Host, true)
+	spenderAddr, err := txClient.ResolveAddress(spender)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	approval := tx_types.Approval{Spender: spenderAddr, TokenId: tokenId, Value: math.NewBigInt(0)}
+	var decimals uint8
+	if action == tx_types.ActionTxActionApprove {
+		decimals, approval.Value, err = parseTokenValue(&txClient, tokenId, tokenValue)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	requester := tx_client.NewRequestGenerator(privKey)
+
+	if nonce <= 0 {
+		nonce, err = txClient.GetNonce(requester.Address())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	data := requester.Approval(action, decimals, nonce+1, approval)
+	var resp string
+	if action == tx_types.ActionTxActionApprove {
+		resp, err = txClient.SendTokenApprove(&data)
+	} else {
+		resp, err = txClient.SendTokenRevoke(&data)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(resp)
+}
+
+func tokenTransferFrom(cmd *cobra.Command, args []string) {
+	if owner == "" || to == "" || tokenValue == "" || priv_key == "" {
+		cmd.HelpFunc()
+	}
+	privKey, err := crypto.PrivateKeyFromString(priv_key)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	txClient := tx_client.NewTxClient(Host, true)
+	ownerAddr, err := txClient.ResolveAddress(owner)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	toAddr, err := txClient.ResolveAddress(to)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	decimals, amount, err := parseTokenValue(&txClient, tokenId, tokenValue)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	requester := tx_client.NewRequestGenerator(privKey)
+
+	if nonce <= 0 {
+		nonce, err = txClient.GetNonce(requester.Address())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	transfer := tx_types.TransferFrom{Owner: ownerAddr, To: toAddr, TokenId: tokenId, Value: amount}
+	data := requester.TransferFrom(decimals, nonce+1, transfer)
+	resp, err := txClient.SendTokenTransferFrom(&data)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(resp)
+}
+
+func tokenAllowance(cmd *cobra.Command, args []string) {
+	if owner == "" || spender == "" {
+		cmd.HelpFunc()
+	}
+	txClient := tx_client.NewTxClient(Host, true)
+	ownerAddr, err := txClient.ResolveAddress(owner)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	spenderAddr, err := txClient.ResolveAddress(spender)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	allowance, err := txClient.GetAllowance(ownerAddr, spenderAddr, tokenId)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(allowance)
+}
+
+// tokenControl runs the freeze, whitelist or pause command of action.
+func tokenControl(action uint8) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		needHolder := tx_types.TokenControlOfAction(action) != tx_types.TokenControlPause
+		if priv_key == "" || (needHolder && holder == "") {
+			cmd.HelpFunc()
+		}
+		privKey, err := crypto.PrivateKeyFromString(priv_key)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		txClient := tx_client.NewTxClient(Host, true)
+		control := tx_types.TokenControl{TokenId: tokenId}
+		if needHolder {
+			control.Holder, err = txClient.ResolveAddress(holder)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		requester := tx_client.NewRequestGenerator(privKey)
+
+		if nonce <= 0 {
+			nonce, err = txClient.GetNonce(requester.Address())
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		data := requester.TokenControl(action, nonce+1, control)
+		resp, err := txClient.SendTokenControl(action, &data)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(resp)
+	}
+}
+
 func tokenList(cmd *cobra.Command, args []string) {
 	txClient := tx_client.NewTxClient(Host, true)
 	list, err := txClient.GetTokenList()
@@ -194,3 +465,17 @@ func tokenList(cmd *cobra.Command, args []string) {
 	}
 	fmt.Println(list)
 }
+
+// parseTokenValue parses the value given in units of 10^decimals of the
+// token into its smallest unit.
+func parseTokenValue(txClient *tx_client.TxClient, tokenId int32, value string) (uint8, *math.BigInt, error) {
+	decimals, err := txClient.GetTokenDecimals(tokenId)
+	if err != nil {
+		return 0, nil, err
+	}
+	amount, ok := math.NewBigIntFromDecimal(value, decimals)
+	if !ok {
+		return 0, nil, fmt.Errorf("value format error: %s", value)
+	}
+	return decimals, amount, nil
+}
@@ -72,7 +72,7 @@ func tepsDataGen(threadNum uint16, db ogdb.Database, total uint16) {
 	for i := uint16(0); i < total; i++ {
 		var reqs rpc.NewTxsRequests
 		for j := uint16(0); j < num; j++ {
-			txReq := requester.NormalTx(0, uint64(i*num+1+j), to, math.NewBigInt(0))
+			txReq := requester.NormalTx(0, 0, uint64(i*num+1+j), to, math.NewBigInt(0))
 			reqs.Txs = append(reqs.Txs, txReq)
 		}
 		data, err := reqs.MarshalMsg(nil)
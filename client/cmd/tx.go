@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/annchain/OG/client/httplib"
+	"github.com/annchain/OG/client/tx_client"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/hexutil"
@@ -41,7 +42,7 @@ var (
 
 func txInit() {
 	txCmd.PersistentFlags().StringVarP(&payload, "payload", "p", "", "payload value")
-	txCmd.PersistentFlags().StringVarP(&to, "to", "t", "", "to 0x***")
+	txCmd.PersistentFlags().StringVarP(&to, "to", "t", "", "to 0x*** or domain name")
 	txCmd.PersistentFlags().StringVarP(&priv_key, "priv_key", "k", "", "priv_key ***")
 	txCmd.PersistentFlags().Int64VarP(&value, "value", "v", 0, "value 1")
 	txCmd.PersistentFlags().Uint64VarP(&nonce, "nonce", "n", 0, "nonce 1")
@@ -63,7 +64,12 @@ func newTx(cmd *cobra.Command, args []string) {
 	if to == "" || value < 1 || priv_key == "" {
 		cmd.HelpFunc()
 	}
-	toAddr := common.HexToAddress(to)
+	txClient := tx_client.NewTxClient(Host, false)
+	toAddr, err := txClient.ResolveAddress(to)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	key, err := crypto.PrivateKeyFromString(priv_key)
 	if err != nil {
 		fmt.Println(err)
@@ -93,7 +99,7 @@ func newTx(cmd *cobra.Command, args []string) {
 	txReq := &NewTxRequest{
 		Nonce:     fmt.Sprintf("%d", tx.AccountNonce),
 		From:      tx.From.Hex(),
-		To:        to,
+		To:        toAddr.Hex(),
 		Data:      payload,
 		Value:     tx.Value.String(),
 		Signature: hexutil.Encode(signature.Bytes),
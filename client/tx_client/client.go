@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/rpc"
+	"github.com/annchain/OG/types/token"
+	"github.com/annchain/OG/types/tx_types"
 	"github.com/sirupsen/logrus"
 	"io/ioutil"
 	"net"
@@ -133,6 +135,48 @@ func (a *TxClient) SendTokenDestroy(request *rpc.NewPublicOfferingRequest) (stri
 	return a.sendTx(request, "token/destroy", "POST")
 }
 
+func (a *TxClient) SendDomainRegister(request *rpc.NewDomainRequest) (string, error) {
+	return a.sendTx(request, "domain/register", "POST")
+}
+
+func (a *TxClient) SendDomainRenew(request *rpc.NewDomainRequest) (string, error) {
+	return a.sendTx(request, "domain/renew", "POST")
+}
+
+func (a *TxClient) SendDomainTransfer(request *rpc.NewDomainRequest) (string, error) {
+	return a.sendTx(request, "domain/transfer", "POST")
+}
+
+func (a *TxClient) SendTokenApprove(request *rpc.NewAllowanceRequest) (string, error) {
+	return a.sendTx(request, "token/approve", "POST")
+}
+
+func (a *TxClient) SendTokenRevoke(request *rpc.NewAllowanceRequest) (string, error) {
+	return a.sendTx(request, "token/revoke", "POST")
+}
+
+func (a *TxClient) SendTokenTransferFrom(request *rpc.NewAllowanceRequest) (string, error) {
+	return a.sendTx(request, "token/transfer_from", "POST")
+}
+
+var tokenControlURIs = map[uint8]string{
+	tx_types.ActionTxActionFreeze:          "token/freeze",
+	tx_types.ActionTxActionUnfreeze:        "token/unfreeze",
+	tx_types.ActionTxActionWhitelistAdd:    "token/whitelist/add",
+	tx_types.ActionTxActionWhitelistRemove: "token/whitelist/remove",
+	tx_types.ActionTxActionPause:           "token/pause",
+	tx_types.ActionTxActionUnpause:         "token/unpause",
+}
+
+// SendTokenControl sends the freeze, whitelist or pause tx of action.
+func (a *TxClient) SendTokenControl(action uint8, request *rpc.NewTokenControlRequest) (string, error) {
+	uri, ok := tokenControlURIs[action]
+	if !ok {
+		return "", fmt.Errorf("unknown token control action %d", action)
+	}
+	return a.sendTx(request, uri, "POST")
+}
+
 func (a *TxClient) sendTx(request interface{}, uri string, methd string) (string, error) {
 	//req := httplib.NewBeegoRequest(url,"POST")
 	//req.SetTimeout(time.Second*10,time.Second*10)
@@ -258,6 +302,104 @@ func (a *TxClient) GetNonce(addr common.Address) (nonce uint64, err error) {
 	return nonceResp.Data, nil
 }
 
+// ResolveAddress parses s as an address, or resolves it through the node if
+// it is a domain name.
+func (a *TxClient) ResolveAddress(s string) (addr common.Address, err error) {
+	if tx_types.ValidateDomainName(s) != nil {
+		return common.StringToAddress(s)
+	}
+	url := a.Host + "/domain/resolve?name=" + s
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return addr, err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return addr, err
+	}
+	defer resp.Body.Close()
+	resDate, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return addr, err
+	}
+	var resolveResp struct {
+		Data rpc.ResolveDomainResponse `json:"data"`
+		Err  string                    `json:"err"`
+	}
+	err = json.Unmarshal(resDate, &resolveResp)
+	if err != nil {
+		return addr, err
+	}
+	if resp.StatusCode != 200 {
+		return addr, fmt.Errorf("resolve domain name %s failed: %s", s, resolveResp.Err)
+	}
+	return common.StringToAddress(resolveResp.Data.Address)
+}
+
+// GetTokenDecimals returns the decimals of the token, 0 for the og token.
+func (a *TxClient) GetTokenDecimals(tokenId int32) (decimals uint8, err error) {
+	if tokenId == token.OGTokenID {
+		return 0, nil
+	}
+	url := fmt.Sprintf("%s/token?id=%d", a.Host, tokenId)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	resDate, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	var tokenResp struct {
+		Data rpc.TokenResponse `json:"data"`
+		Err  string            `json:"err"`
+	}
+	err = json.Unmarshal(resDate, &tokenResp)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("get token %d failed: %s", tokenId, tokenResp.Err)
+	}
+	return tokenResp.Data.Decimals, nil
+}
+
+// GetAllowance returns how much of owner's token spender is allowed to
+// move, in units of 10^decimals of the token.
+func (a *TxClient) GetAllowance(owner, spender common.Address, tokenId int32) (allowance string, err error) {
+	url := fmt.Sprintf("%s/token/allowance?owner=%s&spender=%s&token_id=%d", a.Host, owner.Hex(), spender.Hex(), tokenId)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	resDate, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var allowanceResp struct {
+		Data rpc.AllowanceResponse `json:"data"`
+		Err  string                `json:"err"`
+	}
+	err = json.Unmarshal(resDate, &allowanceResp)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("get allowance failed: %s", allowanceResp.Err)
+	}
+	return allowanceResp.Data.Value, nil
+}
+
 //type TokenList map[string]string
 
 func (a *TxClient) GetTokenList() (TokenList string, err error) {
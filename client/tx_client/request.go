@@ -31,7 +31,10 @@ func NewRequestGenerator(priv crypto.PrivateKey) *RequstGenerator {
 	}
 }
 
-func (r *RequstGenerator) TokenPublishing(nonce uint64, enableSPO bool, tokenName string, value *math.BigInt) rpc.NewPublicOfferingRequest {
+// TokenPublishing signs the IPO of value, given in the smallest unit of the
+// token.
+func (r *RequstGenerator) TokenPublishing(nonce uint64, enableSPO bool, tokenName string, tokenSymbol string, decimals uint8,
+	metadataURI string, controls uint8, value *math.BigInt) rpc.NewPublicOfferingRequest {
 	//pub, priv := crypto.Signer.RandomKeyPair()
 	from := r.address
 	if !r.Nodebug {
@@ -46,9 +49,13 @@ func (r *RequstGenerator) TokenPublishing(nonce uint64, enableSPO bool, tokenNam
 		Action: tx_types.ActionTxActionIPO,
 		From:   &from,
 		ActionData: &tx_types.PublicOffering{
-			Value:     value,
-			EnableSPO: enableSPO,
-			TokenName: tokenName,
+			Value:       value,
+			EnableSPO:   enableSPO,
+			TokenName:   tokenName,
+			TokenSymbol: tokenSymbol,
+			Decimals:    decimals,
+			MetadataURI: metadataURI,
+			Controls:    controls,
 		},
 	}
 	tx.Signature = crypto.Signer.Sign(r.privKey, tx.SignatureTargets()).Bytes[:]
@@ -60,14 +67,18 @@ func (r *RequstGenerator) TokenPublishing(nonce uint64, enableSPO bool, tokenNam
 		panic("not ok")
 	}
 	request := rpc.NewPublicOfferingRequest{
-		Nonce:     nonce,
-		From:      tx.From.Hex(),
-		Value:     value.String(),
-		Signature: tx.Signature.String(),
-		Pubkey:    r.publicKey.String(),
-		Action:    tx_types.ActionTxActionIPO,
-		EnableSPO: enableSPO,
-		TokenName: tokenName,
+		Nonce:       nonce,
+		From:        tx.From.Hex(),
+		Value:       value.DecimalString(decimals),
+		Signature:   tx.Signature.String(),
+		Pubkey:      r.publicKey.String(),
+		Action:      tx_types.ActionTxActionIPO,
+		EnableSPO:   enableSPO,
+		TokenName:   tokenName,
+		TokenSymbol: tokenSymbol,
+		Decimals:    decimals,
+		MetadataURI: metadataURI,
+		Controls:    controls,
 	}
 
 	return request
@@ -118,7 +129,9 @@ func (r *RequstGenerator) TokenDestroy(tokenId int32, nonce uint64) rpc.NewPubli
 	return request
 }
 
-func (r *RequstGenerator) SecondPublicOffering(tokenId int32, nonce uint64, value *math.BigInt) rpc.NewPublicOfferingRequest {
+// SecondPublicOffering signs the SPO of value, given in the smallest unit of
+// the token of decimals.
+func (r *RequstGenerator) SecondPublicOffering(tokenId int32, decimals uint8, nonce uint64, value *math.BigInt) rpc.NewPublicOfferingRequest {
 	//pub, priv := crypto.Signer.RandomKeyPair()
 	from := r.address
 	if !r.Nodebug {
@@ -152,7 +165,7 @@ func (r *RequstGenerator) SecondPublicOffering(tokenId int32, nonce uint64, valu
 	request := rpc.NewPublicOfferingRequest{
 		Nonce:     nonce,
 		From:      tx.From.Hex(),
-		Value:     value.String(),
+		Value:     value.DecimalString(decimals),
 		Signature: tx.Signature.String(),
 		Pubkey:    r.publicKey.String(),
 		Action:    tx_types.ActionTxActionSPO,
@@ -164,7 +177,9 @@ func (r *RequstGenerator) SecondPublicOffering(tokenId int32, nonce uint64, valu
 	return request
 }
 
-func (r *RequstGenerator) NormalTx(tokenId int32, nonce uint64, to common.Address, value *math.BigInt) rpc.NewTxRequest {
+// NormalTx signs the transfer of value, given in the smallest unit of the
+// token of decimals.
+func (r *RequstGenerator) NormalTx(tokenId int32, decimals uint8, nonce uint64, to common.Address, value *math.BigInt) rpc.NewTxRequest {
 	from := r.address
 	if !r.Nodebug {
 		fmt.Println(from.String(), to.String())
@@ -192,10 +207,157 @@ func (r *RequstGenerator) NormalTx(tokenId int32, nonce uint64, to common.Addres
 		Nonce:      nonce,
 		From:      tx.From.Hex(),
 		To:        to.String(),
-		Value:     tx.Value.String(),
+		Value:     tx.Value.DecimalString(decimals),
 		Signature: tx.Signature.String(),
 		Pubkey:    r.publicKey.String(),
 		TokenId:   tokenId,
 	}
 	return request
 }
+
+func (r *RequstGenerator) Domain(action uint8, nonce uint64, domain tx_types.RequestDomain) rpc.NewDomainRequest {
+	from := r.address
+	if !r.Nodebug {
+		fmt.Println(from.String(), domain.String())
+	}
+	tx := tx_types.ActionTx{
+		TxBase: types.TxBase{
+			Type:         types.TxBaseAction,
+			AccountNonce: uint64(nonce),
+			PublicKey:    r.publicKey.Bytes[:],
+		},
+		Action:     action,
+		From:       &from,
+		ActionData: &domain,
+	}
+	tx.Signature = crypto.Signer.Sign(r.privKey, tx.SignatureTargets()).Bytes[:]
+	v := og.TxFormatVerifier{}
+	ok := v.VerifySignature(&tx)
+	if !ok {
+		target := tx.SignatureTargets()
+		fmt.Println(hexutil.Encode(target))
+		panic("not ok")
+	}
+	request := rpc.NewDomainRequest{
+		Nonce:      nonce,
+		From:       tx.From.Hex(),
+		DomainName: domain.DomainName,
+		Period:     domain.Period,
+		Signature:  tx.Signature.String(),
+		Pubkey:     r.publicKey.String(),
+	}
+	if action == tx_types.ActionTransferDomainName {
+		request.To = domain.To.Hex()
+	}
+	return request
+}
+
+// Approval signs the approve or revoke of the allowance, given in the
+// smallest unit of the token of decimals.
+func (r *RequstGenerator) Approval(action uint8, decimals uint8, nonce uint64, approval tx_types.Approval) rpc.NewAllowanceRequest {
+	from := r.address
+	if !r.Nodebug {
+		fmt.Println(from.String(), approval.String())
+	}
+	tx := tx_types.ActionTx{
+		TxBase: types.TxBase{
+			Type:         types.TxBaseAction,
+			AccountNonce: uint64(nonce),
+			PublicKey:    r.publicKey.Bytes[:],
+		},
+		Action:     action,
+		From:       &from,
+		ActionData: &approval,
+	}
+	tx.Signature = crypto.Signer.Sign(r.privKey, tx.SignatureTargets()).Bytes[:]
+	v := og.TxFormatVerifier{}
+	ok := v.VerifySignature(&tx)
+	if !ok {
+		target := tx.SignatureTargets()
+		fmt.Println(hexutil.Encode(target))
+		panic("not ok")
+	}
+	request := rpc.NewAllowanceRequest{
+		Nonce:     nonce,
+		From:      tx.From.Hex(),
+		Spender:   approval.Spender.Hex(),
+		TokenId:   approval.TokenId,
+		Signature: tx.Signature.String(),
+		Pubkey:    r.publicKey.String(),
+	}
+	if action == tx_types.ActionTxActionApprove {
+		request.Value = approval.Value.DecimalString(decimals)
+	}
+	return request
+}
+
+// TransferFrom signs the transfer from the owner, given in the smallest unit
+// of the token of decimals.
+func (r *RequstGenerator) TransferFrom(decimals uint8, nonce uint64, transfer tx_types.TransferFrom) rpc.NewAllowanceRequest {
+	from := r.address
+	if !r.Nodebug {
+		fmt.Println(from.String(), transfer.String())
+	}
+	tx := tx_types.ActionTx{
+		TxBase: types.TxBase{
+			Type:         types.TxBaseAction,
+			AccountNonce: uint64(nonce),
+			PublicKey:    r.publicKey.Bytes[:],
+		},
+		Action:     tx_types.ActionTxActionTransferFrom,
+		From:       &from,
+		ActionData: &transfer,
+	}
+	tx.Signature = crypto.Signer.Sign(r.privKey, tx.SignatureTargets()).Bytes[:]
+	v := og.TxFormatVerifier{}
+	ok := v.VerifySignature(&tx)
+	if !ok {
+		target := tx.SignatureTargets()
+		fmt.Println(hexutil.Encode(target))
+		panic("not ok")
+	}
+	return rpc.NewAllowanceRequest{
+		Nonce:     nonce,
+		From:      tx.From.Hex(),
+		Owner:     transfer.Owner.Hex(),
+		To:        transfer.To.Hex(),
+		TokenId:   transfer.TokenId,
+		Value:     transfer.Value.DecimalString(decimals),
+		Signature: tx.Signature.String(),
+		Pubkey:    r.publicKey.String(),
+	}
+}
+
+// TokenControl signs the freeze, whitelist or pause of the token.
+func (r *RequstGenerator) TokenControl(action uint8, nonce uint64, control tx_types.TokenControl) rpc.NewTokenControlRequest {
+	from := r.address
+	if !r.Nodebug {
+		fmt.Println(from.String(), control.String())
+	}
+	tx := tx_types.ActionTx{
+		TxBase: types.TxBase{
+			Type:         types.TxBaseAction,
+			AccountNonce: uint64(nonce),
+			PublicKey:    r.publicKey.Bytes[:],
+		},
+		Action:     action,
+		From:       &from,
+		ActionData: &control,
+	}
+	tx.Signature = crypto.Signer.Sign(r.privKey, tx.SignatureTargets()).Bytes[:]
+	v := og.TxFormatVerifier{}
+	ok := v.VerifySignature(&tx)
+	if !ok {
+		target := tx.SignatureTargets()
+		fmt.Println(hexutil.Encode(target))
+		panic("not ok")
+	}
+	return rpc.NewTokenControlRequest{
+		Nonce:     nonce,
+		From:      tx.From.Hex(),
+		TokenId:   control.TokenId,
+		Holder:    control.Holder.Hex(),
+		Signature: tx.Signature.String(),
+		Pubkey:    r.publicKey.String(),
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 )
 
 // DO NOT USE MSGP FOR AUTO-GENERATING HERE.
@@ -46,6 +47,29 @@ func NewBigIntFromBigInt(x *big.Int) *BigInt {
 	return &BigInt{big.NewInt(0).SetBytes(x.Bytes())}
 }
 
+// NewBigIntFromDecimal parses the decimal amount x in units of
+// 10^decimals, e.g. "1.5" with 2 decimals is 150. The fraction can't have
+// more than decimals digits.
+func NewBigIntFromDecimal(x string, decimals uint8) (*BigInt, bool) {
+	intPart, fracPart := x, ""
+	if i := strings.IndexByte(x, '.'); i >= 0 {
+		intPart, fracPart = x[:i], x[i+1:]
+		if fracPart == "" {
+			return nil, false
+		}
+	}
+	if intPart == "" || len(fracPart) > int(decimals) {
+		return nil, false
+	}
+	for _, c := range intPart + fracPart {
+		if c < '0' || c > '9' {
+			return nil, false
+		}
+	}
+	fracPart += strings.Repeat("0", int(decimals)-len(fracPart))
+	return NewBigIntFromString(intPart+fracPart, 10)
+}
+
 // GetBytes returns the absolute value of x as a big-endian byte slice.
 func (bi *BigInt) GetBytes() []byte {
 	return bi.Value.Bytes()
@@ -113,6 +137,27 @@ func (bi *BigInt) SetString(x string, base int) {
 	bi.Value.SetString(x, base)
 }
 
+// DecimalString returns the value of x in units of 10^decimals, without
+// the trailing zeros of the fraction.
+func (bi *BigInt) DecimalString(decimals uint8) string {
+	s := new(big.Int).Abs(bi.Value).String()
+	if decimals > 0 {
+		if len(s) <= int(decimals) {
+			s = strings.Repeat("0", int(decimals)-len(s)+1) + s
+		}
+		point := len(s) - int(decimals)
+		frac := strings.TrimRight(s[point:], "0")
+		s = s[:point]
+		if frac != "" {
+			s += "." + frac
+		}
+	}
+	if bi.Value.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
 // GetString returns the value of x as a formatted string in some number base.
 func (bi *BigInt) GetString(base int) string {
 	return bi.Value.Text(base)
@@ -62,3 +62,31 @@ func TestBigInt_Add(t *testing.T) {
 	bi = bi.Add(NewBigInt(99))
 	fmt.Println(bi)
 }
+
+func TestDecimal(t *testing.T) {
+	for _, c := range []struct {
+		decimal  string
+		decimals uint8
+		value    string
+		str      string
+	}{
+		{"1.5", 2, "150", "1.5"},
+		{"0.01", 2, "1", "0.01"},
+		{"100", 0, "100", "100"},
+		{"100", 18, "100000000000000000000", "100"},
+		{"007.10", 3, "7100", "7.1"},
+	} {
+		v, ok := NewBigIntFromDecimal(c.decimal, c.decimals)
+		if !ok {
+			t.Fatalf("parse %s failed", c.decimal)
+		}
+		assert.Equal(t, c.value, v.String())
+		assert.Equal(t, c.str, v.DecimalString(c.decimals))
+	}
+	for _, s := range []string{"", ".", "1.", ".5", "1.234", "-1", "1e3", "1.2.3"} {
+		if _, ok := NewBigIntFromDecimal(s, 2); ok {
+			t.Fatalf("%s should be invalid", s)
+		}
+	}
+	assert.Equal(t, "-0.05", NewBigInt(-5).DecimalString(2))
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/annchain/OG/consensus/annsensus/dkg"
 	"github.com/annchain/OG/consensus/annsensus/term"
 	"github.com/annchain/OG/og"
+	"github.com/annchain/OG/ogdb"
 	"github.com/annchain/OG/types/p2p_message"
 	"github.com/annchain/OG/types/tx_types"
 	"github.com/annchain/kyber/v3/pairing/bn256"
@@ -45,9 +46,10 @@ type AnnSensus struct {
 	campaignFlag bool
 	cryptoType   crypto.CryptoType
 
-	dkg  *dkg.Dkg
-	term *term.Term
-	bft  *bft.BFT // tendermint protocal
+	dkg        *dkg.Dkg
+	term       *term.Term
+	bft        *bft.BFT // tendermint protocal
+	governance *Governance
 
 	dkgPulicKeyChan      chan kyber.Point // channel for receiving dkg response.
 	newTxHandlers        []chan types.Txi // channels to send txs.
@@ -84,6 +86,13 @@ type AnnSensus struct {
 	disable              bool
 	addedGenesisCampaign bool
 	initDone             bool
+
+	// OnConsensusParamsChanged are called when governance changes
+	// the consensus parameters at a term boundary.
+	OnConsensusParamsChanged []func(params ConsensusParams)
+	// GovernanceDB is the db the governance state is saved to so that it
+	// survives restarts, the state is kept in memory only if nil.
+	GovernanceDB ogdb.Database
 }
 
 func NewAnnSensus(termChangeInterval int, disableConsensus bool, cryptoType crypto.CryptoType, campaign bool, partnerNum int,
@@ -112,6 +121,11 @@ func NewAnnSensus(termChangeInterval int, disableConsensus bool, cryptoType cryp
 	ann.genesisAccounts = genesisAccounts
 	sort.Sort(ann.genesisAccounts)
 	ann.term = term.NewTerm(0, partnerNum, termChangeInterval)
+	ann.governance = NewGovernance(ConsensusParams{
+		PartnerNumber:      partnerNum,
+		Threshold:          bft.MajorityTwoThird(partnerNum),
+		TermChangeInterval: termChangeInterval,
+	})
 	ann.newTermChan = make(chan bool)
 	ann.genesisPkChan = make(chan *p2p_message.MessageConsensusDkgGenesisPublicKey)
 	ann.NewPeerConnectedEventListener = make(chan string)
@@ -138,6 +152,9 @@ func (as *AnnSensus) InitAccount(myAccount *account.SampleAccount, sequencerTime
 	judgeNonce func(me *account.SampleAccount) uint64, txCreator *og.TxCreator, Idag og.IDag, onSelfGenTxi chan types.Txi,
 	handleNewTxi func(txi types.Txi, peerId string), sender announcer.MessageSender) {
 	as.MyAccount = myAccount
+	params := as.governance.Params()
+	params.SequencerInterval = int(sequencerTime / time.Millisecond)
+	as.governance.SetParams(params)
 	as.Hub = sender
 	as.dkg.Hub = sender
 	as.Idag = Idag
@@ -491,6 +508,7 @@ func (as *AnnSensus) loop() {
 			}
 			var cps []*tx_types.Campaign
 			var tcs []*tx_types.TermChange
+			var govs []*tx_types.ActionTx
 			for _, tx := range txs {
 				if tx.GetType() == types.TxBaseTypeCampaign {
 					cp := tx.(*tx_types.Campaign)
@@ -503,8 +521,13 @@ func (as *AnnSensus) loop() {
 					}
 				} else if tx.GetType() == types.TxBaseTypeTermChange {
 					tcs = append(tcs, tx.(*tx_types.TermChange))
+				} else if tx.GetType() == types.TxBaseAction {
+					govs = append(govs, tx.(*tx_types.ActionTx))
 				}
 			}
+			if len(govs) > 0 {
+				as.commitGovernance(govs)
+			}
 			// TODO:
 			// here exists a bug:
 			// the isTermChanging check should be here not in commit()
@@ -524,6 +547,7 @@ func (as *AnnSensus) loop() {
 					log.Errorf("change term error: %v", err)
 					goto HandleCampaign
 				}
+				as.applyGovernance()
 				//if i am not a dkg partner , dkg publick key will got from termChange tx
 				if !as.dkg.IsValidPartner() {
 					pk, err := bn256.UnmarshalBinaryPointG2(tc.PkBls)
@@ -813,6 +837,15 @@ func (a *AnnSensus) GetInfo() *ConsensusInfo {
 	return &info
 }
 
+// BftParticipation tells if the bft of the node is started as a partner of
+// the current term and when it committed its last decision.
+func (a *AnnSensus) BftParticipation() (partner bool, lastDecision time.Time) {
+	if a.bft == nil || !a.bft.Started() || !a.dkg.IsValidPartner() {
+		return false, time.Time{}
+	}
+	return true, a.bft.LastDecision()
+}
+
 func (a *AnnSensus) GetBftStatus() interface{} {
 	if a.bft == nil {
 		return nil
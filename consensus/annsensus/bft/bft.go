@@ -28,6 +28,7 @@ import (
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -57,6 +58,9 @@ type BFT struct {
 	myAccount *account.SampleAccount
 
 	started bool
+	// lastDecision is the unix nano of the last decision committed, or of
+	// the start if none.
+	lastDecision int64
 
 	OnSelfGenTxi chan types.Txi
 }
@@ -140,6 +144,12 @@ func (b *BFT) Started() bool {
 	return b.started
 }
 
+// LastDecision returns when the last decision is committed, or when the bft
+// is started if none.
+func (b *BFT) LastDecision() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&b.lastDecision))
+}
+
 func (b *BFT) Reset(TermId int, peersPublicKey []crypto.PublicKey, myId int) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -192,6 +202,7 @@ func (b *BFT) commitDecision(state *HeightRoundState) error {
 			return err
 		}
 	}
+	atomic.StoreInt64(&b.lastDecision, time.Now().UnixNano())
 	log.Trace("commit success")
 	return nil
 }
@@ -237,6 +248,7 @@ func (b *BFT) loop() {
 		case <-b.startBftChan:
 			log.Info("bft got start gossip signal")
 			if !b.started {
+				atomic.StoreInt64(&b.lastDecision, time.Now().UnixNano())
 				goroutine.New(func() {
 					b.BFTPartner.StartNewEra(b.dag.GetHeight(), 0)
 				})
@@ -404,15 +416,29 @@ func (b *BFT) CacheProposal(hash common.Hash, proposal *p2p_message.MessagePropo
 }
 
 func (b *BFT) GetInfo() *BFTInfo {
+	b.mu.RLock()
+	sequencerTime := b.SequencerTime
+	b.mu.RUnlock()
 	bftInfo := BFTInfo{
 		BFTPartner:    b.BFTPartner.PeerInfo,
 		Partners:      b.BFTPartner.PeersInfo,
 		DKGTermId:     b.DKGTermId,
-		SequencerTime: b.SequencerTime,
+		SequencerTime: sequencerTime,
 	}
 	return &bftInfo
 }
 
+// SetSequencerTime changes the interval between two sequencers, taken by
+// the partner from its next proposal on.
+func (b *BFT) SetSequencerTime(sequencerTime time.Duration) {
+	b.mu.Lock()
+	b.SequencerTime = sequencerTime
+	b.mu.Unlock()
+	if partner, ok := b.BFTPartner.BFTPartner.(*DefaultPartner); ok {
+		partner.SetBlockTime(sequencerTime)
+	}
+}
+
 type BFTInfo struct {
 	BFTPartner    PeerInfo      `json:"bft_partner"`
 	DKGTermId     int           `json:"dkg_term_id"`
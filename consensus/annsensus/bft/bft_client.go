@@ -21,6 +21,7 @@ import (
 	"github.com/annchain/OG/common/goroutine"
 	"github.com/annchain/OG/types/p2p_message"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -123,6 +124,10 @@ type DefaultPartner struct {
 	getHeightFunc func() uint64
 	testFlag      bool
 	//wg sync.WaitGroup
+
+	// start of the current height and round, for the metrics.
+	heightStartAt time.Time
+	roundStartAt  time.Time
 }
 
 func (p *DefaultPartner) GetWaiterTimeoutChannel() chan *WaiterRequest {
@@ -153,6 +158,16 @@ func (P *DefaultPartner) SetProposalFunc(proposalFunc func() (p2p_message.Propos
 	P.proposalFunc = proposalFunc
 }
 
+// BlockTime returns the time waited before proposing a new block.
+func (p *DefaultPartner) BlockTime() time.Duration {
+	return time.Duration(atomic.LoadInt64((*int64)(&p.blockTime)))
+}
+
+// SetBlockTime changes the time waited before proposing a new block.
+func (p *DefaultPartner) SetBlockTime(blockTime time.Duration) {
+	atomic.StoreInt64((*int64)(&p.blockTime), int64(blockTime))
+}
+
 func (p *DefaultPartner) GetId() int {
 	return p.Id
 }
@@ -258,6 +273,15 @@ func (p *DefaultPartner) StartNewEra(height uint64, round int) {
 	}).Debug("Starting new round")
 
 	currState, _ := p.initHeightRound(hr)
+	now := time.Now()
+	if hr.Height != p.CurrentHR.Height || p.heightStartAt.IsZero() {
+		p.heightStartAt = now
+	} else if hr.Round != p.CurrentHR.Round {
+		bftRoundChangeMeter.Mark(1)
+	}
+	p.roundStartAt = now
+	bftHeightGauge.Update(int64(hr.Height))
+	bftRoundGauge.Update(int64(hr.Round))
 	// update partner height
 	p.CurrentHR = hr
 
@@ -380,7 +404,7 @@ func (p *DefaultPartner) Proposer(hr p2p_message.HeightRound) int {
 // GetValue generates the value requiring consensus
 func (p *DefaultPartner) GetValue(newBlock bool) (p2p_message.Proposal, uint64) {
 	//don't sleep for the same height new round
-	blockTime := time.After(p.blockTime)
+	blockTime := time.After(p.BlockTime())
 	if newBlock {
 		select {
 		case <-p.quit:
@@ -637,6 +661,10 @@ func (p *DefaultPartner) handlePreCommit(commit *p2p_message.MessagePreCommit) {
 					"hr":    p.CurrentHR.String(),
 					"value": state.MessageProposal.Value,
 				}).Info("Decision")
+				if commit.HeightRound == p.CurrentHR {
+					bftRoundTimer.UpdateSince(p.roundStartAt)
+					bftHeightTimer.UpdateSince(p.heightStartAt)
+				}
 				//send the decision to upper client to process
 				err := p.decisionFunc(state)
 				if err != nil {
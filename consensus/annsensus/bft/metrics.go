@@ -0,0 +1,29 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package bft
+
+import (
+	"github.com/annchain/OG/metrics"
+)
+
+var (
+	// time from the start of the round deciding a height to the decision.
+	bftRoundTimer = metrics.NewRegisteredTimer("consensus/bft/round", nil)
+	// time from the start of the first round of a height to the decision.
+	bftHeightTimer = metrics.NewRegisteredTimer("consensus/bft/height", nil)
+	// rounds started after a round failed to decide.
+	bftRoundChangeMeter = metrics.NewRegisteredMeter("consensus/bft/round_changes", nil)
+	bftHeightGauge      = metrics.NewRegisteredGauge("consensus/bft/current_height", nil)
+	bftRoundGauge       = metrics.NewRegisteredGauge("consensus/bft/current_round", nil)
+)
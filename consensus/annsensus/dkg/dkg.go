@@ -69,6 +69,11 @@ type Dkg struct {
 	OndkgPulicKeyChan chan kyber.Point
 	OngenesisPkChan   chan *p2p_message.MessageConsensusDkgGenesisPublicKey
 	ConfigFilePath    string
+
+	// participant number and threshold for the next dkg round,
+	// zero means unchanged.
+	nextNbParticipants int
+	nextThreshold      int
 }
 
 func NewDkg(dkgOn bool, numParts, threshold int, dag og.IDag,
@@ -118,6 +123,15 @@ func (d *Dkg) GetParticipantNumber() int {
 	return d.partner.NbParticipants
 }
 
+// SetNextParticipants changes the participant number and threshold used
+// by the next dkg round. The running partner is not affected.
+func (d *Dkg) SetNextParticipants(numParts, threshold int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextNbParticipants = numParts
+	d.nextThreshold = threshold
+}
+
 func (d *Dkg) Reset(myCampaign *tx_types.Campaign) {
 	if myCampaign == nil {
 		log.Warn("nil campagin,  i am not a dkg partner")
@@ -133,6 +147,12 @@ func (d *Dkg) Reset(myCampaign *tx_types.Campaign) {
 	p := NewDKGPartner(bn256.NewSuiteG2())
 	p.NbParticipants = d.partner.NbParticipants
 	p.Threshold = d.partner.Threshold
+	if d.nextNbParticipants > 0 {
+		p.NbParticipants = d.nextNbParticipants
+	}
+	if d.nextThreshold > 0 {
+		p.Threshold = d.nextThreshold
+	}
 	p.PartPubs = []kyber.Point{}
 	if myCampaign != nil {
 		index := -1
@@ -932,6 +952,26 @@ func (d *Dkg) Sign(msg []byte, termId int) (partSig []byte, err error) {
 	return partner.Sig(msg)
 }
 
+// SignByPartSec signs msg with the dkg secret of current term, whose public
+// key is published in the campaign of the node. Unlike the partial
+// signature of the key share, anyone can verify it by VerifyByPartPub.
+func (d *Dkg) SignByPartSec(msg []byte) ([]byte, error) {
+	if d.partner.MyPartSec == nil {
+		return nil, fmt.Errorf("not a dkg partner of term %d", d.TermId)
+	}
+	return bls.Sign(d.partner.Suite, d.partner.MyPartSec, msg)
+}
+
+// VerifyByPartPub verifies the signature of msg signed by SignByPartSec
+// with the dkg public key partPub published in a campaign.
+func VerifyByPartPub(partPub []byte, msg []byte, sig []byte) error {
+	pub, err := bn256.UnmarshalBinaryPointG2(partPub)
+	if err != nil {
+		return fmt.Errorf("unmarshal dkg public key error: %v", err)
+	}
+	return bls.Verify(bn256.NewSuiteG2(), pub, msg, sig)
+}
+
 func (d *Dkg) GetJoinPublicKey(termId int) kyber.Point {
 	partner := d.partner
 	if termId < d.TermId {
@@ -47,6 +47,10 @@ func (d *DummyDag) GetTxisByNumber(id uint64) types.Txis {
 	return txis
 }
 
+func (d *DummyDag) GetTermChangeByPkBls(pkBls []byte) *tx_types.TermChange {
+	return nil
+}
+
 func (d *DummyDag) LatestSequencer() *tx_types.Sequencer {
 	return tx_types.RandomSequencer()
 }
@@ -0,0 +1,60 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package annsensus
+
+import (
+	"fmt"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/consensus/annsensus/finality"
+)
+
+// FinalityCertificate generates the finality certificate of a confirmed tx.
+func (as *AnnSensus) FinalityCertificate(hash common.Hash) (*finality.Certificate, error) {
+	tx := as.Idag.GetTx(hash)
+	if tx == nil {
+		return nil, fmt.Errorf("tx not confirmed: %s", hash.Hex())
+	}
+	seq := as.Idag.GetSequencerByHeight(tx.GetHeight())
+	if seq == nil {
+		return nil, fmt.Errorf("sequencer not found at height %d", tx.GetHeight())
+	}
+	if len(seq.BlsJointSig) == 0 {
+		return nil, fmt.Errorf("sequencer %d is not signed by the committee", seq.Height)
+	}
+	termId, tc := as.term.GetTermChangeByPkBls(seq.BlsJointPubKey)
+	if tc == nil {
+		// the term changes confirmed before a restart are only in the db.
+		tc = as.Idag.GetTermChangeByPkBls(seq.BlsJointPubKey)
+		if tc == nil {
+			return nil, fmt.Errorf("term change not found for sequencer %d", seq.Height)
+		}
+		termId = tc.TermID
+	}
+	proof, err := finality.BuildProof(seq, hash, as.Idag.GetTxisByNumber(seq.Height))
+	if err != nil {
+		return nil, err
+	}
+	return &finality.Certificate{
+		TxHash:          hash,
+		SequencerHash:   seq.GetTxHash(),
+		SequencerHeight: seq.Height,
+		BlsJointSig:     seq.BlsJointSig,
+		BlsJointPubKey:  seq.BlsJointPubKey,
+		TermId:          termId,
+		CryptoType:      as.cryptoType,
+		Senators:        tc.SigSet,
+		Proof:           proof,
+	}, nil
+}
@@ -0,0 +1,231 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package finality
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/annchain/kyber/v3/pairing/bn256"
+	"github.com/annchain/kyber/v3/sign/bls"
+	"golang.org/x/crypto/sha3"
+)
+
+// ProofNode contains the fields needed to recalculate a tx hash
+// without the whole tx.
+type ProofNode struct {
+	ParentsHash common.Hashes `json:"parents_hash"`
+	Weight      uint64        `json:"weight"`
+	Data        hexutil.Bytes `json:"data,omitempty"` // only used by archives
+	Compression uint8         `json:"compression,omitempty"`
+	ContentType string        `json:"content_type,omitempty"`
+	// Timestamp is hashed in the sequencers from the block context fork
+	// height on, which is told by TimestampHashed.
+	Timestamp       int64       `json:"timestamp,omitempty"`
+	TimestampHashed bool        `json:"timestamp_hashed,omitempty"`
+	MinedHash       common.Hash `json:"mined_hash"`
+}
+
+func NewProofNode(tx types.Txi) *ProofNode {
+	node := &ProofNode{
+		ParentsHash: tx.Parents(),
+		Weight:      tx.GetWeight(),
+		MinedHash:   tx.GetBase().CalcMinedHash(),
+	}
+	if ac, ok := tx.(*tx_types.Archive); ok {
+		node.Data = ac.Data
+		node.Compression = ac.Compression
+		node.ContentType = ac.ContentType
+	}
+	// the hash of the sequencer tells whether its timestamp is hashed.
+	if seq, ok := tx.(*tx_types.Sequencer); ok && seq.GetTxHash() == seq.CalcTxHashAt(true) {
+		node.Timestamp = seq.Timestamp
+		node.TimestampHashed = true
+	}
+	return node
+}
+
+// Hash calculates the tx hash the same way as TxBase.CalcTxHash and
+// Sequencer.CalcTxHashAt do.
+func (n *ProofNode) Hash() (hash common.Hash) {
+	w := types.NewBinaryWriter()
+
+	for _, ancestor := range n.ParentsHash {
+		w.Write(ancestor.Bytes)
+	}
+	w.Write(n.Weight)
+	if n.TimestampHashed {
+		w.Write(n.Timestamp)
+	}
+	if len(n.Data) > 0 {
+		w.Write([]byte(n.Data))
+	}
+	if n.ContentType != "" || n.Compression != tx_types.ArchiveCompressionNone {
+		w.Write(n.Compression, []byte(n.ContentType))
+	}
+	w.Write(n.MinedHash.Bytes)
+	result := sha3.Sum256(w.Bytes())
+	hash.MustSetBytes(result[0:], common.PaddingNone)
+	return
+}
+
+func (n *ProofNode) hasParent(hash common.Hash) bool {
+	for _, parent := range n.ParentsHash {
+		if parent == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// Certificate proves that a tx is final. The tx is an ancestor of the
+// sequencer, the sequencer is signed by the bls joint key of the
+// committee, and the joint key is signed by every senator of the term.
+type Certificate struct {
+	TxHash          common.Hash        `json:"tx_hash"`
+	SequencerHash   common.Hash        `json:"sequencer_hash"`
+	SequencerHeight uint64             `json:"sequencer_height"`
+	BlsJointSig     hexutil.Bytes      `json:"bls_joint_sig"`
+	BlsJointPubKey  hexutil.Bytes      `json:"bls_joint_pub_key"`
+	TermId          uint64             `json:"term_id"`
+	CryptoType      crypto.CryptoType  `json:"crypto_type"`
+	Senators        []*tx_types.SigSet `json:"senators"`
+	// Proof is the path from the sequencer down to the tx. Each node's
+	// hash is one of the parents of the node before it, and the tx hash
+	// is one of the parents of the last node.
+	Proof []*ProofNode `json:"proof"`
+}
+
+// BuildProof searches the path from the sequencer to the tx inside the
+// batch confirmed by the sequencer.
+func BuildProof(seq *tx_types.Sequencer, txHash common.Hash, batch types.Txis) ([]*ProofNode, error) {
+	if seq.GetTxHash() == txHash {
+		return nil, nil
+	}
+	txs := make(map[common.Hash]types.Txi)
+	for _, tx := range batch {
+		txs[tx.GetTxHash()] = tx
+	}
+	txs[seq.GetTxHash()] = seq
+
+	// children records the child from which each tx is visited first.
+	children := make(map[common.Hash]common.Hash)
+	queue := []common.Hash{seq.GetTxHash()}
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		for _, parent := range txs[hash].Parents() {
+			if _, visited := children[parent]; visited {
+				continue
+			}
+			if parent == txHash {
+				return buildPath(txs, children, hash, seq.GetTxHash()), nil
+			}
+			if _, ok := txs[parent]; !ok {
+				// confirmed by former sequencers.
+				continue
+			}
+			children[parent] = hash
+			queue = append(queue, parent)
+		}
+	}
+	return nil, fmt.Errorf("tx %s is not confirmed by sequencer %s", txHash.Hex(), seq.GetTxHash().Hex())
+}
+
+func buildPath(txs map[common.Hash]types.Txi, children map[common.Hash]common.Hash, last common.Hash, root common.Hash) []*ProofNode {
+	var path []*ProofNode
+	for hash := last; ; hash = children[hash] {
+		path = append([]*ProofNode{NewProofNode(txs[hash])}, path...)
+		if hash == root {
+			return path
+		}
+	}
+}
+
+// Verify checks the certificate by itself, requiring at least threshold
+// senators to have signed the bls joint public key. The caller should also
+// check that the senators' public keys are the ones it trusts.
+func (c *Certificate) Verify(threshold int) error {
+	if err := c.verifyProof(); err != nil {
+		return err
+	}
+	suite := bn256.NewSuiteG2()
+	pubKey, err := bn256.UnmarshalBinaryPointG2(c.BlsJointPubKey)
+	if err != nil {
+		return fmt.Errorf("unmarshal bls joint public key error: %v", err)
+	}
+	if err := bls.Verify(suite, pubKey, c.SequencerHash.ToBytes(), c.BlsJointSig); err != nil {
+		return fmt.Errorf("verify bls joint signature error: %v", err)
+	}
+	if len(c.Senators) == 0 {
+		return fmt.Errorf("no senators")
+	}
+	if len(c.Senators) < threshold {
+		return fmt.Errorf("%d senators signed, %d required", len(c.Senators), threshold)
+	}
+	signer := crypto.NewSigner(c.CryptoType)
+	seen := make(map[string]bool)
+	for _, s := range c.Senators {
+		if s == nil {
+			return fmt.Errorf("nil senator")
+		}
+		key := string(s.PublicKey)
+		if seen[key] {
+			return fmt.Errorf("duplicate senator %s", hexutil.Encode(s.PublicKey))
+		}
+		seen[key] = true
+		pk := crypto.PublicKeyFromBytes(c.CryptoType, s.PublicKey)
+		sig := crypto.SignatureFromBytes(c.CryptoType, s.Signature)
+		if !signer.Verify(pk, sig, c.BlsJointPubKey) {
+			return fmt.Errorf("senator %s didn't sign the bls joint public key", hexutil.Encode(s.PublicKey))
+		}
+	}
+	return nil
+}
+
+func (c *Certificate) verifyProof() error {
+	if len(c.Proof) == 0 {
+		if c.TxHash != c.SequencerHash {
+			return fmt.Errorf("empty proof")
+		}
+		return nil
+	}
+	if c.Proof[0].Hash() != c.SequencerHash {
+		return fmt.Errorf("proof root is not the sequencer")
+	}
+	for i := 1; i < len(c.Proof); i++ {
+		if !c.Proof[i-1].hasParent(c.Proof[i].Hash()) {
+			return fmt.Errorf("broken proof at %d", i)
+		}
+	}
+	if !c.Proof[len(c.Proof)-1].hasParent(c.TxHash) {
+		return fmt.Errorf("tx is not in the proof")
+	}
+	return nil
+}
+
+// HasSenator returns true if the public key is one of the senators.
+func (c *Certificate) HasSenator(publicKey []byte) bool {
+	for _, s := range c.Senators {
+		if s != nil && bytes.Equal(s.PublicKey, publicKey) {
+			return true
+		}
+	}
+	return false
+}
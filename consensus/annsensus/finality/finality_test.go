@@ -0,0 +1,150 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package finality
+
+import (
+	"testing"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/annchain/kyber/v3/pairing/bn256"
+	"github.com/annchain/kyber/v3/sign/bls"
+	"github.com/annchain/kyber/v3/util/random"
+)
+
+func genCertificate(t *testing.T) (*Certificate, types.Txis) {
+	old := tx_types.RandomTx()
+	ac := tx_types.RandomArchive()
+	ac.ParentsHash = common.Hashes{old.GetTxHash()}
+	ac.Hash = ac.CalcTxHash()
+	tx := tx_types.RandomTx()
+	tx.ParentsHash = common.Hashes{ac.GetTxHash(), old.GetTxHash()}
+	tx.Hash = tx.CalcTxHash()
+	seq := tx_types.RandomSequencer()
+	seq.ParentsHash = common.Hashes{tx.GetTxHash()}
+	seq.Hash = seq.CalcTxHash()
+
+	suite := bn256.NewSuiteG2()
+	priv, pub := bls.NewKeyPair(suite, random.New())
+	jointSig, err := bls.Sign(suite, priv, seq.GetTxHash().ToBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jointPub, err := pub.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := crypto.NewSigner(crypto.CryptoTypeSecp256k1)
+	var senators []*tx_types.SigSet
+	for i := 0; i < 4; i++ {
+		pk, sk := signer.RandomKeyPair()
+		senators = append(senators, &tx_types.SigSet{
+			PublicKey: pk.Bytes,
+			Signature: signer.Sign(sk, jointPub).Bytes,
+		})
+	}
+
+	batch := types.Txis{ac, tx}
+	proof, err := BuildProof(seq, ac.GetTxHash(), batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Certificate{
+		TxHash:          ac.GetTxHash(),
+		SequencerHash:   seq.GetTxHash(),
+		SequencerHeight: seq.Height,
+		BlsJointSig:     jointSig,
+		BlsJointPubKey:  jointPub,
+		TermId:          1,
+		CryptoType:      crypto.CryptoTypeSecp256k1,
+		Senators:        senators,
+		Proof:           proof,
+	}, batch
+}
+
+func TestCertificate_Verify(t *testing.T) {
+	cert, _ := genCertificate(t)
+	if len(cert.Proof) != 2 {
+		t.Fatal("proof should contain the sequencer and the tx", len(cert.Proof))
+	}
+	if err := cert.Verify(len(cert.Senators)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cert.Verify(len(cert.Senators) + 1); err == nil {
+		t.Fatal("less senators than the threshold should fail")
+	}
+
+	cert.TxHash = common.RandomHash()
+	if err := cert.Verify(len(cert.Senators)); err == nil {
+		t.Fatal("tx not in the proof should fail")
+	}
+}
+
+func TestCertificate_VerifyTampered(t *testing.T) {
+	cert, _ := genCertificate(t)
+	cert.Proof[1].Weight++
+	if err := cert.Verify(len(cert.Senators)); err == nil {
+		t.Fatal("tampered proof should fail")
+	}
+
+	cert, _ = genCertificate(t)
+	cert.BlsJointSig[0] ^= 0xff
+	if err := cert.Verify(len(cert.Senators)); err == nil {
+		t.Fatal("tampered bls sig should fail")
+	}
+
+	cert, _ = genCertificate(t)
+	cert.Senators[0].Signature[1] ^= 0xff
+	if err := cert.Verify(len(cert.Senators)); err == nil {
+		t.Fatal("tampered senator sig should fail")
+	}
+}
+
+func TestBuildProof_NotConfirmed(t *testing.T) {
+	cert, batch := genCertificate(t)
+	seq := tx_types.RandomSequencer()
+	seq.ParentsHash = common.Hashes{cert.Proof[0].ParentsHash[0]}
+	seq.Hash = seq.CalcTxHash()
+	if _, err := BuildProof(seq, common.RandomHash(), batch); err == nil {
+		t.Fatal("unknown tx should not be proved")
+	}
+}
+
+func TestProofNode_Archive(t *testing.T) {
+	ac := tx_types.RandomArchive()
+	if NewProofNode(ac).Hash() != ac.CalcTxHash() {
+		t.Fatal("proof node hash mismatch for legacy archive")
+	}
+	ac.ContentType = "text/plain"
+	ac.Compression = tx_types.ArchiveCompressionGzip
+	if NewProofNode(ac).Hash() != ac.CalcTxHash() {
+		t.Fatal("proof node hash mismatch for archive with metadata")
+	}
+}
+
+func TestProofNode_Sequencer(t *testing.T) {
+	for _, timestampSigned := range []bool{false, true} {
+		seq := tx_types.RandomSequencer()
+		seq.Timestamp = 1000
+		seq.Hash = seq.CalcTxHashAt(timestampSigned)
+		node := NewProofNode(seq)
+		if node.TimestampHashed != timestampSigned || node.Hash() != seq.GetTxHash() {
+			t.Fatalf("proof node hash mismatch for sequencer, timestamp signed %v", timestampSigned)
+		}
+	}
+}
@@ -0,0 +1,489 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package annsensus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/goroutine"
+	"github.com/annchain/OG/consensus/annsensus/dkg"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+)
+
+// governanceKey is the key of the governance state in ogdb.
+var governanceKey = []byte("governance")
+
+// ConsensusParams are the consensus parameters that can be changed
+// on chain by governance proposals.
+type ConsensusParams struct {
+	PartnerNumber      int         `json:"partner_number"`
+	Threshold          int         `json:"threshold"`
+	TermChangeInterval int         `json:"term_change_interval"`
+	SequencerInterval  int         `json:"sequencer_interval"` // in milliseconds
+	MaxTxHash          common.Hash `json:"max_tx_hash"`
+}
+
+func (p ConsensusParams) apply(proposal *tx_types.GovernanceProposal) ConsensusParams {
+	switch proposal.Parameter {
+	case tx_types.GovernanceParamPartnerNumber:
+		p.PartnerNumber = int(proposal.Value)
+	case tx_types.GovernanceParamThreshold:
+		p.Threshold = int(proposal.Value)
+	case tx_types.GovernanceParamTermChangeInterval:
+		p.TermChangeInterval = int(proposal.Value)
+	case tx_types.GovernanceParamSequencerInterval:
+		p.SequencerInterval = int(proposal.Value)
+	case tx_types.GovernanceParamMaxTxHash:
+		p.MaxTxHash = proposal.HashValue
+	}
+	// a threshold above the partner number could never be reached, it is
+	// lowered whenever the threshold or the partner number changes.
+	if p.PartnerNumber > 0 && p.Threshold > p.PartnerNumber {
+		p.Threshold = p.PartnerNumber
+	}
+	return p
+}
+
+// ProposalStatus records a governance proposal and the votes it received.
+type ProposalStatus struct {
+	Hash      common.Hash                 `json:"hash"`
+	Proposer  common.Address              `json:"proposer"`
+	Proposal  tx_types.GovernanceProposal `json:"proposal"`
+	Approvals []common.Address            `json:"approvals"`
+	Rejects   []common.Address            `json:"rejects"`
+	Passed    bool                        `json:"passed"`
+	Applied   bool                        `json:"applied"`
+
+	voted map[common.Address]bool
+}
+
+// Governance tallies the confirmed governance proposals and votes.
+// Passed proposals are kept pending and only applied at term boundaries.
+type Governance struct {
+	params    ConsensusParams
+	proposals map[common.Hash]*ProposalStatus
+	pending   []*ProposalStatus
+
+	mu sync.RWMutex
+}
+
+func NewGovernance(params ConsensusParams) *Governance {
+	return &Governance{
+		params:    params,
+		proposals: make(map[common.Hash]*ProposalStatus),
+	}
+}
+
+// Params returns the active consensus parameters.
+func (g *Governance) Params() ConsensusParams {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.params
+}
+
+// SetParams overrides the active parameters, normally used at init.
+func (g *Governance) SetParams(params ConsensusParams) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.params = params
+}
+
+// AddProposal records a confirmed proposal raised in term termId.
+func (g *Governance) AddProposal(tx *tx_types.ActionTx, termId uint64) error {
+	proposal := tx.GetGovernanceProposal()
+	if proposal == nil {
+		return fmt.Errorf("not a governance proposal")
+	}
+	if err := proposal.ValidateValue(); err != nil {
+		return err
+	}
+	if proposal.TermId != termId {
+		return fmt.Errorf("proposal is raised in term %d, current term %d", proposal.TermId, termId)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.proposals[tx.GetTxHash()]; ok {
+		return fmt.Errorf("duplicate proposal %s", tx.GetTxHash().Hex())
+	}
+	g.proposals[tx.GetTxHash()] = &ProposalStatus{
+		Hash:     tx.GetTxHash(),
+		Proposer: tx.Sender(),
+		Proposal: *proposal,
+		voted:    make(map[common.Address]bool),
+	}
+	return nil
+}
+
+// AddVote records a confirmed vote checked by verify, which should make
+// sure the voter is a senator and the vote is signed by it. A proposal
+// passes once the number of approvals reaches the active threshold. It
+// returns true if this vote makes the proposal pass.
+func (g *Governance) AddVote(tx *tx_types.ActionTx, verify func(voter common.Address, vote *tx_types.GovernanceVote) error) (bool, error) {
+	vote := tx.GetGovernanceVote()
+	if vote == nil {
+		return false, fmt.Errorf("not a governance vote")
+	}
+	if err := verify(tx.Sender(), vote); err != nil {
+		return false, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ps, ok := g.proposals[vote.ProposalHash]
+	if !ok {
+		return false, fmt.Errorf("proposal not found: %s", vote.ProposalHash.Hex())
+	}
+	if ps.Passed {
+		return false, nil
+	}
+	voter := tx.Sender()
+	if ps.voted[voter] {
+		return false, fmt.Errorf("%s already voted for %s", voter.Hex(), vote.ProposalHash.Hex())
+	}
+	ps.voted[voter] = true
+	if !vote.Approve {
+		ps.Rejects = append(ps.Rejects, voter)
+		return false, nil
+	}
+	ps.Approvals = append(ps.Approvals, voter)
+	if len(ps.Approvals) < g.params.Threshold {
+		return false, nil
+	}
+	ps.Passed = true
+	g.pending = append(g.pending, ps)
+	return true, nil
+}
+
+// GetProposal returns the status of a proposal, nil if not found.
+func (g *Governance) GetProposal(hash common.Hash) *ProposalStatus {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.proposals[hash]
+}
+
+// Proposals returns all the proposals recorded, ordered by term id.
+func (g *Governance) Proposals() []*ProposalStatus {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var proposals []*ProposalStatus
+	for _, ps := range g.proposals {
+		proposals = append(proposals, ps)
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].Proposal.TermId != proposals[j].Proposal.TermId {
+			return proposals[i].Proposal.TermId < proposals[j].Proposal.TermId
+		}
+		return proposals[i].Hash.Cmp(proposals[j].Hash) < 0
+	})
+	return proposals
+}
+
+// ApplyPending applies all the passed proposals in the order they passed,
+// drops the proposals of former terms and returns the new parameters.
+// It should be called at term boundaries only.
+func (g *Governance) ApplyPending(newTermId uint64) (ConsensusParams, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	changed := len(g.pending) > 0
+	for _, ps := range g.pending {
+		g.params = g.params.apply(&ps.Proposal)
+		ps.Applied = true
+	}
+	g.pending = nil
+	for hash, ps := range g.proposals {
+		if ps.Proposal.TermId < newTermId && !ps.Passed {
+			delete(g.proposals, hash)
+		}
+	}
+	return g.params, changed
+}
+
+// governanceData is the governance state saved in ogdb.
+type governanceData struct {
+	Params    ConsensusParams   `json:"params"`
+	Proposals []*ProposalStatus `json:"proposals"`
+	Pending   []common.Hash     `json:"pending"`
+}
+
+// Save writes the governance state to db.
+func (g *Governance) Save(db ogdb.Database) error {
+	g.mu.RLock()
+	data := governanceData{Params: g.params}
+	for _, ps := range g.proposals {
+		data.Proposals = append(data.Proposals, ps)
+	}
+	for _, ps := range g.pending {
+		data.Pending = append(data.Pending, ps.Hash)
+	}
+	bts, err := json.Marshal(data)
+	g.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return db.Put(governanceKey, bts)
+}
+
+// Load restores the governance state saved in db. It returns false if it
+// was never saved.
+func (g *Governance) Load(db ogdb.Database) (bool, error) {
+	has, err := db.Has(governanceKey)
+	if err != nil || !has {
+		return false, err
+	}
+	bts, err := db.Get(governanceKey)
+	if err != nil {
+		return false, err
+	}
+	var data governanceData
+	if err := json.Unmarshal(bts, &data); err != nil {
+		return false, err
+	}
+
+	proposals := make(map[common.Hash]*ProposalStatus)
+	for _, ps := range data.Proposals {
+		ps.voted = make(map[common.Address]bool)
+		for _, voter := range ps.Approvals {
+			ps.voted[voter] = true
+		}
+		for _, voter := range ps.Rejects {
+			ps.voted[voter] = true
+		}
+		proposals[ps.Hash] = ps
+	}
+	var pending []*ProposalStatus
+	for _, hash := range data.Pending {
+		ps, ok := proposals[hash]
+		if !ok {
+			return false, fmt.Errorf("pending proposal not found: %s", hash.Hex())
+		}
+		pending = append(pending, ps)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.params = data.Params
+	g.proposals = proposals
+	g.pending = pending
+	return true, nil
+}
+
+// LoadGovernance restores the governance state saved in GovernanceDB and
+// activates its consensus parameters. Nothing is restored if the node
+// never saved it.
+func (as *AnnSensus) LoadGovernance() error {
+	if as.GovernanceDB == nil {
+		return nil
+	}
+	loaded, err := as.governance.Load(as.GovernanceDB)
+	if err != nil || !loaded {
+		return err
+	}
+	params := as.governance.Params()
+	log.WithField("params", params).Info("governance state loaded")
+	as.setConsensusParams(params)
+	return nil
+}
+
+// saveGovernance saves the governance state in GovernanceDB.
+func (as *AnnSensus) saveGovernance() {
+	if as.GovernanceDB == nil {
+		return
+	}
+	if err := as.governance.Save(as.GovernanceDB); err != nil {
+		log.WithError(err).Error("save governance error")
+	}
+}
+
+// SetMaxTxHash sets the initial max tx hash parameter.
+func (as *AnnSensus) SetMaxTxHash(hash common.Hash) {
+	params := as.governance.Params()
+	params.MaxTxHash = hash
+	as.governance.SetParams(params)
+}
+
+// GovernanceParams returns the active consensus parameters.
+func (as *AnnSensus) GovernanceParams() ConsensusParams {
+	return as.governance.Params()
+}
+
+// GovernanceProposals returns the proposals of current and former terms.
+func (as *AnnSensus) GovernanceProposals() []*ProposalStatus {
+	return as.governance.Proposals()
+}
+
+// GetGovernanceProposal returns the status of a proposal, nil if not found.
+func (as *AnnSensus) GetGovernanceProposal(hash common.Hash) *ProposalStatus {
+	return as.governance.GetProposal(hash)
+}
+
+// ProposeParamChange generates a governance proposal to change a
+// consensus parameter. Only senators of current term can propose.
+func (as *AnnSensus) ProposeParamChange(param uint8, value uint64, hashValue common.Hash) error {
+	if as.disable {
+		return fmt.Errorf("annsensus disabled")
+	}
+	if as.term.GetSenator(as.MyAccount.Address) == nil {
+		return fmt.Errorf("only senators can propose")
+	}
+	proposal := &tx_types.GovernanceProposal{
+		TermId:    as.term.ID(),
+		Parameter: param,
+		Value:     value,
+		HashValue: hashValue,
+	}
+	if err := proposal.ValidateValue(); err != nil {
+		return err
+	}
+	tx := &tx_types.ActionTx{
+		TxBase: types.TxBase{
+			Type:      types.TxBaseAction,
+			PublicKey: as.MyAccount.PublicKey.Bytes[:],
+		},
+		Action:     tx_types.ActionTxActionGovernanceProposal,
+		From:       &as.MyAccount.Address,
+		ActionData: proposal,
+	}
+	log.WithField("proposal", proposal).Debug("gen governance proposal")
+	as.sendTx(tx)
+	return nil
+}
+
+// VoteProposal generates a vote for a proposal. The vote carries the bls
+// signature of the proposal hash by the dkg key of the senator's campaign.
+func (as *AnnSensus) VoteProposal(hash common.Hash, approve bool) error {
+	if as.disable {
+		return fmt.Errorf("annsensus disabled")
+	}
+	if as.term.GetSenator(as.MyAccount.Address) == nil {
+		return fmt.Errorf("only senators can vote")
+	}
+	if as.governance.GetProposal(hash) == nil {
+		return fmt.Errorf("proposal not found: %s", hash.Hex())
+	}
+	sig, err := as.dkg.SignByPartSec(hash.ToBytes())
+	if err != nil {
+		return err
+	}
+	tx := &tx_types.ActionTx{
+		TxBase: types.TxBase{
+			Type:      types.TxBaseAction,
+			PublicKey: as.MyAccount.PublicKey.Bytes[:],
+		},
+		Action: tx_types.ActionTxActionGovernanceVote,
+		From:   &as.MyAccount.Address,
+		ActionData: &tx_types.GovernanceVote{
+			ProposalHash: hash,
+			Approve:      approve,
+			BlsSig:       sig,
+		},
+	}
+	log.WithField("proposal", hash.TerminalString()).WithField("approve", approve).Debug("gen governance vote")
+	as.sendTx(tx)
+	return nil
+}
+
+func (as *AnnSensus) sendTx(tx types.Txi) {
+	goroutine.New(func() {
+		for _, c := range as.newTxHandlers {
+			c <- tx
+		}
+	})
+}
+
+// commitGovernance records the confirmed governance txs. Proposals
+// are handled before votes since both may be confirmed together.
+func (as *AnnSensus) commitGovernance(txs []*tx_types.ActionTx) {
+	for _, tx := range txs {
+		if tx.GetGovernanceProposal() == nil {
+			continue
+		}
+		if err := as.governance.AddProposal(tx, as.term.ID()); err != nil {
+			log.WithError(err).WithField("tx", tx).Warn("add governance proposal error")
+		}
+	}
+	for _, tx := range txs {
+		if tx.GetGovernanceVote() == nil {
+			continue
+		}
+		passed, err := as.governance.AddVote(tx, as.verifyVote)
+		if err != nil {
+			log.WithError(err).WithField("tx", tx).Warn("add governance vote error")
+			continue
+		}
+		if passed {
+			log.WithField("proposal", tx.GetGovernanceVote().ProposalHash.TerminalString()).Info("governance proposal passed")
+		}
+	}
+	as.saveGovernance()
+}
+
+// verifyVote checks that voter is a senator of current term and vote is
+// signed by the dkg key published in the senator's campaign, which every
+// node is able to verify.
+func (as *AnnSensus) verifyVote(voter common.Address, vote *tx_types.GovernanceVote) error {
+	senator := as.term.GetSenator(voter)
+	if senator == nil {
+		return fmt.Errorf("%s is not a senator", voter.Hex())
+	}
+	if len(vote.BlsSig) == 0 {
+		return fmt.Errorf("vote has no bls signature")
+	}
+	if err := dkg.VerifyByPartPub(senator.DkgPublicKey(), vote.ProposalHash.ToBytes(), vote.BlsSig); err != nil {
+		return fmt.Errorf("verify vote bls signature failed: %v", err)
+	}
+	return nil
+}
+
+// applyGovernance applies the passed proposals after a term change.
+func (as *AnnSensus) applyGovernance() {
+	params, changed := as.governance.ApplyPending(as.term.ID())
+	as.saveGovernance()
+	if !changed {
+		return
+	}
+	log.WithField("params", params).Info("consensus params changed")
+	as.setConsensusParams(params)
+}
+
+// setConsensusParams activates the consensus parameters params.
+func (as *AnnSensus) setConsensusParams(params ConsensusParams) {
+	as.term.UpdateParams(params.PartnerNumber, params.TermChangeInterval)
+	as.dkg.SetNextParticipants(params.PartnerNumber, params.Threshold)
+	as.mu.Lock()
+	as.NbParticipants = params.PartnerNumber
+	as.Threshold = params.Threshold
+	as.mu.Unlock()
+	if as.bft != nil && params.SequencerInterval > 0 {
+		as.bft.SetSequencerTime(time.Duration(params.SequencerInterval) * time.Millisecond)
+	}
+	for _, f := range as.OnConsensusParamsChanged {
+		f(params)
+	}
+}
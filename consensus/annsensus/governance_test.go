@@ -0,0 +1,227 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package annsensus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/consensus/annsensus/dkg"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/annchain/kyber/v3/pairing/bn256"
+	"github.com/annchain/kyber/v3/sign/bls"
+)
+
+func newGovernanceTx(from common.Address, action uint8, data tx_types.ActionData) *tx_types.ActionTx {
+	tx := &tx_types.ActionTx{
+		TxBase:     types.TxBase{Type: types.TxBaseAction},
+		Action:     action,
+		From:       &from,
+		ActionData: data,
+	}
+	tx.Hash = common.RandomHash()
+	return tx
+}
+
+// acceptVote accepts any vote.
+func acceptVote(voter common.Address, vote *tx_types.GovernanceVote) error {
+	return nil
+}
+
+func TestGovernance_ApplyPending(t *testing.T) {
+	g := NewGovernance(ConsensusParams{PartnerNumber: 4, Threshold: 3, TermChangeInterval: 10})
+	senators := []common.Address{common.RandomAddress(), common.RandomAddress(), common.RandomAddress(), common.RandomAddress()}
+
+	proposal := newGovernanceTx(senators[0], tx_types.ActionTxActionGovernanceProposal,
+		&tx_types.GovernanceProposal{TermId: 1, Parameter: tx_types.GovernanceParamTermChangeInterval, Value: 20})
+	if err := g.AddProposal(proposal, 2); err == nil {
+		t.Fatal("proposal of another term should be rejected")
+	}
+	if err := g.AddProposal(proposal, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddProposal(proposal, 1); err == nil {
+		t.Fatal("duplicate proposal should be rejected")
+	}
+
+	vote := func(i int, approve bool) (bool, error) {
+		return g.AddVote(newGovernanceTx(senators[i], tx_types.ActionTxActionGovernanceVote,
+			&tx_types.GovernanceVote{ProposalHash: proposal.GetTxHash(), Approve: approve}), acceptVote)
+	}
+	if passed, err := vote(0, true); err != nil || passed {
+		t.Fatal("should not pass", passed, err)
+	}
+	if _, err := vote(0, true); err == nil {
+		t.Fatal("duplicate vote should be rejected")
+	}
+	if passed, err := vote(1, false); err != nil || passed {
+		t.Fatal("should not pass", passed, err)
+	}
+	if passed, err := vote(2, true); err != nil || passed {
+		t.Fatal("should not pass", passed, err)
+	}
+	if passed, err := vote(3, true); err != nil || !passed {
+		t.Fatal("should pass", passed, err)
+	}
+	if g.Params().TermChangeInterval != 10 {
+		t.Fatal("params should not change before term boundary")
+	}
+
+	params, changed := g.ApplyPending(2)
+	if !changed || params.TermChangeInterval != 20 {
+		t.Fatal("params not applied", changed, params)
+	}
+	ps := g.GetProposal(proposal.GetTxHash())
+	if ps == nil || !ps.Applied {
+		t.Fatal("proposal should be kept as applied", ps)
+	}
+	if _, changed = g.ApplyPending(3); changed {
+		t.Fatal("nothing should be applied")
+	}
+}
+
+func TestGovernance_DropExpired(t *testing.T) {
+	g := NewGovernance(ConsensusParams{PartnerNumber: 4, Threshold: 3})
+	proposal := newGovernanceTx(common.RandomAddress(), tx_types.ActionTxActionGovernanceProposal,
+		&tx_types.GovernanceProposal{TermId: 1, Parameter: tx_types.GovernanceParamPartnerNumber, Value: 5})
+	if err := g.AddProposal(proposal, 1); err != nil {
+		t.Fatal(err)
+	}
+	g.ApplyPending(2)
+	if g.GetProposal(proposal.GetTxHash()) != nil {
+		t.Fatal("unpassed proposal of former term should be dropped")
+	}
+}
+
+func TestGovernance_SaveLoad(t *testing.T) {
+	db := ogdb.NewMemDatabase()
+
+	g := NewGovernance(ConsensusParams{PartnerNumber: 4, Threshold: 2, TermChangeInterval: 10})
+	if loaded, err := g.Load(db); err != nil || loaded {
+		t.Fatal("nothing should be loaded", loaded, err)
+	}
+	voters := []common.Address{common.RandomAddress(), common.RandomAddress()}
+	vote := func(g *Governance, voter common.Address, proposal *tx_types.ActionTx) (bool, error) {
+		return g.AddVote(newGovernanceTx(voter, tx_types.ActionTxActionGovernanceVote,
+			&tx_types.GovernanceVote{ProposalHash: proposal.GetTxHash(), Approve: true}), acceptVote)
+	}
+	passed := newGovernanceTx(common.RandomAddress(), tx_types.ActionTxActionGovernanceProposal,
+		&tx_types.GovernanceProposal{TermId: 1, Parameter: tx_types.GovernanceParamTermChangeInterval, Value: 20})
+	open := newGovernanceTx(common.RandomAddress(), tx_types.ActionTxActionGovernanceProposal,
+		&tx_types.GovernanceProposal{TermId: 1, Parameter: tx_types.GovernanceParamPartnerNumber, Value: 5})
+	for _, tx := range []*tx_types.ActionTx{passed, open} {
+		if err := g.AddProposal(tx, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := vote(g, voters[0], passed); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := vote(g, voters[1], passed); err != nil || !ok {
+		t.Fatal("should pass", ok, err)
+	}
+	if _, err := vote(g, voters[0], open); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Save(db); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewGovernance(ConsensusParams{})
+	if loaded, err := restored.Load(db); err != nil || !loaded {
+		t.Fatal("governance should be loaded", loaded, err)
+	}
+	if restored.Params() != g.Params() {
+		t.Fatal("params mismatch", restored.Params())
+	}
+	if restored.GetProposal(open.GetTxHash()) == nil {
+		t.Fatal("open proposal should be restored")
+	}
+	if _, err := vote(restored, voters[0], open); err == nil {
+		t.Fatal("duplicate vote should be rejected after restore")
+	}
+	params, changed := restored.ApplyPending(2)
+	if !changed || params.TermChangeInterval != 20 {
+		t.Fatal("restored pending proposal not applied", changed, params)
+	}
+}
+
+func TestGovernance_VerifyVote(t *testing.T) {
+	g := NewGovernance(ConsensusParams{PartnerNumber: 4, Threshold: 1})
+	proposal := newGovernanceTx(common.RandomAddress(), tx_types.ActionTxActionGovernanceProposal,
+		&tx_types.GovernanceProposal{TermId: 1, Parameter: tx_types.GovernanceParamTermChangeInterval, Value: 20})
+	if err := g.AddProposal(proposal, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the senator signs with the secret of the dkg key in its campaign.
+	suite := bn256.NewSuiteG2()
+	sec := suite.Scalar().Pick(suite.RandomStream())
+	pub, _ := suite.Point().Mul(sec, nil).MarshalBinary()
+	senator := common.RandomAddress()
+	verify := func(voter common.Address, vote *tx_types.GovernanceVote) error {
+		if voter != senator {
+			return fmt.Errorf("%s is not a senator", voter.Hex())
+		}
+		return dkg.VerifyByPartPub(pub, vote.ProposalHash.ToBytes(), vote.BlsSig)
+	}
+	vote := func(voter common.Address, sig []byte) (bool, error) {
+		return g.AddVote(newGovernanceTx(voter, tx_types.ActionTxActionGovernanceVote,
+			&tx_types.GovernanceVote{ProposalHash: proposal.GetTxHash(), Approve: true, BlsSig: sig}), verify)
+	}
+
+	sig, err := bls.Sign(suite, sec, proposal.GetTxHash().ToBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vote(common.RandomAddress(), sig); err == nil {
+		t.Fatal("vote of other than a senator should be rejected")
+	}
+	forged, _ := bls.Sign(suite, suite.Scalar().Pick(suite.RandomStream()), proposal.GetTxHash().ToBytes())
+	if _, err := vote(senator, forged); err == nil {
+		t.Fatal("vote signed by another key should be rejected")
+	}
+	if passed, err := vote(senator, sig); err != nil || !passed {
+		t.Fatal("should pass", passed, err)
+	}
+}
+
+func TestGovernance_ClampThreshold(t *testing.T) {
+	g := NewGovernance(ConsensusParams{PartnerNumber: 4, Threshold: 3})
+	propose := func(param uint8, value uint64) {
+		tx := newGovernanceTx(common.RandomAddress(), tx_types.ActionTxActionGovernanceProposal,
+			&tx_types.GovernanceProposal{TermId: 1, Parameter: param, Value: value})
+		if err := g.AddProposal(tx, 1); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < g.Params().Threshold; i++ {
+			if _, err := g.AddVote(newGovernanceTx(common.RandomAddress(), tx_types.ActionTxActionGovernanceVote,
+				&tx_types.GovernanceVote{ProposalHash: tx.GetTxHash(), Approve: true}), acceptVote); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	propose(tx_types.GovernanceParamPartnerNumber, 2)
+	if params, _ := g.ApplyPending(2); params.PartnerNumber != 2 || params.Threshold != 2 {
+		t.Fatal("threshold should be lowered to the partner number", params)
+	}
+	propose(tx_types.GovernanceParamThreshold, 5)
+	if params, _ := g.ApplyPending(3); params.Threshold != 2 {
+		t.Fatal("threshold should not exceed the partner number", params)
+	}
+}
@@ -14,6 +14,7 @@
 package term
 
 import (
+	"bytes"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/types/tx_types"
@@ -41,6 +42,7 @@ type Term struct {
 	mu                sync.RWMutex
 	currentTermChange *tx_types.TermChange
 	genesisTermChange *tx_types.TermChange
+	termChanges       map[uint64]*tx_types.TermChange
 	started           bool
 }
 
@@ -55,6 +57,7 @@ func NewTerm(id uint64, participantNumber int, termChangeInterval int) *Term {
 		candidates:         make(map[common.Address]*tx_types.Campaign),
 		alsorans:           make(map[common.Address]*tx_types.Campaign),
 		campaigns:          make(map[common.Address]*tx_types.Campaign),
+		termChanges:        make(map[uint64]*tx_types.TermChange),
 	}
 }
 
@@ -72,6 +75,16 @@ func (t *Term) UpdateID(id uint64) {
 	t.id = id
 }
 
+// UpdateParams changes the partner number and term change interval
+// used to decide the next term change.
+func (t *Term) UpdateParams(participantNumber int, termChangeInterval int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.partsNum = participantNumber
+	t.termChangeInterval = termChangeInterval
+}
+
 func (t *Term) SwitchFlag(flag bool) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -90,6 +103,20 @@ func (t *Term) GetGenesisTermChange() *tx_types.TermChange {
 	return t.genesisTermChange
 }
 
+// GetTermChangeByPkBls returns the term change that introduced the
+// bls joint public key, together with the term id it started.
+func (t *Term) GetTermChangeByPkBls(pkBls []byte) (uint64, *tx_types.TermChange) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for id, tc := range t.termChanges {
+		if bytes.Equal(tc.PkBls, pkBls) {
+			return id, tc
+		}
+	}
+	return 0, nil
+}
+
 func (t *Term) Changing() bool {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
@@ -233,7 +260,7 @@ func (t *Term) ChangeTerm(tc *tx_types.TermChange, lastHeight uint64) error {
 
 	snts := make(map[common.Address]*Senator)
 	for addr, c := range t.candidates {
-		s := newSenator(addr, c.PublicKey, tc.PkBls)
+		s := newSenator(addr, c.PublicKey, tc.PkBls, c.DkgPublicKey)
 		snts[addr] = s
 	}
 
@@ -260,6 +287,7 @@ func (t *Term) ChangeTerm(tc *tx_types.TermChange, lastHeight uint64) error {
 	// 2. process alsorans.
 
 	t.id++
+	t.termChanges[t.id] = tc
 	t.startedHeight = lastHeight
 	log.WithField("startedHeight", t.startedHeight).WithField("len senators ", len(t.senators)).WithField("id ", t.id).Info("term changed , id updated")
 	t.flag = false
@@ -270,21 +298,29 @@ type Senator struct {
 	addr         common.Address
 	pk           []byte
 	blspk        []byte
+	dkgPk        []byte
 	Id           int
 	CampaignHash common.Hash
 	// TODO:
 	// more variables?
 }
 
-func newSenator(addr common.Address, publickey, blspk []byte) *Senator {
+func newSenator(addr common.Address, publickey, blspk, dkgPk []byte) *Senator {
 	s := &Senator{}
 	s.addr = addr
 	s.pk = publickey
 	s.blspk = blspk
+	s.dkgPk = dkgPk
 
 	return s
 }
 
+// DkgPublicKey returns the dkg public key published in the campaign of the
+// senator.
+func (s *Senator) DkgPublicKey() []byte {
+	return s.dkgPk
+}
+
 type Senators map[common.Address]*Senator
 
 func (t *Term) GetSenator(address common.Address) *Senator {
@@ -185,3 +185,39 @@ func (a *AnnSensus) VerifyRequestedTermChange(t *tx_types.TermChange) bool {
 	log.WithField("tc ", t).Trace("verify ok ")
 	return true
 }
+
+// VerifyGovernance verifies governance proposals and votes. Both should be
+// sent by a senator of current term, and votes should carry the bls
+// signature of the proposal hash by the senator's campaign dkg key.
+func (a *AnnSensus) VerifyGovernance(tx *tx_types.ActionTx) bool {
+	if a.disable {
+		log.WithField("tx ", tx).Warn("annsensus disabled ")
+		return true
+	}
+	if a.term.GetSenator(tx.Sender()) == nil {
+		log.WithField("address ", tx.Sender().ShortString()).Warn("governance tx is not from a senator")
+		return false
+	}
+	if proposal := tx.GetGovernanceProposal(); proposal != nil {
+		if err := proposal.ValidateValue(); err != nil {
+			log.WithError(err).WithField("tx ", tx).Warn("invalid governance proposal")
+			return false
+		}
+		if proposal.TermId != a.term.ID() {
+			log.WithField("term id ", proposal.TermId).WithField("current ", a.term.ID()).Warn("proposal term id mismatch")
+			return false
+		}
+		return true
+	}
+	vote := tx.GetGovernanceVote()
+	if vote == nil {
+		log.WithField("tx ", tx).Warn("invalid governance tx")
+		return false
+	}
+	if err := a.verifyVote(tx.Sender(), vote); err != nil {
+		log.WithError(err).WithField("tx ", tx).Warn("invalid governance vote")
+		return false
+	}
+	log.WithField("tx ", tx).Trace("verify ok ")
+	return true
+}
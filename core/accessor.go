@@ -23,6 +23,8 @@ import (
 	"github.com/annchain/OG/ogdb"
 	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/types/tx_types"
+	"github.com/annchain/OG/vm/eth/rlp"
+	vmtypes "github.com/annchain/OG/vm/types"
 	log "github.com/sirupsen/logrus"
 	"strconv"
 	"sync"
@@ -33,6 +35,16 @@ var (
 	prefixLatestSeqKey = []byte("latestseq")
 
 	prefixReceiptKey = []byte("rp")
+	prefixLogsKey    = []byte("lg")
+
+	prefixContractABIKey   = []byte("abi")
+	prefixContractInfoKey  = []byte("ci")
+	prefixDeploymentABIKey = []byte("dabi")
+	prefixABINonceKey      = []byte("abin")
+
+	prefixArchiveContentKey = []byte("arc")
+
+	prefixTermChangeKey = []byte("tcb")
 
 	prefixTransactionKey = []byte("tx")
 	prefixTxHashFlowKey  = []byte("fl")
@@ -70,6 +82,34 @@ func receiptKey(seqID uint64) []byte {
 	return append(prefixReceiptKey, encodeUint64(seqID)...)
 }
 
+func logsKey(seqID uint64) []byte {
+	return append(prefixLogsKey, encodeUint64(seqID)...)
+}
+
+func contractABIKey(addr common.Address) []byte {
+	return append(prefixContractABIKey, addr.ToBytes()...)
+}
+
+func contractInfoKey(addr common.Address) []byte {
+	return append(prefixContractInfoKey, addr.ToBytes()...)
+}
+
+func deploymentABIKey(txHash common.Hash) []byte {
+	return append(prefixDeploymentABIKey, txHash.ToBytes()...)
+}
+
+func abiNonceKey(addr common.Address) []byte {
+	return append(prefixABINonceKey, addr.ToBytes()...)
+}
+
+func archiveContentKey(contentHash common.Hash) []byte {
+	return append(prefixArchiveContentKey, contentHash.ToBytes()...)
+}
+
+func termChangeKey(pkBls []byte) []byte {
+	return append(prefixTermChangeKey, pkBls...)
+}
+
 func transactionKey(hash common.Hash) []byte {
 	return append(prefixTransactionKey, hash.ToBytes()...)
 }
@@ -414,6 +454,159 @@ func (da *Accessor) ReadReceipt(seqID uint64, hash common.Hash) *Receipt {
 	return receipt
 }
 
+// WriteLogs writes the logs emitted by the txs confirmed by sequencer seqID.
+func (da *Accessor) WriteLogs(putter *Putter, seqID uint64, logs []*vmtypes.Log) error {
+	storageLogs := make([]*vmtypes.LogForStorage, len(logs))
+	for i, l := range logs {
+		storageLogs[i] = (*vmtypes.LogForStorage)(l)
+	}
+	data, err := rlp.EncodeToBytes(storageLogs)
+	if err != nil {
+		return fmt.Errorf("encode seq%d's logs err: %v", seqID, err)
+	}
+	err = da.put(putter, logsKey(seqID), data)
+	if err != nil {
+		return fmt.Errorf("write seq%d's logs err: %v", seqID, err)
+	}
+	return nil
+}
+
+// ReadLogs returns the logs emitted by the txs confirmed by sequencer seqID.
+func (da *Accessor) ReadLogs(seqID uint64) []*vmtypes.Log {
+	data, _ := da.db.Get(logsKey(seqID))
+	if len(data) == 0 {
+		return nil
+	}
+	var storageLogs []*vmtypes.LogForStorage
+	err := rlp.DecodeBytes(data, &storageLogs)
+	if err != nil {
+		log.WithError(err).Errorf("decode seq%d's logs error", seqID)
+		return nil
+	}
+	logs := make([]*vmtypes.Log, len(storageLogs))
+	for i, l := range storageLogs {
+		logs[i] = (*vmtypes.Log)(l)
+	}
+	return logs
+}
+
+// WriteContractABI stores the json abi of the contract at addr.
+func (da *Accessor) WriteContractABI(putter *Putter, addr common.Address, abi []byte) error {
+	return da.put(putter, contractABIKey(addr), abi)
+}
+
+// ReadContractABI returns the json abi of the contract at addr, nil if
+// none was registered.
+func (da *Accessor) ReadContractABI(addr common.Address) []byte {
+	data, _ := da.db.Get(contractABIKey(addr))
+	return data
+}
+
+// WriteABINonce stores the nonce of the latest abi registration of the
+// contract at addr.
+func (da *Accessor) WriteABINonce(putter *Putter, addr common.Address, nonce uint64) error {
+	return da.put(putter, abiNonceKey(addr), encodeUint64(nonce))
+}
+
+// ReadABINonce returns the nonce of the latest abi registration of the
+// contract at addr, 0 if the abi was never registered by the creator.
+func (da *Accessor) ReadABINonce(addr common.Address) uint64 {
+	data, _ := da.db.Get(abiNonceKey(addr))
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteDeploymentABI stores the json abi given with the contract creating
+// tx until the tx is confirmed.
+func (da *Accessor) WriteDeploymentABI(txHash common.Hash, abi []byte) error {
+	return da.db.Put(deploymentABIKey(txHash), abi)
+}
+
+// ReadDeploymentABI returns the json abi given with the contract creating
+// tx, nil if there is none.
+func (da *Accessor) ReadDeploymentABI(txHash common.Hash) []byte {
+	data, _ := da.db.Get(deploymentABIKey(txHash))
+	return data
+}
+
+// DeleteDeploymentABI deletes the json abi given with the contract creating
+// tx once it is registered at the created address.
+func (da *Accessor) DeleteDeploymentABI(txHash common.Hash) error {
+	return da.db.Delete(deploymentABIKey(txHash))
+}
+
+// WriteContractInfo records the creation of the contract at addr.
+func (da *Accessor) WriteContractInfo(putter *Putter, addr common.Address, info *ContractInfo) error {
+	data, err := info.MarshalMsg(nil)
+	if err != nil {
+		return fmt.Errorf("marshal contract info of %s err: %v", addr.Hex(), err)
+	}
+	err = da.put(putter, contractInfoKey(addr), data)
+	if err != nil {
+		return fmt.Errorf("write contract info of %s err: %v", addr.Hex(), err)
+	}
+	return nil
+}
+
+// ReadContractInfo returns the creation record of the contract at addr,
+// nil if the contract was not created by a tx.
+func (da *Accessor) ReadContractInfo(addr common.Address) *ContractInfo {
+	data, _ := da.db.Get(contractInfoKey(addr))
+	if len(data) == 0 {
+		return nil
+	}
+	var info ContractInfo
+	_, err := info.UnmarshalMsg(data)
+	if err != nil {
+		return nil
+	}
+	return &info
+}
+
+// WriteArchiveHashes stores the hashes of the archives holding the content
+// whose sha256 is contentHash.
+func (da *Accessor) WriteArchiveHashes(putter *Putter, contentHash common.Hash, hashs *common.Hashes) error {
+	data, err := hashs.MarshalMsg(nil)
+	if err != nil {
+		return err
+	}
+	return da.put(putter, archiveContentKey(contentHash), data)
+}
+
+// WriteTermChangeHash stores the hash of the term change introducing the
+// bls joint public key pkBls.
+func (da *Accessor) WriteTermChangeHash(putter *Putter, pkBls []byte, hash common.Hash) error {
+	return da.put(putter, termChangeKey(pkBls), hash.ToBytes())
+}
+
+// ReadTermChangeHash returns the hash of the term change introducing the
+// bls joint public key pkBls, nil if there is none.
+func (da *Accessor) ReadTermChangeHash(pkBls []byte) *common.Hash {
+	data, _ := da.db.Get(termChangeKey(pkBls))
+	if len(data) == 0 {
+		return nil
+	}
+	hash := common.BytesToHash(data)
+	return &hash
+}
+
+// ReadArchiveHashes returns the hashes of the archives holding the content
+// whose sha256 is contentHash, nil if there is none.
+func (da *Accessor) ReadArchiveHashes(contentHash common.Hash) common.Hashes {
+	data, _ := da.db.Get(archiveContentKey(contentHash))
+	if len(data) == 0 {
+		return nil
+	}
+	var hashs common.Hashes
+	_, err := hashs.UnmarshalMsg(data)
+	if err != nil {
+		return nil
+	}
+	return hashs
+}
+
 // WriteTransaction write the tx or sequencer into ogdb.
 func (da *Accessor) WriteTransaction(putter *Putter, tx types.Txi) error {
 	var prefix, data []byte
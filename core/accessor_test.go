@@ -182,6 +182,80 @@ func TestLatestSeqStorage(t *testing.T) {
 	}
 }
 
+func TestArchiveHashesStorage(t *testing.T) {
+	t.Parallel()
+
+	db, remove := newTestLDB("TestArchiveHashesStorage")
+	defer remove()
+
+	acc := core.NewAccessor(db)
+
+	contentHash := tx_types.ArchiveContentHash([]byte("content"))
+	if hashs := acc.ReadArchiveHashes(contentHash); hashs != nil {
+		t.Fatalf("should have no archive before written, got %v", hashs)
+	}
+	hashs := common.Hashes{common.RandomHash(), common.RandomHash()}
+	err := acc.WriteArchiveHashes(nil, contentHash, &hashs)
+	if err != nil {
+		t.Fatalf("write archive hashes error: %v", err)
+	}
+	hashsRead := acc.ReadArchiveHashes(contentHash)
+	if len(hashsRead) != 2 || hashsRead[0] != hashs[0] || hashsRead[1] != hashs[1] {
+		t.Fatalf("archive hashes read %v are not the same as written %v", hashsRead, hashs)
+	}
+}
+
+func TestDeploymentABIStorage(t *testing.T) {
+	t.Parallel()
+
+	db, remove := newTestLDB("TestDeploymentABIStorage")
+	defer remove()
+
+	acc := core.NewAccessor(db)
+
+	txHash := common.RandomHash()
+	if data := acc.ReadDeploymentABI(txHash); data != nil {
+		t.Fatalf("should have no abi before written, got %s", data)
+	}
+	contractABI := []byte(`[{"type":"function","name":"get","inputs":[],"outputs":[]}]`)
+	if err := acc.WriteDeploymentABI(txHash, contractABI); err != nil {
+		t.Fatalf("write deployment abi error: %v", err)
+	}
+	// the abi is kept by the db rather than the accessor.
+	if data := core.NewAccessor(db).ReadDeploymentABI(txHash); string(data) != string(contractABI) {
+		t.Fatalf("deployment abi read %s is not the same as written %s", data, contractABI)
+	}
+	if err := acc.DeleteDeploymentABI(txHash); err != nil {
+		t.Fatalf("delete deployment abi error: %v", err)
+	}
+	if data := acc.ReadDeploymentABI(txHash); data != nil {
+		t.Fatalf("deployment abi not deleted, got %s", data)
+	}
+}
+
+func TestTermChangeHashStorage(t *testing.T) {
+	t.Parallel()
+
+	db, remove := newTestLDB("TestTermChangeHashStorage")
+	defer remove()
+
+	acc := core.NewAccessor(db)
+
+	pkBls := common.RandomHash().ToBytes()
+	if hash := acc.ReadTermChangeHash(pkBls); hash != nil {
+		t.Fatalf("should have no term change before written, got %v", hash)
+	}
+	hash := common.RandomHash()
+	err := acc.WriteTermChangeHash(nil, pkBls, hash)
+	if err != nil {
+		t.Fatalf("write term change hash error: %v", err)
+	}
+	hashRead := acc.ReadTermChangeHash(pkBls)
+	if hashRead == nil || *hashRead != hash {
+		t.Fatalf("term change hash read %v is not the same as written %s", hashRead, hash)
+	}
+}
+
 func TestBalanceStorage(t *testing.T) {
 	t.Parallel()
 
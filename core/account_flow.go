@@ -44,7 +44,7 @@ func (a *AccountFlows) Add(tx types.Txi) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if tx.GetType() == types.TxBaseTypeArchive {
+	if tx_types.AnonymousArchive(tx) {
 		return
 	}
 
@@ -52,6 +52,7 @@ func (a *AccountFlows) Add(tx types.Txi) {
 	if af == nil {
 		af = NewAccountFlow(state.NewBalanceSet())
 	}
+	transfer := transferFromOf(tx)
 	if tx.GetType() == types.TxBaseTypeNormal {
 		txn := tx.(*tx_types.Tx)
 		if af.balances[txn.TokenId] == nil {
@@ -59,10 +60,54 @@ func (a *AccountFlows) Add(tx types.Txi) {
 			af.balances[txn.TokenId] = NewBalanceState(blc)
 		}
 	}
-	af.Add(tx)
+	if transfer != nil {
+		key := allowanceKey{transfer.Owner, transfer.TokenId}
+		if af.allowances[key] == nil {
+			allowance := a.pool.dag.GetAllowance(transfer.Owner, tx.Sender(), transfer.TokenId)
+			af.allowances[key] = NewBalanceState(allowance)
+		}
+	}
+	if err := af.Add(tx); err == nil && transfer != nil {
+		a.addOwnerSpent(transfer)
+	}
 	a.afs[tx.Sender()] = af
 }
 
+// addOwnerSpent books the value of a transfer-from tx on the balance state
+// of the owner, whose tokens the tx spends.
+func (a *AccountFlows) addOwnerSpent(transfer *tx_types.TransferFrom) {
+	of := a.afs[transfer.Owner]
+	if of == nil {
+		of = NewAccountFlow(state.NewBalanceSet())
+		a.afs[transfer.Owner] = of
+	}
+	if of.balances[transfer.TokenId] == nil {
+		blc := a.pool.dag.GetBalance(transfer.Owner, transfer.TokenId)
+		of.balances[transfer.TokenId] = NewBalanceState(blc)
+	}
+	if err := of.balances[transfer.TokenId].TrySubBalance(transfer.Value); err != nil {
+		log.WithField("owner", transfer.Owner).WithError(err).Warn("book transfer-from on owner's balance failed")
+		return
+	}
+	of.transfersFrom++
+}
+
+// removeOwnerSpent rolls back the value of a transfer-from tx booked on the
+// balance state of the owner.
+func (a *AccountFlows) removeOwnerSpent(transfer *tx_types.TransferFrom) {
+	of := a.afs[transfer.Owner]
+	if of == nil || of.transfersFrom == 0 || of.balances[transfer.TokenId] == nil {
+		return
+	}
+	if err := of.balances[transfer.TokenId].TryRemoveValue(transfer.Value); err != nil {
+		log.WithField("owner", transfer.Owner).WithError(err).Warn("remove transfer-from from owner's balance failed")
+	}
+	of.transfersFrom--
+	if of.empty() {
+		delete(a.afs, transfer.Owner)
+	}
+}
+
 func (a *AccountFlows) Get(addr common.Address) *AccountFlow {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -85,6 +130,19 @@ func (a *AccountFlows) GetBalanceState(addr common.Address, tokenID int32) *Bala
 	return bls[tokenID]
 }
 
+// GetAllowanceState returns the allowance owner approved to spender and how
+// much of it is spent by the transfer-from txs of spender in pool.
+func (a *AccountFlows) GetAllowanceState(spender, owner common.Address, tokenID int32) *BalanceState {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	af := a.afs[spender]
+	if af == nil {
+		return nil
+	}
+	return af.allowances[allowanceKey{owner, tokenID}]
+}
+
 func (a *AccountFlows) GetTxByNonce(addr common.Address, nonce uint64) types.Txi {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -126,18 +184,42 @@ func (a *AccountFlows) Remove(tx types.Txi) {
 		log.WithField("tx", tx).Warnf("remove tx from accountflows failed")
 		return
 	}
+	if flow.GetTx(tx.GetNonce()) != nil {
+		if transfer := transferFromOf(tx); transfer != nil {
+			a.removeOwnerSpent(transfer)
+		}
+	}
 	flow.Remove(tx.GetNonce())
 	// remove account flow if there is no txs sent by this address in pool
-	if flow.Len() == 0 {
+	if flow.empty() {
 		delete(a.afs, tx.Sender())
 	}
 }
 
 // AccountFlow stores the information about an address. It includes the
-// balance state of the account among the txpool,
+// balance state of the account among the txpool, which is also spent by the
+// transfer-from txs of other accounts counted in transfersFrom.
 type AccountFlow struct {
-	balances map[int32]*BalanceState
-	txlist   *TxList
+	balances      map[int32]*BalanceState
+	allowances    map[allowanceKey]*BalanceState
+	txlist        *TxList
+	transfersFrom int
+}
+
+// allowanceKey identifies the allowance an owner approved to the account
+// on one token.
+type allowanceKey struct {
+	owner   common.Address
+	tokenID int32
+}
+
+// transferFromOf returns the transfer of tx if it is a transfer-from tx.
+func transferFromOf(tx types.Txi) *tx_types.TransferFrom {
+	actionTx, ok := tx.(*tx_types.ActionTx)
+	if !ok {
+		return nil
+	}
+	return actionTx.GetTransferFrom()
 }
 
 func NewAccountFlow(originBalance state.BalanceSet) *AccountFlow {
@@ -147,8 +229,9 @@ func NewAccountFlow(originBalance state.BalanceSet) *AccountFlow {
 	}
 
 	return &AccountFlow{
-		balances: bls,
-		txlist:   NewTxList(),
+		balances:   bls,
+		allowances: map[allowanceKey]*BalanceState{},
+		txlist:     NewTxList(),
 	}
 }
 func (af *AccountFlow) BalanceState(tokenID int32) *BalanceState {
@@ -163,6 +246,12 @@ func (af *AccountFlow) Len() int {
 	return af.txlist.Len()
 }
 
+// empty tells if the flow has neither txs of the account nor transfer-from
+// txs spending its balance.
+func (af *AccountFlow) empty() bool {
+	return af.Len() == 0 && af.transfersFrom == 0
+}
+
 // GetTx get a tx from accountflow.
 func (af *AccountFlow) GetTx(nonce uint64) types.Txi {
 	return af.txlist.Get(nonce)
@@ -176,6 +265,18 @@ func (af *AccountFlow) Add(tx types.Txi) error {
 		log.WithField("tx", tx).Errorf("add tx that has same nonce")
 		return fmt.Errorf("already exists")
 	}
+	if transfer := transferFromOf(tx); transfer != nil {
+		allowance := af.allowances[allowanceKey{transfer.Owner, transfer.TokenId}]
+		if allowance == nil {
+			af.txlist.Put(tx)
+			return fmt.Errorf("allowance state not exists for addr: %s", tx.Sender().Hex())
+		}
+		if err := allowance.TrySubBalance(transfer.Value); err != nil {
+			return err
+		}
+		af.txlist.Put(tx)
+		return nil
+	}
 	if tx.GetType() != types.TxBaseTypeNormal {
 		af.txlist.Put(tx)
 		return nil
@@ -201,6 +302,18 @@ func (af *AccountFlow) Remove(nonce uint64) error {
 	if tx == nil {
 		return nil
 	}
+	if transfer := transferFromOf(tx); transfer != nil {
+		allowance := af.allowances[allowanceKey{transfer.Owner, transfer.TokenId}]
+		if allowance == nil {
+			af.txlist.Remove(nonce)
+			return fmt.Errorf("allowance state not exists for addr: %s", tx.Sender().Hex())
+		}
+		if err := allowance.TryRemoveValue(transfer.Value); err != nil {
+			return err
+		}
+		af.txlist.Remove(nonce)
+		return nil
+	}
 	if tx.GetType() != types.TxBaseTypeNormal {
 		af.txlist.Remove(nonce)
 		return nil
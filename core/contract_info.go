@@ -0,0 +1,30 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package core
+
+import (
+	"github.com/annchain/OG/common"
+)
+
+//go:generate msgp
+
+//msgp:tuple ContractInfo
+
+// ContractInfo records how a contract was created by a tx.
+type ContractInfo struct {
+	Creator common.Address
+	TxHash  common.Hash
+	// SeqHeight is the height of the sequencer confirming the tx.
+	SeqHeight uint64
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/goroutine"
 	"github.com/annchain/OG/status"
+	"github.com/annchain/OG/types/token"
 	"github.com/annchain/OG/types/tx_types"
 	"github.com/syndtr/goleveldb/leveldb"
 	"sort"
@@ -32,9 +33,12 @@ import (
 	"github.com/annchain/OG/core/state"
 	"github.com/annchain/OG/ogdb"
 	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/vm/abi"
 	evm "github.com/annchain/OG/vm/eth/core/vm"
+	"github.com/annchain/OG/vm/eth/params"
 	"github.com/annchain/OG/vm/ovm"
 	vmtypes "github.com/annchain/OG/vm/types"
+	"github.com/annchain/OG/vm/wasm"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -51,6 +55,11 @@ var (
 
 type DagConfig struct {
 	GenesisPath string
+	// ChainConfig decides the OVM instruction set by sequencer height.
+	ChainConfig *params.ChainConfig
+	// ParallelWorkers is the number of txs of a batch executed
+	// concurrently, they are executed one by one if not more than 1.
+	ParallelWorkers int
 }
 
 type Dag struct {
@@ -248,7 +257,12 @@ func (dag *Dag) Push(batch *ConfirmBatch) error {
 	dag.mu.Lock()
 	defer dag.mu.Unlock()
 
-	return dag.push(batch)
+	defer dagPushTimer.UpdateSince(time.Now())
+	err := dag.push(batch)
+	if err == nil {
+		dagHeightGauge.Update(int64(batch.Seq.Height))
+	}
+	return err
 }
 
 // PrePush simulates the action of pushing sequencer into Dag ledger. Simulates will
@@ -507,6 +521,82 @@ func (dag *Dag) GetReceipt(hash common.Hash) *Receipt {
 	return dag.accessor.ReadReceipt(seqid, hash)
 }
 
+// GetTxLogs returns the logs emitted by the contracts during the execution
+// of the tx.
+func (dag *Dag) GetTxLogs(hash common.Hash) []*vmtypes.Log {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	tx := dag.getTx(hash)
+	if tx == nil {
+		return nil
+	}
+	var logs []*vmtypes.Log
+	for _, l := range dag.accessor.ReadLogs(tx.GetHeight()) {
+		if l.TxHash == hash {
+			logs = append(logs, l)
+		}
+	}
+	return logs
+}
+
+// WriteContractABI registers the json abi of the contract at addr, which is
+// used to encode calls and decode results and logs of the contract. Only
+// the creator of the contract may register or overwrite its abi, with a
+// nonce greater than the one of the last registration so that a signed
+// registration can't be replayed.
+func (dag *Dag) WriteContractABI(addr common.Address, sender common.Address, nonce uint64, data []byte) error {
+	if _, err := abi.JSON(data); err != nil {
+		return err
+	}
+	dag.mu.Lock()
+	defer dag.mu.Unlock()
+
+	info := dag.accessor.ReadContractInfo(addr)
+	if info == nil {
+		return fmt.Errorf("contract %s not found", addr.Hex())
+	}
+	if info.Creator != sender {
+		return fmt.Errorf("only the creator %s may register the abi of %s", info.Creator.Hex(), addr.Hex())
+	}
+	if latest := dag.accessor.ReadABINonce(addr); nonce <= latest {
+		return fmt.Errorf("abi nonce %d of %s should be greater than %d", nonce, addr.Hex(), latest)
+	}
+	dbBatch := dag.accessor.NewBatch()
+	if err := dag.accessor.WriteContractABI(dbBatch, addr, data); err != nil {
+		return err
+	}
+	if err := dag.accessor.WriteABINonce(dbBatch, addr, nonce); err != nil {
+		return err
+	}
+	return dbBatch.Write()
+}
+
+// AddDeploymentABI stores the json abi given with the contract creating tx
+// in the db, it is registered at the created address when the tx is
+// confirmed, even if the node restarts in between.
+func (dag *Dag) AddDeploymentABI(txHash common.Hash, data []byte) error {
+	if _, err := abi.JSON(data); err != nil {
+		return err
+	}
+	return dag.accessor.WriteDeploymentABI(txHash, data)
+}
+
+// GetContractABI returns the registered abi of the contract at addr, nil
+// if there is none.
+func (dag *Dag) GetContractABI(addr common.Address) *abi.ABI {
+	data := dag.accessor.ReadContractABI(addr)
+	if len(data) == 0 {
+		return nil
+	}
+	contractABI, err := abi.JSON(data)
+	if err != nil {
+		log.WithError(err).WithField("address", addr).Warn("invalid contract abi in db")
+		return nil
+	}
+	return contractABI
+}
+
 func (dag *Dag) GetSequencerByHash(hash common.Hash) *tx_types.Sequencer {
 	dag.mu.RLock()
 	defer dag.mu.RUnlock()
@@ -649,6 +739,14 @@ func (dag *Dag) getToken(tokenId int32) *state.TokenObject {
 	return dag.statedb.GetTokenObject(tokenId)
 }
 
+// GetTokenBySymbol returns the token using the symbol, nil if not found.
+func (dag *Dag) GetTokenBySymbol(symbol string) *state.TokenObject {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.statedb.GetTokenObjectBySymbol(symbol)
+}
+
 func (dag *Dag) GetLatestTokenId() int32 {
 	dag.mu.RLock()
 	defer dag.mu.RUnlock()
@@ -703,6 +801,120 @@ func (dag *Dag) getState(addr common.Address, key common.Hash) common.Hash {
 	return dag.statedb.GetState(addr, key)
 }
 
+// GetStateAt returns the contract's state after the txs confirmed by the
+// sequencer at height.
+func (dag *Dag) GetStateAt(addr common.Address, key common.Hash, height uint64) (common.Hash, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	if height == dag.latestSequencer.Height {
+		return dag.getState(addr, key), nil
+	}
+	seq := dag.getSequencerByHeight(height)
+	if seq == nil {
+		return common.Hash{}, fmt.Errorf("sequencer not found at height %d", height)
+	}
+	db, err := state.NewStateDB(state.DefaultStateDBConfig(), dag.statedb.Database(), seq.StateRoot)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("open state of height %d error: %v", height, err)
+	}
+	return db.GetState(addr, key), nil
+}
+
+// GetCode returns the code of the contract at addr.
+func (dag *Dag) GetCode(addr common.Address) []byte {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.statedb.GetCode(addr)
+}
+
+// GetCodeHash returns the hash of the code of the contract at addr.
+func (dag *Dag) GetCodeHash(addr common.Address) common.Hash {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.statedb.GetCodeHash(addr)
+}
+
+// GetContractInfo returns the creator and the creating tx of the contract
+// at addr, nil if it was not created by a tx.
+func (dag *Dag) GetContractInfo(addr common.Address) *ContractInfo {
+	return dag.accessor.ReadContractInfo(addr)
+}
+
+// GetArchiveHashes returns the hashes of the confirmed archives holding the
+// content whose sha256 is contentHash.
+func (dag *Dag) GetArchiveHashes(contentHash common.Hash) common.Hashes {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.accessor.ReadArchiveHashes(contentHash)
+}
+
+// GetTermChangeByPkBls returns the confirmed term change introducing the
+// bls joint public key pkBls, nil if not found.
+func (dag *Dag) GetTermChangeByPkBls(pkBls []byte) *tx_types.TermChange {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	hash := dag.accessor.ReadTermChangeHash(pkBls)
+	if hash == nil {
+		return nil
+	}
+	tc, _ := dag.getTx(*hash).(*tx_types.TermChange)
+	return tc
+}
+
+// GetDomain returns the record of the domain name, nil if it was never
+// registered. The record may be expired.
+func (dag *Dag) GetDomain(name string) *state.DomainObject {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.statedb.GetDomain(name)
+}
+
+// ResolveDomain returns the owner of the domain name at the latest
+// sequencer.
+func (dag *Dag) ResolveDomain(name string) (common.Address, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	domain := dag.statedb.GetDomain(name)
+	if domain == nil || domain.Expired(dag.latestSequencer.Height) {
+		return common.Address{}, fmt.Errorf("domain name %s is not registered", name)
+	}
+	return domain.Owner, nil
+}
+
+// GetTokenHolderFlags returns the state.TokenHolder flags of the holder
+// of the token.
+func (dag *Dag) GetTokenHolderFlags(tokenID int32, holder common.Address) uint8 {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.statedb.GetTokenHolderFlags(tokenID, holder)
+}
+
+// CheckTokenTransfer tells if the controls of the token allow moving it
+// from one holder to another.
+func (dag *Dag) CheckTokenTransfer(tokenID int32, from, to common.Address) error {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.statedb.CheckTokenTransfer(tokenID, from, to)
+}
+
+// GetAllowance returns how much of owner's token spender is allowed to
+// move by transfer-from txs.
+func (dag *Dag) GetAllowance(owner, spender common.Address, tokenID int32) *math.BigInt {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	return dag.statedb.GetAllowance(owner, spender, tokenID)
+}
+
 //GetTxsByAddress get all txs from this address
 func (dag *Dag) GetTxsByAddress(addr common.Address) []types.Txi {
 	dag.mu.RLock()
@@ -742,7 +954,7 @@ func (dag *Dag) prePush(batch *ConfirmBatch) (common.Hash, error) {
 
 	dag.preloadDB.Reset()
 	for _, txi := range batch.Txs {
-		_, _, err := dag.ProcessTransaction(txi, true)
+		_, _, err := dag.processTransaction(txi, true, batch.Seq)
 		if err != nil {
 			return common.Hash{}, fmt.Errorf("process tx error: %v", err)
 		}
@@ -768,12 +980,25 @@ func (dag *Dag) push(batch *ConfirmBatch) error {
 	sort.Sort(batch.Txs)
 	txhashes := common.Hashes{}
 	consTxs := []types.Txi{}
-	sId := dag.statedb.Snapshot()
-
+	var logs []*vmtypes.Log
+	// the txs whose deployment abis are registered by the batch.
+	var deployedABIs common.Hashes
 	for _, txi := range batch.Txs {
 		txi.GetBase().Height = batch.Seq.Height
+	}
+	specs := dag.speculate(batch)
+	written := newAccessSet()
+	sId := dag.statedb.Snapshot()
 
-		_, receipt, err := dag.ProcessTransaction(txi, false)
+	for i, txi := range batch.Txs {
+		dag.statedb.Prepare(txi.GetTxHash(), i)
+		var receipt *Receipt
+		var err error
+		if dag.conf.ParallelWorkers > 1 {
+			receipt, err = dag.processBatchTx(txi, batch.Seq, specs[i], written)
+		} else {
+			_, receipt, err = dag.processTransaction(txi, false, batch.Seq)
+		}
 		if err != nil {
 			dag.Revert(sId, nil)
 			log.WithField("sid ", sId).WithField("hash ", txi.GetTxHash()).WithError(err).Warn(
@@ -781,6 +1006,29 @@ func (dag *Dag) push(batch *ConfirmBatch) error {
 			return err
 		}
 		receipts[txi.GetTxHash().Hex()] = receipt
+		logs = append(logs, dag.statedb.GetLogs(txi.GetTxHash())...)
+		// record the creator of the contract created by the tx and the abi
+		// given with it.
+		if receipt != nil && receipt.Status == ReceiptStatusSuccess && receipt.ContractAddress != emptyAddress {
+			info := &ContractInfo{
+				Creator:   txi.Sender(),
+				TxHash:    txi.GetTxHash(),
+				SeqHeight: batch.Seq.Height,
+			}
+			err = dag.accessor.WriteContractInfo(dbBatch, receipt.ContractAddress, info)
+			if err != nil {
+				dag.Revert(sId, nil)
+				return err
+			}
+			if data := dag.accessor.ReadDeploymentABI(txi.GetTxHash()); data != nil {
+				err = dag.accessor.WriteContractABI(dbBatch, receipt.ContractAddress, data)
+				if err != nil {
+					dag.Revert(sId, nil)
+					return err
+				}
+				deployedABIs = append(deployedABIs, txi.GetTxHash())
+			}
+		}
 
 		txhashes = append(txhashes, txi.GetTxHash())
 		// TODO
@@ -790,7 +1038,49 @@ func (dag *Dag) push(batch *ConfirmBatch) error {
 		if txType == types.TxBaseTypeCampaign || txType == types.TxBaseTypeTermChange {
 			consTxs = append(consTxs, txi)
 		}
+		if actionTx, ok := txi.(*tx_types.ActionTx); ok && actionTx.IsGovernance() {
+			consTxs = append(consTxs, txi)
+		}
+	}
+	// index the archives by their content hash.
+	archives := make(map[common.Hash]common.Hashes)
+	for _, txi := range batch.Txs {
+		archive, ok := txi.(*tx_types.Archive)
+		if !ok {
+			continue
+		}
+		contentHash, err := archive.ContentHash()
+		if err != nil {
+			log.WithError(err).WithField("tx", archive).Warn("invalid archive content, not indexed")
+			continue
+		}
+		if _, ok := archives[contentHash]; !ok {
+			archives[contentHash] = dag.accessor.ReadArchiveHashes(contentHash)
+		}
+		archives[contentHash] = append(archives[contentHash], archive.GetTxHash())
+	}
+	for contentHash, hashs := range archives {
+		hashs := hashs
+		err = dag.accessor.WriteArchiveHashes(dbBatch, contentHash, &hashs)
+		if err != nil {
+			dag.Revert(sId, nil)
+			return err
+		}
+	}
+	// index the term changes by their bls joint public key, so that the
+	// term of a sequencer is still found after a restart.
+	for _, txi := range consTxs {
+		tc, ok := txi.(*tx_types.TermChange)
+		if !ok {
+			continue
+		}
+		err = dag.accessor.WriteTermChangeHash(dbBatch, tc.PkBls, tc.GetTxHash())
+		if err != nil {
+			dag.Revert(sId, nil)
+			return err
+		}
 	}
+
 	var writedTxs types.Txis
 	for _, txi := range batch.Txs {
 		err = dag.WriteTransaction(dbBatch, txi)
@@ -821,6 +1111,16 @@ func (dag *Dag) push(batch *ConfirmBatch) error {
 	if err != nil {
 		return err
 	}
+	if len(logs) > 0 {
+		for _, l := range logs {
+			l.SequenceID = batch.Seq.Height
+			l.BlockHash = batch.Seq.GetTxHash()
+		}
+		err = dag.accessor.WriteLogs(dbBatch, batch.Seq.Height, logs)
+		if err != nil {
+			return err
+		}
+	}
 
 	// commit statedb's changes to trie and triedb
 	root, errdb := dag.statedb.Commit()
@@ -871,6 +1171,9 @@ func (dag *Dag) push(batch *ConfirmBatch) error {
 		return err
 	}
 	dag.latestSequencer = batch.Seq
+	for _, hash := range deployedABIs {
+		dag.accessor.DeleteDeploymentABI(hash)
+	}
 
 	log.Tracef("successfully store seq: %s", batch.Seq.GetTxHash())
 
@@ -919,7 +1222,7 @@ func (dag *Dag) ReadConfirmTime(seqHeight uint64) *types.ConfirmTime {
 func (dag *Dag) WriteTransaction(putter *Putter, tx types.Txi) error {
 	// Write tx hash. This is aimed to allow users to query tx hash
 	// by sender address and tx nonce.
-	if tx.GetType() != types.TxBaseTypeArchive {
+	if !tx_types.AnonymousArchive(tx) {
 		err := dag.accessor.WriteTxHashByNonce(putter, tx.Sender(), tx.GetNonce(), tx.GetTxHash())
 		if err != nil {
 			return fmt.Errorf("write latest nonce err: %v", err)
@@ -943,26 +1246,56 @@ func (dag *Dag) DeleteTransaction(hash common.Hash) error {
 // ProcessTransaction execute the tx and update the data in statedb.
 //
 // Besides balance and nonce, if a tx is trying to create or call a
-// contract, vm part will be initiated to handle this.
+// contract, vm part will be initiated to handle this. The tx is executed
+// in the context of the latest sequencer.
 func (dag *Dag) ProcessTransaction(tx types.Txi, preload bool) ([]byte, *Receipt, error) {
-	// update nonce
-	if tx.GetType() == types.TxBaseTypeArchive {
-		//receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusArchiveSuccess, "", emptyAddress)
-		return nil, nil, nil
-	}
+	return dag.processTransaction(tx, preload, dag.latestSequencer)
+}
 
+// processTransaction executes the tx in the context of seq, which is the
+// sequencer confirming it. Block related opcodes read from seq so that
+// every node gets the same result.
+func (dag *Dag) processTransaction(tx types.Txi, preload bool, seq *tx_types.Sequencer) ([]byte, *Receipt, error) {
 	var db state.StateDBInterface
 	if preload {
 		db = dag.preloadDB
 	} else {
 		db = dag.statedb
 	}
+	return dag.executeTransaction(db, dag.statedb, tx, seq, nil, nil)
+}
+
+// executeTransaction executes tx on db, the token actions are applied on
+// tokenDB. tracer and callTracer are enabled in the OVM when not nil.
+// The txs of a batch may conflict on a name, a symbol, a balance, an
+// allowance or the controls of a token, which the pool can't tell, so a
+// refused action or transfer is recorded in the receipt only and doesn't
+// fail the batch.
+func (dag *Dag) executeTransaction(db state.StateDBInterface, tokenDB *state.StateDB, tx types.Txi, seq *tx_types.Sequencer,
+	tracer evm.Tracer, callTracer ovm.CallTracer) ([]byte, *Receipt, error) {
+	var height uint64
+	if seq != nil {
+		height = seq.Height
+	}
+	// the nodes before the signed archive fork can't decode such archives.
+	if archive, ok := tx.(*tx_types.Archive); ok && archive.Extended() && !dag.chainConfig().IsSignedArchive(height) {
+		return nil, nil, fmt.Errorf("signed archives are not enabled at height %d", height)
+	}
+	// update nonce
+	if tx_types.AnonymousArchive(tx) {
+		//receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusArchiveSuccess, "", emptyAddress)
+		return nil, nil, nil
+	}
 
 	curNonce := db.GetNonce(tx.Sender())
 	if !db.Exist(tx.Sender()) || tx.GetNonce() > curNonce {
 		db.SetNonce(tx.Sender(), tx.GetNonce())
 	}
 
+	if tx.GetType() == types.TxBaseTypeArchive {
+		return nil, nil, nil
+	}
+
 	if tx.GetType() == types.TxBaseTypeSequencer {
 		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusSuccess, "", emptyAddress)
 		return nil, receipt, nil
@@ -977,7 +1310,22 @@ func (dag *Dag) ProcessTransaction(tx types.Txi, preload bool) ([]byte, *Receipt
 	}
 	if tx.GetType() == types.TxBaseAction {
 		actionTx := tx.(*tx_types.ActionTx)
-		receipt, err := dag.processTokenTransaction(actionTx)
+		// governance proposals and votes are tallied by the consensus
+		// module once they are confirmed.
+		if actionTx.IsGovernance() {
+			receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusSuccess, "", emptyAddress)
+			return nil, receipt, nil
+		}
+		if actionTx.IsDomain() {
+			return nil, dag.processDomainTransaction(tokenDB, actionTx, seq), nil
+		}
+		if actionTx.IsAllowance() {
+			return nil, dag.processAllowanceTransaction(db, tokenDB, actionTx, seq), nil
+		}
+		if actionTx.IsTokenControl() {
+			return nil, dag.processTokenControlTransaction(tokenDB, actionTx), nil
+		}
+		receipt, err := dag.processTokenTransaction(tokenDB, actionTx, seq)
 		if err != nil {
 			return nil, receipt, fmt.Errorf("process action tx error: %v", err)
 		}
@@ -989,13 +1337,29 @@ func (dag *Dag) ProcessTransaction(tx types.Txi, preload bool) ([]byte, *Receipt
 		return nil, receipt, nil
 	}
 
-	// transfer balance
+	// transfer balance and return when its not contract related tx.
+	// value of contract related txs is moved by the ovm in tx token since
+	// the token contract fork.
 	txnormal := tx.(*tx_types.Tx)
-	if txnormal.Value.Value.Sign() != 0 && !txnormal.To.EqualTo(emptyAddress) {
-		db.SubTokenBalance(txnormal.Sender(), txnormal.TokenId, txnormal.Value)
-		db.AddTokenBalance(txnormal.To, txnormal.TokenId, txnormal.Value)
+	if txnormal.TokenId != token.OGTokenID && txnormal.Value.Value.Sign() != 0 {
+		if err := tokenDB.CheckTokenTransfer(txnormal.TokenId, txnormal.Sender(), txnormal.To); err != nil {
+			return nil, NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress), nil
+		}
+	}
+	txContext := &ovm.TxContext{
+		From:     txnormal.Sender(),
+		Value:    txnormal.Value,
+		Data:     txnormal.Data,
+		GasPrice: math.NewBigInt(0),
+		GasLimit: DefaultGasLimit,
+	}
+	setSequencerContext(txContext, seq)
+	if len(txnormal.Data) == 0 || !dag.chainConfig().IsTokenContract(txContext.SequenceID) {
+		if txnormal.Value.Value.Sign() != 0 && !txnormal.To.EqualTo(emptyAddress) {
+			db.SubTokenBalance(txnormal.Sender(), txnormal.TokenId, txnormal.Value)
+			db.AddTokenBalance(txnormal.To, txnormal.TokenId, txnormal.Value)
+		}
 	}
-	// return when its not contract related tx.
 	if len(txnormal.Data) == 0 {
 		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusSuccess, "", emptyAddress)
 		return nil, receipt, nil
@@ -1004,44 +1368,35 @@ func (dag *Dag) ProcessTransaction(tx types.Txi, preload bool) ([]byte, *Receipt
 	// create ovm object.
 	//
 	// TODO gaslimit not implemented yet.
-	var vmContext *vmtypes.Context
-	if preload {
-		vmContext = ovm.NewOVMContext(&ovm.DefaultChainContext{}, &DefaultCoinbase, dag.preloadDB)
-	} else {
-		vmContext = ovm.NewOVMContext(&ovm.DefaultChainContext{}, &DefaultCoinbase, dag.statedb)
-	}
-
-	txContext := &ovm.TxContext{
-		From:       txnormal.Sender(),
-		Value:      txnormal.Value,
-		Data:       txnormal.Data,
-		GasPrice:   math.NewBigInt(0),
-		GasLimit:   DefaultGasLimit,
-		Coinbase:   DefaultCoinbase,
-		SequenceID: dag.latestSequencer.Height,
-	}
-	// TODO more interpreters should be initialized, here only evm.
-	evmInterpreter := evm.NewEVMInterpreter(vmContext, txContext,
-		&evm.InterpreterConfig{
-			Debug: false,
-		})
-	ovmconf := &ovm.OVMConfig{
-		NoRecursion: false,
-	}
-	ogvm := ovm.NewOVM(vmContext, []ovm.Interpreter{evmInterpreter}, ovmconf)
+	vmContext := ovm.NewOVMContext(&dagChainContext{dag}, &DefaultCoinbase, db)
+	vmContext.TokenID = txnormal.TokenId
+	ogvm := dag.newOVM(vmContext, txContext, tracer, callTracer)
 
 	var ret []byte
-	//var leftOverGas uint64
 	var contractAddress = emptyAddress
 	var err error
 	var receipt *Receipt
+	var leftOverGas uint64
+	start := time.Now()
+	if tracer != nil {
+		tracer.CaptureStart(txContext.From, txnormal.To, txnormal.To.Bytes == emptyAddress.Bytes, txContext.Data, txContext.GasLimit, txContext.Value.Value)
+	}
 	if txnormal.To.Bytes == emptyAddress.Bytes {
-		ret, contractAddress, _, err = ogvm.Create(vmtypes.AccountRef(txContext.From), txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
+		ret, contractAddress, leftOverGas, err = ogvm.Create(vmtypes.AccountRef(txContext.From), txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
 	} else {
-		ret, _, err = ogvm.Call(vmtypes.AccountRef(txContext.From), txnormal.To, txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
+		ret, leftOverGas, err = ogvm.Call(vmtypes.AccountRef(txContext.From), txnormal.To, txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
+	}
+	if tracer != nil {
+		tracer.CaptureEnd(ret, txContext.GasLimit-leftOverGas, time.Since(start), err)
 	}
 	if err != nil {
-		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusOVMFailed, err.Error(), emptyAddress)
+		result := err.Error()
+		if err == vmtypes.ErrExecutionReverted {
+			if reason, ok := vmtypes.UnpackRevertReason(ret); ok {
+				result = fmt.Sprintf("%s: %s", result, reason)
+			}
+		}
+		receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusOVMFailed, result, emptyAddress)
 		log.WithError(err).Warn("vm processing error")
 		return nil, receipt, fmt.Errorf("vm processing error: %v", err)
 	}
@@ -1053,7 +1408,44 @@ func (dag *Dag) ProcessTransaction(tx types.Txi, preload bool) ([]byte, *Receipt
 	return ret, receipt, nil
 }
 
-func (dag *Dag) processTokenTransaction(tx *tx_types.ActionTx) (*Receipt, error) {
+// setSequencerContext fills the block related fields of the OVM context
+// with seq. TIMESTAMP is in seconds as contracts expect.
+func setSequencerContext(txContext *ovm.TxContext, seq *tx_types.Sequencer) {
+	txContext.Coinbase = DefaultCoinbase
+	txContext.Time = math.NewBigInt(0)
+	if seq == nil {
+		return
+	}
+	txContext.SequenceID = seq.Height
+	txContext.Time = math.NewBigInt(seq.Timestamp / 1000)
+	if seq.Issuer != nil {
+		txContext.Coinbase = *seq.Issuer
+	}
+}
+
+// dagChainContext gives the OVM access to the confirmed sequencers. Callers
+// should already hold dag.mu if needed.
+type dagChainContext struct {
+	dag *Dag
+}
+
+func (c *dagChainContext) GetSequencerHash(height uint64) common.Hash {
+	seq := c.dag.getSequencerByHeight(height)
+	if seq == nil {
+		return common.Hash{}
+	}
+	return seq.GetTxHash()
+}
+
+func (c *dagChainContext) GetSequencer(height uint64) (common.Hash, []byte, []byte, bool) {
+	seq := c.dag.getSequencerByHeight(height)
+	if seq == nil {
+		return common.Hash{}, nil, nil, false
+	}
+	return seq.GetTxHash(), seq.BlsJointSig, seq.BlsJointPubKey, true
+}
+
+func (dag *Dag) processTokenTransaction(db *state.StateDB, tx *tx_types.ActionTx, seq *tx_types.Sequencer) (*Receipt, error) {
 
 	actionData := tx.ActionData.(*tx_types.PublicOffering)
 	if tx.Action == tx_types.ActionTxActionIPO {
@@ -1062,7 +1454,24 @@ func (dag *Dag) processTokenTransaction(tx *tx_types.ActionTx) (*Receipt, error)
 		reIssuable := actionData.EnableSPO
 		amount := actionData.Value
 
-		tokenID, err := dag.statedb.IssueToken(issuer, name, "", reIssuable, amount)
+		var height uint64
+		if seq != nil {
+			height = seq.Height
+		}
+		if actionData.HasMetadata() && !dag.chainConfig().IsTokenMetadata(height) {
+			err := fmt.Errorf("token metadata is not enabled at height %d", height)
+			return NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress), nil
+		}
+
+		// only the first tx of a batch offering a symbol issues the token.
+		if actionData.TokenSymbol != "" {
+			if token := db.GetTokenObjectBySymbol(actionData.TokenSymbol); token != nil {
+				err := fmt.Errorf("token symbol %s is used by token %d", actionData.TokenSymbol, token.TokenID)
+				return NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress), nil
+			}
+		}
+		tokenID, err := db.IssueToken(issuer, name, actionData.TokenSymbol, actionData.Decimals, actionData.MetadataURI,
+			actionData.Controls, reIssuable, amount)
 		if err != nil {
 			receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress)
 			return receipt, err
@@ -1075,7 +1484,7 @@ func (dag *Dag) processTokenTransaction(tx *tx_types.ActionTx) (*Receipt, error)
 		tokenID := actionData.TokenId
 		amount := actionData.Value
 
-		err := dag.statedb.ReIssueToken(tokenID, amount)
+		err := db.ReIssueToken(tokenID, amount)
 		if err != nil {
 			receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress)
 			return receipt, err
@@ -1086,7 +1495,7 @@ func (dag *Dag) processTokenTransaction(tx *tx_types.ActionTx) (*Receipt, error)
 	if tx.Action == tx_types.ActionTxActionDestroy {
 		tokenID := actionData.TokenId
 
-		err := dag.statedb.DestroyToken(tokenID)
+		err := db.DestroyToken(tokenID)
 		if err != nil {
 			receipt := NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress)
 			return receipt, err
@@ -1100,31 +1509,286 @@ func (dag *Dag) processTokenTransaction(tx *tx_types.ActionTx) (*Receipt, error)
 	return receipt, err
 }
 
+// processDomainTransaction registers, renews or transfers a domain name at
+// the height of seq.
+func (dag *Dag) processDomainTransaction(db *state.StateDB, tx *tx_types.ActionTx, seq *tx_types.Sequencer) *Receipt {
+	var height uint64
+	if seq != nil {
+		height = seq.Height
+	}
+	if !dag.chainConfig().IsDomainName(height) {
+		err := fmt.Errorf("domain names are not enabled at height %d", height)
+		return NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress)
+	}
+	request := tx.GetDomainName()
+	err := request.Validate(tx.Action)
+	if err == nil {
+		switch tx.Action {
+		case tx_types.ActionRequestDomainName:
+			err = db.RegisterDomain(request.DomainName, tx.Sender(), height, request.Period)
+		case tx_types.ActionRenewDomainName:
+			err = db.RenewDomain(request.DomainName, tx.Sender(), height, request.Period, tx_types.MaxDomainPeriod)
+		case tx_types.ActionTransferDomainName:
+			err = db.TransferDomain(request.DomainName, tx.Sender(), request.To, height)
+		}
+	}
+	if err != nil {
+		log.WithField("tx", tx).WithError(err).Debug("domain action failed")
+		return NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress)
+	}
+	return NewReceipt(tx.GetTxHash(), ReceiptStatusSuccess, request.DomainName, emptyAddress)
+}
+
+// processAllowanceTransaction approves, revokes or spends an allowance.
+// The allowances are kept in tokenDB while a transfer-from moves the
+// balances in db like a normal tx.
+func (dag *Dag) processAllowanceTransaction(db state.StateDBInterface, tokenDB *state.StateDB, tx *tx_types.ActionTx, seq *tx_types.Sequencer) *Receipt {
+	var height uint64
+	if seq != nil {
+		height = seq.Height
+	}
+	if !dag.chainConfig().IsAllowance(height) {
+		err := fmt.Errorf("allowances are not enabled at height %d", height)
+		return NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress)
+	}
+	var err error
+	switch tx.Action {
+	case tx_types.ActionTxActionApprove, tx_types.ActionTxActionRevoke:
+		approval := tx.GetApproval()
+		if err = approval.Validate(tx.Action); err != nil {
+			break
+		}
+		value := math.NewBigInt(0)
+		if tx.Action == tx_types.ActionTxActionApprove {
+			value = approval.Value
+		}
+		tokenDB.SetAllowance(tx.Sender(), approval.Spender, approval.TokenId, value)
+	case tx_types.ActionTxActionTransferFrom:
+		transfer := tx.GetTransferFrom()
+		if err = transfer.Validate(); err != nil {
+			break
+		}
+		if err = tokenDB.CheckTokenTransfer(transfer.TokenId, transfer.Owner, transfer.To); err != nil {
+			break
+		}
+		balance := db.GetTokenBalance(transfer.Owner, transfer.TokenId)
+		if balance.Value.Cmp(transfer.Value.Value) < 0 {
+			err = fmt.Errorf("balance of %s not enough", transfer.Owner.Hex())
+			break
+		}
+		if err = tokenDB.SpendAllowance(transfer.Owner, tx.Sender(), transfer.TokenId, transfer.Value); err != nil {
+			break
+		}
+		db.SubTokenBalance(transfer.Owner, transfer.TokenId, transfer.Value)
+		db.AddTokenBalance(transfer.To, transfer.TokenId, transfer.Value)
+	}
+	if err != nil {
+		log.WithField("tx", tx).WithError(err).Debug("allowance action failed")
+		return NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress)
+	}
+	return NewReceipt(tx.GetTxHash(), ReceiptStatusSuccess, "", emptyAddress)
+}
+
+// processTokenControlTransaction freezes, whitelists or pauses a token on
+// behalf of its issuer. The result of the action is recorded in the receipt
+// so that the controls of a token can be audited from its receipts.
+func (dag *Dag) processTokenControlTransaction(db *state.StateDB, tx *tx_types.ActionTx) *Receipt {
+	control := tx.GetTokenControl()
+	err := control.Validate(tx.Action)
+	if err == nil {
+		err = checkTokenControl(db.GetTokenObject(control.TokenId), tx)
+	}
+	if err != nil {
+		log.WithField("tx", tx).WithError(err).Debug("token control failed")
+		return NewReceipt(tx.GetTxHash(), ReceiptStatusFailed, err.Error(), emptyAddress)
+	}
+	var result string
+	switch tx.Action {
+	case tx_types.ActionTxActionFreeze, tx_types.ActionTxActionUnfreeze:
+		frozen := tx.Action == tx_types.ActionTxActionFreeze
+		db.SetTokenHolderFlag(control.TokenId, control.Holder, state.TokenHolderFrozen, frozen)
+		result = fmt.Sprintf("token %d frozen %v: %s", control.TokenId, frozen, control.Holder.Hex())
+	case tx_types.ActionTxActionWhitelistAdd, tx_types.ActionTxActionWhitelistRemove:
+		listed := tx.Action == tx_types.ActionTxActionWhitelistAdd
+		db.SetTokenHolderFlag(control.TokenId, control.Holder, state.TokenHolderWhitelisted, listed)
+		result = fmt.Sprintf("token %d whitelisted %v: %s", control.TokenId, listed, control.Holder.Hex())
+	case tx_types.ActionTxActionPause, tx_types.ActionTxActionUnpause:
+		paused := tx.Action == tx_types.ActionTxActionPause
+		db.PauseToken(control.TokenId, paused)
+		result = fmt.Sprintf("token %d paused %v", control.TokenId, paused)
+	}
+	return NewReceipt(tx.GetTxHash(), ReceiptStatusSuccess, result, emptyAddress)
+}
+
+// checkTokenControl tells if the sender of tx is allowed to apply its
+// control action on tk.
+func checkTokenControl(tk *state.TokenObject, tx *tx_types.ActionTx) error {
+	if tk == nil || tk.Destroyed {
+		return fmt.Errorf("token not found or destroyed")
+	}
+	if tk.Issuer != tx.Sender() {
+		return fmt.Errorf("only the issuer can control the token")
+	}
+	if !tk.HasControl(tx_types.TokenControlOfAction(tx.Action)) {
+		return fmt.Errorf("control is not enabled on token %d", tk.TokenID)
+	}
+	return nil
+}
+
+// TraceTransaction re-executes the confirmed tx with the tracers enabled.
+// The tx is executed on the state of the parent sequencer after replaying
+// the txs confirmed before it by the same sequencer. The dag state is not
+// touched.
+func (dag *Dag) TraceTransaction(hash common.Hash, tracer evm.Tracer, callTracer ovm.CallTracer) (*Receipt, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	height, err := dag.getTxConfirmHeight(hash)
+	if err != nil {
+		return nil, err
+	}
+	if height == 0 {
+		return nil, fmt.Errorf("tx not confirmed or in genesis: %s", hash)
+	}
+	seq := dag.getSequencerByHeight(height)
+	parent := dag.getSequencerByHeight(height - 1)
+	if seq == nil || parent == nil {
+		return nil, fmt.Errorf("sequencer not found at height %d", height)
+	}
+	hashes := dag.getTxsHashesByNumber(height)
+	if hashes == nil {
+		return nil, fmt.Errorf("txs not found at height %d", height)
+	}
+	db, err := state.NewStateDB(state.DefaultStateDBConfig(), dag.statedb.Database(), parent.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("open state of height %d error: %v", parent.Height, err)
+	}
+
+	txs := dag.getTxis(*hashes)
+	sort.Sort(txs)
+	for _, txi := range txs {
+		if txi.GetTxHash() == hash {
+			_, receipt, _ := dag.executeTransaction(db, db, txi, seq, tracer, callTracer)
+			return receipt, nil
+		}
+		if _, _, err := dag.executeTransaction(db, db, txi, seq, nil, nil); err != nil {
+			return nil, fmt.Errorf("replay tx %s error: %v", txi.GetTxHash(), err)
+		}
+	}
+	return nil, fmt.Errorf("tx not found at height %d: %s", height, hash)
+}
+
+// SimulationResult is the outcome of a tx executed by SimulateTransaction.
+type SimulationResult struct {
+	GasUsed      uint64
+	ReturnData   []byte
+	RevertReason string
+	// Err is the execution error, nil if the tx would succeed.
+	Err     error
+	Receipt *Receipt
+}
+
+// SimulateTransaction executes tx on a throwaway copy of the latest state
+// as if it was confirmed by the next sequencer. The dag state is not
+// touched. tx nonce is set to the next nonce of the sender if not given.
+func (dag *Dag) SimulateTransaction(tx *tx_types.Tx) (*SimulationResult, error) {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+
+	db, err := state.NewStateDB(state.DefaultStateDBConfig(), dag.statedb.Database(), dag.latestSequencer.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("open latest state error: %v", err)
+	}
+	if tx.AccountNonce == 0 {
+		tx.AccountNonce = db.GetNonce(tx.Sender()) + 1
+	}
+	tx.GetBase().Hash = tx.CalcTxHash()
+	if tx.Value.Value.Cmp(db.GetTokenBalance(tx.Sender(), tx.TokenId).Value) > 0 {
+		return nil, fmt.Errorf("insufficient balance of token %d", tx.TokenId)
+	}
+
+	callTracer := ovm.NewCallTreeTracer()
+	_, receipt, err := dag.executeTransaction(db, db, tx, dag.nextSequencer(), nil, callTracer)
+	result := &SimulationResult{
+		Err:     err,
+		Receipt: receipt,
+	}
+	if root := callTracer.Root(); root != nil {
+		result.GasUsed = root.GasUsed
+		result.ReturnData = root.Output
+		if root.Error == vmtypes.ErrExecutionReverted.Error() {
+			result.RevertReason, _ = vmtypes.UnpackRevertReason(root.Output)
+		}
+	}
+	return result, nil
+}
+
+// nextSequencer returns a sequencer standing for the next one confirming
+// the txs in pool, at the height after the latest sequencer and issued by
+// the same issuer now.
+func (dag *Dag) nextSequencer() *tx_types.Sequencer {
+	next := &tx_types.Sequencer{
+		TxBase:    types.TxBase{Type: types.TxBaseTypeSequencer, Height: dag.latestSequencer.Height + 1},
+		Issuer:    dag.latestSequencer.Issuer,
+		Timestamp: time.Now().UnixNano() / 1e6,
+	}
+	if next.Timestamp < dag.latestSequencer.Timestamp {
+		next.Timestamp = dag.latestSequencer.Timestamp
+	}
+	return next
+}
+
+// chainConfig returns the fork heights of the OVM.
+func (dag *Dag) chainConfig() *params.ChainConfig {
+	if dag.conf.ChainConfig == nil {
+		return params.DefaultChainConfig
+	}
+	return dag.conf.ChainConfig
+}
+
+// newOVM creates the OVM running a tx confirmed at the height of txContext
+// with the features forked in at that height. tracer and callTracer are
+// enabled when not nil.
+func (dag *Dag) newOVM(vmContext *vmtypes.Context, txContext *ovm.TxContext, tracer evm.Tracer, callTracer ovm.CallTracer) *ovm.OVM {
+	chainConfig := dag.chainConfig()
+	var interpreters []ovm.Interpreter
+	// the wasm interpreter goes first as the evm one runs any code.
+	if chainConfig.IsWASM(txContext.SequenceID) {
+		interpreters = append(interpreters, wasm.NewWASMInterpreter(vmContext, txContext))
+	}
+	evmInterpreter := evm.NewEVMInterpreter(vmContext, txContext,
+		&evm.InterpreterConfig{
+			Debug:       tracer != nil,
+			Tracer:      tracer,
+			ChainConfig: chainConfig,
+		})
+	ovmconf := &ovm.OVMConfig{
+		NoRecursion:   false,
+		Tracer:        callTracer,
+		TokenContract: chainConfig.IsTokenContract(txContext.SequenceID),
+		OGPrecompiles: chainConfig.IsOGPrecompiles(txContext.SequenceID),
+		SequenceID:    txContext.SequenceID,
+	}
+	interpreters = append(interpreters, evmInterpreter)
+	return ovm.NewOVM(vmContext, interpreters, ovmconf)
+}
+
 // CallContract calls contract but disallow any modifications on
 // statedb. This method will call ovm.StaticCall() to satisfy this.
 func (dag *Dag) CallContract(addr common.Address, data []byte) ([]byte, error) {
 	// create ovm object.
 	//
 	// TODO gaslimit not implemented yet.
-	vmContext := ovm.NewOVMContext(&ovm.DefaultChainContext{}, &DefaultCoinbase, dag.statedb)
+	vmContext := ovm.NewOVMContext(&dagChainContext{dag}, &DefaultCoinbase, dag.statedb)
 	txContext := &ovm.TxContext{
-		From:       DefaultCoinbase,
-		Value:      math.NewBigInt(0),
-		Data:       data,
-		GasPrice:   math.NewBigInt(0),
-		GasLimit:   DefaultGasLimit,
-		Coinbase:   DefaultCoinbase,
-		SequenceID: dag.latestSequencer.Height,
-	}
-	// TODO more interpreters should be initialized, here only evm.
-	evmInterpreter := evm.NewEVMInterpreter(vmContext, txContext,
-		&evm.InterpreterConfig{
-			Debug: false,
-		})
-	ovmconf := &ovm.OVMConfig{
-		NoRecursion: false,
-	}
-	ogvm := ovm.NewOVM(vmContext, []ovm.Interpreter{evmInterpreter}, ovmconf)
+		From:     DefaultCoinbase,
+		Value:    math.NewBigInt(0),
+		Data:     data,
+		GasPrice: math.NewBigInt(0),
+		GasLimit: DefaultGasLimit,
+	}
+	setSequencerContext(txContext, dag.latestSequencer)
+	ogvm := dag.newOVM(vmContext, txContext, nil, nil)
 
 	ret, _, err := ogvm.StaticCall(vmtypes.AccountRef(txContext.From), addr, txContext.Data, txContext.GasLimit)
 	return ret, err
@@ -1,7 +1,6 @@
 package core
 
 import (
-	"fmt"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/core/state"
 	"testing"
@@ -13,7 +12,7 @@ func TestDag_Stop(t *testing.T) {
 	}
 	oldHash := dag.statedb.Root()
 	newHash, _ := common.HexStringToHash("0x0000000000000000000000000000000000000000000000000000000000000000")
-	fmt.Println(oldHash != newHash)
-	fmt.Println(newHash.Empty(), oldHash.Empty())
-	dag.SaveStateRoot()
+	if oldHash != newHash || !oldHash.Empty() {
+		t.Fatalf("root of an empty statedb should be empty, got %s", oldHash.Hex())
+	}
 }
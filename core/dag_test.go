@@ -21,21 +21,27 @@ import (
 
 	"encoding/hex"
 	"fmt"
+	"math/big"
 
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/core/state"
 	"github.com/annchain/OG/og"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/vm/eth/params"
 )
 
 var (
 	testAddress01 = "0x0b5d53f433b7e4a4f853a01e987f977497dda261"
 	testAddress02 = "0x0b5d53f433b7e4a4f853a01e987f977497dda262"
+
+	// testGenesisPath is the genesis accounts file of the node.
+	testGenesisPath = "../genesis.json"
 )
 
 func newTestDag(t *testing.T, dbDirPrefix string) (*core.Dag, *tx_types.Sequencer, func()) {
-	conf := core.DagConfig{}
+	conf := core.DagConfig{GenesisPath: testGenesisPath}
 	db, remove := newTestLDB(dbDirPrefix)
 	stdbconf := state.DefaultStateDBConfig()
 	dag, errnew := core.NewDag(conf, stdbconf, db, nil)
@@ -43,7 +49,7 @@ func newTestDag(t *testing.T, dbDirPrefix string) (*core.Dag, *tx_types.Sequence
 		t.Fatalf("new dag failed with error: %v", errnew)
 	}
 
-	genesis, balance := core.DefaultGenesis("genesis.json")
+	genesis, balance := core.DefaultGenesis(testGenesisPath)
 	err := dag.Init(genesis, balance)
 	if err != nil {
 		t.Fatalf("init dag failed with error: %v", err)
@@ -95,7 +101,7 @@ func TestDagInit(t *testing.T) {
 func TestDagLoadGenesis(t *testing.T) {
 	t.Parallel()
 
-	conf := core.DagConfig{}
+	conf := core.DagConfig{GenesisPath: testGenesisPath}
 	db, remove := newTestLDB("TestDagLoadGenesis")
 	defer remove()
 	dag, errnew := core.NewDag(conf, state.DefaultStateDBConfig(), db, nil)
@@ -104,7 +110,7 @@ func TestDagLoadGenesis(t *testing.T) {
 	}
 
 	acc := core.NewAccessor(db)
-	genesis, _ := core.DefaultGenesis("genesis.json")
+	genesis, _ := core.DefaultGenesis(testGenesisPath)
 	err := acc.WriteGenesis(genesis)
 	if err != nil {
 		t.Fatalf("can't write genesis into db: %v", err)
@@ -285,7 +291,7 @@ func TestDagProcess(t *testing.T) {
 	// pay a 10 bill to contract
 	transferValue := int64(10)
 	payTx := &tx_types.Tx{}
-	setTx.SetSender(addr)
+	payTx.SetSender(addr)
 	payTx.Value = math.NewBigInt(transferValue)
 	payTx.To = contractAddr
 	ret, _, err = dag.ProcessTransaction(payTx, false)
@@ -335,3 +341,163 @@ func TestDag_PrePush(t *testing.T) {
 	//}
 
 }
+
+func TestDag_WriteContractABI(t *testing.T) {
+	dag, finish := newParallelTestDag(t, 1)
+	defer finish()
+
+	contractABI := []byte(`[{"type":"function","name":"get","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`)
+	addr := crypto.CreateAddress(testDeployer, 0)
+	if err := dag.WriteContractABI(addr, testDeployer, 1, contractABI); err == nil {
+		t.Fatalf("should not register the abi of an unknown contract")
+	}
+
+	// the abi given with the creating tx is registered when it's confirmed.
+	tx := newParallelTestTx(testDeployer, common.Address{}, 0, deployCode(counterCode))
+	if err := dag.AddDeploymentABI(tx.GetTxHash(), contractABI); err != nil {
+		t.Fatalf("add deployment abi error: %v", err)
+	}
+	if dag.GetContractABI(addr) != nil {
+		t.Fatalf("abi registered before the creating tx is confirmed")
+	}
+	pushParallelTestBatch(t, dag, types.Txis{tx})
+	if dag.GetContractABI(addr) == nil {
+		t.Fatalf("abi of the creating tx not registered")
+	}
+
+	other := common.HexToAddress(testAddress01)
+	if err := dag.WriteContractABI(addr, other, 1, contractABI); err == nil {
+		t.Fatalf("should not register the abi by other than the creator")
+	}
+	if err := dag.WriteContractABI(addr, testDeployer, 0, contractABI); err == nil {
+		t.Fatalf("should not register the abi with nonce 0")
+	}
+	if err := dag.WriteContractABI(addr, testDeployer, 2, contractABI); err != nil {
+		t.Fatalf("register abi by the creator error: %v", err)
+	}
+	for _, nonce := range []uint64{1, 2} {
+		if err := dag.WriteContractABI(addr, testDeployer, nonce, contractABI); err == nil {
+			t.Fatalf("should not register the abi with a used nonce %d", nonce)
+		}
+	}
+	if err := dag.WriteContractABI(addr, testDeployer, 3, contractABI); err != nil {
+		t.Fatalf("register abi with a newer nonce error: %v", err)
+	}
+}
+
+func TestDag_SignedArchiveFork(t *testing.T) {
+	newArchive := func() *tx_types.Archive {
+		archive := &tx_types.Archive{
+			TxBase:      types.TxBase{Type: types.TxBaseTypeArchive},
+			Data:        []byte("content"),
+			ContentType: "text/plain",
+		}
+		archive.SetHash(archive.CalcTxHash())
+		return archive
+	}
+
+	dag, finish := newConfigTestDag(t, core.DagConfig{})
+	defer finish()
+	seq := newTestSeq(dag.LatestSequencer().Height + 1)
+	if err := dag.Push(&core.ConfirmBatch{Seq: seq, Txs: types.Txis{newArchive()}}); err == nil {
+		t.Fatalf("should not confirm an archive with a content type before the fork")
+	}
+
+	chainConfig := &params.ChainConfig{ChainID: big.NewInt(0), SignedArchiveHeight: big.NewInt(1)}
+	forked, finishForked := newConfigTestDag(t, core.DagConfig{ChainConfig: chainConfig})
+	defer finishForked()
+	pushParallelTestBatch(t, forked, types.Txis{newArchive()})
+}
+
+func TestDag_DomainNameFork(t *testing.T) {
+	newRequest := func() *tx_types.ActionTx {
+		tx := &tx_types.ActionTx{
+			TxBase:     types.TxBase{Type: types.TxBaseAction, AccountNonce: 1},
+			Action:     tx_types.ActionRequestDomainName,
+			From:       &testDeployer,
+			ActionData: &tx_types.RequestDomain{DomainName: "annchain", Period: 10},
+		}
+		tx.SetHash(tx.CalcTxHash())
+		return tx
+	}
+
+	dag, finish := newConfigTestDag(t, core.DagConfig{})
+	defer finish()
+	tx := newRequest()
+	pushParallelTestBatch(t, dag, types.Txis{tx})
+	if receipt := dag.GetReceipt(tx.GetTxHash()); receipt == nil || receipt.Status != core.ReceiptStatusFailed {
+		t.Fatalf("domain name registered before the fork, receipt: %v", receipt)
+	}
+	if dag.GetDomain("annchain") != nil {
+		t.Fatalf("domain name registered before the fork")
+	}
+
+	chainConfig := &params.ChainConfig{ChainID: big.NewInt(0), DomainNameHeight: big.NewInt(1)}
+	forked, finishForked := newConfigTestDag(t, core.DagConfig{ChainConfig: chainConfig})
+	defer finishForked()
+	tx = newRequest()
+	pushParallelTestBatch(t, forked, types.Txis{tx})
+	if receipt := forked.GetReceipt(tx.GetTxHash()); receipt == nil || receipt.Status != core.ReceiptStatusSuccess {
+		t.Fatalf("domain name not registered after the fork, receipt: %v", receipt)
+	}
+	if forked.GetDomain("annchain") == nil {
+		t.Fatalf("domain name not registered after the fork")
+	}
+}
+
+func TestDag_AllowanceFork(t *testing.T) {
+	spender := common.HexToAddress(testAddress01)
+	newApproval := func() *tx_types.ActionTx {
+		tx := &tx_types.ActionTx{
+			TxBase:     types.TxBase{Type: types.TxBaseAction, AccountNonce: 1},
+			Action:     tx_types.ActionTxActionApprove,
+			From:       &testDeployer,
+			ActionData: &tx_types.Approval{Spender: spender, Value: math.NewBigInt(100)},
+		}
+		tx.SetHash(tx.CalcTxHash())
+		return tx
+	}
+
+	dag, finish := newConfigTestDag(t, core.DagConfig{})
+	defer finish()
+	tx := newApproval()
+	pushParallelTestBatch(t, dag, types.Txis{tx})
+	if receipt := dag.GetReceipt(tx.GetTxHash()); receipt == nil || receipt.Status != core.ReceiptStatusFailed {
+		t.Fatalf("allowance approved before the fork, receipt: %v", receipt)
+	}
+	if allowance := dag.GetAllowance(testDeployer, spender, 0); allowance.GetInt64() != 0 {
+		t.Fatalf("allowance approved before the fork: %s", allowance)
+	}
+
+	chainConfig := &params.ChainConfig{ChainID: big.NewInt(0), AllowanceHeight: big.NewInt(1)}
+	forked, finishForked := newConfigTestDag(t, core.DagConfig{ChainConfig: chainConfig})
+	defer finishForked()
+	tx = newApproval()
+	pushParallelTestBatch(t, forked, types.Txis{tx})
+	if receipt := forked.GetReceipt(tx.GetTxHash()); receipt == nil || receipt.Status != core.ReceiptStatusSuccess {
+		t.Fatalf("allowance not approved after the fork, receipt: %v", receipt)
+	}
+	if allowance := forked.GetAllowance(testDeployer, spender, 0); allowance.GetInt64() != 100 {
+		t.Fatalf("allowance should be 100 after the fork, get: %s", allowance)
+	}
+}
+
+func TestDag_SimulateTransactionHeight(t *testing.T) {
+	chainConfig := &params.ChainConfig{ChainID: big.NewInt(0), BlockContextHeight: big.NewInt(1)}
+	dag, finish := newConfigTestDag(t, core.DagConfig{ChainConfig: chainConfig})
+	defer finish()
+
+	// the init code returns NUMBER, which is enabled from height 1 on.
+	code := []byte{0x43, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}
+	tx := newParallelTestTx(testDeployer, common.Address{}, 0, code).(*tx_types.Tx)
+	result, err := dag.SimulateTransaction(tx)
+	if err != nil {
+		t.Fatalf("simulate tx error: %v", err)
+	}
+	if result.Err != nil {
+		t.Fatalf("simulated tx failed: %v", result.Err)
+	}
+	if number := new(big.Int).SetBytes(result.ReturnData); number.Uint64() != dag.LatestSequencer().Height+1 {
+		t.Fatalf("tx should be simulated at height %d, get %s", dag.LatestSequencer().Height+1, number)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package core
+
+import (
+	"github.com/annchain/OG/metrics"
+)
+
+var (
+	// time from a tx added to the pool to its confirmation by a sequencer.
+	txConfirmTimer  = metrics.NewRegisteredTimer("core/txpool/confirm", nil)
+	seqConfirmMeter = metrics.NewRegisteredMeter("core/txpool/confirmed/seqs", nil)
+	txConfirmMeter  = metrics.NewRegisteredMeter("core/txpool/confirmed/txs", nil)
+
+	dagPushTimer   = metrics.NewRegisteredTimer("core/dag/push", nil)
+	dagHeightGauge = metrics.NewRegisteredGauge("core/dag/height", nil)
+)
+
+// registerPoolMetrics registers the gauges of the sizes of the pool.
+func registerPoolMetrics(pool *TxPool) {
+	metrics.NewRegisteredFunctionalGauge("core/txpool/queue", nil, func() int64 {
+		return int64(len(pool.queue))
+	})
+	metrics.NewRegisteredFunctionalGauge("core/txpool/txs", nil, func() int64 {
+		return int64(pool.txLookup.Count())
+	})
+	metrics.NewRegisteredFunctionalGauge("core/txpool/tips", nil, func() int64 {
+		return int64(pool.tips.Count())
+	})
+	metrics.NewRegisteredFunctionalGauge("core/txpool/pendings", nil, func() int64 {
+		return int64(pool.pendings.Count())
+	})
+	metrics.NewRegisteredFunctionalGauge("core/txpool/badtxs", nil, func() int64 {
+		return int64(pool.badtxs.Count())
+	})
+}
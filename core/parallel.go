@@ -0,0 +1,429 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package core
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/goroutine"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	vmtypes "github.com/annchain/OG/vm/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// The txs of a batch are executed optimistically in parallel: every tx is
+// first speculated concurrently on its own copy of the state before the
+// batch, recording the state it reads and writes. The txs are then
+// applied in order, a tx whose reads do not intersect the writes of the
+// txs before it gets its recorded writes replayed on the statedb, the
+// others are executed again. The resulting state is the one of the
+// sequential execution.
+
+type balanceKey struct {
+	addr    common.Address
+	tokenID int32
+}
+
+type storageKey struct {
+	addr common.Address
+	key  common.Hash
+}
+
+// accessSet is the part of the state read or written by txs.
+type accessSet struct {
+	// accounts are the accounts of which any field is accessed.
+	accounts map[common.Address]struct{}
+	// resets are the accounts created or suicided, which changes all
+	// their balances and storage.
+	resets   map[common.Address]struct{}
+	balances map[balanceKey]struct{}
+	storage  map[storageKey]struct{}
+	// all is set once the txs read or wrote untracked state.
+	all bool
+}
+
+func newAccessSet() *accessSet {
+	return &accessSet{
+		accounts: make(map[common.Address]struct{}),
+		resets:   make(map[common.Address]struct{}),
+		balances: make(map[balanceKey]struct{}),
+		storage:  make(map[storageKey]struct{}),
+	}
+}
+
+// conflicts tells if the reads depend on the writes.
+func (s *accessSet) conflicts(writes *accessSet) bool {
+	if s.all || writes.all {
+		return true
+	}
+	for addr := range s.accounts {
+		if _, ok := writes.accounts[addr]; ok {
+			return true
+		}
+	}
+	for key := range s.balances {
+		if _, ok := writes.resets[key.addr]; ok {
+			return true
+		}
+		if _, ok := writes.balances[key]; ok {
+			return true
+		}
+	}
+	for key := range s.storage {
+		if _, ok := writes.resets[key.addr]; ok {
+			return true
+		}
+		if _, ok := writes.storage[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *accessSet) merge(other *accessSet) {
+	s.all = s.all || other.all
+	for addr := range other.accounts {
+		s.accounts[addr] = struct{}{}
+	}
+	for addr := range other.resets {
+		s.resets[addr] = struct{}{}
+	}
+	for key := range other.balances {
+		s.balances[key] = struct{}{}
+	}
+	for key := range other.storage {
+		s.storage[key] = struct{}{}
+	}
+}
+
+// trackedStateDB records the state read and written by a tx executed on
+// db. The writes are also kept as operations to be replayed on another
+// db, those reverted by the OVM are dropped.
+type trackedStateDB struct {
+	db     state.StateDBInterface
+	reads  *accessSet
+	writes *accessSet
+
+	ops []func(db state.StateDBInterface)
+	// snapshots maps the snapshot ids of db to the number of ops then.
+	snapshots map[int]int
+}
+
+func newTrackedStateDB(db state.StateDBInterface) *trackedStateDB {
+	return &trackedStateDB{
+		db:        db,
+		reads:     newAccessSet(),
+		writes:    newAccessSet(),
+		snapshots: make(map[int]int),
+	}
+}
+
+// replay applies the writes on db.
+func (t *trackedStateDB) replay(db state.StateDBInterface) {
+	for _, op := range t.ops {
+		op(db)
+	}
+}
+
+func (t *trackedStateDB) readAccount(addr common.Address) {
+	t.reads.accounts[addr] = struct{}{}
+}
+
+func (t *trackedStateDB) readBalance(addr common.Address, tokenID int32) {
+	t.reads.balances[balanceKey{addr, tokenID}] = struct{}{}
+}
+
+func (t *trackedStateDB) write(addr common.Address, op func(db state.StateDBInterface)) {
+	t.writes.accounts[addr] = struct{}{}
+	t.ops = append(t.ops, op)
+	op(t.db)
+}
+
+// touch applies op, writing a balance or storage slot of addr. The account
+// itself is only written if op creates it or changes whether it is empty.
+func (t *trackedStateDB) touch(addr common.Address, op func(db state.StateDBInterface)) {
+	exist, empty := t.db.Exist(addr), t.db.Empty(addr)
+	t.ops = append(t.ops, op)
+	op(t.db)
+	if t.db.Exist(addr) != exist || t.db.Empty(addr) != empty {
+		t.writes.accounts[addr] = struct{}{}
+	}
+}
+
+func (t *trackedStateDB) writeBalance(addr common.Address, tokenID int32, op func(db state.StateDBInterface)) {
+	t.writes.balances[balanceKey{addr, tokenID}] = struct{}{}
+	t.touch(addr, op)
+}
+
+func copyBigInt(v *math.BigInt) *math.BigInt {
+	return &math.BigInt{Value: new(big.Int).Set(v.Value)}
+}
+
+func (t *trackedStateDB) CreateAccount(addr common.Address) {
+	t.readAccount(addr)
+	t.writes.resets[addr] = struct{}{}
+	t.write(addr, func(db state.StateDBInterface) { db.CreateAccount(addr) })
+}
+
+func (t *trackedStateDB) SubBalance(addr common.Address, amount *math.BigInt) {
+	t.SubTokenBalance(addr, 0, amount)
+}
+
+func (t *trackedStateDB) SubTokenBalance(addr common.Address, tokenID int32, amount *math.BigInt) {
+	// the statedb ignores zero amounts, the OVM moves them on every call.
+	if amount.Value.Sign() == 0 {
+		return
+	}
+	amount = copyBigInt(amount)
+	t.readBalance(addr, tokenID)
+	t.writeBalance(addr, tokenID, func(db state.StateDBInterface) { db.SubTokenBalance(addr, tokenID, amount) })
+}
+
+func (t *trackedStateDB) AddBalance(addr common.Address, amount *math.BigInt) {
+	t.AddTokenBalance(addr, 0, amount)
+}
+
+func (t *trackedStateDB) AddTokenBalance(addr common.Address, tokenID int32, amount *math.BigInt) {
+	if amount.Value.Sign() == 0 {
+		return
+	}
+	amount = copyBigInt(amount)
+	t.readBalance(addr, tokenID)
+	t.writeBalance(addr, tokenID, func(db state.StateDBInterface) { db.AddTokenBalance(addr, tokenID, amount) })
+}
+
+func (t *trackedStateDB) SetTokenBalance(addr common.Address, tokenID int32, balance *math.BigInt) {
+	balance = copyBigInt(balance)
+	t.writeBalance(addr, tokenID, func(db state.StateDBInterface) { db.SetTokenBalance(addr, tokenID, balance) })
+}
+
+func (t *trackedStateDB) GetBalance(addr common.Address) *math.BigInt {
+	return t.GetTokenBalance(addr, 0)
+}
+
+func (t *trackedStateDB) GetTokenBalance(addr common.Address, tokenID int32) *math.BigInt {
+	t.readBalance(addr, tokenID)
+	return t.db.GetTokenBalance(addr, tokenID)
+}
+
+// CheckTokenTransfer is not tracked, the controls of the tokens are changed
+// by the action txs only, which are followed by the execution of the rest
+// of the batch again.
+func (t *trackedStateDB) CheckTokenTransfer(tokenID int32, from, to common.Address) error {
+	return t.db.CheckTokenTransfer(tokenID, from, to)
+}
+
+func (t *trackedStateDB) GetNonce(addr common.Address) uint64 {
+	t.readAccount(addr)
+	return t.db.GetNonce(addr)
+}
+
+func (t *trackedStateDB) SetNonce(addr common.Address, nonce uint64) {
+	t.write(addr, func(db state.StateDBInterface) { db.SetNonce(addr, nonce) })
+}
+
+func (t *trackedStateDB) GetCodeHash(addr common.Address) common.Hash {
+	t.readAccount(addr)
+	return t.db.GetCodeHash(addr)
+}
+
+func (t *trackedStateDB) GetCode(addr common.Address) []byte {
+	t.readAccount(addr)
+	return t.db.GetCode(addr)
+}
+
+func (t *trackedStateDB) SetCode(addr common.Address, code []byte) {
+	code = common.CopyBytes(code)
+	t.write(addr, func(db state.StateDBInterface) { db.SetCode(addr, code) })
+}
+
+func (t *trackedStateDB) GetCodeSize(addr common.Address) int {
+	t.readAccount(addr)
+	return t.db.GetCodeSize(addr)
+}
+
+func (t *trackedStateDB) AddRefund(gas uint64) {
+	t.ops = append(t.ops, func(db state.StateDBInterface) { db.AddRefund(gas) })
+	t.db.AddRefund(gas)
+}
+
+func (t *trackedStateDB) SubRefund(gas uint64) {
+	t.ops = append(t.ops, func(db state.StateDBInterface) { db.SubRefund(gas) })
+	t.db.SubRefund(gas)
+}
+
+func (t *trackedStateDB) GetRefund() uint64 {
+	return t.db.GetRefund()
+}
+
+func (t *trackedStateDB) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	t.reads.storage[storageKey{addr, key}] = struct{}{}
+	return t.db.GetCommittedState(addr, key)
+}
+
+func (t *trackedStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	t.reads.storage[storageKey{addr, key}] = struct{}{}
+	return t.db.GetState(addr, key)
+}
+
+func (t *trackedStateDB) SetState(addr common.Address, key common.Hash, value common.Hash) {
+	t.writes.storage[storageKey{addr, key}] = struct{}{}
+	t.touch(addr, func(db state.StateDBInterface) { db.SetState(addr, key, value) })
+}
+
+func (t *trackedStateDB) AppendJournal(entry state.JournalEntry) {
+	t.db.AppendJournal(entry)
+}
+
+func (t *trackedStateDB) Suicide(addr common.Address) bool {
+	t.readAccount(addr)
+	t.writes.resets[addr] = struct{}{}
+	var suicided bool
+	t.write(addr, func(db state.StateDBInterface) { suicided = db.Suicide(addr) })
+	return suicided
+}
+
+func (t *trackedStateDB) HasSuicided(addr common.Address) bool {
+	t.readAccount(addr)
+	return t.db.HasSuicided(addr)
+}
+
+func (t *trackedStateDB) Exist(addr common.Address) bool {
+	t.readAccount(addr)
+	return t.db.Exist(addr)
+}
+
+func (t *trackedStateDB) Empty(addr common.Address) bool {
+	t.readAccount(addr)
+	return t.db.Empty(addr)
+}
+
+func (t *trackedStateDB) RevertToSnapshot(id int) {
+	t.db.RevertToSnapshot(id)
+	if n, ok := t.snapshots[id]; ok {
+		t.ops = t.ops[:n]
+	}
+}
+
+func (t *trackedStateDB) Snapshot() int {
+	id := t.db.Snapshot()
+	t.snapshots[id] = len(t.ops)
+	return id
+}
+
+func (t *trackedStateDB) AddLog(l *vmtypes.Log) {
+	t.ops = append(t.ops, func(db state.StateDBInterface) { db.AddLog(l) })
+	t.db.AddLog(l)
+}
+
+func (t *trackedStateDB) AddPreimage(hash common.Hash, preimage []byte) {
+	t.db.AddPreimage(hash, preimage)
+}
+
+func (t *trackedStateDB) ForEachStorage(addr common.Address, f func(common.Hash, common.Hash) bool) {
+	// the whole storage is read.
+	t.reads.accounts[addr] = struct{}{}
+	t.reads.all = true
+	t.db.ForEachStorage(addr, f)
+}
+
+func (t *trackedStateDB) String() string {
+	return t.db.String()
+}
+
+// speculation is the result of a tx executed on the state before its batch.
+type speculation struct {
+	tracked *trackedStateDB
+	receipt *Receipt
+	err     error
+}
+
+// speculate executes the txs of the batch concurrently, each on its own
+// copy of the state before the batch. The action txs, which update the
+// tokens out of the tracked statedb, are not speculated.
+func (dag *Dag) speculate(batch *ConfirmBatch) []*speculation {
+	specs := make([]*speculation, len(batch.Txs))
+	workers := dag.conf.ParallelWorkers
+	if workers > len(batch.Txs) {
+		workers = len(batch.Txs)
+	}
+	if workers <= 1 {
+		return specs
+	}
+
+	root := dag.statedb.Root()
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		goroutine.New(func() {
+			defer wg.Done()
+			for i := range jobs {
+				specs[i] = dag.speculateTx(batch.Txs[i], batch.Seq, root)
+			}
+		})
+	}
+	for i, txi := range batch.Txs {
+		if txi.GetType() != types.TxBaseAction {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return specs
+}
+
+func (dag *Dag) speculateTx(txi types.Txi, seq *tx_types.Sequencer, root common.Hash) (spec *speculation) {
+	// the tx may fail differently on a state missing the writes of the
+	// txs before it, it is executed again in that case.
+	defer func() {
+		if r := recover(); r != nil {
+			log.WithField("tx", txi).Debugf("speculative execution panicked: %v", r)
+			spec = nil
+		}
+	}()
+	db, err := state.NewStateDB(state.DefaultStateDBConfig(), dag.statedb.Database(), root)
+	if err != nil {
+		log.WithError(err).Warn("open state for speculative execution error")
+		return nil
+	}
+	tracked := newTrackedStateDB(db)
+	_, receipt, err := dag.executeTransaction(tracked, db, txi, seq, nil, nil)
+	return &speculation{tracked: tracked, receipt: receipt, err: err}
+}
+
+// processBatchTx applies the tx on the statedb from its speculation if the
+// tx does not depend on written, the state written by the txs before it
+// in the batch, or executes it again otherwise. The writes of the tx are
+// added to written.
+func (dag *Dag) processBatchTx(txi types.Txi, seq *tx_types.Sequencer, spec *speculation, written *accessSet) (*Receipt, error) {
+	if spec != nil && spec.err == nil && !spec.tracked.reads.conflicts(written) {
+		spec.tracked.replay(dag.statedb)
+		written.merge(spec.tracked.writes)
+		return spec.receipt, nil
+	}
+
+	tracked := newTrackedStateDB(dag.statedb)
+	_, receipt, err := dag.executeTransaction(tracked, dag.statedb, txi, seq, nil, nil)
+	written.merge(tracked.writes)
+	if txi.GetType() == types.TxBaseAction {
+		written.all = true
+	}
+	return receipt, err
+}
@@ -0,0 +1,173 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package core_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+)
+
+var (
+	// counterCode increases the storage slot of the caller.
+	counterCode = []byte{0x33, 0x54, 0x60, 0x01, 0x01, 0x33, 0x55, 0x00}
+	// sharedCounterCode increases the storage slot 0, its calls conflict.
+	sharedCounterCode = []byte{0x60, 0x00, 0x54, 0x60, 0x01, 0x01, 0x60, 0x00, 0x55, 0x00}
+
+	testDeployer = common.HexToAddress("0x0d")
+)
+
+// deployCode returns the init code returning code.
+func deployCode(code []byte) []byte {
+	n := byte(len(code))
+	init := []byte{0x60, n, 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, n, 0x60, 0x00, 0xf3}
+	return append(init, code...)
+}
+
+func newParallelTestDag(tb testing.TB, workers int) (*core.Dag, func()) {
+	return newConfigTestDag(tb, core.DagConfig{ParallelWorkers: workers})
+}
+
+// newConfigTestDag creates a dag with conf on a mem db and an empty genesis.
+func newConfigTestDag(tb testing.TB, conf core.DagConfig) (*core.Dag, func()) {
+	dir, err := ioutil.TempDir(os.TempDir(), "ogdb_test_parallel_")
+	if err != nil {
+		tb.Fatalf("create temp dir failed with error: %v", err)
+	}
+	genesisPath := filepath.Join(dir, "genesis.json")
+	if err := ioutil.WriteFile(genesisPath, []byte(`{"accounts":[]}`), 0644); err != nil {
+		tb.Fatalf("write genesis failed with error: %v", err)
+	}
+
+	conf.GenesisPath = genesisPath
+	dag, err := core.NewDag(conf, state.DefaultStateDBConfig(), ogdb.NewMemDatabase(), nil)
+	if err != nil {
+		tb.Fatalf("new dag failed with error: %v", err)
+	}
+	genesis, balance := core.DefaultGenesis(genesisPath)
+	if err := dag.Init(genesis, balance); err != nil {
+		tb.Fatalf("init dag failed with error: %v", err)
+	}
+	return dag, func() { os.RemoveAll(dir) }
+}
+
+func newParallelTestTx(from common.Address, to common.Address, nonce uint64, data []byte) types.Txi {
+	tx := &tx_types.Tx{
+		TxBase: types.TxBase{
+			Type:         types.TxBaseTypeNormal,
+			AccountNonce: nonce,
+		},
+		From:  &from,
+		To:    to,
+		Value: math.NewBigInt(0),
+		Data:  data,
+	}
+	tx.SetHash(tx.CalcTxHash())
+	return tx
+}
+
+func pushParallelTestBatch(tb testing.TB, dag *core.Dag, txs types.Txis) {
+	seq := newTestSeq(dag.LatestSequencer().Height + 1)
+	if err := dag.Push(&core.ConfirmBatch{Seq: seq, Txs: txs}); err != nil {
+		tb.Fatalf("push confirm batch to dag failed: %v", err)
+	}
+}
+
+// newParallelTestBatches returns the batch deploying the counters followed
+// by batches of calls from distinct senders, one in every sharedEvery of
+// them calling the shared counter.
+func newParallelTestBatches(batches int, senders int, sharedEvery int) []types.Txis {
+	counter := crypto.CreateAddress(testDeployer, 0)
+	shared := crypto.CreateAddress(testDeployer, 1)
+	txs := []types.Txis{{
+		newParallelTestTx(testDeployer, common.Address{}, 0, deployCode(counterCode)),
+		newParallelTestTx(testDeployer, common.Address{}, 1, deployCode(sharedCounterCode)),
+	}}
+	for b := 0; b < batches; b++ {
+		var batch types.Txis
+		for i := 0; i < senders; i++ {
+			from := common.HexToAddress(fmt.Sprintf("0x%x", 0x100+i))
+			to := counter
+			if i%sharedEvery == 0 {
+				to = shared
+			}
+			batch = append(batch, newParallelTestTx(from, to, uint64(b), []byte{0x01}))
+		}
+		// a second tx of a sender depends on its first one.
+		from := common.HexToAddress("0x100")
+		batch = append(batch, newParallelTestTx(from, counter, uint64(b)+uint64(batches), []byte{0x01}))
+		txs = append(txs, batch)
+	}
+	return txs
+}
+
+func TestParallelPush(t *testing.T) {
+	batches := newParallelTestBatches(3, 20, 4)
+
+	sequential, finish := newParallelTestDag(t, 1)
+	defer finish()
+	parallel, finish := newParallelTestDag(t, 8)
+	defer finish()
+	for _, txs := range batches {
+		pushParallelTestBatch(t, sequential, txs)
+		pushParallelTestBatch(t, parallel, txs)
+
+		root := sequential.LatestSequencer().StateRoot
+		if parallel.LatestSequencer().StateRoot != root {
+			t.Fatalf("state root %s of parallel execution, expected %s",
+				parallel.LatestSequencer().StateRoot.Hex(), root.Hex())
+		}
+	}
+
+	counter := crypto.CreateAddress(testDeployer, 0)
+	shared := crypto.CreateAddress(testDeployer, 1)
+	key := common.BytesToHash(common.LeftPadBytes(common.HexToAddress("0x101").ToBytes(), common.HashLength))
+	if v := parallel.GetState(counter, key); v.ToBytes()[common.HashLength-1] != 3 {
+		t.Fatalf("unexpected counter %s", v.Hex())
+	}
+	if v := parallel.GetState(shared, common.Hash{}); v.ToBytes()[common.HashLength-1] != 15 {
+		t.Fatalf("unexpected shared counter %s", v.Hex())
+	}
+}
+
+func benchmarkPush(b *testing.B, workers int) {
+	batches := newParallelTestBatches(b.N, 200, 50)
+	dag, finish := newParallelTestDag(b, workers)
+	defer finish()
+	pushParallelTestBatch(b, dag, batches[0])
+
+	b.ResetTimer()
+	for _, txs := range batches[1:] {
+		pushParallelTestBatch(b, dag, txs)
+	}
+}
+
+func BenchmarkPushSequential(b *testing.B) {
+	benchmarkPush(b, 1)
+}
+
+func BenchmarkPushParallel(b *testing.B) {
+	benchmarkPush(b, 8)
+}
@@ -16,6 +16,9 @@ package core
 import (
 	"fmt"
 	"github.com/annchain/OG/common"
+	"strings"
+
+	vmtypes "github.com/annchain/OG/vm/types"
 )
 
 type ReceiptStatus uint8
@@ -46,6 +49,16 @@ func NewReceipt(hash common.Hash, status ReceiptStatus, pResult string, addr com
 	}
 }
 
+// RevertReason returns the reason given by a reverted contract, empty if
+// there is none.
+func (r *Receipt) RevertReason() string {
+	prefix := vmtypes.ErrExecutionReverted.Error() + ": "
+	if r.Status != ReceiptStatusOVMFailed || !strings.HasPrefix(r.ProcessResult, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(r.ProcessResult, prefix)
+}
+
 func (r *Receipt) ToJsonMap() map[string]interface{} {
 	jm := make(map[string]interface{})
 	jm["hash"] = r.TxHash.Hex()
@@ -0,0 +1,37 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package state
+
+import (
+	"github.com/annchain/OG/common"
+)
+
+const AllowanceKeyPrefix = "alID"
+
+// allowanceKey identifies the allowance owner approved to spender on one
+// token.
+type allowanceKey struct {
+	owner   common.Address
+	spender common.Address
+	tokenID int32
+}
+
+// AllowanceTrieKey returns the trie key of the allowance owner approved to
+// spender on the token. The allowance is stored as the big-endian bytes of
+// the amount.
+func AllowanceTrieKey(owner, spender common.Address, tokenID int32) []byte {
+	key := append([]byte(AllowanceKeyPrefix), owner.ToBytes()...)
+	key = append(key, spender.ToBytes()...)
+	return append(key, common.ByteInt32(tokenID)...)
+}
@@ -0,0 +1,54 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package state
+
+import (
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+)
+
+const DomainKeyPrefix = "dmID"
+
+// DomainTrieKey returns the trie key of the domain name. The name is
+// hashed so that the key never collides with an address.
+func DomainTrieKey(name string) []byte {
+	return append([]byte(DomainKeyPrefix), crypto.Keccak256([]byte(name))...)
+}
+
+//go:generate msgp
+
+//msgp:tuple DomainObject
+
+// DomainObject records the owner of a domain name. The name resolves to its
+// owner until the sequencer height Expiry, after which anyone can register
+// it again.
+type DomainObject struct {
+	Name   string
+	Owner  common.Address
+	Expiry uint64
+}
+
+// Expired tells if the domain name is expired at the sequencer height.
+func (d *DomainObject) Expired(height uint64) bool {
+	return height > d.Expiry
+}
+
+func (d *DomainObject) Encode() ([]byte, error) {
+	return d.MarshalMsg(nil)
+}
+
+func (d *DomainObject) Decode(b []byte) error {
+	_, err := d.UnmarshalMsg(b)
+	return err
+}
@@ -156,6 +156,7 @@ type (
 	createTokenChange struct {
 		prevLatestTokenID int32
 		tokenID           int32
+		symbol            string
 	}
 	resetTokenChange struct {
 		tokenID int32
@@ -168,6 +169,27 @@ type (
 		tokenID       int32
 		prevDestroyed bool
 	}
+	pauseChange struct {
+		tokenID    int32
+		prevPaused bool
+	}
+
+	// Changes to domain names
+	domainChange struct {
+		name      string
+		prev      *DomainObject
+		prevDirty bool
+	}
+	allowanceChange struct {
+		key       allowanceKey
+		prev      *math.BigInt
+		prevDirty bool
+	}
+	tokenHolderChange struct {
+		key       tokenHolderKey
+		prev      uint8
+		prevDirty bool
+	}
 )
 
 func (ch createObjectChange) Revert(s *StateDB) {
@@ -301,17 +323,13 @@ func (ch refundChange) TokenDirtied() int32 {
 }
 
 func (ch addLogChange) Revert(s *StateDB) {
-	// TODO
-	// Log not implemented yet. To avoid the error, comment
-	// this function.
-
-	// logs := s.logs[ch.txhash]
-	// if len(logs) == 1 {
-	// 	delete(s.logs, ch.txhash)
-	// } else {
-	// 	s.logs[ch.txhash] = logs[:len(logs)-1]
-	// }
-	// s.logSize--
+	logs := s.logs[ch.txhash]
+	if len(logs) == 1 {
+		delete(s.logs, ch.txhash)
+	} else {
+		s.logs[ch.txhash] = logs[:len(logs)-1]
+	}
+	s.logSize--
 }
 
 func (ch addLogChange) Dirtied() *common.Address {
@@ -342,6 +360,9 @@ func (ch createTokenChange) Revert(s *StateDB) {
 	s.latestTokenID = ch.prevLatestTokenID
 	delete(s.tokens, ch.tokenID)
 	delete(s.dirtyTokens, ch.tokenID)
+	if ch.symbol != "" {
+		delete(s.tokenSymbols, ch.symbol)
+	}
 }
 
 func (ch createTokenChange) Dirtied() *common.Address {
@@ -397,3 +418,67 @@ func (ch destroyChange) Dirtied() *common.Address {
 func (ch destroyChange) TokenDirtied() int32 {
 	return ch.tokenID
 }
+
+func (ch pauseChange) Revert(s *StateDB) {
+	tkObj := s.getTokenObject(ch.tokenID)
+	if tkObj != nil {
+		tkObj.Paused = ch.prevPaused
+	}
+}
+
+func (ch pauseChange) Dirtied() *common.Address {
+	return nil
+}
+
+func (ch pauseChange) TokenDirtied() int32 {
+	return ch.tokenID
+}
+
+func (ch domainChange) Revert(s *StateDB) {
+	if ch.prev == nil {
+		delete(s.domains, ch.name)
+	} else {
+		s.domains[ch.name] = ch.prev
+	}
+	if !ch.prevDirty {
+		delete(s.dirtyDomains, ch.name)
+	}
+}
+
+func (ch domainChange) Dirtied() *common.Address {
+	return nil
+}
+
+func (ch domainChange) TokenDirtied() int32 {
+	return TokenNotDirtied
+}
+
+func (ch allowanceChange) Revert(s *StateDB) {
+	s.allowances[ch.key] = ch.prev
+	if !ch.prevDirty {
+		delete(s.dirtyAllowances, ch.key)
+	}
+}
+
+func (ch allowanceChange) Dirtied() *common.Address {
+	return nil
+}
+
+func (ch allowanceChange) TokenDirtied() int32 {
+	return TokenNotDirtied
+}
+
+func (ch tokenHolderChange) Revert(s *StateDB) {
+	s.tokenHolders[ch.key] = ch.prev
+	if !ch.prevDirty {
+		delete(s.dirtyTokenHolders, ch.key)
+	}
+}
+
+func (ch tokenHolderChange) Dirtied() *common.Address {
+	return nil
+}
+
+func (ch tokenHolderChange) TokenDirtied() int32 {
+	return TokenNotDirtied
+}
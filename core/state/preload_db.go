@@ -131,6 +131,13 @@ func (pd *PreloadDB) GetBalance(addr common.Address) *math.BigInt {
 func (pd *PreloadDB) GetTokenBalance(addr common.Address, tokenID int32) *math.BigInt {
 	return pd.getBalance(addr, tokenID)
 }
+
+// CheckTokenTransfer checks the controls of the token in the statedb, they
+// are only changed by the action txs which don't run on the preload db.
+func (pd *PreloadDB) CheckTokenTransfer(tokenID int32, from, to common.Address) error {
+	return pd.sd.CheckTokenTransfer(tokenID, from, to)
+}
+
 func (pd *PreloadDB) getBalance(addr common.Address, tokenID int32) *math.BigInt {
 	state := pd.getStateObject(addr)
 	if state == nil {
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/annchain/OG/common"
 	tkType "github.com/annchain/OG/types/token"
+	"github.com/annchain/OG/types/tx_types"
+	"math/big"
 	"sync"
 	"time"
 
@@ -55,6 +57,14 @@ type StateDB struct {
 	root common.Hash
 
 	refund uint64
+
+	// logs emitted by the contracts, keyed by the hash of the tx set by
+	// Prepare.
+	thash   common.Hash
+	txIndex int
+	logs    map[common.Hash][]*vmtypes.Log
+	logSize uint
+
 	// journal records every action which will change statedb's data
 	// and it's for VM term revert only.
 	journal     *journal
@@ -71,6 +81,20 @@ type StateDB struct {
 	dirtyLatestTokenID bool
 	tokens             map[int32]*TokenObject
 	dirtyTokens        map[int32]struct{}
+	// ids of the tokens by symbol
+	tokenSymbols map[string]int32
+
+	// domain name information
+	domains      map[string]*DomainObject
+	dirtyDomains map[string]struct{}
+
+	// token allowances
+	allowances      map[allowanceKey]*math.BigInt
+	dirtyAllowances map[allowanceKey]struct{}
+
+	// flags of the holders of the tokens with controls
+	tokenHolders      map[tokenHolderKey]uint8
+	dirtyTokenHolders map[tokenHolderKey]struct{}
 
 	close chan struct{}
 
@@ -90,19 +114,27 @@ func NewStateDB(conf StateDBConfig, db Database, root common.Hash) (*StateDB, er
 	}
 
 	sd := &StateDB{
-		conf:          conf,
-		db:            db,
-		trie:          tr,
-		states:        make(map[common.Address]*StateObject),
-		dirtyset:      make(map[common.Address]struct{}),
-		latestTokenID: latestTokenID,
-		tokens:        make(map[int32]*TokenObject),
-		dirtyTokens:   make(map[int32]struct{}),
-		journal:       newJournal(),
-		snapshotID:    0,
-		snapshotSet:   make([]shot, 0),
-		close:         make(chan struct{}),
-		root:          root,
+		conf:              conf,
+		db:                db,
+		trie:              tr,
+		states:            make(map[common.Address]*StateObject),
+		dirtyset:          make(map[common.Address]struct{}),
+		latestTokenID:     latestTokenID,
+		tokens:            make(map[int32]*TokenObject),
+		dirtyTokens:       make(map[int32]struct{}),
+		tokenSymbols:      make(map[string]int32),
+		domains:           make(map[string]*DomainObject),
+		dirtyDomains:      make(map[string]struct{}),
+		allowances:        make(map[allowanceKey]*math.BigInt),
+		dirtyAllowances:   make(map[allowanceKey]struct{}),
+		tokenHolders:      make(map[tokenHolderKey]uint8),
+		dirtyTokenHolders: make(map[tokenHolderKey]struct{}),
+		logs:              make(map[common.Hash][]*vmtypes.Log),
+		journal:           newJournal(),
+		snapshotID:        0,
+		snapshotSet:       make([]shot, 0),
+		close:             make(chan struct{}),
+		root:              root,
 	}
 
 	return sd, nil
@@ -450,19 +482,30 @@ Token part
 */
 
 // IssueToken creates a new token according to offered token information.
-func (sd *StateDB) IssueToken(issuer common.Address, name, symbol string, reIssuable bool, fstIssue *math.BigInt) (int32, error) {
+// The symbol, if any, must not be used by another token.
+func (sd *StateDB) IssueToken(issuer common.Address, name, symbol string, decimals uint8, metadataURI string,
+	controls uint8, reIssuable bool, fstIssue *math.BigInt) (int32, error) {
 	tokenID := sd.latestTokenID + 1
 
 	oldToken := sd.getTokenObject(tokenID)
 	if oldToken != nil {
 		return 0, fmt.Errorf("already exist token with tokenID: %d", tokenID)
 	}
-	newToken := NewTokenObject(tokenID, issuer, name, symbol, reIssuable, fstIssue, sd)
+	if symbol != "" {
+		if token := sd.getTokenObjectBySymbol(symbol); token != nil {
+			return 0, fmt.Errorf("token symbol %s is used by token %d", symbol, token.TokenID)
+		}
+	}
+	newToken := NewTokenObject(tokenID, issuer, name, symbol, decimals, metadataURI, controls, reIssuable, fstIssue, sd)
 	sd.AppendJournal(&createTokenChange{
 		prevLatestTokenID: sd.latestTokenID,
 		tokenID:           tokenID,
+		symbol:            symbol,
 	})
 	sd.tokens[tokenID] = newToken
+	if symbol != "" {
+		sd.tokenSymbols[symbol] = tokenID
+	}
 	sd.latestTokenID = tokenID
 	sd.dirtyLatestTokenID = true
 
@@ -517,6 +560,32 @@ func (sd *StateDB) getTokenObject(tokenID int32) *TokenObject {
 	return token
 }
 
+// GetTokenObjectBySymbol returns the token using the symbol, destroyed or
+// not, nil if no token uses it.
+func (sd *StateDB) GetTokenObjectBySymbol(symbol string) *TokenObject {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	return sd.getTokenObjectBySymbol(symbol)
+}
+
+func (sd *StateDB) getTokenObjectBySymbol(symbol string) *TokenObject {
+	tokenID, exist := sd.tokenSymbols[symbol]
+	if !exist {
+		data, err := sd.trie.TryGet(TokenSymbolTrieKey(symbol))
+		if err != nil {
+			log.Errorf("load token symbol from trie err: %v", err)
+			return nil
+		}
+		if len(data) < 4 {
+			return nil
+		}
+		tokenID = common.GetInt32(data, 0)
+		sd.tokenSymbols[symbol] = tokenID
+	}
+	return sd.getTokenObject(tokenID)
+}
+
 func (sd *StateDB) setTokenObject(tokenID int32, tkObj *TokenObject) {
 	oldTkObj := sd.getTokenObject(tokenID)
 	sd.AppendJournal(&resetTokenChange{
@@ -530,6 +599,256 @@ func (sd *StateDB) LatestTokenID() int32 {
 	return sd.latestTokenID
 }
 
+// PauseToken pauses or unpauses all the transfers of the token.
+func (sd *StateDB) PauseToken(tokenID int32, paused bool) error {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	tkObj := sd.getTokenObject(tokenID)
+	if tkObj == nil {
+		return fmt.Errorf("token not exists")
+	}
+	tkObj.SetPaused(paused)
+	return nil
+}
+
+// GetTokenHolderFlags returns the TokenHolder flags of the holder of the
+// token.
+func (sd *StateDB) GetTokenHolderFlags(tokenID int32, holder common.Address) uint8 {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	return sd.getTokenHolderFlags(tokenHolderKey{tokenID, holder})
+}
+
+func (sd *StateDB) getTokenHolderFlags(key tokenHolderKey) uint8 {
+	flags, exist := sd.tokenHolders[key]
+	if exist {
+		return flags
+	}
+	data, err := sd.trie.TryGet(TokenHolderTrieKey(key.tokenID, key.holder))
+	if err != nil {
+		log.Errorf("load token holder from trie err: %v", err)
+		return 0
+	}
+	if len(data) > 0 {
+		flags = data[0]
+	}
+	sd.tokenHolders[key] = flags
+	return flags
+}
+
+// SetTokenHolderFlag sets or clears the TokenHolder flag of the holder of
+// the token.
+func (sd *StateDB) SetTokenHolderFlag(tokenID int32, holder common.Address, flag uint8, set bool) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	key := tokenHolderKey{tokenID, holder}
+	prev := sd.getTokenHolderFlags(key)
+	_, dirty := sd.dirtyTokenHolders[key]
+	sd.AppendJournal(&tokenHolderChange{
+		key:       key,
+		prev:      prev,
+		prevDirty: dirty,
+	})
+	if set {
+		sd.tokenHolders[key] = prev | flag
+	} else {
+		sd.tokenHolders[key] = prev &^ flag
+	}
+	sd.dirtyTokenHolders[key] = struct{}{}
+}
+
+// CheckTokenTransfer tells if the controls of the token allow moving it
+// from one holder to another. The transfers of a paused token and of the
+// frozen holders are refused, and only the whitelisted holders and the
+// issuer can send or receive a token with a whitelist.
+func (sd *StateDB) CheckTokenTransfer(tokenID int32, from, to common.Address) error {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	tkObj := sd.getTokenObject(tokenID)
+	if tkObj == nil || tkObj.Controls == 0 {
+		return nil
+	}
+	if tkObj.Paused {
+		return fmt.Errorf("token %d is paused", tokenID)
+	}
+	for _, addr := range []common.Address{from, to} {
+		flags := sd.getTokenHolderFlags(tokenHolderKey{tokenID, addr})
+		if flags&TokenHolderFrozen != 0 {
+			return fmt.Errorf("token %d of %s is frozen", tokenID, addr.Hex())
+		}
+		if tkObj.HasControl(tx_types.TokenControlWhitelist) && addr != tkObj.Issuer && flags&TokenHolderWhitelisted == 0 {
+			return fmt.Errorf("%s is not in the whitelist of token %d", addr.Hex(), tokenID)
+		}
+	}
+	return nil
+}
+
+/**
+Domain part
+*/
+
+// GetDomain returns the record of the domain name, nil if it was never
+// registered. The record may be expired.
+func (sd *StateDB) GetDomain(name string) *DomainObject {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	return sd.getDomain(name)
+}
+
+func (sd *StateDB) getDomain(name string) *DomainObject {
+	domain, exist := sd.domains[name]
+	if exist {
+		return domain
+	}
+	domain, err := sd.loadDomainObject(name)
+	if err != nil {
+		log.Errorf("load domain from trie err: %v", err)
+		return nil
+	}
+	if domain == nil {
+		return nil
+	}
+	sd.domains[name] = domain
+	return domain
+}
+
+func (sd *StateDB) setDomain(domain *DomainObject) {
+	_, dirty := sd.dirtyDomains[domain.Name]
+	sd.AppendJournal(&domainChange{
+		name:      domain.Name,
+		prev:      sd.getDomain(domain.Name),
+		prevDirty: dirty,
+	})
+	sd.domains[domain.Name] = domain
+	sd.dirtyDomains[domain.Name] = struct{}{}
+}
+
+// RegisterDomain registers the domain name for owner from the sequencer
+// height to height+period. The name must be free or expired.
+func (sd *StateDB) RegisterDomain(name string, owner common.Address, height uint64, period uint64) error {
+	domain := sd.getDomain(name)
+	if domain != nil && !domain.Expired(height) {
+		return fmt.Errorf("domain name %s is registered by %s", name, domain.Owner.Hex())
+	}
+	sd.setDomain(&DomainObject{
+		Name:   name,
+		Owner:  owner,
+		Expiry: height + period,
+	})
+	return nil
+}
+
+// RenewDomain extends the registration of the domain name by period. Only
+// the owner can renew it before it expires, and the registration can't
+// last longer than maxPeriod from height.
+func (sd *StateDB) RenewDomain(name string, owner common.Address, height uint64, period uint64, maxPeriod uint64) error {
+	domain, err := sd.ownedDomain(name, owner, height)
+	if err != nil {
+		return err
+	}
+	if domain.Expiry+period > height+maxPeriod {
+		return fmt.Errorf("domain name %s can't be registered for more than %d heights", name, maxPeriod)
+	}
+	sd.setDomain(&DomainObject{
+		Name:   name,
+		Owner:  owner,
+		Expiry: domain.Expiry + period,
+	})
+	return nil
+}
+
+// TransferDomain gives the domain name to another owner until it expires.
+func (sd *StateDB) TransferDomain(name string, owner common.Address, to common.Address, height uint64) error {
+	domain, err := sd.ownedDomain(name, owner, height)
+	if err != nil {
+		return err
+	}
+	sd.setDomain(&DomainObject{
+		Name:   name,
+		Owner:  to,
+		Expiry: domain.Expiry,
+	})
+	return nil
+}
+
+func (sd *StateDB) ownedDomain(name string, owner common.Address, height uint64) (*DomainObject, error) {
+	domain := sd.getDomain(name)
+	if domain == nil || domain.Expired(height) {
+		return nil, fmt.Errorf("domain name %s is not registered", name)
+	}
+	if domain.Owner != owner {
+		return nil, fmt.Errorf("domain name %s is owned by %s", name, domain.Owner.Hex())
+	}
+	return domain, nil
+}
+
+/**
+Allowance part
+*/
+
+// GetAllowance returns how much of owner's token spender is allowed to
+// move, zero if owner never approved spender.
+func (sd *StateDB) GetAllowance(owner, spender common.Address, tokenID int32) *math.BigInt {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	return math.NewBigIntFromBigInt(sd.getAllowance(allowanceKey{owner, spender, tokenID}).Value)
+}
+
+func (sd *StateDB) getAllowance(key allowanceKey) *math.BigInt {
+	allowance, exist := sd.allowances[key]
+	if exist {
+		return allowance
+	}
+	data, err := sd.trie.TryGet(AllowanceTrieKey(key.owner, key.spender, key.tokenID))
+	if err != nil {
+		log.Errorf("load allowance from trie err: %v", err)
+		return math.NewBigInt(0)
+	}
+	allowance = math.NewBigIntFromBigInt(new(big.Int).SetBytes(data))
+	sd.allowances[key] = allowance
+	return allowance
+}
+
+// SetAllowance sets how much of owner's token spender is allowed to move.
+// A zero allowance removes it from the trie.
+func (sd *StateDB) SetAllowance(owner, spender common.Address, tokenID int32, value *math.BigInt) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.setAllowance(allowanceKey{owner, spender, tokenID}, value)
+}
+
+func (sd *StateDB) setAllowance(key allowanceKey, value *math.BigInt) {
+	_, dirty := sd.dirtyAllowances[key]
+	sd.AppendJournal(&allowanceChange{
+		key:       key,
+		prev:      sd.getAllowance(key),
+		prevDirty: dirty,
+	})
+	sd.allowances[key] = math.NewBigIntFromBigInt(value.Value)
+	sd.dirtyAllowances[key] = struct{}{}
+}
+
+// SpendAllowance reduces the allowance owner approved to spender by value.
+func (sd *StateDB) SpendAllowance(owner, spender common.Address, tokenID int32, value *math.BigInt) error {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	key := allowanceKey{owner, spender, tokenID}
+	allowance := sd.getAllowance(key)
+	if allowance.Value.Cmp(value.Value) < 0 {
+		return fmt.Errorf("allowance not enough, allowance: %s, value: %s", allowance.String(), value.String())
+	}
+	sd.setAllowance(key, allowance.Sub(value))
+	return nil
+}
+
 func (sd *StateDB) AppendJournal(entry JournalEntry) {
 	sd.journal.append(entry)
 }
@@ -557,9 +876,27 @@ func (sd *StateDB) HasSuicided(addr common.Address) bool {
 	return stobj.suicided
 }
 
+// Prepare sets the hash and the index of the tx being executed, the logs
+// added afterwards belong to it.
+func (sd *StateDB) Prepare(thash common.Hash, txIndex int) {
+	sd.thash = thash
+	sd.txIndex = txIndex
+}
+
 func (sd *StateDB) AddLog(l *vmtypes.Log) {
-	// TODO
-	// Not implemented yet
+	sd.AppendJournal(&addLogChange{txhash: sd.thash})
+
+	l.TxHash = sd.thash
+	l.TxIndex = uint(sd.txIndex)
+	l.Index = sd.logSize
+	sd.logs[sd.thash] = append(sd.logs[sd.thash], l)
+	sd.logSize++
+}
+
+// GetLogs returns the logs added by the tx since the last
+// ClearJournalAndRefund.
+func (sd *StateDB) GetLogs(hash common.Hash) []*vmtypes.Log {
+	return sd.logs[hash]
 }
 
 func (sd *StateDB) AddPreimage(h common.Hash, b []byte) {
@@ -614,9 +951,27 @@ func (sd *StateDB) loadTokenObject(tokenID int32) (*TokenObject, error) {
 	if err != nil {
 		return nil, fmt.Errorf("decode token err: %v", err)
 	}
+	token.db = sd
 	return &token, nil
 }
 
+// loadDomainObject loads domain object from trie.
+func (sd *StateDB) loadDomainObject(name string) (*DomainObject, error) {
+	data, err := sd.trie.TryGet(DomainTrieKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("get domain from trie err: %v", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var domain DomainObject
+	err = domain.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode domain err: %v", err)
+	}
+	return &domain, nil
+}
+
 // Commit tries to save dirty data to memory trie db.
 func (sd *StateDB) Commit() (common.Hash, error) {
 	sd.mu.Lock()
@@ -667,6 +1022,13 @@ func (sd *StateDB) commit() (common.Hash, error) {
 		if err := sd.trie.TryUpdate(tkType.TokenTrieKey(tokenID), data); err != nil {
 			log.Errorf("commit token %d to trie error: %d", tokenID, err)
 		}
+		// the symbol never changes, the tokens issued without one are
+		// not indexed.
+		if token.Symbol != "" {
+			if err := sd.trie.TryUpdate(TokenSymbolTrieKey(token.Symbol), common.ByteInt32(tokenID)); err != nil {
+				log.Errorf("commit token %d symbol to trie error: %v", tokenID, err)
+			}
+		}
 		delete(sd.dirtyTokens, tokenID)
 	}
 	if sd.dirtyLatestTokenID {
@@ -676,6 +1038,36 @@ func (sd *StateDB) commit() (common.Hash, error) {
 		sd.dirtyLatestTokenID = false
 	}
 
+	// commit dirty domain information
+	for name := range sd.dirtyDomains {
+		data, _ := sd.domains[name].Encode()
+		if err := sd.trie.TryUpdate(DomainTrieKey(name), data); err != nil {
+			log.Errorf("commit domain %s to trie error: %v", name, err)
+		}
+		delete(sd.dirtyDomains, name)
+	}
+
+	// commit dirty allowances
+	for key := range sd.dirtyAllowances {
+		trieKey := AllowanceTrieKey(key.owner, key.spender, key.tokenID)
+		if err := sd.trie.TryUpdate(trieKey, sd.allowances[key].GetBytes()); err != nil {
+			log.Errorf("commit allowance of %s to %s on token %d to trie error: %v", key.owner.Hex(), key.spender.Hex(), key.tokenID, err)
+		}
+		delete(sd.dirtyAllowances, key)
+	}
+
+	// commit dirty token holders
+	for key := range sd.dirtyTokenHolders {
+		var data []byte
+		if flags := sd.tokenHolders[key]; flags != 0 {
+			data = []byte{flags}
+		}
+		if err := sd.trie.TryUpdate(TokenHolderTrieKey(key.tokenID, key.holder), data); err != nil {
+			log.Errorf("commit token %d holder %s to trie error: %v", key.tokenID, key.holder.Hex(), err)
+		}
+		delete(sd.dirtyTokenHolders, key)
+	}
+
 	// commit current trie into triedb.
 	rootHash, err := sd.trie.Commit(func(leaf []byte, parent common.Hash) error {
 		account := NewAccountData()
@@ -733,6 +1125,8 @@ func (sd *StateDB) ClearJournalAndRefund() {
 	sd.snapshotID = 0
 	sd.snapshotSet = sd.snapshotSet[:0]
 	sd.refund = 0
+	sd.logs = make(map[common.Hash][]*vmtypes.Log)
+	sd.logSize = 0
 }
 
 func (sd *StateDB) String() string {
@@ -3,6 +3,7 @@ package state
 import (
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/math"
+	vmtypes "github.com/annchain/OG/vm/types"
 )
 
 // StateDB is an OVM database for full state querying.
@@ -17,6 +18,7 @@ type StateDBInterface interface {
 	// Retrieve the balance from the given address or 0 if object not found
 	GetBalance(common.Address) *math.BigInt
 	GetTokenBalance(common.Address, int32) *math.BigInt
+	CheckTokenTransfer(int32, common.Address, common.Address) error
 
 	GetNonce(common.Address) uint64
 	SetNonce(common.Address, uint64)
@@ -61,7 +63,7 @@ type StateDBInterface interface {
 	// Snapshot creates a new revision
 	Snapshot() int
 
-	//AddLog(*Log)
+	AddLog(*vmtypes.Log)
 	AddPreimage(common.Hash, []byte)
 
 	ForEachStorage(common.Address, func(common.Hash, common.Hash) bool)
@@ -8,6 +8,8 @@ import (
 	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/core/state"
 	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/types/tx_types"
+	vmtypes "github.com/annchain/OG/vm/types"
 )
 
 func newTestStateDB(t *testing.T) *state.StateDB {
@@ -106,3 +108,249 @@ func TestStateWorkFlow(t *testing.T) {
 	}
 
 }
+
+func TestStateLogs(t *testing.T) {
+	t.Parallel()
+
+	stdb := newTestStateDB(t)
+	addr := common.HexToAddress(testAddress)
+	txHash := crypto.Keccak256Hash([]byte("tx"))
+
+	stdb.Prepare(txHash, 1)
+	stdb.AddLog(&vmtypes.Log{Address: addr, Data: []byte{1}})
+	snapshot := stdb.Snapshot()
+	stdb.AddLog(&vmtypes.Log{Address: addr, Data: []byte{2}})
+	stdb.RevertToSnapshot(snapshot)
+	stdb.AddLog(&vmtypes.Log{Address: addr, Data: []byte{3}})
+
+	logs := stdb.GetLogs(txHash)
+	if len(logs) != 2 || logs[0].Data[0] != 1 || logs[1].Data[0] != 3 {
+		t.Fatalf("reverted log not dropped: %v", logs)
+	}
+	if logs[1].TxHash != txHash || logs[1].TxIndex != 1 || logs[1].Index != 1 {
+		t.Fatalf("log fields not set: %+v", logs[1])
+	}
+
+	stdb.ClearJournalAndRefund()
+	if len(stdb.GetLogs(txHash)) != 0 {
+		t.Fatalf("logs not cleared")
+	}
+}
+
+func TestStateDomain(t *testing.T) {
+	t.Parallel()
+
+	db := ogdb.NewMemDatabase()
+	stdb, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(db), common.Hash{})
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	owner := common.HexToAddress(testAddress)
+	other := common.HexToAddress("0x889e0b36dc6f2c06eb68d9c5f53434e4c42c8d19")
+
+	if err := stdb.RegisterDomain("alice", owner, 10, 100); err != nil {
+		t.Fatalf("register domain error: %v", err)
+	}
+	if err := stdb.RegisterDomain("alice", other, 50, 100); err == nil {
+		t.Fatalf("registered domain registered again")
+	}
+	if err := stdb.TransferDomain("alice", other, other, 50); err == nil {
+		t.Fatalf("domain transferred by others")
+	}
+	snapshot := stdb.Snapshot()
+	if err := stdb.TransferDomain("alice", owner, other, 50); err != nil {
+		t.Fatalf("transfer domain error: %v", err)
+	}
+	stdb.RevertToSnapshot(snapshot)
+	if err := stdb.RenewDomain("alice", owner, 50, 100, 160); err != nil {
+		t.Fatalf("renew domain error: %v", err)
+	}
+	if err := stdb.RenewDomain("alice", owner, 50, 100, 160); err == nil {
+		t.Fatalf("domain renewed for too long")
+	}
+
+	root, err := stdb.Commit()
+	if err != nil {
+		t.Fatalf("commit error: %v", err)
+	}
+	stdb.Database().TrieDB().Commit(root, false)
+	stdb, err = state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(db), root)
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	domain := stdb.GetDomain("alice")
+	if domain == nil || domain.Owner != owner || domain.Expiry != 210 {
+		t.Fatalf("domain not committed: %v", domain)
+	}
+	if !domain.Expired(211) {
+		t.Fatalf("domain should expire after 210")
+	}
+	if err := stdb.RegisterDomain("alice", other, 211, 100); err != nil {
+		t.Fatalf("register expired domain error: %v", err)
+	}
+	if stdb.GetDomain("bob") != nil {
+		t.Fatalf("unregistered domain found")
+	}
+}
+
+func TestStateTokenSymbol(t *testing.T) {
+	t.Parallel()
+
+	stdb := newTestStateDB(t)
+	issuer := common.HexToAddress(testAddress)
+
+	id, err := stdb.IssueToken(issuer, "bitcoin", "BTC", 8, "https://bitcoin.org", 0, true, math.NewBigInt(100))
+	if err != nil {
+		t.Fatalf("issue token error: %v", err)
+	}
+	if _, err = stdb.IssueToken(issuer, "bitcoin cash", "BTC", 8, "", 0, true, math.NewBigInt(100)); err == nil {
+		t.Fatalf("token symbol used twice")
+	}
+	token := stdb.GetTokenObjectBySymbol("BTC")
+	if token == nil || token.TokenID != id || token.Decimals != 8 || token.MetadataURI != "https://bitcoin.org" {
+		t.Fatalf("token not found by symbol: %v", token)
+	}
+
+	data, _ := token.Encode()
+	var decoded state.TokenObject
+	if err = decoded.Decode(data); err != nil {
+		t.Fatalf("decode token error: %v", err)
+	}
+	if decoded.Symbol != "BTC" || decoded.Decimals != 8 || decoded.MetadataURI != token.MetadataURI {
+		t.Fatalf("token metadata lost: %v", decoded)
+	}
+
+	// the tokens without metadata keep the encoding they had before it.
+	legacyID, err := stdb.IssueToken(issuer, "legacy", "", 0, "", 0, false, math.NewBigInt(100))
+	if err != nil {
+		t.Fatalf("issue token error: %v", err)
+	}
+	if data, _ = stdb.GetTokenObject(legacyID).Encode(); data[0] != 0x97 {
+		t.Fatalf("legacy token encoded with %x fields", data[0])
+	}
+
+	// the symbol index is stored in the trie.
+	root, err := stdb.Commit()
+	if err != nil {
+		t.Fatalf("commit error: %v", err)
+	}
+	reopened, err := state.NewStateDB(state.DefaultStateDBConfig(), stdb.Database(), root)
+	if err != nil {
+		t.Fatalf("reopen state error: %v", err)
+	}
+	if token = reopened.GetTokenObjectBySymbol("BTC"); token == nil || token.TokenID != id {
+		t.Fatalf("token not found by symbol after commit: %v", token)
+	}
+	if token = reopened.GetTokenObjectBySymbol(""); token != nil {
+		t.Fatalf("token found by empty symbol: %v", token)
+	}
+}
+
+func TestStateAllowance(t *testing.T) {
+	t.Parallel()
+
+	db := ogdb.NewMemDatabase()
+	stdb, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(db), common.Hash{})
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	owner := common.HexToAddress(testAddress)
+	spender := common.HexToAddress("0x889e0b36dc6f2c06eb68d9c5f53434e4c42c8d19")
+
+	if stdb.GetAllowance(owner, spender, 1).Sign() != 0 {
+		t.Fatalf("allowance exists before approval")
+	}
+	stdb.SetAllowance(owner, spender, 1, math.NewBigInt(100))
+	snapshot := stdb.Snapshot()
+	if err := stdb.SpendAllowance(owner, spender, 1, math.NewBigInt(30)); err != nil {
+		t.Fatalf("spend allowance error: %v", err)
+	}
+	stdb.RevertToSnapshot(snapshot)
+	if err := stdb.SpendAllowance(owner, spender, 1, math.NewBigInt(40)); err != nil {
+		t.Fatalf("spend allowance error: %v", err)
+	}
+	if err := stdb.SpendAllowance(owner, spender, 1, math.NewBigInt(61)); err == nil {
+		t.Fatalf("spent more than the allowance")
+	}
+	if stdb.GetAllowance(owner, spender, 0).Sign() != 0 || stdb.GetAllowance(spender, owner, 1).Sign() != 0 {
+		t.Fatalf("allowance leaked to another token or spender")
+	}
+
+	root, err := stdb.Commit()
+	if err != nil {
+		t.Fatalf("commit error: %v", err)
+	}
+	stdb.Database().TrieDB().Commit(root, false)
+	stdb, err = state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(db), root)
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	if allowance := stdb.GetAllowance(owner, spender, 1); allowance.GetInt64() != 60 {
+		t.Fatalf("allowance not committed: %s", allowance)
+	}
+}
+
+func TestStateTokenControls(t *testing.T) {
+	t.Parallel()
+
+	db := ogdb.NewMemDatabase()
+	stdb, err := state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(db), common.Hash{})
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	issuer := common.HexToAddress(testAddress)
+	holder := common.HexToAddress("0x889e0b36dc6f2c06eb68d9c5f53434e4c42c8d19")
+	other := common.HexToAddress("0x4b5d53f433b7e4a4f853a01e987f977497dda263")
+
+	controls := tx_types.TokenControlFreeze | tx_types.TokenControlWhitelist | tx_types.TokenControlPause
+	id, err := stdb.IssueToken(issuer, "bond", "BOND", 2, "", controls, false, math.NewBigInt(100))
+	if err != nil {
+		t.Fatalf("issue token error: %v", err)
+	}
+	if stdb.CheckTokenTransfer(id, issuer, holder) == nil {
+		t.Fatalf("transfer to a holder out of the whitelist")
+	}
+	stdb.SetTokenHolderFlag(id, holder, state.TokenHolderWhitelisted, true)
+	if err := stdb.CheckTokenTransfer(id, issuer, holder); err != nil {
+		t.Fatalf("transfer to whitelisted holder error: %v", err)
+	}
+	if stdb.CheckTokenTransfer(id, holder, other) == nil {
+		t.Fatalf("transfer to an address out of the whitelist")
+	}
+
+	snapshot := stdb.Snapshot()
+	stdb.SetTokenHolderFlag(id, holder, state.TokenHolderFrozen, true)
+	if stdb.CheckTokenTransfer(id, holder, issuer) == nil {
+		t.Fatalf("frozen holder transferred")
+	}
+	stdb.RevertToSnapshot(snapshot)
+	if err := stdb.PauseToken(id, true); err != nil {
+		t.Fatalf("pause token error: %v", err)
+	}
+	if stdb.CheckTokenTransfer(id, issuer, holder) == nil {
+		t.Fatalf("paused token transferred")
+	}
+
+	root, err := stdb.Commit()
+	if err != nil {
+		t.Fatalf("commit error: %v", err)
+	}
+	stdb.Database().TrieDB().Commit(root, false)
+	stdb, err = state.NewStateDB(state.DefaultStateDBConfig(), state.NewDatabase(db), root)
+	if err != nil {
+		t.Fatalf("create StateDB error: %v", err)
+	}
+	token := stdb.GetTokenObject(id)
+	if token == nil || token.Controls != controls || !token.Paused {
+		t.Fatalf("token controls not committed: %v", token)
+	}
+	if flags := stdb.GetTokenHolderFlags(id, holder); flags != state.TokenHolderWhitelisted {
+		t.Fatalf("holder flags not committed: %d", flags)
+	}
+	if err := stdb.PauseToken(id, false); err != nil {
+		t.Fatalf("unpause token error: %v", err)
+	}
+	if err := stdb.CheckTokenTransfer(id, holder, issuer); err != nil {
+		t.Fatalf("transfer after unpause error: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package state
+
+import (
+	"github.com/annchain/OG/common"
+)
+
+const TokenHolderKeyPrefix = "thID"
+
+// flags of a holder of a token with controls.
+const (
+	TokenHolderFrozen uint8 = 1 << iota
+	TokenHolderWhitelisted
+)
+
+// tokenHolderKey identifies a holder of one token.
+type tokenHolderKey struct {
+	tokenID int32
+	holder  common.Address
+}
+
+// TokenHolderTrieKey returns the trie key of the flags of the holder of the
+// token. The flags are stored as one byte.
+func TokenHolderTrieKey(tokenID int32, holder common.Address) []byte {
+	key := append([]byte(TokenHolderKeyPrefix), common.ByteInt32(tokenID)...)
+	return append(key, holder.ToBytes()...)
+}
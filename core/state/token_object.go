@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/math"
+	"github.com/tinylib/msgp/msgp"
 )
 
 const (
@@ -11,9 +12,17 @@ const (
 	MaxTokenSymbol = 5
 )
 
-//go:generate msgp
+const TokenSymbolKeyPrefix = "tsID"
 
-//msgp:tuple TokenObject
+// TokenSymbolTrieKey returns the trie key of the id of the token using the
+// symbol.
+func TokenSymbolTrieKey(symbol string) []byte {
+	return append([]byte(TokenSymbolKeyPrefix), symbol...)
+}
+
+//go:generate msgp -unexported
+
+//msgp:ignore TokenObject
 type TokenObject struct {
 	TokenID    int32
 	Name       string
@@ -24,10 +33,21 @@ type TokenObject struct {
 	Issues    []*math.BigInt
 	Destroyed bool
 
+	// Decimals is the number of digits of the fraction of the amounts
+	// shown to the users.
+	Decimals    uint8
+	MetadataURI string
+
+	// Controls is the set of tx_types.TokenControl flags chosen at the IPO.
+	// Paused stops all the transfers of the token.
+	Controls uint8
+	Paused   bool
+
 	db StateDBInterface
 }
 
-func NewTokenObject(tokenID int32, issuer common.Address, name, symbol string, reIssuable bool, fstIssue *math.BigInt, db StateDBInterface) *TokenObject {
+func NewTokenObject(tokenID int32, issuer common.Address, name, symbol string, decimals uint8, metadataURI string,
+	controls uint8, reIssuable bool, fstIssue *math.BigInt, db StateDBInterface) *TokenObject {
 
 	if len(name) > MaxTokenName {
 		name = name[:MaxTokenName]
@@ -42,6 +62,9 @@ func NewTokenObject(tokenID int32, issuer common.Address, name, symbol string, r
 	t.Issuer = issuer
 	t.Name = name
 	t.Symbol = symbol
+	t.Decimals = decimals
+	t.MetadataURI = metadataURI
+	t.Controls = controls
 	t.ReIssuable = reIssuable
 	t.Issues = []*math.BigInt{math.NewBigIntFromBigInt(fstIssue.Value)}
 	t.Destroyed = false
@@ -63,6 +86,15 @@ func (t *TokenObject) GetSymbol() string {
 	return t.Symbol
 }
 
+func (t *TokenObject) GetDecimals() uint8 {
+	return t.Decimals
+}
+
+// HasControl tells if the control was enabled on the token at the IPO.
+func (t *TokenObject) HasControl(control uint8) bool {
+	return t.Controls&control != 0
+}
+
 func (t *TokenObject) CanReIssue() bool {
 	return t.ReIssuable
 }
@@ -104,14 +136,95 @@ func (t *TokenObject) Destroy() {
 	t.Destroyed = true
 }
 
+// SetPaused pauses or unpauses the transfers of the token.
+func (t *TokenObject) SetPaused(paused bool) {
+	t.db.AppendJournal(&pauseChange{
+		tokenID:    t.TokenID,
+		prevPaused: t.Paused,
+	})
+	t.Paused = paused
+}
+
 func (t *TokenObject) CopyRaw(tObj *TokenObject) {
 	t.TokenID = tObj.TokenID
 	t.Name = tObj.Name
 	t.Symbol = tObj.Symbol
+	t.Decimals = tObj.Decimals
+	t.MetadataURI = tObj.MetadataURI
 	t.Issuer = tObj.Issuer
 	t.ReIssuable = tObj.ReIssuable
 	t.Issues = tObj.Issues
 	t.Destroyed = tObj.Destroyed
+	t.Controls = tObj.Controls
+	t.Paused = tObj.Paused
+}
+
+// A TokenObject is encoded as the tuple of legacyTokenObject if it has no
+// metadata nor controls, so that the state root of the tokens issued
+// before they existed is kept, as the one of metadataTokenObject otherwise.
+
+//msgp:tuple legacyTokenObject
+type legacyTokenObject struct {
+	TokenID    int32
+	Name       string
+	Symbol     string
+	Issuer     common.Address
+	ReIssuable bool
+	Issues     []*math.BigInt
+	Destroyed  bool
+}
+
+//msgp:tuple metadataTokenObject
+type metadataTokenObject struct {
+	TokenID     int32
+	Name        string
+	Symbol      string
+	Issuer      common.Address
+	ReIssuable  bool
+	Issues      []*math.BigInt
+	Destroyed   bool
+	Decimals    uint8
+	MetadataURI string
+	Controls    uint8
+	Paused      bool
+}
+
+const legacyTokenObjectFields = 7
+
+func (t *TokenObject) isLegacy() bool {
+	return t.Decimals == 0 && t.MetadataURI == "" && t.Controls == 0 && !t.Paused
+}
+
+func (t *TokenObject) MarshalMsg(b []byte) ([]byte, error) {
+	if t.isLegacy() {
+		l := &legacyTokenObject{t.TokenID, t.Name, t.Symbol, t.Issuer, t.ReIssuable, t.Issues, t.Destroyed}
+		return l.MarshalMsg(b)
+	}
+	m := &metadataTokenObject{t.TokenID, t.Name, t.Symbol, t.Issuer, t.ReIssuable, t.Issues, t.Destroyed,
+		t.Decimals, t.MetadataURI, t.Controls, t.Paused}
+	return m.MarshalMsg(b)
+}
+
+func (t *TokenObject) UnmarshalMsg(bts []byte) ([]byte, error) {
+	size, _, err := msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return nil, err
+	}
+	var m metadataTokenObject
+	if size == legacyTokenObjectFields {
+		var l legacyTokenObject
+		if bts, err = l.UnmarshalMsg(bts); err != nil {
+			return nil, err
+		}
+		m = metadataTokenObject{TokenID: l.TokenID, Name: l.Name, Symbol: l.Symbol, Issuer: l.Issuer,
+			ReIssuable: l.ReIssuable, Issues: l.Issues, Destroyed: l.Destroyed}
+	} else if bts, err = m.UnmarshalMsg(bts); err != nil {
+		return nil, err
+	}
+	t.TokenID, t.Name, t.Symbol, t.Issuer = m.TokenID, m.Name, m.Symbol, m.Issuer
+	t.ReIssuable, t.Issues, t.Destroyed = m.ReIssuable, m.Issues, m.Destroyed
+	t.Decimals, t.MetadataURI, t.Controls, t.Paused = m.Decimals, m.MetadataURI, m.Controls, m.Paused
+	return bts, nil
 }
 
 func (t *TokenObject) Encode() ([]byte, error) {
@@ -9,8 +9,8 @@ import (
 	"github.com/tinylib/msgp/msgp"
 )
 
-func TestMarshalUnmarshalTokenObject(t *testing.T) {
-	v := TokenObject{}
+func TestMarshalUnmarshallegacyTokenObject(t *testing.T) {
+	v := legacyTokenObject{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -32,8 +32,8 @@ func TestMarshalUnmarshalTokenObject(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgTokenObject(b *testing.B) {
-	v := TokenObject{}
+func BenchmarkMarshalMsglegacyTokenObject(b *testing.B) {
+	v := legacyTokenObject{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -41,8 +41,8 @@ func BenchmarkMarshalMsgTokenObject(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgTokenObject(b *testing.B) {
-	v := TokenObject{}
+func BenchmarkAppendMsglegacyTokenObject(b *testing.B) {
+	v := legacyTokenObject{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -53,8 +53,8 @@ func BenchmarkAppendMsgTokenObject(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalTokenObject(b *testing.B) {
-	v := TokenObject{}
+func BenchmarkUnmarshallegacyTokenObject(b *testing.B) {
+	v := legacyTokenObject{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -67,8 +67,8 @@ func BenchmarkUnmarshalTokenObject(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeTokenObject(t *testing.T) {
-	v := TokenObject{}
+func TestEncodeDecodelegacyTokenObject(t *testing.T) {
+	v := legacyTokenObject{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -77,7 +77,7 @@ func TestEncodeDecodeTokenObject(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := TokenObject{}
+	vn := legacyTokenObject{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -91,8 +91,8 @@ func TestEncodeDecodeTokenObject(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeTokenObject(b *testing.B) {
-	v := TokenObject{}
+func BenchmarkEncodelegacyTokenObject(b *testing.B) {
+	v := legacyTokenObject{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -105,8 +105,121 @@ func BenchmarkEncodeTokenObject(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeTokenObject(b *testing.B) {
-	v := TokenObject{}
+func BenchmarkDecodelegacyTokenObject(b *testing.B) {
+	v := legacyTokenObject{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalUnmarshalmetadataTokenObject(t *testing.T) {
+	v := metadataTokenObject{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgmetadataTokenObject(b *testing.B) {
+	v := metadataTokenObject{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgmetadataTokenObject(b *testing.B) {
+	v := metadataTokenObject{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalmetadataTokenObject(b *testing.B) {
+	v := metadataTokenObject{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodemetadataTokenObject(t *testing.T) {
+	v := metadataTokenObject{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
+	}
+
+	vn := metadataTokenObject{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodemetadataTokenObject(b *testing.B) {
+	v := metadataTokenObject{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodemetadataTokenObject(b *testing.B) {
+	v := metadataTokenObject{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -23,7 +23,9 @@ import (
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/goroutine"
 	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/metrics"
 	"github.com/annchain/OG/status"
+	"github.com/annchain/OG/types/token"
 	"github.com/annchain/OG/types/tx_types"
 
 	"math/rand"
@@ -92,6 +94,7 @@ type TxPool struct {
 
 	onNewTxReceived      map[channelName]chan types.Txi   // for notifications of new txs.
 	OnBatchConfirmed     []chan map[common.Hash]types.Txi // for notifications of confirmation.
+	OnSeqConfirmed       []chan *ConfirmBatch             // for notifications of the confirmed sequencer with its txs.
 	OnNewLatestSequencer []chan bool                      // for broadcasting new latest sequencer to record height
 
 	maxWeight     uint64
@@ -132,6 +135,7 @@ func NewTxPool(conf TxPoolConfig, d *Dag) *TxPool {
 	pool.onNewTxReceived = make(map[channelName]chan types.Txi)
 	pool.OnBatchConfirmed = []chan map[common.Hash]types.Txi{}
 	pool.confirmStatus = &ConfirmStatus{RefreshTime: time.Minute * time.Duration(conf.ConfirmStatusRefreshTime)}
+	registerPoolMetrics(pool)
 
 	return pool
 }
@@ -173,6 +177,8 @@ type txEnvelope struct {
 	txType   TxType
 	status   TxStatus
 	judgeNum int
+	// addedAt is when the tx is added to the pool.
+	addedAt time.Time
 }
 
 func newTxEnvelope(t TxType, status TxStatus, tx types.Txi, judgeNum int) *txEnvelope {
@@ -181,6 +187,7 @@ func newTxEnvelope(t TxType, status TxStatus, tx types.Txi, judgeNum int) *txEnv
 	te.status = status
 	te.tx = tx
 	te.judgeNum = judgeNum
+	te.addedAt = time.Now()
 
 	return te
 }
@@ -587,7 +594,7 @@ func (pool *TxPool) commit(tx types.Txi) error {
 		pool.pendings.Add(parent)
 		pool.txLookup.SwitchStatus(pHash, TxStatusPending)
 	}
-	if tx.GetType() != types.TxBaseTypeArchive {
+	if !tx_types.AnonymousArchive(tx) {
 		// add tx to pool
 		if pool.flows.Get(tx.Sender()) == nil {
 			pool.flows.ResetFlow(tx.Sender(), state.NewBalanceSet())
@@ -616,6 +623,150 @@ const (
 	TxQualityIsFatal
 )
 
+// domainTxQuality checks the domain action against the name at the next
+// sequencer height.
+func (pool *TxPool) domainTxQuality(tx *tx_types.ActionTx) TxQuality {
+	request := tx.GetDomainName()
+	if request == nil {
+		log.WithField("tx ", tx).Warn("domain request not found")
+		return TxQualityIsFatal
+	}
+	if err := request.Validate(tx.Action); err != nil {
+		log.WithField("tx ", tx).WithError(err).Warn("bad domain request")
+		return TxQualityIsFatal
+	}
+	height := pool.dag.LatestSequencer().Height + 1
+	if !pool.dag.chainConfig().IsDomainName(height) {
+		log.WithField("tx ", tx).Warn("domain names are not enabled yet")
+		return TxQualityIsFatal
+	}
+	domain := pool.dag.GetDomain(request.DomainName)
+	registered := domain != nil && !domain.Expired(height)
+	if tx.Action == tx_types.ActionRequestDomainName {
+		if registered {
+			log.WithField("tx ", tx).WithField("domain", domain).Warn("domain name is registered already")
+			return TxQualityIsFatal
+		}
+		return TxQualityIsGood
+	}
+	if !registered {
+		log.WithField("tx ", tx).Warn("domain name is not registered")
+		return TxQualityIsFatal
+	}
+	if domain.Owner != tx.Sender() {
+		log.WithField("tx ", tx).WithField("domain", domain).Warn("you are not the owner of the domain name")
+		return TxQualityIsFatal
+	}
+	if tx.Action == tx_types.ActionRenewDomainName && domain.Expiry+request.Period > height+tx_types.MaxDomainPeriod {
+		log.WithField("tx ", tx).WithField("domain", domain).Warn("domain name renewed for too long")
+		return TxQualityIsFatal
+	}
+	return TxQualityIsGood
+}
+
+// allowanceTxQuality checks the approve, revoke and transfer-from txs. A
+// transfer-from should be covered by both the allowance left to the sender
+// and the balance of the owner after the txs in pool.
+func (pool *TxPool) allowanceTxQuality(tx *tx_types.ActionTx) TxQuality {
+	if !pool.dag.chainConfig().IsAllowance(pool.dag.LatestSequencer().Height + 1) {
+		log.WithField("tx ", tx).Warn("allowances are not enabled yet")
+		return TxQualityIsFatal
+	}
+	var tokenID int32
+	if tx.Action == tx_types.ActionTxActionTransferFrom {
+		transfer := tx.GetTransferFrom()
+		if transfer == nil {
+			log.WithField("tx ", tx).Warn("transfer data not found")
+			return TxQualityIsFatal
+		}
+		if err := transfer.Validate(); err != nil {
+			log.WithField("tx ", tx).WithError(err).Warn("bad transfer")
+			return TxQualityIsFatal
+		}
+		tokenID = transfer.TokenId
+	} else {
+		approval := tx.GetApproval()
+		if approval == nil {
+			log.WithField("tx ", tx).Warn("approval data not found")
+			return TxQualityIsFatal
+		}
+		if err := approval.Validate(tx.Action); err != nil {
+			log.WithField("tx ", tx).WithError(err).Warn("bad approval")
+			return TxQualityIsFatal
+		}
+		if approval.Spender == tx.Sender() {
+			log.WithField("tx ", tx).Warn("approve to yourself")
+			return TxQualityIsFatal
+		}
+		tokenID = approval.TokenId
+	}
+	if tokenID != token.OGTokenID {
+		tk := pool.dag.GetToken(tokenID)
+		if tk == nil || tk.Destroyed {
+			log.WithField("tx ", tx).Warn("token not found or destroyed")
+			return TxQualityIsFatal
+		}
+	}
+	if tx.Action != tx_types.ActionTxActionTransferFrom {
+		return TxQualityIsGood
+	}
+
+	transfer := tx.GetTransferFrom()
+	if err := pool.dag.CheckTokenTransfer(transfer.TokenId, transfer.Owner, transfer.To); err != nil {
+		log.WithField("tx", tx).WithError(err).Trace("fatal tx, transfer refused by token controls")
+		return TxQualityIsFatal
+	}
+	allowance := pool.flows.GetAllowanceState(tx.Sender(), transfer.Owner, transfer.TokenId)
+	if allowance == nil {
+		allowance = NewBalanceState(pool.dag.GetAllowance(transfer.Owner, tx.Sender(), transfer.TokenId))
+	}
+	if transfer.Value.Value.Cmp(allowance.OriginBalance().Value) > 0 {
+		log.WithField("tx", tx).Tracef("fatal tx, tx's value larger than allowance")
+		return TxQualityIsFatal
+	}
+	totalspent := math.NewBigInt(0)
+	if totalspent.Value.Add(allowance.spent.Value, transfer.Value.Value).Cmp(
+		allowance.originBalance.Value) > 0 {
+		log.WithField("tx", tx).Tracef("bad tx, total spent larger than allowance")
+		return TxQualityIsBad
+	}
+
+	stateOwner := pool.flows.GetBalanceState(transfer.Owner, transfer.TokenId)
+	if stateOwner == nil {
+		stateOwner = NewBalanceState(pool.dag.GetBalance(transfer.Owner, transfer.TokenId))
+	}
+	if transfer.Value.Value.Cmp(stateOwner.OriginBalance().Value) > 0 {
+		log.WithField("tx", tx).Tracef("fatal tx, tx's value larger than owner's balance")
+		return TxQualityIsFatal
+	}
+	totalspent = math.NewBigInt(0)
+	if totalspent.Value.Add(stateOwner.spent.Value, transfer.Value.Value).Cmp(
+		stateOwner.originBalance.Value) > 0 {
+		log.WithField("tx", tx).Tracef("bad tx, owner's total spent larger than balance")
+		return TxQualityIsBad
+	}
+	return TxQualityIsGood
+}
+
+// tokenControlTxQuality checks that the sender of the token control tx is
+// the issuer of a token with the control enabled.
+func (pool *TxPool) tokenControlTxQuality(tx *tx_types.ActionTx) TxQuality {
+	control := tx.GetTokenControl()
+	if control == nil {
+		log.WithField("tx ", tx).Warn("token control data not found")
+		return TxQualityIsFatal
+	}
+	if err := control.Validate(tx.Action); err != nil {
+		log.WithField("tx ", tx).WithError(err).Warn("bad token control")
+		return TxQualityIsFatal
+	}
+	if err := checkTokenControl(pool.dag.GetToken(control.TokenId), tx); err != nil {
+		log.WithField("tx ", tx).WithError(err).Warn("token control refused")
+		return TxQualityIsFatal
+	}
+	return TxQualityIsGood
+}
+
 func (pool *TxPool) isBadTx(tx types.Txi) TxQuality {
 	// check if the tx's parents exists and if is badtx
 	for _, parentHash := range tx.Parents() {
@@ -634,7 +785,12 @@ func (pool *TxPool) isBadTx(tx types.Txi) TxQuality {
 		}
 	}
 
-	if tx.GetType() == types.TxBaseTypeArchive {
+	if archive, ok := tx.(*tx_types.Archive); ok && archive.Extended() &&
+		!pool.dag.chainConfig().IsSignedArchive(pool.dag.LatestSequencer().Height+1) {
+		log.WithField("tx", tx).Warn("signed archives are not enabled yet")
+		return TxQualityIsFatal
+	}
+	if tx_types.AnonymousArchive(tx) {
 		return TxQualityIsGood
 	}
 
@@ -685,6 +841,14 @@ func (pool *TxPool) isBadTx(tx types.Txi) TxQuality {
 			stateFrom = NewBalanceState(originBalance)
 		}
 
+		// the transfers refused by the controls of the token are fatal.
+		if tx.TokenId != token.OGTokenID && tx.Value.Value.Sign() != 0 {
+			if err := pool.dag.CheckTokenTransfer(tx.TokenId, tx.Sender(), tx.To); err != nil {
+				log.WithField("tx", tx).WithError(err).Trace("fatal tx, transfer refused by token controls")
+				return TxQualityIsFatal
+			}
+		}
+
 		// if tx's value is larger than its balance, return fatal.
 		if tx.Value.Value.Cmp(stateFrom.OriginBalance().Value) > 0 {
 			log.WithField("tx", tx).Tracef("fatal tx, tx's value larger than balance")
@@ -701,8 +865,20 @@ func (pool *TxPool) isBadTx(tx types.Txi) TxQuality {
 		}
 	case *tx_types.ActionTx:
 		if tx.Action == tx_types.ActionTxActionIPO {
-			//actionData := tx.ActionData.(*tx_types.PublicOffering)
+			actionData := tx.ActionData.(*tx_types.PublicOffering)
 			//actionData.TokenId = pool.dag.GetLatestTokenId()
+			if actionData.HasMetadata() && !pool.dag.chainConfig().IsTokenMetadata(pool.dag.LatestSequencer().Height+1) {
+				log.WithField("tx ", tx).Warn("token metadata is not enabled yet")
+				return TxQualityIsFatal
+			}
+			if err := actionData.ValidateMetadata(); err != nil {
+				log.WithField("tx ", tx).WithError(err).Warn("bad token metadata")
+				return TxQualityIsFatal
+			}
+			if token := pool.dag.GetTokenBySymbol(actionData.TokenSymbol); token != nil {
+				log.WithField("tx ", tx).WithField("token ", token).Warn("token symbol is used already")
+				return TxQualityIsFatal
+			}
 		}
 		if tx.Action == tx_types.ActionTxActionSPO {
 			actionData := tx.ActionData.(*tx_types.PublicOffering)
@@ -748,6 +924,47 @@ func (pool *TxPool) isBadTx(tx types.Txi) TxQuality {
 				return TxQualityIsFatal
 			}
 		}
+		if tx.IsDomain() {
+			if quality := pool.domainTxQuality(tx); quality != TxQualityIsGood {
+				return quality
+			}
+		}
+		if tx.IsAllowance() {
+			if quality := pool.allowanceTxQuality(tx); quality != TxQualityIsGood {
+				return quality
+			}
+		}
+		if tx.IsTokenControl() {
+			if quality := pool.tokenControlTxQuality(tx); quality != TxQualityIsGood {
+				return quality
+			}
+		}
+		if tx.Action == tx_types.ActionTxActionGovernanceProposal {
+			proposal := tx.GetGovernanceProposal()
+			if proposal == nil {
+				log.WithField("tx ", tx).Warn("governance proposal data not found")
+				return TxQualityIsFatal
+			}
+			if err := proposal.ValidateValue(); err != nil {
+				log.WithField("tx ", tx).WithError(err).Warn("bad governance proposal")
+				return TxQualityIsFatal
+			}
+		}
+		if tx.Action == tx_types.ActionTxActionGovernanceVote {
+			vote := tx.GetGovernanceVote()
+			if vote == nil {
+				log.WithField("tx ", tx).Warn("governance vote data not found")
+				return TxQualityIsFatal
+			}
+			proposalTx, ok := pool.get(vote.ProposalHash).(*tx_types.ActionTx)
+			if !ok {
+				proposalTx, ok = pool.dag.GetTx(vote.ProposalHash).(*tx_types.ActionTx)
+			}
+			if !ok || proposalTx.GetGovernanceProposal() == nil {
+				log.WithField("tx ", tx).Warn("voted governance proposal not found")
+				return TxQualityIsFatal
+			}
+		}
 	case *tx_types.Campaign:
 		// TODO
 	case *tx_types.TermChange:
@@ -823,6 +1040,15 @@ func (pool *TxPool) confirm(seq *tx_types.Sequencer) error {
 			pool.confirmStatus.AddConfirm(normalTx.GetConfirm())
 		}
 	}
+	if metrics.Enabled {
+		for hash := range elders {
+			if txEnv := pool.txLookup.GetEnvelope(hash); txEnv != nil {
+				txConfirmTimer.UpdateSince(txEnv.addedAt)
+			}
+		}
+		seqConfirmMeter.Mark(1)
+		txConfirmMeter.Mark(int64(len(batch.Txs)))
+	}
 
 	// solve conflicts of txs in pool
 	pool.solveConflicts(batch)
@@ -841,6 +1067,12 @@ func (pool *TxPool) confirm(seq *tx_types.Sequencer) error {
 		}
 		c <- elders
 	}
+	for _, c := range pool.OnSeqConfirmed {
+		if status.NodeStopped {
+			break
+		}
+		c <- batch
+	}
 	for _, c := range pool.OnNewLatestSequencer {
 		if status.NodeStopped {
 			break
@@ -940,7 +1172,7 @@ func (pool *TxPool) verifyConfirmBatch(seq *tx_types.Sequencer, elders map[commo
 			cTxs = append(cTxs, txi)
 		}
 
-		if txi.GetType() == types.TxBaseTypeArchive {
+		if tx_types.AnonymousArchive(txi) {
 			continue
 		}
 
@@ -978,6 +1210,17 @@ func (pool *TxPool) verifyConfirmBatch(seq *tx_types.Sequencer, elders map[commo
 				batch[tx.Sender()] = batchFrom
 			}
 			batchFrom.TxList.put(tx)
+			// a transfer-from spends the balance of the owner.
+			if transfer := transferFromOf(tx); transfer != nil {
+				batchOwner, okOwner := batch[transfer.Owner]
+				if !okOwner {
+					batchOwner = &BatchDetail{}
+					batchOwner.TxList = NewTxList()
+					batchOwner.Neg = make(map[int32]*math.BigInt)
+					batch[transfer.Owner] = batchOwner
+				}
+				batchOwner.AddNeg(transfer.TokenId, transfer.Value)
+			}
 		}
 	}
 	// verify balance and nonce
@@ -35,13 +35,13 @@ func newTestTxPool(t *testing.T) (*core.TxPool, *core.Dag, *tx_types.Sequencer,
 		TxValidTime:   7,
 	}
 	db := ogdb.NewMemDatabase()
-	dag, errnew := core.NewDag(core.DagConfig{}, state.DefaultStateDBConfig(), db, nil)
+	dag, errnew := core.NewDag(core.DagConfig{GenesisPath: testGenesisPath}, state.DefaultStateDBConfig(), db, nil)
 	if errnew != nil {
 		t.Fatalf("new a dag failed with error: %v", errnew)
 	}
 	pool := core.NewTxPool(txpoolconfig, dag)
 
-	genesis, balance := core.DefaultGenesis("genesis.json")
+	genesis, balance := core.DefaultGenesis(testGenesisPath)
 	err := dag.Init(genesis, balance)
 	if err != nil {
 		t.Fatalf("init dag failed with error: %v", err)
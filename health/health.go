@@ -0,0 +1,98 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health reports the liveness and readiness of the node to the
+// orchestrators from named checks.
+package health
+
+import (
+	"sync"
+	"time"
+
+	"github.com/annchain/OG/metrics"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckResult is the status of a check.
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the status of all the checks of a Checker, ok if all are ok.
+type Report struct {
+	Status string                  `json:"status"`
+	Time   time.Time               `json:"time"`
+	Checks map[string]*CheckResult `json:"checks"`
+}
+
+// Healthy tells if all the checks are ok.
+func (r *Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+// Checker runs a set of named checks, each being a metrics.Healthcheck.
+type Checker struct {
+	mu       sync.Mutex
+	registry metrics.Registry
+	names    []string
+}
+
+func NewChecker() *Checker {
+	return &Checker{registry: metrics.NewRegistry()}
+}
+
+// Register adds a check, it is unhealthy when f returns an error.
+func (c *Checker) Register(name string, f func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hc := metrics.NewStandardHealthcheck(func(h metrics.Healthcheck) {
+		if err := f(); err != nil {
+			h.Unhealthy(err)
+		} else {
+			h.Healthy()
+		}
+	})
+	if c.registry.Register(name, hc) == nil {
+		c.names = append(c.names, name)
+	}
+}
+
+// Check runs all the checks and returns their report.
+func (c *Checker) Check() *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.registry.RunHealthchecks()
+	report := &Report{
+		Status: StatusOK,
+		Time:   time.Now(),
+		Checks: make(map[string]*CheckResult),
+	}
+	for _, name := range c.names {
+		result := &CheckResult{Status: StatusOK}
+		if err := c.registry.Get(name).(metrics.Healthcheck).Error(); err != nil {
+			result.Status = StatusFail
+			result.Error = err.Error()
+			report.Status = StatusFail
+		}
+		report.Checks[name] = result
+	}
+	return report
+}
@@ -0,0 +1,47 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package health
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChecker(t *testing.T) {
+	c := NewChecker()
+	peers := 0
+	c.Register("sync", func() error { return nil })
+	c.Register("peers", func() error {
+		if peers == 0 {
+			return errors.New("no peer")
+		}
+		return nil
+	})
+
+	report := c.Check()
+	if report.Healthy() || report.Checks["sync"].Status != StatusOK {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if r := report.Checks["peers"]; r.Status != StatusFail || r.Error != "no peer" {
+		t.Fatalf("unexpected peers check %+v", r)
+	}
+
+	peers = 1
+	if report := c.Check(); !report.Healthy() || report.Checks["peers"].Error != "" {
+		t.Fatalf("expected healthy, got %+v", report)
+	}
+	if report := NewChecker().Check(); !report.Healthy() || len(report.Checks) != 0 {
+		t.Fatalf("expected empty checker healthy, got %+v", report)
+	}
+}
@@ -1,5 +1,7 @@
 package metrics
 
+import "sync"
+
 // Healthchecks hold an error value describing an arbitrary up/down status.
 type Healthcheck interface {
 	Check()
@@ -14,7 +16,13 @@ func NewHealthcheck(f func(Healthcheck)) Healthcheck {
 	if !Enabled {
 		return NilHealthcheck{}
 	}
-	return &StandardHealthcheck{nil, f}
+	return NewStandardHealthcheck(f)
+}
+
+// NewStandardHealthcheck constructs a new StandardHealthcheck even if the
+// metrics are disabled, for the checks which are always needed.
+func NewStandardHealthcheck(f func(Healthcheck)) *StandardHealthcheck {
+	return &StandardHealthcheck{f: f}
 }
 
 // NilHealthcheck is a no-op.
@@ -35,6 +43,7 @@ func (NilHealthcheck) Unhealthy(error) {}
 // StandardHealthcheck is the standard implementation of a Healthcheck and
 // stores the status and a function to call to update the status.
 type StandardHealthcheck struct {
+	mu  sync.RWMutex
 	err error
 	f   func(Healthcheck)
 }
@@ -46,16 +55,22 @@ func (h *StandardHealthcheck) Check() {
 
 // Error returns the healthcheck's status, which will be nil if it is healthy.
 func (h *StandardHealthcheck) Error() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 	return h.err
 }
 
 // Healthy marks the healthcheck as healthy.
 func (h *StandardHealthcheck) Healthy() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.err = nil
 }
 
 // Unhealthy marks the healthcheck as unhealthy.  The error is stored and
 // may be retrieved by the Error method.
 func (h *StandardHealthcheck) Unhealthy(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.err = err
 }
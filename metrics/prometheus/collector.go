@@ -0,0 +1,177 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/annchain/OG/metrics"
+)
+
+const (
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+	typeSummary = "summary"
+)
+
+var (
+	// quantiles of the histograms and timers.
+	quantiles = []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+	// percentiles of the resetting timers, matching quantiles.
+	percentiles = []float64{50, 75, 95, 99, 99.9}
+)
+
+// collector writes metrics in the Prometheus text exposition format.
+type collector struct {
+	buff *bytes.Buffer
+}
+
+func newCollector() *collector {
+	return &collector{buff: new(bytes.Buffer)}
+}
+
+// metricName turns a metric name like "p2p/InboundTraffic" into a valid
+// Prometheus name like "p2p_InboundTraffic".
+func metricName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		valid := c == '_' || c == ':' ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9' && i > 0)
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func (c *collector) writeType(name string, typ string) {
+	fmt.Fprintf(c.buff, "# TYPE %s %s\n", name, typ)
+}
+
+func (c *collector) writeValue(name string, v float64) {
+	fmt.Fprintf(c.buff, "%s %s\n", name, formatValue(v))
+}
+
+func (c *collector) addCounter(name string, v float64) {
+	name = metricName(name)
+	c.writeType(name, typeCounter)
+	c.writeValue(name, v)
+}
+
+func (c *collector) addGauge(name string, v float64) {
+	name = metricName(name)
+	c.writeType(name, typeGauge)
+	c.writeValue(name, v)
+}
+
+func (c *collector) addSummary(name string, values []float64, count int64, sum float64) {
+	name = metricName(name)
+	c.writeType(name, typeSummary)
+	for i, q := range quantiles {
+		fmt.Fprintf(c.buff, "%s{quantile=\"%s\"} %s\n", name, formatValue(q), formatValue(values[i]))
+	}
+	c.writeValue(name+"_sum", sum)
+	c.writeValue(name+"_count", float64(count))
+}
+
+// addMetric writes a metric of the registry by its type. The durations of
+// the timers are exported in seconds.
+func (c *collector) addMetric(name string, i interface{}) {
+	seconds := float64(time.Second)
+	switch m := i.(type) {
+	case metrics.Counter:
+		c.addCounter(name, float64(m.Count()))
+	case metrics.Gauge:
+		c.addGauge(name, float64(m.Value()))
+	case metrics.GaugeFloat64:
+		c.addGauge(name, m.Value())
+	case metrics.Meter:
+		c.addCounter(name, float64(m.Count()))
+	case metrics.Histogram:
+		s := m.Snapshot()
+		c.addSummary(name, s.Percentiles(quantiles), s.Count(), float64(s.Sum()))
+	case metrics.Timer:
+		s := m.Snapshot()
+		values := s.Percentiles(quantiles)
+		for i := range values {
+			values[i] /= seconds
+		}
+		c.addSummary(name+"_seconds", values, s.Count(), float64(s.Sum())/seconds)
+	case metrics.ResettingTimer:
+		s := m.Snapshot()
+		all := s.Values()
+		if len(all) == 0 {
+			return
+		}
+		ps := s.Percentiles(percentiles)
+		values := make([]float64, len(ps))
+		var sum float64
+		for i, p := range ps {
+			values[i] = float64(p) / seconds
+		}
+		for _, v := range all {
+			sum += float64(v)
+		}
+		c.addSummary(name+"_seconds", values, int64(len(all)), sum/seconds)
+	}
+}
+
+// addBenchmark writes a value of the benchmarks of a performance reporter,
+// the values which are not numbers are skipped.
+func (c *collector) addBenchmark(name string, v interface{}, counter bool) {
+	var f float64
+	switch n := v.(type) {
+	case int:
+		f = float64(n)
+	case int32:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case uint:
+		f = float64(n)
+	case uint32:
+		f = float64(n)
+	case uint64:
+		f = float64(n)
+	case float32:
+		f = float64(n)
+	case float64:
+		f = n
+	case bool:
+		if n {
+			f = 1
+		}
+	default:
+		return
+	}
+	if counter {
+		c.addCounter(name, f)
+	} else {
+		c.addGauge(name, f)
+	}
+}
+
+// benchmarkName returns the name of the benchmark key of a reporter.
+func benchmarkName(reporter string, key string) string {
+	return "perf_" + strings.ToLower(reporter) + "_" + key
+}
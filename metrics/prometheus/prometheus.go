@@ -0,0 +1,73 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prometheus exposes a metrics registry and the benchmarks of the
+// performance reporters to Prometheus.
+package prometheus
+
+import (
+	"net/http"
+	"runtime"
+	"sort"
+
+	"github.com/annchain/OG/metrics"
+	"github.com/annchain/OG/performance"
+)
+
+// Handler returns an http handler writing the metrics of reg and the
+// benchmarks of the reporters of monitor, which may be nil, in the
+// Prometheus text format.
+func Handler(reg metrics.Registry, monitor *performance.PerformanceMonitor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := newCollector()
+
+		var names []string
+		all := make(map[string]interface{})
+		reg.Each(func(name string, i interface{}) {
+			names = append(names, name)
+			all[name] = i
+		})
+		sort.Strings(names)
+		for _, name := range names {
+			c.addMetric(name, all[name])
+		}
+		c.addGauge("perf_goroutines", float64(runtime.NumGoroutine()))
+		if monitor != nil {
+			for _, reporter := range monitor.Reporters() {
+				addReporter(c, reporter)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write(c.buff.Bytes())
+	})
+}
+
+func addReporter(c *collector, reporter performance.PerformanceReporter) {
+	counters := make(map[string]bool)
+	if cr, ok := reporter.(performance.CounterReporter); ok {
+		for _, key := range cr.Counters() {
+			counters[key] = true
+		}
+	}
+	benchmarks := reporter.GetBenchmarks()
+	var keys []string
+	for key := range benchmarks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c.addBenchmark(benchmarkName(reporter.Name(), key), benchmarks[key], counters[key])
+	}
+}
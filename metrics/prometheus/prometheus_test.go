@@ -0,0 +1,92 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package prometheus
+
+import (
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annchain/OG/metrics"
+	"github.com/annchain/OG/performance"
+)
+
+func TestMain(m *testing.M) {
+	metrics.Enabled = true
+	os.Exit(m.Run())
+}
+
+type testReporter struct{}
+
+func (testReporter) Name() string { return "TestReporter" }
+
+func (testReporter) GetBenchmarks() map[string]interface{} {
+	return map[string]interface{}{
+		"queue":    3,
+		"received": uint64(7),
+		"name":     "skipped",
+	}
+}
+
+func (testReporter) Counters() []string { return []string{"received"} }
+
+func TestHandler(t *testing.T) {
+	reg := metrics.NewRegistry()
+	metrics.NewRegisteredCounter("p2p/InboundConnects", reg).Inc(2)
+	metrics.NewRegisteredGauge("core/txpool/tips", reg).Update(5)
+	metrics.NewRegisteredMeter("og/messages/in/MessageTypePing", reg).Mark(100)
+	timer := metrics.NewRegisteredTimer("core/dag/push", reg)
+	timer.Update(time.Second)
+	timer.Update(3 * time.Second)
+
+	monitor := &performance.PerformanceMonitor{}
+	monitor.Register(testReporter{})
+
+	w := httptest.NewRecorder()
+	Handler(reg, monitor).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+
+	for _, line := range []string{
+		"# TYPE p2p_InboundConnects counter\np2p_InboundConnects 2\n",
+		"# TYPE core_txpool_tips gauge\ncore_txpool_tips 5\n",
+		"# TYPE og_messages_in_MessageTypePing counter\nog_messages_in_MessageTypePing 100\n",
+		"# TYPE core_dag_push_seconds summary\n",
+		"core_dag_push_seconds{quantile=\"0.5\"} 2\n",
+		"core_dag_push_seconds_sum 4\ncore_dag_push_seconds_count 2\n",
+		"# TYPE perf_testreporter_queue gauge\nperf_testreporter_queue 3\n",
+		"# TYPE perf_testreporter_received counter\nperf_testreporter_received 7\n",
+		"# TYPE perf_goroutines gauge\n",
+	} {
+		if !strings.Contains(body, line) {
+			t.Fatalf("missing %q in\n%s", line, body)
+		}
+	}
+	if strings.Contains(body, "perf_testreporter_name") {
+		t.Fatalf("non numeric benchmark exported")
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"eth/fetcher/prop/announces/in": "eth_fetcher_prop_announces_in",
+		"peer_ab.cd-1_knownMsg":         "peer_ab_cd_1_knownMsg",
+		"1st":                           "_st",
+	} {
+		if got := metricName(name); got != expected {
+			t.Fatalf("%s: expected %s, got %s", name, expected, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package mylog
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MainModule is the module name of the logrus standard logger.
+const MainModule = "main"
+
+var (
+	modulesMu sync.RWMutex
+	modules   = map[string]*logrus.Logger{}
+)
+
+// moduleName returns the module of a logger from its output file,
+// "og_p2p" is the module "p2p".
+func moduleName(outputFile string) string {
+	name := strings.TrimSuffix(outputFile, ".log")
+	return strings.TrimPrefix(name, "og_")
+}
+
+func registerModule(name string, logger *logrus.Logger) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	modules[name] = logger
+}
+
+// ModuleLevels returns the log level of each module.
+func ModuleLevels() map[string]string {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+	levels := map[string]string{
+		MainModule: logrus.GetLevel().String(),
+	}
+	for name, logger := range modules {
+		levels[name] = logger.GetLevel().String()
+	}
+	return levels
+}
+
+// Modules returns the sorted names of the modules.
+func Modules() []string {
+	var names []string
+	for name := range ModuleLevels() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetModuleLevel changes the log level of a module at runtime.
+func SetModuleLevel(module string, level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if module == MainModule {
+		logrus.SetLevel(lvl)
+		return nil
+	}
+	modulesMu.RLock()
+	logger, ok := modules[module]
+	modulesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown log module %q", module)
+	}
+	logger.SetLevel(lvl)
+	return nil
+}
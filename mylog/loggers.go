@@ -71,5 +71,6 @@ func InitLogger(logger *logrus.Logger, logdir string, outputFile string) *logrus
 		ExitFunc:     logger.ExitFunc,
 		ReportCaller: logger.ReportCaller,
 	}
+	registerModule(moduleName(outputFile), newLogger)
 	return newLogger
 }
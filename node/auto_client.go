@@ -439,6 +439,9 @@ func (c *AutoClient) doRawTx(txi types.Txi) bool {
 	} else if txi.GetType() == types.TxBaseTypeTermChange {
 		cp := txi.(*tx_types.TermChange)
 		cp.Issuer = &me.Address
+	} else if txi.GetType() == types.TxBaseAction {
+		tx := txi.(*tx_types.ActionTx)
+		tx.From = &me.Address
 	}
 	s := crypto.NewSigner(me.PublicKey.Type)
 	txi.GetBase().Signature = s.Sign(me.PrivateKey, txi.SignatureTargets()).Bytes
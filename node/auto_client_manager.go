@@ -32,6 +32,12 @@ type AutoClientManager struct {
 	wg                     sync.WaitGroup
 	RegisterReceiver       func(c chan types.Txi)
 	delegate               *Delegate
+
+	// paused by the operator, the clients are not resumed when the node
+	// gets up to date.
+	pauseMu  sync.Mutex
+	paused   bool
+	upToDate bool
 }
 
 func (m *AutoClientManager) Init(accountIndices []int, delegate *Delegate, coinBaseAccount *account.SampleAccount) {
@@ -132,6 +138,36 @@ func (m *AutoClientManager) SetTxIntervalUs(interval int) {
 	}
 }
 
+// Pause stops all the clients from generating txs until Resume.
+func (m *AutoClientManager) Pause() {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	m.paused = true
+	for _, client := range m.Clients {
+		client.Pause()
+	}
+}
+
+// Resume restarts the clients paused by Pause, once the node is up to date.
+func (m *AutoClientManager) Resume() {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	m.paused = false
+	if !m.upToDate {
+		return
+	}
+	for _, client := range m.Clients {
+		client.Resume()
+	}
+}
+
+// Paused tells if the clients are paused by the operator.
+func (m *AutoClientManager) Paused() bool {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	return m.paused
+}
+
 func (m *AutoClientManager) Start() {
 	for _, client := range m.Clients {
 		m.wg.Add(1)
@@ -155,15 +191,18 @@ func (c *AutoClientManager) eventLoop() {
 	for {
 		select {
 		case v := <-c.UpToDateEventListener:
+			c.pauseMu.Lock()
+			c.upToDate = v
 			for _, client := range c.Clients {
 				if !v {
 					logrus.Info("pausing client")
 					client.Pause()
-				} else {
+				} else if !c.paused {
 					logrus.Info("resuming client")
 					client.Resume()
 				}
 			}
+			c.pauseMu.Unlock()
 		//case <-time.After(time.Second * 20):
 		//continue
 		case <-c.quit:
@@ -19,6 +19,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/annchain/OG/auth"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/io"
 	"github.com/annchain/OG/p2p"
@@ -154,6 +155,39 @@ func NewP2PServer(privKey *ecdsa.PrivateKey, isBootNode bool) *p2p.Server {
 	return &p2p.Server{Config: p2pConfig}
 }
 
+// newAuthenticator reads the authentication of the rpc and websocket
+// servers, nil if disabled.
+func newAuthenticator() (*auth.Authenticator, error) {
+	if !viper.GetBool("auth.enabled") {
+		return nil, nil
+	}
+	conf := auth.Config{
+		JWTSecret:      viper.GetString("auth.jwt_secret"),
+		JWTQuota:       viper.GetInt("auth.jwt_quota"),
+		AnonymousQuota: viper.GetInt("auth.anonymous_quota"),
+		AllowedOrigins: viper.GetStringSlice("auth.allowed_origins"),
+	}
+	var err error
+	conf.AnonymousGroups, err = auth.ParseGroups(viper.GetStringSlice("auth.anonymous_groups"))
+	if err != nil {
+		return nil, err
+	}
+	for name := range viper.GetStringMap("auth.keys") {
+		prefix := "auth.keys." + name + "."
+		groups, err := auth.ParseGroups(viper.GetStringSlice(prefix + "groups"))
+		if err != nil {
+			return nil, err
+		}
+		conf.Keys = append(conf.Keys, auth.Key{
+			Name:   name,
+			Key:    viper.GetString(prefix + "key"),
+			Groups: groups,
+			Quota:  viper.GetInt(prefix + "quota"),
+		})
+	}
+	return auth.NewAuthenticator(conf)
+}
+
 func GetHostName() string {
 	// Kubernetes first
 	if v, ok := os.LookupEnv("HOSTNAME"); ok {
@@ -0,0 +1,95 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/annchain/OG/consensus/annsensus"
+	"github.com/annchain/OG/health"
+	"github.com/annchain/OG/og"
+	"github.com/annchain/OG/og/syncer"
+	"github.com/annchain/OG/ogdb"
+	"github.com/spf13/viper"
+)
+
+// newLiveChecker checks that the node is running, the orchestrators restart
+// it otherwise.
+func newLiveChecker(org *og.Og) *health.Checker {
+	live := health.NewChecker()
+	live.Register("dag", func() error {
+		if org.Dag.LatestSequencer() == nil {
+			return fmt.Errorf("no sequencer in dag")
+		}
+		return nil
+	})
+	return live
+}
+
+// newReadyChecker checks that the node is synced and connected, the
+// orchestrators route no requests to it otherwise.
+func newReadyChecker(org *og.Og, hub *og.Hub, syncManager *syncer.SyncManager, annSensus *annsensus.AnnSensus) *health.Checker {
+	minPeers := viper.GetInt("health.min_peers")
+	maxSeqAge := time.Duration(viper.GetInt("health.max_sequencer_age_s")) * time.Second
+	maxBftIdle := time.Duration(viper.GetInt("health.max_bft_idle_s")) * time.Second
+	dbErrorWindow := time.Duration(viper.GetInt("health.db_error_window_s")) * time.Second
+
+	ready := health.NewChecker()
+	ready.Register("sync", func() error {
+		if syncManager.Status != syncer.SyncStatusIncremental {
+			return fmt.Errorf("catching up with the best peer")
+		}
+		return nil
+	})
+	ready.Register("peers", func() error {
+		if n := hub.PeerCount(); n < minPeers {
+			return fmt.Errorf("%d peers connected, %d required", n, minPeers)
+		}
+		return nil
+	})
+	ready.Register("sequencer", func() error {
+		seq := org.Dag.LatestSequencer()
+		if seq == nil {
+			return fmt.Errorf("no sequencer in dag")
+		}
+		age := time.Since(time.Unix(0, seq.Timestamp*int64(time.Millisecond)))
+		if maxSeqAge > 0 && age > maxSeqAge {
+			return fmt.Errorf("last sequencer %d confirmed %s ago", seq.Height, age.Round(time.Second))
+		}
+		return nil
+	})
+	if annSensus != nil {
+		ready.Register("bft", func() error {
+			partner, lastDecision := annSensus.BftParticipation()
+			if !partner {
+				return nil
+			}
+			if idle := time.Since(lastDecision); maxBftIdle > 0 && idle > maxBftIdle {
+				return fmt.Errorf("no bft decision for %s", idle.Round(time.Second))
+			}
+			return nil
+		})
+	}
+	if db, ok := org.Db.(ogdb.WriteErrorReporter); ok {
+		ready.Register("db", func() error {
+			at, err := db.LastWriteError()
+			if err != nil && time.Since(at) < dbErrorWindow {
+				return fmt.Errorf("write failed %s ago: %v", time.Since(at).Round(time.Second), err)
+			}
+			return nil
+		})
+	}
+	return ready
+}
@@ -20,7 +20,9 @@ import (
 	"github.com/annchain/OG/account"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/encryption"
+	"github.com/annchain/OG/common/goroutine"
 	"github.com/annchain/OG/common/io"
+	"github.com/annchain/OG/metrics"
 	"github.com/annchain/OG/p2p/ioperformance"
 	"github.com/annchain/OG/rpc"
 	"github.com/annchain/OG/status"
@@ -104,6 +106,15 @@ func NewNode() *Node {
 	if viper.GetBool("hub.disable_feedback") == true {
 		feedBack = og.NormalMode
 	}
+	defaultHubConfig := og.DefaultHubConfig()
+	banThreshold := viper.GetInt("hub.ban_threshold")
+	if banThreshold == 0 {
+		banThreshold = defaultHubConfig.BanThreshold
+	}
+	banDuration := viper.GetInt("hub.ban_duration_seconds")
+	if banDuration == 0 {
+		banDuration = defaultHubConfig.BanDurationSeconds
+	}
 	hub := og.NewHub(&og.HubConfig{
 		OutgoingBufferSize:            viper.GetInt("hub.outgoing_buffer_size"),
 		IncomingBufferSize:            viper.GetInt("hub.incoming_buffer_size"),
@@ -112,7 +123,15 @@ func NewNode() *Node {
 		MaxPeers:                      maxPeers,
 		BroadCastMode:                 feedBack,
 		DisableEncryptGossip:          viper.GetBool("hub.disable_encrypt_gossip"),
+		BanThreshold:                  banThreshold,
+		BanDurationSeconds:            banDuration,
+		PeerRateLimit: og.RateLimit{
+			Rate:  viper.GetFloat64("hub.rate_limit.peer_rate"),
+			Burst: viper.GetInt("hub.rate_limit.peer_burst"),
+		},
+		MessageRateLimits: getMessageRateLimits(),
 	})
+	hub.SetBanStore(org.Db)
 
 	// let og be the status source of hub to provide chain info to other peers
 	hub.StatusDataProvider = org
@@ -172,6 +191,7 @@ func NewNode() *Node {
 	txFormatVerifier := &og.TxFormatVerifier{
 		MaxTxHash:    common.HexToHash(viper.GetString("max_tx_hash")),
 		MaxMinedHash: common.HexToHash(viper.GetString("max_mined_hash")),
+		ChainConfig:  org.ChainConfig,
 	}
 	if txFormatVerifier.MaxMinedHash == common.HexToHash("0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF") {
 		txFormatVerifier.NoVerifyMindHash = true
@@ -271,6 +291,7 @@ func NewNode() *Node {
 		syncManager.IncrementalSyncer.NewLatestSequencerCh)
 	m.NewSequencerHandler = syncManager.IncrementalSyncer
 	m.NewTxsHandler = syncManager.IncrementalSyncer
+	m.NewTxHashesHandler = syncManager.IncrementalSyncer
 	m.NewTxHandler = syncManager.IncrementalSyncer
 	m.FetchByHashResponseHandler = syncManager.IncrementalSyncer
 	m.CampaignHandler = syncManager.IncrementalSyncer
@@ -285,9 +306,19 @@ func NewNode() *Node {
 	syncManager.IncrementalSyncer.OnNewTxiReceived = append(syncManager.IncrementalSyncer.OnNewTxiReceived, txBuffer.ReceivedNewTxsChan)
 
 	txBuffer.Syncer = syncManager.IncrementalSyncer
+	txBuffer.OnBadTx = append(txBuffer.OnBadTx, func(tx types.Txi) {
+		hub.AdjustPeerScore(syncManager.IncrementalSyncer.GetTxSource(tx.GetTxHash()), og.ScoreBadTx, "bad tx")
+	})
 	announcer := syncer.NewAnnouncer(m)
+	if viper.GetBool("hub.compact_relay.enabled") {
+		announcer.EnableCompactRelay(syncer.CompactRelayConfig{
+			AnnounceBatchSize:      viper.GetInt("hub.compact_relay.announce_batch_size"),
+			AnnounceIntervalMillis: viper.GetInt("hub.compact_relay.announce_interval_ms"),
+		})
+	}
 	txBuffer.Announcer = announcer
 	n.Components = append(n.Components, syncManager)
+	n.Components = append(n.Components, announcer)
 
 	messageHandler32 := &og.IncomingMessageHandlerOG02{
 		Hub: hub,
@@ -316,6 +347,7 @@ func NewNode() *Node {
 		DebugNodeId:        viper.GetInt("debug.node_id"),
 		GraphVerifier:      graphVerifier,
 		GetStateRoot:       org.TxPool,
+		ChainConfig:        org.ChainConfig,
 	}
 
 	// TODO: move to (embeded) client. It is not part of OG
@@ -390,6 +422,7 @@ func NewNode() *Node {
 			VerifyTermChange: annSensus.VerifyTermChange,
 			VerifySequencer:  annSensus.VerifySequencer,
 			VerifyCampaign:   annSensus.VerifyCampaign,
+			VerifyGovernance: annSensus.VerifyGovernance,
 		}
 		txBuffer.Verifiers = append(txBuffer.Verifiers, consensusVerifier)
 
@@ -397,6 +430,18 @@ func NewNode() *Node {
 			autoClientManager.JudgeNonce, txCreator, org.Dag, txBuffer.SelfGeneratedNewTxChan,
 			syncManager.IncrementalSyncer.HandleNewTxi, hub)
 		logrus.Info("my pk ", annSensus.MyAccount.PublicKey.String())
+		annSensus.SetMaxTxHash(txFormatVerifier.GetMaxTxHash())
+		annSensus.OnConsensusParamsChanged = append(annSensus.OnConsensusParamsChanged, func(params annsensus.ConsensusParams) {
+			if params.MaxTxHash.Empty() {
+				return
+			}
+			txFormatVerifier.SetMaxTxHash(params.MaxTxHash)
+			txCreator.SetMaxTxHash(params.MaxTxHash)
+		})
+		annSensus.GovernanceDB = org.Db
+		if err := annSensus.LoadGovernance(); err != nil {
+			logrus.WithError(err).Fatal("load governance state error")
+		}
 		hub.SetEncryptionKey(&annSensus.MyAccount.PrivateKey)
 
 		syncManager.OnUpToDate = append(syncManager.OnUpToDate, annSensus.UpdateEvent)
@@ -445,10 +490,17 @@ func NewNode() *Node {
 	n.Components = append(n.Components, m)
 	org.Manager = m
 
+	authenticator, err := newAuthenticator()
+	if err != nil {
+		logrus.WithError(err).Fatal("bad auth config")
+	}
+
 	// rpc server
 	var rpcServer *rpc.RpcServer
 	if viper.GetBool("rpc.enabled") {
 		rpcServer = rpc.NewRpcServer(viper.GetString("rpc.port"))
+		rpcServer.TLSCertFile = viper.GetString("rpc.tls_cert")
+		rpcServer.TLSKeyFile = viper.GetString("rpc.tls_key")
 		n.Components = append(n.Components, rpcServer)
 	}
 
@@ -464,16 +516,30 @@ func NewNode() *Node {
 		if !disableConsensus {
 			rpcServer.C.AnnSensus = annSensus
 		}
+		rpcServer.C.LiveChecker = newLiveChecker(org)
+		if disableConsensus {
+			rpcServer.C.ReadyChecker = newReadyChecker(org, hub, syncManager, nil)
+		} else {
+			rpcServer.C.ReadyChecker = newReadyChecker(org, hub, syncManager, annSensus)
+		}
 
 		rpcServer.C.PerformanceMonitor = pm
+		rpcServer.C.Auth = authenticator
+		rpcServer.C.DumpDir = viper.GetString("admin.dump_dir")
 	}
 
 	// websocket server
 	if viper.GetBool("websocket.enabled") {
 		wsServer := wserver.NewServer(fmt.Sprintf(":%d", viper.GetInt("websocket.port")))
+		wsServer.TLSCertFile = viper.GetString("websocket.tls_cert")
+		wsServer.TLSKeyFile = viper.GetString("websocket.tls_key")
+		if authenticator != nil {
+			wsServer.SetAuthenticator(authenticator)
+		}
 		n.Components = append(n.Components, wsServer)
 		org.TxPool.RegisterOnNewTxReceived(wsServer.NewTxReceivedChan, "wsServer.NewTxReceivedChan", true)
-		org.TxPool.OnBatchConfirmed = append(org.TxPool.OnBatchConfirmed, wsServer.BatchConfirmedChan)
+		org.TxPool.OnSeqConfirmed = append(org.TxPool.OnSeqConfirmed, wsServer.SeqConfirmedChan)
+		wsServer.GetTxLogs = org.Dag.GetTxLogs
 		pm.Register(wsServer)
 	}
 
@@ -500,6 +566,11 @@ func NewNode() *Node {
 	pm.Register(txCounter)
 
 	n.Components = append(n.Components, pm)
+	if metrics.Enabled {
+		goroutine.New(func() {
+			metrics.CollectProcessMetrics(3 * time.Second)
+		})
+	}
 	ioPerformance := ioperformance.Init()
 	n.Components = append(n.Components, ioPerformance)
 	return n
@@ -517,6 +588,24 @@ func StringArrayToIntArray(arr []string) []int {
 	return a
 }
 
+// getMessageRateLimits reads the per message type limits configured as
+// [hub.rate_limit.message_types.<MessageType>] with rate and burst.
+func getMessageRateLimits() map[p2p_message.MessageType]og.RateLimit {
+	limits := make(map[p2p_message.MessageType]og.RateLimit)
+	for name := range viper.GetStringMap("hub.rate_limit.message_types") {
+		messageType, ok := og.MessageTypeByName(name)
+		if !ok {
+			logrus.WithField("type", name).Fatal("unknown message type in rate limit config")
+		}
+		key := "hub.rate_limit.message_types." + name
+		limits[messageType] = og.RateLimit{
+			Rate:  viper.GetFloat64(key + ".rate"),
+			Burst: viper.GetInt(key + ".burst"),
+		}
+	}
+	return limits
+}
+
 func (n *Node) Start() {
 	for _, component := range n.Components {
 		logrus.Infof("Starting %s", component.Name())
@@ -545,6 +634,7 @@ func SetupCallbacks(m *og.MessageRouter, hub *og.Hub) {
 	hub.CallbackRegistry[p2p_message.MessageTypeFetchByHashResponse] = m.RouteFetchByHashResponse
 	hub.CallbackRegistry[p2p_message.MessageTypeNewTx] = m.RouteNewTx
 	hub.CallbackRegistry[p2p_message.MessageTypeNewTxs] = m.RouteNewTxs
+	hub.CallbackRegistry[p2p_message.MessageTypeNewTxHashes] = m.RouteNewTxHashes
 	hub.CallbackRegistry[p2p_message.MessageTypeNewSequencer] = m.RouteNewSequencer
 	hub.CallbackRegistry[p2p_message.MessageTypeGetMsg] = m.RouteGetMsg
 	hub.CallbackRegistry[p2p_message.MessageTypeSequencerHeader] = m.RouteSequencerHeader
@@ -24,6 +24,7 @@ import (
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/og/downloader"
 	"github.com/annchain/OG/og/fetcher"
+	"github.com/annchain/OG/ogdb"
 	"github.com/annchain/OG/p2p"
 	log "github.com/sirupsen/logrus"
 
@@ -81,6 +82,8 @@ type Hub struct {
 	encryptionPrivKey    *crypto.PrivateKey
 	encryptionPubKey     *crypto.PublicKey
 	disableEncryptGossip bool
+	reputation           *PeerReputation
+	rateLimiter          *MessageRateLimiter
 }
 
 func (h *Hub) GetBenchmarks() map[string]interface{} {
@@ -122,6 +125,10 @@ type HubConfig struct {
 	MaxPeers                      int
 	BroadCastMode                 uint8
 	DisableEncryptGossip          bool
+	BanThreshold                  int
+	BanDurationSeconds            int
+	PeerRateLimit                 RateLimit                             // limit of all messages from a peer
+	MessageRateLimits             map[p2p_message.MessageType]RateLimit // limits of each message type from a peer
 }
 
 const (
@@ -138,6 +145,8 @@ func DefaultHubConfig() HubConfig {
 		MaxPeers:                      50,
 		BroadCastMode:                 NormalMode,
 		DisableEncryptGossip:          false,
+		BanThreshold:                  -100,
+		BanDurationSeconds:            3600,
 	}
 	return config
 }
@@ -157,6 +166,8 @@ func (h *Hub) Init(config *HubConfig) {
 	h.CallbackRegistryOG02 = make(map[p2p_message.MessageType]func(*p2PMessage))
 	h.broadCastMode = config.BroadCastMode
 	h.disableEncryptGossip = config.DisableEncryptGossip
+	h.reputation = NewPeerReputation(config.BanThreshold, time.Second*time.Duration(config.BanDurationSeconds))
+	h.rateLimiter = NewMessageRateLimiter(config.PeerRateLimit, config.MessageRateLimits)
 }
 
 func NewHub(config *HubConfig) *Hub {
@@ -212,6 +223,45 @@ func (h *Hub) SetEncryptionKey(priv *crypto.PrivateKey) {
 	h.encryptionPubKey = priv.PublicKey()
 }
 
+// SetBanStore sets the db where peer bans are persisted.
+func (h *Hub) SetBanStore(db ogdb.Database) {
+	h.reputation.SetDB(db)
+}
+
+// AdjustPeerScore changes the score of the peer. The peer is disconnected
+// and banned if its score drops below the threshold.
+func (h *Hub) AdjustPeerScore(peerId string, delta int, reason string) {
+	if peerId == "" {
+		return
+	}
+	if delta < 0 {
+		log.WithField("peer", peerId).WithField("delta", delta).WithField("reason", reason).Debug("peer misbehaved")
+	}
+	if banned := h.reputation.Adjust(peerId, delta); banned {
+		log.WithField("peer", peerId).WithField("reason", reason).Warn("peer banned")
+		h.RemovePeer(peerId)
+	}
+}
+
+// BanPeer disconnects the peer and bans it for the given duration.
+func (h *Hub) BanPeer(peerId string, duration time.Duration) {
+	h.reputation.Ban(peerId, duration)
+	h.RemovePeer(peerId)
+}
+
+func (h *Hub) UnbanPeer(peerId string) {
+	h.reputation.Unban(peerId)
+}
+
+func (h *Hub) BannedPeers() []BannedPeer {
+	return h.reputation.BannedPeers()
+}
+
+// RateLimitStats returns the counters of messages dropped by rate limiting.
+func (h *Hub) RateLimitStats() *RateLimitStats {
+	return h.rateLimiter.Stats()
+}
+
 func (h *Hub) newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
 	return newPeer(version, p, rw)
 }
@@ -223,6 +273,10 @@ func (h *Hub) handle(p *peer) error {
 	if h.peers.Len() >= h.maxPeers && !p.Peer.Info().Network.Trusted {
 		return p2p.DiscTooManyPeers
 	}
+	if h.reputation.IsBanned(p.id) {
+		log.WithField("id", p.id).Debug("reject banned peer")
+		return p2p.DiscUselessPeer
+	}
 	log.WithField("name", p.Name()).WithField("id", p.id).Info("OG peer connected")
 	// Execute the og handshake
 	statusData := h.StatusDataProvider.GetCurrentNodeStatus()
@@ -240,6 +294,7 @@ func (h *Hub) handle(p *peer) error {
 		log.WithError(err).Error("og peer registration failed")
 		return err
 	}
+	ogPeersGauge.Update(int64(h.peers.Len()))
 
 	log.Debug("register peer locally")
 
@@ -272,12 +327,18 @@ func (h *Hub) handleMsg(p *peer) error {
 		return err
 	}
 	if msg.Size > ProtocolMaxMsgSize {
+		h.AdjustPeerScore(p.id, ScoreOversizeMsg, "message too large")
 		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
 	}
 	defer msg.Discard()
 	// Handle the message depending on its contents
 	data, err := msg.GetPayLoad()
+	markMessageBytes(true, p2p_message.MessageType(msg.Code), int(msg.Size))
 	m := p2PMessage{messageType: p2p_message.MessageType(msg.Code), data: data, sourceID: p.id, version: p.version}
+	if m.messageType != p2p_message.StatusMsg && !h.rateLimiter.Allow(p.id, m.messageType) {
+		msgLog.WithField("type", m.messageType).WithField("from", p.String()).Debug("rate limited, discard")
+		return nil
+	}
 	//log.Debug("start handle p2p message ",p2pMsg.messageType)
 	switch m.messageType {
 	case p2p_message.StatusMsg:
@@ -297,6 +358,7 @@ func (h *Hub) handleMsg(p *peer) error {
 			m.disableEncrypt = true
 		}
 		if !m.checkRequiredSize() {
+			h.AdjustPeerScore(p.id, ScoreInvalidMsg, "invalid message size")
 			return fmt.Errorf("msg len error")
 		}
 		m.calculateHash()
@@ -319,6 +381,7 @@ func (h *Hub) handleMsg(p *peer) error {
 						// TODO delete
 						log.Errorf("unmarshal  error msg: %x", m.data)
 						log.WithField("type ", m.messageType).WithError(err).Warn("handle msg error")
+						h.AdjustPeerScore(p.id, ScoreInvalidMsg, "undecodable message")
 						return err
 					}
 					msgLog.WithField("type", m.messageType.String()).WithField("from", p.String()).WithField(
@@ -345,6 +408,7 @@ func (h *Hub) handleMsg(p *peer) error {
 		err = m.Unmarshal()
 		if err != nil {
 			log.WithField("type ", m.messageType).WithError(err).Warn("handle msg error")
+			h.AdjustPeerScore(p.id, ScoreInvalidMsg, "undecodable message")
 			return err
 		}
 		m.calculateHash()
@@ -378,6 +442,7 @@ func (h *Hub) handleMsg(p *peer) error {
 	msgLog.WithField("type", m.messageType.String()).WithField("from", p.String()).WithField(
 		"Message", m.message.String()).WithField("len ", len(m.data)).Debug("received a message")
 
+	h.reputation.Adjust(p.id, ScoreGoodMsg)
 	h.incoming <- &m
 	return nil
 }
@@ -393,10 +458,12 @@ func (h *Hub) RemovePeer(id string) {
 
 	// Unregister the peer from the downloader (should already done) and OG peer set
 	h.Downloader.UnregisterPeer(id)
+	h.rateLimiter.RemovePeer(id)
 	if err := h.peers.Unregister(id); err != nil {
 		log.WithField("peer", "id").WithError(err).
 			Error("Peer removal failed")
 	}
+	ogPeersGauge.Update(int64(h.peers.Len()))
 	// Hard disconnect at the networking layer
 	if peer != nil {
 		peer.Peer.Disconnect(p2p.DiscUselessPeer)
@@ -461,6 +528,10 @@ func (h *Hub) loopSend() {
 				h.broadcastMessage(m)
 			case sendingTypeBroacastWithLink:
 				h.broadcastMessageWithLink(m)
+			case sendingTypeAnnounce:
+				h.announceTxHashes(m)
+			case sendingTypeBroadcastToLegacy:
+				h.broadcastMessageToLegacy(m)
 
 			default:
 				log.WithField("type ", m.sendingType).Error("unknown sending  type")
@@ -584,6 +655,29 @@ func (h *Hub) SendToAnynomous(messageType p2p_message.MessageType, msg p2p_messa
 
 }
 
+//AnnounceTxHashes announces tx hashes to the peers which don't know them yet.
+//peers will fetch the bodies they miss.
+func (h *Hub) AnnounceTxHashes(hashes common.Hashes) {
+	msgOut := &p2PMessage{messageType: p2p_message.MessageTypeNewTxHashes, sendingType: sendingTypeAnnounce,
+		message: &p2p_message.MessageNewTxHashes{Hashes: &hashes}}
+	msgLog.WithField("count", len(hashes)).Trace("announce tx hashes")
+	h.outgoing <- msgOut
+}
+
+//BroadcastMessageToLegacy broadcasts to the peers below og/03 which can't
+//receive tx hash announcements.
+func (h *Hub) BroadcastMessageToLegacy(messageType p2p_message.MessageType, msg p2p_message.Message) {
+	msgOut := &p2PMessage{messageType: messageType, message: msg, sendingType: sendingTypeBroadcastToLegacy}
+	err := msgOut.Marshal()
+	if err != nil {
+		msgLog.WithError(err).WithField("type", messageType).Warn("broadcast message init msg  err")
+		return
+	}
+	msgOut.calculateHash()
+	msgLog.WithField("size ", len(msgOut.data)).WithField("type", messageType).Debug("broadcast message to legacy peers")
+	h.outgoing <- msgOut
+}
+
 func (h *Hub) RelayMessage(msgOut *p2PMessage) {
 	msgLog.WithField("size", len(msgOut.data)).WithField("type", msgOut.messageType).Trace("relay message")
 	h.outgoing <- msgOut
@@ -703,6 +797,48 @@ func (h *Hub) broadcastMessageWithLink(msg *p2PMessage) {
 	return
 }
 
+func (h *Hub) broadcastMessageToLegacy(msg *p2PMessage) {
+	peers := h.peers.PeersWithoutMsg(*msg.hash, msg.messageType)
+	for _, peer := range peers {
+		if peer.version < OG03 {
+			peer.AsyncSendMessage(msg)
+		}
+	}
+}
+
+//announceTxHashes sends each peer only the hashes it hasn't seen. peers
+//below og/03 get the txs pushed by BroadcastMessageToLegacy instead.
+func (h *Hub) announceTxHashes(msg *p2PMessage) {
+	hashes := msg.message.(*p2p_message.MessageNewTxHashes).Hashes
+	for _, peer := range h.peers.Peers() {
+		if peer.version < OG03 {
+			continue
+		}
+		var unknown common.Hashes
+		for _, hash := range *hashes {
+			if peer.knownMsg.Contains(p2p_message.NewMsgKey(p2p_message.MessageTypeNewTx, hash)) ||
+				peer.knownMsg.Contains(p2p_message.NewMsgKey(p2p_message.MessageTypeControl, hash)) {
+				continue
+			}
+			unknown = append(unknown, hash)
+		}
+		if len(unknown) == 0 {
+			continue
+		}
+		pMsg := &p2PMessage{messageType: p2p_message.MessageTypeNewTxHashes,
+			message: &p2p_message.MessageNewTxHashes{Hashes: &unknown}}
+		if err := pMsg.Marshal(); err != nil {
+			msgLog.WithError(err).Warn("marshal tx hashes error")
+			return
+		}
+		pMsg.calculateHash()
+		for _, hash := range unknown {
+			peer.MarkMessage(p2p_message.MessageTypeNewTx, hash)
+		}
+		peer.AsyncSendMessage(pMsg)
+	}
+}
+
 /*
 func (h *Hub) broadcastMessageWithFilter(msg *p2PMessage) {
 	newSeq := msg.Message.(*p2p_message.MessageNewSequencer)
@@ -837,6 +973,11 @@ func (h *Hub) receiveMessage(msg *p2PMessage) {
 	}
 }
 
+// PeerCount returns the number of the og peers connected.
+func (h *Hub) PeerCount() int {
+	return h.peers.Len()
+}
+
 // NodeInfo retrieves some protocol metadata about the running host node.
 func (h *Hub) PeersInfo() []*PeerInfo {
 	peers := h.peers.Peers()
@@ -844,7 +985,9 @@ func (h *Hub) PeersInfo() []*PeerInfo {
 	infos := make([]*PeerInfo, 0, len(peers))
 	for _, peer := range peers {
 		if peer != nil {
-			infos = append(infos, peer.Info())
+			info := peer.Info()
+			info.Score = h.reputation.Score(peer.id)
+			infos = append(infos, info)
 		}
 	}
 	return infos
@@ -27,6 +27,7 @@ type MessageRouter struct {
 	FetchByHashResponseHandler          FetchByHashResponseHandler
 	NewTxHandler                        NewTxHandler
 	NewTxsHandler                       NewTxsHandler
+	NewTxHashesHandler                  NewTxHashesHandler
 	NewSequencerHandler                 NewSequencerHandler
 	GetMsgHandler                       GetMsgHandler
 	ControlMsgHandler                   ControlMsgHandler
@@ -88,6 +89,10 @@ type NewTxsHandler interface {
 	HandleNewTxs(newTxs *p2p_message.MessageNewTxs, peerId string)
 }
 
+type NewTxHashesHandler interface {
+	HandleNewTxHashes(msg *p2p_message.MessageNewTxHashes, peerId string)
+}
+
 type NewSequencerHandler interface {
 	HandleNewSequencer(msg *p2p_message.MessageNewSequencer, peerId string)
 }
@@ -206,6 +211,10 @@ func (m *MessageRouter) RouteNewTxs(msg *p2PMessage) {
 	m.NewTxsHandler.HandleNewTxs(msg.message.(*p2p_message.MessageNewTxs), msg.sourceID)
 }
 
+func (m *MessageRouter) RouteNewTxHashes(msg *p2PMessage) {
+	m.NewTxHashesHandler.HandleNewTxHashes(msg.message.(*p2p_message.MessageNewTxHashes), msg.sourceID)
+}
+
 func (m *MessageRouter) RouteNewSequencer(msg *p2PMessage) {
 	m.NewSequencerHandler.HandleNewSequencer(msg.message.(*p2p_message.MessageNewSequencer), msg.sourceID)
 }
@@ -327,3 +336,14 @@ func (m *MessageRouter) SendToPeer(peerId string, messageType p2p_message.Messag
 		msgLog.WithError(err).Warn("send failed")
 	}
 }
+
+// AnnounceTxHashes announces tx hashes to peers for compact relay
+func (m *MessageRouter) AnnounceTxHashes(hashes common.Hashes) {
+	m.Hub.AnnounceTxHashes(hashes)
+}
+
+// BroadcastMessageToLegacy pushes the message to the peers which don't
+// support compact relay
+func (m *MessageRouter) BroadcastMessageToLegacy(messageType p2p_message.MessageType, message p2p_message.Message) {
+	m.Hub.BroadcastMessageToLegacy(messageType, message)
+}
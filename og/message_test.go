@@ -19,6 +19,7 @@ import (
 	"github.com/annchain/OG/types/p2p_message"
 	"github.com/annchain/OG/types/tx_types"
 	"github.com/annchain/gcache"
+	"github.com/deckarep/golang-set"
 	"github.com/sirupsen/logrus"
 	"testing"
 	"time"
@@ -81,3 +82,41 @@ func TestP2PMessage_Unmarshal(t *testing.T) {
 	//fmt.Println(p2pMsg)
 	fmt.Println(p2pMsg.message)
 }
+
+func TestHub_AnnounceTxHashes(t *testing.T) {
+	known := common.RandomHash()
+	unknown := common.RandomHash()
+	newTestPeer := func(id string, version int) *peer {
+		return &peer{id: id, version: version, knownMsg: mapset.NewSet(), queuedMsg: make(chan []*p2PMessage, 1)}
+	}
+	pa, pb, legacy := newTestPeer("a", OG03), newTestPeer("b", OG03), newTestPeer("legacy", OG02)
+	pb.MarkMessage(p2p_message.MessageTypeNewTx, known)
+	hub := &Hub{peers: newPeerSet()}
+	hub.peers.peers[pa.id] = pa
+	hub.peers.peers[pb.id] = pb
+	hub.peers.peers[legacy.id] = legacy
+
+	hashes := common.Hashes{known, unknown}
+	hub.announceTxHashes(&p2PMessage{messageType: p2p_message.MessageTypeNewTxHashes,
+		message: &p2p_message.MessageNewTxHashes{Hashes: &hashes}})
+
+	expected := map[*peer]int{pa: 2, pb: 1}
+	for p, count := range expected {
+		msgs := <-p.queuedMsg
+		got := msgs[0].message.(*p2p_message.MessageNewTxHashes)
+		if len(*got.Hashes) != count {
+			t.Fatalf("peer %s expected %d hashes, got %d", p.id, count, len(*got.Hashes))
+		}
+	}
+
+	if len(legacy.queuedMsg) != 0 {
+		t.Fatal("hashes announced to a peer below og/03")
+	}
+
+	// everything is known now, nothing should be sent again
+	hub.announceTxHashes(&p2PMessage{messageType: p2p_message.MessageTypeNewTxHashes,
+		message: &p2p_message.MessageNewTxHashes{Hashes: &hashes}})
+	if len(pa.queuedMsg) != 0 || len(pb.queuedMsg) != 0 {
+		t.Fatal("hashes announced twice")
+	}
+}
@@ -30,16 +30,17 @@ import (
 const (
 	OG01 = 01
 	OG02 = 02
+	OG03 = 03
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "og"
 
 // ProtocolVersions are the supported versions of the og protocol (first is primary).
-var ProtocolVersions = []uint32{OG02, OG01}
+var ProtocolVersions = []uint32{OG03, OG02, OG01}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []p2p_message.MessageType{p2p_message.MessageTypeOg02Length, p2p_message.MessageTypeOg01Length}
+var ProtocolLengths = []p2p_message.MessageType{p2p_message.MessageTypeOg03Length, p2p_message.MessageTypeOg02Length, p2p_message.MessageTypeOg01Length}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -51,6 +52,8 @@ const (
 	sendingTypeMulticastToSource
 	sendingTypeBroacastWithFilter
 	sendingTypeBroacastWithLink
+	sendingTypeAnnounce
+	sendingTypeBroadcastToLegacy
 )
 
 type p2PMessage struct {
@@ -115,6 +118,8 @@ func (m *p2PMessage) calculateHash() {
 		data = append(data, []byte(m.sourceID+"sq")...)
 	case p2p_message.MessageTypeGetMsg:
 		data = append(data, []byte(m.sourceID+"gm")...)
+	case p2p_message.MessageTypeNewTxHashes:
+		data = append(data, []byte(m.sourceID+"nh")...)
 	default:
 	}
 	h := sha256.New()
@@ -181,6 +186,12 @@ func (p *p2PMessage) GetMarkHashes() common.Hashes {
 	case p2p_message.MessageTypeTxsResponse:
 		msg := p.message.(*p2p_message.MessageTxsResponse)
 		return msg.Hashes()
+	case p2p_message.MessageTypeNewTxHashes:
+		msg := p.message.(*p2p_message.MessageNewTxHashes)
+		if msg.Hashes == nil {
+			return nil
+		}
+		return *msg.Hashes
 	default:
 		return nil
 	}
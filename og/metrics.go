@@ -0,0 +1,34 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package og
+
+import (
+	"github.com/annchain/OG/metrics"
+	"github.com/annchain/OG/types/p2p_message"
+)
+
+var ogPeersGauge = metrics.NewRegisteredGauge("og/peers", nil)
+
+// markMessageBytes meters the bytes of the messages of a type received or
+// sent.
+func markMessageBytes(in bool, msgType p2p_message.MessageType, size int) {
+	if !metrics.Enabled {
+		return
+	}
+	name := "og/messages/out/" + msgType.String()
+	if in {
+		name = "og/messages/in/" + msgType.String()
+	}
+	metrics.GetOrRegisterMeter(name, nil).Mark(int64(size))
+}
@@ -15,6 +15,7 @@ package og
 
 import (
 	"fmt"
+	"math/big"
 	"os"
 	"sync"
 	"time"
@@ -32,6 +33,7 @@ import (
 	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/core/state"
 	"github.com/annchain/OG/ogdb"
+	"github.com/annchain/OG/vm/eth/params"
 	"github.com/latifrons/soccerdash"
 )
 
@@ -42,6 +44,7 @@ type Og struct {
 	TxPool   *core.TxPool
 	Manager  *MessageRouter
 	TxBuffer *TxBuffer
+	Db       ogdb.Database
 
 	NewLatestSequencerCh chan bool //for broadcasting new latest sequencer to record height
 
@@ -50,7 +53,9 @@ type Og struct {
 	//reporter *soccerdash.Reporter
 
 	NetworkId uint64
-	quit      chan bool
+	// ChainConfig gives the fork heights of the chain.
+	ChainConfig *params.ChainConfig
+	quit        chan bool
 }
 
 func (og *Og) GetCurrentNodeStatus() p2p_message.StatusData {
@@ -95,11 +100,18 @@ func NewOg(config OGConfig) (*Og, error) {
 	if err != nil {
 		return nil, err
 	}
-	dagConfig := core.DagConfig{GenesisPath: config.GenesisPath}
+	chainConfig := getChainConfig(config.NetworkId)
+	og.ChainConfig = chainConfig
+	dagConfig := core.DagConfig{
+		GenesisPath:     config.GenesisPath,
+		ChainConfig:     chainConfig,
+		ParallelWorkers: viper.GetInt("dag.parallel_workers"),
+	}
 	stateDbConfig := state.StateDBConfig{
 		PurgeTimer:     time.Duration(viper.GetInt("statedb.purge_timer_s")),
 		BeatExpireTime: time.Second * time.Duration(viper.GetInt("statedb.beat_expire_time_s")),
 	}
+	og.Db = db
 	og.Dag, err = core.NewDag(dagConfig, stateDbConfig, db, testDb)
 	if err != nil {
 		logrus.WithError(err).Warning("create db error")
@@ -311,3 +323,30 @@ func (og *Og) PushNodeData() {
 	s.StartBlocking()
 
 }
+
+// getChainConfig reads the OVM fork heights. Forks not configured are never
+// activated. CHAINID returns the network id unless vm.chain_id is set.
+func getChainConfig(networkId uint64) *params.ChainConfig {
+	chainConfig := &params.ChainConfig{ChainID: new(big.Int).SetUint64(networkId)}
+	if viper.IsSet("vm.chain_id") {
+		chainConfig.ChainID = new(big.Int).SetUint64(viper.GetUint64("vm.chain_id"))
+	}
+	forkHeight := func(key string) *big.Int {
+		if !viper.IsSet(key) {
+			return nil
+		}
+		return new(big.Int).SetUint64(viper.GetUint64(key))
+	}
+	chainConfig.ConstantinopleHeight = forkHeight("vm.constantinople_height")
+	chainConfig.PetersburgHeight = forkHeight("vm.petersburg_height")
+	chainConfig.IstanbulHeight = forkHeight("vm.istanbul_height")
+	chainConfig.BlockContextHeight = forkHeight("vm.block_context_height")
+	chainConfig.TokenContractHeight = forkHeight("vm.token_contract_height")
+	chainConfig.WASMHeight = forkHeight("vm.wasm_height")
+	chainConfig.OGPrecompilesHeight = forkHeight("vm.og_precompiles_height")
+	chainConfig.TokenMetadataHeight = forkHeight("vm.token_metadata_height")
+	chainConfig.SignedArchiveHeight = forkHeight("vm.signed_archive_height")
+	chainConfig.DomainNameHeight = forkHeight("vm.domain_name_height")
+	chainConfig.AllowanceHeight = forkHeight("vm.allowance_height")
+	return chainConfig
+}
@@ -82,6 +82,7 @@ type PeerInfo struct {
 	Link        bool   `json:"link"`
 	Addrs       string `json:"addrs"`
 	InBound     bool   `json:"in_bound"`
+	Score       int    `json:"score"`
 }
 
 func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
@@ -205,6 +206,7 @@ func (p *peer) SendMessages(messages []*p2PMessage) error {
 
 func (p *peer) sendRawMessage(msgType p2p_message.MessageType, msgBytes []byte) error {
 	msgLog.WithField("to ", p.id).WithField("type ", msgType).WithField("size", len(msgBytes)).Trace("send msg")
+	markMessageBytes(false, msgType, len(msgBytes))
 	return p2p.Send(p.rw, msgType.Code(), msgBytes)
 
 }
@@ -361,6 +363,7 @@ func (p *peer) sendRequest(msgType p2p_message.MessageType, request p2p_message.
 		return err
 	}
 	clog.WithField("size", len(data)).Debug("send")
+	markMessageBytes(false, msgType, len(data))
 	err = p2p.Send(p.rw, p2p.MsgCodeType(msgType), data)
 	if err != nil {
 		clog.WithError(err).Warn("send failed")
@@ -0,0 +1,195 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package og
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/annchain/OG/metrics"
+	"github.com/annchain/OG/types/p2p_message"
+)
+
+var rateLimitDroppedCounter = metrics.NewRegisteredCounter("og/hub/ratelimit/dropped", nil)
+
+// RateLimit is the token bucket config. Rate is the number of messages
+// allowed per second, Burst is the size of the bucket. A non-positive
+// rate means no limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type peerBuckets struct {
+	all    *tokenBucket
+	byType map[p2p_message.MessageType]*tokenBucket
+}
+
+// RateLimitStats counts the messages dropped by the rate limiter.
+type RateLimitStats struct {
+	Dropped       int64            `json:"dropped"`
+	DroppedByType map[string]int64 `json:"dropped_by_type"`
+	DroppedByPeer map[string]int64 `json:"dropped_by_peer"`
+}
+
+// MessageRateLimiter limits incoming messages with a token bucket per peer,
+// and a token bucket per peer and message type for the types configured.
+type MessageRateLimiter struct {
+	peerLimit  RateLimit
+	typeLimits map[p2p_message.MessageType]RateLimit
+	buckets    map[string]*peerBuckets
+
+	droppedByType map[p2p_message.MessageType]int64
+	droppedByPeer map[string]int64
+	typeCounters  map[p2p_message.MessageType]metrics.Counter
+
+	mu sync.Mutex
+}
+
+func NewMessageRateLimiter(peerLimit RateLimit, typeLimits map[p2p_message.MessageType]RateLimit) *MessageRateLimiter {
+	if typeLimits == nil {
+		typeLimits = make(map[p2p_message.MessageType]RateLimit)
+	}
+	return &MessageRateLimiter{
+		peerLimit:     peerLimit,
+		typeLimits:    typeLimits,
+		buckets:       make(map[string]*peerBuckets),
+		droppedByType: make(map[p2p_message.MessageType]int64),
+		droppedByPeer: make(map[string]int64),
+		typeCounters:  make(map[p2p_message.MessageType]metrics.Counter),
+	}
+}
+
+// Allow returns false if the message should be dropped.
+func (l *MessageRateLimiter) Allow(peerId string, messageType p2p_message.MessageType) bool {
+	typeLimit, typeLimited := l.typeLimits[messageType]
+	typeLimited = typeLimited && typeLimit.Rate > 0
+	if l.peerLimit.Rate <= 0 && !typeLimited {
+		return true
+	}
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pb, ok := l.buckets[peerId]
+	if !ok {
+		pb = &peerBuckets{byType: make(map[p2p_message.MessageType]*tokenBucket)}
+		if l.peerLimit.Rate > 0 {
+			pb.all = newTokenBucket(l.peerLimit, now)
+		}
+		l.buckets[peerId] = pb
+	}
+	if typeLimited {
+		b, ok := pb.byType[messageType]
+		if !ok {
+			b = newTokenBucket(typeLimit, now)
+			pb.byType[messageType] = b
+		}
+		if !b.allow(now) {
+			l.drop(peerId, messageType)
+			return false
+		}
+	}
+	if pb.all != nil && !pb.all.allow(now) {
+		l.drop(peerId, messageType)
+		return false
+	}
+	return true
+}
+
+func (l *MessageRateLimiter) drop(peerId string, messageType p2p_message.MessageType) {
+	l.droppedByType[messageType]++
+	l.droppedByPeer[peerId]++
+	rateLimitDroppedCounter.Inc(1)
+	counter, ok := l.typeCounters[messageType]
+	if !ok {
+		counter = metrics.GetOrRegisterCounter("og/hub/ratelimit/dropped/"+messageType.String(), nil)
+		l.typeCounters[messageType] = counter
+	}
+	counter.Inc(1)
+}
+
+// RemovePeer releases the buckets and the drop count of a disconnected
+// peer.
+func (l *MessageRateLimiter) RemovePeer(peerId string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.buckets, peerId)
+	delete(l.droppedByPeer, peerId)
+}
+
+func (l *MessageRateLimiter) Stats() *RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := &RateLimitStats{
+		DroppedByType: make(map[string]int64),
+		DroppedByPeer: make(map[string]int64),
+	}
+	for t, n := range l.droppedByType {
+		stats.DroppedByType[t.String()] = n
+		stats.Dropped += n
+	}
+	for id, n := range l.droppedByPeer {
+		stats.DroppedByPeer[id] = n
+	}
+	return stats
+}
+
+// MessageTypeByName finds the message type by its name case-insensitively,
+// e.g. MessageTypeNewTxs.
+func MessageTypeByName(name string) (p2p_message.MessageType, bool) {
+	for t := p2p_message.StatusMsg; t < p2p_message.MessageTypeOg03Length; t++ {
+		if strings.EqualFold(t.String(), name) {
+			return t, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,75 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package og
+
+import (
+	"testing"
+	"time"
+
+	"github.com/annchain/OG/types/p2p_message"
+)
+
+func TestMessageRateLimiter_Allow(t *testing.T) {
+	l := NewMessageRateLimiter(RateLimit{Rate: 1, Burst: 10}, map[p2p_message.MessageType]RateLimit{
+		p2p_message.MessageTypeNewTxs: {Rate: 1, Burst: 2},
+	})
+	for i := 0; i < 2; i++ {
+		if !l.Allow("a", p2p_message.MessageTypeNewTxs) {
+			t.Fatal("should be allowed", i)
+		}
+	}
+	if l.Allow("a", p2p_message.MessageTypeNewTxs) {
+		t.Fatal("type limit exceeded")
+	}
+	// other types and other peers are not affected
+	if !l.Allow("a", p2p_message.MessageTypeNewTx) || !l.Allow("b", p2p_message.MessageTypeNewTxs) {
+		t.Fatal("should be allowed")
+	}
+	for i := 0; i < 7; i++ {
+		l.Allow("a", p2p_message.MessageTypeNewTx)
+	}
+	if l.Allow("a", p2p_message.MessageTypeNewTx) {
+		t.Fatal("peer limit exceeded")
+	}
+
+	stats := l.Stats()
+	if stats.Dropped != 2 || stats.DroppedByPeer["a"] != 2 || stats.DroppedByType[p2p_message.MessageTypeNewTxs.String()] != 1 {
+		t.Fatal("stats error", stats)
+	}
+
+	l.RemovePeer("a")
+	if _, ok := l.Stats().DroppedByPeer["a"]; ok {
+		t.Fatal("drop count of the removed peer is kept")
+	}
+}
+
+func TestTokenBucket_Refill(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(RateLimit{Rate: 10, Burst: 1}, now)
+	if !b.allow(now) || b.allow(now) {
+		t.Fatal("burst error")
+	}
+	if !b.allow(now.Add(100 * time.Millisecond)) {
+		t.Fatal("bucket should be refilled")
+	}
+}
+
+func TestMessageTypeByName(t *testing.T) {
+	if mt, ok := MessageTypeByName("messagetypenewtxs"); !ok || mt != p2p_message.MessageTypeNewTxs {
+		t.Fatal("lookup error", mt, ok)
+	}
+	if _, ok := MessageTypeByName("unknown"); ok {
+		t.Fatal("should not be found")
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package og
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/annchain/OG/ogdb"
+	log "github.com/sirupsen/logrus"
+)
+
+// score changes of peer behaviours.
+const (
+	ScoreGoodMsg     = 1
+	ScoreBadTx       = -20
+	ScoreInvalidMsg  = -50
+	ScoreOversizeMsg = -100
+
+	MaxPeerScore = 100
+)
+
+var prefixPeerBanKey = []byte("pb")
+
+// PeerReputation keeps a score for each peer. Peers whose score drops
+// below the threshold are banned for a while. Bans are persisted in the
+// node db so that they survive restarts.
+type PeerReputation struct {
+	scores       map[string]int
+	bans         map[string]time.Time
+	banThreshold int
+	banDuration  time.Duration
+	db           ogdb.Database
+
+	mu sync.RWMutex
+}
+
+// BannedPeer is a banned peer and the time the ban expires.
+type BannedPeer struct {
+	Id     string    `json:"id"`
+	Expire time.Time `json:"expire"`
+}
+
+func NewPeerReputation(banThreshold int, banDuration time.Duration) *PeerReputation {
+	return &PeerReputation{
+		scores:       make(map[string]int),
+		bans:         make(map[string]time.Time),
+		banThreshold: banThreshold,
+		banDuration:  banDuration,
+	}
+}
+
+// SetDB sets the db used to persist bans.
+func (r *PeerReputation) SetDB(db ogdb.Database) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.db = db
+}
+
+func (r *PeerReputation) Score(id string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.scores[id]
+}
+
+// Adjust changes the score of the peer and returns true if the peer
+// gets banned.
+func (r *PeerReputation) Adjust(id string, delta int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	score := r.scores[id] + delta
+	if score > MaxPeerScore {
+		score = MaxPeerScore
+	}
+	r.scores[id] = score
+	if score > r.banThreshold {
+		return false
+	}
+	r.ban(id, time.Now().Add(r.banDuration))
+	return true
+}
+
+// Ban bans the peer for the given duration.
+func (r *PeerReputation) Ban(id string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ban(id, time.Now().Add(duration))
+}
+
+func (r *PeerReputation) ban(id string, expire time.Time) {
+	r.bans[id] = expire
+	// reset the score so that the peer starts over when the ban expires.
+	delete(r.scores, id)
+	if r.db == nil {
+		return
+	}
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(expire.Unix()))
+	if err := r.db.Put(peerBanKey(id), value); err != nil {
+		log.WithError(err).WithField("peer", id).Warn("failed to persist peer ban")
+	}
+}
+
+// Unban lifts the ban of the peer.
+func (r *PeerReputation) Unban(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.unban(id)
+}
+
+func (r *PeerReputation) unban(id string) {
+	delete(r.bans, id)
+	if r.db == nil {
+		return
+	}
+	if err := r.db.Delete(peerBanKey(id)); err != nil {
+		log.WithError(err).WithField("peer", id).Warn("failed to delete peer ban")
+	}
+}
+
+// IsBanned checks the ban list, then the db for bans made before restart.
+func (r *PeerReputation) IsBanned(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expire, ok := r.bans[id]
+	if !ok && r.db != nil {
+		data, err := r.db.Get(peerBanKey(id))
+		if err == nil && len(data) == 8 {
+			expire = time.Unix(int64(binary.BigEndian.Uint64(data)), 0)
+			r.bans[id] = expire
+			ok = true
+		}
+	}
+	if !ok {
+		return false
+	}
+	if time.Now().After(expire) {
+		r.unban(id)
+		return false
+	}
+	return true
+}
+
+// BannedPeers returns the peers banned currently.
+func (r *PeerReputation) BannedPeers() []BannedPeer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var peers []BannedPeer
+	for id, expire := range r.bans {
+		if now.After(expire) {
+			continue
+		}
+		peers = append(peers, BannedPeer{Id: id, Expire: expire})
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Id < peers[j].Id
+	})
+	return peers
+}
+
+func peerBanKey(id string) []byte {
+	return append(prefixPeerBanKey, []byte(id)...)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package og
+
+import (
+	"testing"
+	"time"
+
+	"github.com/annchain/OG/ogdb"
+)
+
+func TestPeerReputation_Adjust(t *testing.T) {
+	r := NewPeerReputation(-100, time.Hour)
+	for i := 0; i < 200; i++ {
+		r.Adjust("a", ScoreGoodMsg)
+	}
+	if r.Score("a") != MaxPeerScore {
+		t.Fatal("score should be capped", r.Score("a"))
+	}
+	for i := 0; i < 9; i++ {
+		if r.Adjust("a", ScoreBadTx) {
+			t.Fatal("banned too early", i)
+		}
+	}
+	if !r.Adjust("a", ScoreBadTx) {
+		t.Fatal("should be banned")
+	}
+	if !r.IsBanned("a") || r.IsBanned("b") {
+		t.Fatal("ban list error")
+	}
+	r.Unban("a")
+	if r.IsBanned("a") {
+		t.Fatal("should be unbanned")
+	}
+}
+
+func TestPeerReputation_Persist(t *testing.T) {
+	db := ogdb.NewMemDatabase()
+	r := NewPeerReputation(-100, time.Hour)
+	r.SetDB(db)
+	r.Adjust("a", ScoreOversizeMsg)
+	r.Ban("b", -time.Second)
+
+	// restart
+	r = NewPeerReputation(-100, time.Hour)
+	r.SetDB(db)
+	if !r.IsBanned("a") {
+		t.Fatal("ban should be loaded from db")
+	}
+	if r.IsBanned("b") {
+		t.Fatal("ban should be expired")
+	}
+	if has, _ := db.Has(peerBanKey("b")); has {
+		t.Fatal("expired ban should be deleted")
+	}
+	if peers := r.BannedPeers(); len(peers) != 1 || peers[0].Id != "a" {
+		t.Fatal("banned peers error", peers)
+	}
+}
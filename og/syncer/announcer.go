@@ -14,19 +14,67 @@
 package syncer
 
 import (
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/goroutine"
 	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/types/p2p_message"
 	"github.com/annchain/OG/types/tx_types"
+	"time"
 )
 
+// CompactRelayConfig enables the announce-then-fetch relay mode. Txs are
+// gossiped as hashes only and peers pull the bodies they miss.
+// Sequencers and consensus messages are always pushed.
+type CompactRelayConfig struct {
+	Enabled                bool
+	AnnounceBatchSize      int
+	AnnounceIntervalMillis int
+}
+
 type Announcer struct {
 	messageSender MessageSender
+	compactRelay  CompactRelayConfig
+	announceQueue chan common.Hash
+	quit          chan struct{}
 }
 
 func NewAnnouncer(messageSender MessageSender) *Announcer {
 	return &Announcer{
 		messageSender: messageSender,
+		quit:          make(chan struct{}),
+	}
+}
+
+// EnableCompactRelay switches normal txs to hash announcements. Must be
+// called before Start.
+func (m *Announcer) EnableCompactRelay(config CompactRelayConfig) {
+	if config.AnnounceBatchSize <= 0 {
+		config.AnnounceBatchSize = 256
+	}
+	if config.AnnounceIntervalMillis <= 0 {
+		config.AnnounceIntervalMillis = 100
 	}
+	config.Enabled = true
+	m.compactRelay = config
+	m.announceQueue = make(chan common.Hash, config.AnnounceBatchSize*4)
+}
+
+func (m *Announcer) CompactRelayEnabled() bool {
+	return m.compactRelay.Enabled
+}
+
+func (m *Announcer) Start() {
+	if m.compactRelay.Enabled {
+		goroutine.New(m.loopAnnounce)
+	}
+}
+
+func (m *Announcer) Stop() {
+	close(m.quit)
+}
+
+func (m *Announcer) Name() string {
+	return "Announcer"
 }
 
 //BroadcastNewTx brodcast newly created txi message
@@ -34,6 +82,10 @@ func (m *Announcer) BroadcastNewTx(txi types.Txi) {
 	switch tx := txi.(type) {
 	case *tx_types.Tx:
 		msgTx := p2p_message.MessageNewTx{RawTx: tx.RawTx()}
+		if m.announce(tx) {
+			m.messageSender.BroadcastMessageToLegacy(p2p_message.MessageTypeNewTx, &msgTx)
+			return
+		}
 		m.messageSender.BroadcastMessageWithLink(p2p_message.MessageTypeNewTx, &msgTx)
 	case *tx_types.Sequencer:
 		msgTx := p2p_message.MessageNewSequencer{RawSequencer: tx.RawSequencer()}
@@ -53,14 +105,66 @@ func (m *Announcer) BroadcastNewTx(txi types.Txi) {
 		msg := p2p_message.MessageNewArchive{
 			Archive: tx,
 		}
+		if m.announce(tx) {
+			m.messageSender.BroadcastMessageToLegacy(p2p_message.MessageTypeArchive, &msg)
+			return
+		}
 		m.messageSender.BroadcastMessage(p2p_message.MessageTypeArchive, &msg)
 	case *tx_types.ActionTx:
 		msg := p2p_message.MessageNewActionTx{
 			ActionTx: tx,
 		}
+		if m.announce(tx) {
+			m.messageSender.BroadcastMessageToLegacy(p2p_message.MessageTypeActionTX, &msg)
+			return
+		}
 		m.messageSender.BroadcastMessage(p2p_message.MessageTypeActionTX, &msg)
 
 	default:
 		log.Warn("never come here, unknown tx type ", tx)
 	}
 }
+
+// announce queues the tx hash for the next announcement batch. It returns
+// false if compact relay is disabled or the queue is full, in which case
+// the tx should be pushed as usual.
+func (m *Announcer) announce(txi types.Txi) bool {
+	if !m.compactRelay.Enabled {
+		return false
+	}
+	select {
+	case m.announceQueue <- txi.GetTxHash():
+		return true
+	default:
+		log.WithField("tx", txi).Debug("announce queue full, push tx instead")
+		return false
+	}
+}
+
+func (m *Announcer) loopAnnounce() {
+	interval := time.Duration(m.compactRelay.AnnounceIntervalMillis) * time.Millisecond
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var batch common.Hashes
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		m.messageSender.AnnounceTxHashes(batch)
+		batch = nil
+	}
+	for {
+		select {
+		case hash := <-m.announceQueue:
+			batch = append(batch, hash)
+			if len(batch) >= m.compactRelay.AnnounceBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-m.quit:
+			log.Info("Announcer received quit message. Quitting...")
+			return
+		}
+	}
+}
@@ -16,6 +16,7 @@ package syncer
 import (
 	"github.com/annchain/OG/common/goroutine"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	// "github.com/annchain/OG/ffchan"
@@ -80,7 +81,7 @@ type CatchupSyncer struct {
 	OnWorkingStateChanged         []chan CatchupSyncerStatus
 	OnNewTxiReceived              []chan types.Txi
 	NewPeerConnectedEventListener chan string
-	syncFlag                      bool
+	syncFlag                      uint32 // 1 for is syncing
 	WorkState                     CatchupSyncerStatus
 	mu                            sync.RWMutex
 	BootStrapNode                 bool
@@ -95,6 +96,7 @@ func (c *CatchupSyncer) Init() {
 	c.quitLoopEvent = make(chan bool)
 	c.NewPeerConnectedEventListener = make(chan string)
 	c.quit = make(chan bool)
+	registerSyncMetrics(c)
 }
 
 func (c *CatchupSyncer) Start() {
@@ -174,10 +176,17 @@ func (c *CatchupSyncer) loopSync() {
 	}
 }
 
+// Resync starts a sync to the best peer now, even if the syncer is not
+// enabled. It does nothing if a sync is already running.
+func (c *CatchupSyncer) Resync() {
+	log.Info("catchup syncer forced to resync")
+	goroutine.New(func() {
+		c.syncToLatest()
+	})
+}
+
 func (c *CatchupSyncer) isSyncing() bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.syncFlag
+	return atomic.LoadUint32(&c.syncFlag) == 1
 }
 
 //getWorkState
@@ -187,16 +196,13 @@ func (c *CatchupSyncer) getWorkState() CatchupSyncerStatus {
 	return c.WorkState
 }
 
-func (c *CatchupSyncer) setSyncFlag() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.syncFlag = true
+// trySetSyncFlag sets the sync flag, it returns false if it is set already.
+func (c *CatchupSyncer) trySetSyncFlag() bool {
+	return atomic.CompareAndSwapUint32(&c.syncFlag, 0, 1)
 }
 
 func (c *CatchupSyncer) unsetSyncFlag() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.syncFlag = false
+	atomic.StoreUint32(&c.syncFlag, 0)
 }
 
 func (c *CatchupSyncer) CacheNewTxEnabled() bool {
@@ -212,11 +218,10 @@ func (c *CatchupSyncer) CacheNewTxEnabled() bool {
 }
 
 func (c *CatchupSyncer) syncToLatest() error {
-	if c.isSyncing() {
+	if !c.trySetSyncFlag() {
 		log.Trace("catchup syncing task is busy")
 		return nil
 	}
-	c.setSyncFlag()
 	defer c.unsetSyncFlag()
 	//get best peer ,and sync with this peer until we catchup
 
@@ -241,6 +246,7 @@ func (c *CatchupSyncer) syncToLatest() error {
 		log.WithField("peerId", bpId).WithField("seq", seqId).WithField("ourId", ourId).
 			Debug("catchup sync with best peer")
 		c.currentBestHeight = seqId
+		catchupSyncMeter.Mark(1)
 		// Run the sync cycle, and disable fast sync if we've went past the pivot block
 		if err := c.Downloader.Synchronise(bpId, bpHash, seqId, c.SyncMode); err != nil {
 			log.WithError(err).Warn("catchup sync failed")
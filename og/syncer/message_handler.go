@@ -14,9 +14,11 @@
 package syncer
 
 import (
+	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/types/p2p_message"
 	"sort"
+	"time"
 )
 
 func (m *IncrementalSyncer) HandleNewTxi(tx types.Txi, peerId string) {
@@ -37,6 +39,7 @@ func (m *IncrementalSyncer) HandleNewTxi(tx types.Txi, peerId string) {
 		log.WithField("tx ", tx).Debug("cache txs for future.")
 	}
 
+	m.addTxSource(tx.GetTxHash(), peerId)
 	if tx.GetType() == types.TxBaseTypeSequencer {
 		m.SequencerCache.Add(tx.GetTxHash(), peerId)
 	}
@@ -85,6 +88,7 @@ func (m *IncrementalSyncer) HandleNewTxs(newTxs *p2p_message.MessageNewTxs, peer
 		if !m.Enabled {
 			log.WithField("tx ", tx).Debug("cache txs for future.")
 		}
+		m.addTxSource(tx.GetTxHash(), peerId)
 		validTxs = append(validTxs, tx.Tx())
 	}
 
@@ -98,6 +102,47 @@ func (m *IncrementalSyncer) HandleNewTxs(newTxs *p2p_message.MessageNewTxs, peer
 	log.WithField("q", newTxs).Debug("incremental received MessageNewTxs")
 }
 
+// HandleNewTxHashes handles tx hashes announced under compact relay and
+// requests the unknown ones from the announcing peer. Bodies come back as
+// a normal MessageSyncResponse.
+func (m *IncrementalSyncer) HandleNewTxHashes(msg *p2p_message.MessageNewTxHashes, peerId string) {
+	if msg.Hashes == nil || len(*msg.Hashes) == 0 {
+		log.Debug("empty MessageNewTxHashes")
+		return
+	}
+	if !m.Enabled {
+		if !m.cacheNewTxEnabled() {
+			log.Debug("incremental received tx hashes but sync disabled")
+			return
+		}
+	}
+	var missing common.Hashes
+	for _, hash := range *msg.Hashes {
+		if m.isKnownHash(hash) || m.bufferedIncomingTxCache.Has(hash) {
+			continue
+		}
+		// someone else has announced it and the body is on its way.
+		if m.firedTxCache.Has(hash) {
+			continue
+		}
+		m.firedTxCache.Set(hash, FireHistory{
+			FiredTimes: 1,
+			StartTime:  time.Now(),
+			LastTime:   time.Now(),
+		})
+		missing = append(missing, hash)
+	}
+	if len(missing) == 0 {
+		return
+	}
+	req := p2p_message.MessageSyncRequest{
+		Hashes:    &missing,
+		RequestId: p2p_message.MsgCounter.Get(),
+	}
+	log.WithField("count", len(missing)).WithField("peer", peerId).Debug("fetch announced txs")
+	m.messageSender.SendToPeer(peerId, p2p_message.MessageTypeFetchByHashRequest, &req)
+}
+
 func (m *IncrementalSyncer) HandleNewSequencer(newSeq *p2p_message.MessageNewSequencer, peerId string) {
 	seq := newSeq.RawSequencer.Sequencer()
 	if seq == nil {
@@ -200,6 +245,7 @@ func (m *IncrementalSyncer) HandleFetchByHashResponse(syncResponse *p2p_message.
 		if !m.Enabled {
 			log.WithField("tx ", rawTx).Debug("cache txs for future.")
 		}
+		m.addTxSource(rawTx.GetTxHash(), sourceId)
 		txis = append(txis, rawTx.Txi())
 	}
 	//if testVal!=len(syncResponse.RawSequencers) {
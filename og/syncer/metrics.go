@@ -0,0 +1,45 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package syncer
+
+import (
+	"github.com/annchain/OG/metrics"
+)
+
+var catchupSyncMeter = metrics.NewRegisteredMeter("og/sync/catchup", nil)
+
+// registerSyncMetrics registers the gauges of the sync state of c.
+func registerSyncMetrics(c *CatchupSyncer) {
+	// lag is the number of sequencers the best peer is ahead of us.
+	metrics.NewRegisteredFunctionalGauge("og/sync/lag", nil, func() int64 {
+		if c.PeerProvider == nil || c.NodeStatusDataProvider == nil {
+			return 0
+		}
+		_, _, seqId, err := c.PeerProvider.BestPeerInfo()
+		if err != nil {
+			return 0
+		}
+		ourId := c.NodeStatusDataProvider.GetCurrentNodeStatus().CurrentId
+		if seqId <= ourId {
+			return 0
+		}
+		return int64(seqId - ourId)
+	})
+	metrics.NewRegisteredFunctionalGauge("og/sync/syncing", nil, func() int64 {
+		if c.isSyncing() {
+			return 1
+		}
+		return 0
+	})
+}
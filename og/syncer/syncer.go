@@ -34,6 +34,8 @@ type MessageSender interface {
 	MulticastToSource(messageType p2p_message.MessageType, message p2p_message.Message, sourceMsgHash *common.Hash)
 	BroadcastMessageWithLink(messageType p2p_message.MessageType, message p2p_message.Message)
 	SendToPeer(peerId string, messageType p2p_message.MessageType, msg p2p_message.Message)
+	AnnounceTxHashes(hashes common.Hashes)
+	BroadcastMessageToLegacy(messageType p2p_message.MessageType, message p2p_message.Message)
 }
 
 type FireHistory struct {
@@ -68,6 +70,7 @@ type IncrementalSyncer struct {
 	bloomFilterStatus        *BloomFilterFireStatus
 	RemoveContrlMsgFromCache func(hash common.Hash)
 	SequencerCache           *SequencerCache
+	txSourceCache            gcache.Cache // peer from which the tx is received first.
 }
 
 func (m *IncrementalSyncer) GetBenchmarks() map[string]interface{} {
@@ -118,7 +121,26 @@ func NewIncrementalSyncer(config *SyncerConfig, messageSender MessageSender, get
 		cacheNewTxEnabled:    cacheNewTxEnabled,
 		bloomFilterStatus:    NewBloomFilterFireStatus(120, 500),
 		SequencerCache:       NewSequencerCache(15),
+		txSourceCache: gcache.New(config.BufferedIncomingTxCacheMaxSize).LRU().
+			Expiration(time.Second * time.Duration(config.BufferedIncomingTxCacheExpirationSeconds)).Build(),
+	}
+}
+
+// GetTxSource returns the peer from which the tx is received, empty
+// if the tx is generated locally or already expired.
+func (m *IncrementalSyncer) GetTxSource(hash common.Hash) string {
+	v, err := m.txSourceCache.GetIFPresent(hash)
+	if err != nil {
+		return ""
+	}
+	return v.(string)
+}
+
+func (m *IncrementalSyncer) addTxSource(hash common.Hash, peerId string) {
+	if peerId == "" || m.txSourceCache.Has(hash) {
+		return
 	}
+	m.txSourceCache.Set(hash, peerId)
 }
 
 func (m *IncrementalSyncer) Start() {
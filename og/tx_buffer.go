@@ -71,6 +71,7 @@ type IDag interface {
 	GetSequencerByHash(hash common.Hash) *tx_types.Sequencer
 	GetBalance(addr common.Address, tokenID int32) *math.BigInt
 	GetLatestNonce(addr common.Address) (uint64, error)
+	GetTermChangeByPkBls(pkBls []byte) *tx_types.TermChange
 }
 
 // TxBuffer rebuild graph by buffering newly incoming txs and find their parents.
@@ -91,6 +92,7 @@ type TxBuffer struct {
 	knownCache             gcache.Cache // txs that are already fulfilled and pushed to txpool
 	txAddedToPoolChan      chan types.Txi
 	OnProposalSeqCh        chan common.Hash
+	OnBadTx                []func(tx types.Txi) // called when a tx fails the independent verifiers
 	//children               *childrenCache //key : phash ,value :
 	//HandlingQueue           txQueue
 	TestNoVerify bool
@@ -300,6 +302,7 @@ func (b *TxBuffer) handleTx(tx types.Txi) {
 			}
 			if !verifier.Verify(tx) {
 				logrus.WithField("tx", tx).WithField("verifier", verifier).Warn("bad tx")
+				b.reportBadTx(tx)
 				return
 			}
 		}
@@ -323,6 +326,7 @@ func (b *TxBuffer) verify(tx types.Txi, validTxs *types.Txis) {
 			}
 			if !verifier.Verify(tx) {
 				logrus.WithField("tx", tx).WithField("verifier", verifier).Warn("bad tx")
+				b.reportBadTx(tx)
 				return
 			}
 		}
@@ -334,6 +338,12 @@ func (b *TxBuffer) verify(tx types.Txi, validTxs *types.Txis) {
 	b.wg.Done()
 }
 
+func (b *TxBuffer) reportBadTx(tx types.Txi) {
+	for _, f := range b.OnBadTx {
+		f(tx)
+	}
+}
+
 // in parallel
 func (b *TxBuffer) handleTxs(txs types.Txis) {
 	logrus.WithField("tx", txs).Debug("buffer is handling txs")
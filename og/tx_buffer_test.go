@@ -61,6 +61,10 @@ func (d *dummyDag) GetTxisByNumber(id uint64) types.Txis {
 	return nil
 }
 
+func (d *dummyDag) GetTermChangeByPkBls(pkBls []byte) *tx_types.TermChange {
+	return nil
+}
+
 func (d *dummyDag) GetTestTxisByNumber(id uint64) types.Txis {
 	return nil
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/og/miner"
 	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/vm/eth/params"
 	"github.com/sirupsen/logrus"
 )
 
@@ -167,6 +168,27 @@ type TxCreator struct {
 	NoVerifyMaxTxHash  bool
 	GetStateRoot       GetStateRoot
 	TxFormatVerifier   TxFormatVerifier
+	// ChainConfig gives the fork heights changing the hash and signature of
+	// the txs.
+	ChainConfig *params.ChainConfig
+
+	mu sync.RWMutex // guards MaxTxHash once the creator runs
+}
+
+// GetMaxTxHash returns the difficulty of TxHash.
+func (m *TxCreator) GetMaxTxHash() common.Hash {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.MaxTxHash
+}
+
+// SetMaxTxHash changes the difficulty of TxHash.
+func (m *TxCreator) SetMaxTxHash(hash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MaxTxHash = hash
 }
 
 func (t *TxCreator) GetArchiveNonce() uint64 {
@@ -192,12 +214,20 @@ func (m *TxCreator) NewUnsignedTx(from common.Address, to common.Address, value
 }
 
 func (m *TxCreator) NewArchiveWithSeal(data []byte) (tx types.Txi, err error) {
+	return m.NewCompressedArchiveWithSeal(data, "", tx_types.ArchiveCompressionNone)
+}
+
+// NewCompressedArchiveWithSeal seals an anonymous archive. data must be
+// already compressed with compression.
+func (m *TxCreator) NewCompressedArchiveWithSeal(data []byte, contentType string, compression uint8) (tx types.Txi, err error) {
 	tx = &tx_types.Archive{
 		TxBase: types.TxBase{
 			AccountNonce: m.GetArchiveNonce(),
 			Type:         types.TxBaseTypeArchive,
 		},
-		Data: data,
+		Data:        data,
+		ContentType: contentType,
+		Compression: compression,
 	}
 
 	if ok := m.SealTx(tx, nil); !ok {
@@ -210,6 +240,33 @@ func (m *TxCreator) NewArchiveWithSeal(data []byte) (tx types.Txi, err error) {
 	return tx, nil
 }
 
+// NewSignedArchiveWithSeal seals an archive signed by from. data must be
+// already compressed with compression.
+func (m *TxCreator) NewSignedArchiveWithSeal(from common.Address, data []byte, contentType string, compression uint8,
+	nonce uint64, pubkey crypto.PublicKey, sig crypto.Signature) (tx types.Txi, err error) {
+	tx = &tx_types.Archive{
+		TxBase: types.TxBase{
+			AccountNonce: nonce,
+			Type:         types.TxBaseTypeArchive,
+		},
+		From:        &from,
+		Data:        data,
+		ContentType: contentType,
+		Compression: compression,
+	}
+	tx.GetBase().Signature = sig.Bytes
+	tx.GetBase().PublicKey = pubkey.Bytes
+
+	if ok := m.SealTx(tx, nil); !ok {
+		logrus.Warn("failed to seal tx")
+		err = fmt.Errorf("failed to seal tx")
+		return
+	}
+	logrus.WithField("tx", tx).Debugf("tx generated")
+
+	return tx, nil
+}
+
 func (m *TxCreator) NewTxWithSeal(from common.Address, to common.Address, value *math.BigInt, data []byte,
 	nonce uint64, pubkey crypto.PublicKey, sig crypto.Signature, tokenId int32) (tx types.Txi, err error) {
 	tx = &tx_types.Tx{
@@ -239,7 +296,8 @@ func (m *TxCreator) NewTxWithSeal(from common.Address, to common.Address, value
 }
 
 func (m *TxCreator) NewActionTxWithSeal(from common.Address, to common.Address, value *math.BigInt, action byte,
-	nonce uint64, enableSpo bool, TokenId int32, tokenName string, pubkey crypto.PublicKey, sig crypto.Signature) (tx types.Txi, err error) {
+	nonce uint64, enableSpo bool, TokenId int32, tokenName string, tokenSymbol string, decimals uint8, metadataURI string,
+	controls uint8, pubkey crypto.PublicKey, sig crypto.Signature) (tx types.Txi, err error) {
 	tx = &tx_types.ActionTx{
 		From: &from,
 		// TODO
@@ -250,10 +308,14 @@ func (m *TxCreator) NewActionTxWithSeal(from common.Address, to common.Address,
 		},
 		Action: action,
 		ActionData: &tx_types.PublicOffering{
-			Value:     value,
-			EnableSPO: enableSpo,
-			TokenId:   TokenId,
-			TokenName: tokenName,
+			Value:       value,
+			EnableSPO:   enableSpo,
+			TokenId:     TokenId,
+			TokenName:   tokenName,
+			TokenSymbol: tokenSymbol,
+			Decimals:    decimals,
+			MetadataURI: metadataURI,
+			Controls:    controls,
 		},
 	}
 	tx.GetBase().Signature = sig.Bytes
@@ -269,6 +331,82 @@ func (m *TxCreator) NewActionTxWithSeal(from common.Address, to common.Address,
 	return tx, nil
 }
 
+// NewDomainTxWithSeal seals the action tx registering, renewing or
+// transferring a domain name.
+func (m *TxCreator) NewDomainTxWithSeal(from common.Address, action byte, request tx_types.RequestDomain,
+	nonce uint64, pubkey crypto.PublicKey, sig crypto.Signature) (tx types.Txi, err error) {
+	tx = &tx_types.ActionTx{
+		From: &from,
+		TxBase: types.TxBase{
+			AccountNonce: nonce,
+			Type:         types.TxBaseAction,
+		},
+		Action:     action,
+		ActionData: &request,
+	}
+	tx.GetBase().Signature = sig.Bytes
+	tx.GetBase().PublicKey = pubkey.Bytes
+
+	if ok := m.SealTx(tx, nil); !ok {
+		logrus.Warn("failed to seal tx")
+		err = fmt.Errorf("failed to seal tx")
+		return
+	}
+	logrus.WithField("tx", tx).Debugf("tx generated")
+	return tx, nil
+}
+
+// NewAllowanceTxWithSeal seals the action tx approving, revoking or
+// spending an allowance. data is an Approval or a TransferFrom matching
+// the action.
+func (m *TxCreator) NewAllowanceTxWithSeal(from common.Address, action byte, data tx_types.ActionData,
+	nonce uint64, pubkey crypto.PublicKey, sig crypto.Signature) (tx types.Txi, err error) {
+	tx = &tx_types.ActionTx{
+		From: &from,
+		TxBase: types.TxBase{
+			AccountNonce: nonce,
+			Type:         types.TxBaseAction,
+		},
+		Action:     action,
+		ActionData: data,
+	}
+	tx.GetBase().Signature = sig.Bytes
+	tx.GetBase().PublicKey = pubkey.Bytes
+
+	if ok := m.SealTx(tx, nil); !ok {
+		logrus.Warn("failed to seal tx")
+		err = fmt.Errorf("failed to seal tx")
+		return
+	}
+	logrus.WithField("tx", tx).Debugf("tx generated")
+	return tx, nil
+}
+
+// NewTokenControlTxWithSeal seals the action tx freezing, whitelisting or
+// pausing a token.
+func (m *TxCreator) NewTokenControlTxWithSeal(from common.Address, action byte, control tx_types.TokenControl,
+	nonce uint64, pubkey crypto.PublicKey, sig crypto.Signature) (tx types.Txi, err error) {
+	tx = &tx_types.ActionTx{
+		From: &from,
+		TxBase: types.TxBase{
+			AccountNonce: nonce,
+			Type:         types.TxBaseAction,
+		},
+		Action:     action,
+		ActionData: &control,
+	}
+	tx.GetBase().Signature = sig.Bytes
+	tx.GetBase().PublicKey = pubkey.Bytes
+
+	if ok := m.SealTx(tx, nil); !ok {
+		logrus.Warn("failed to seal tx")
+		err = fmt.Errorf("failed to seal tx")
+		return
+	}
+	logrus.WithField("tx", tx).Debugf("tx generated")
+	return tx, nil
+}
+
 func (m *TxCreator) NewSignedTx(from common.Address, to common.Address, value *math.BigInt, accountNonce uint64,
 	privateKey crypto.PrivateKey, tokenId int32) types.Txi {
 	if privateKey.Type != crypto.Signer.GetCryptoType() {
@@ -303,7 +441,7 @@ func (m *TxCreator) NewSignedSequencer(issuer common.Address, height uint64, acc
 	tx := m.NewUnsignedSequencer(issuer, height, accountNonce)
 	// do sign work
 	logrus.Tracef("seq before sign, the sign type is: %s", crypto.Signer.GetCryptoType().String())
-	signature := crypto.Signer.Sign(privateKey, tx.SignatureTargets())
+	signature := crypto.Signer.Sign(privateKey, signatureTargets(tx, m.ChainConfig))
 	tx.GetBase().Signature = signature.Bytes
 	tx.GetBase().PublicKey = crypto.Signer.PubKey(privateKey).Bytes
 	return tx
@@ -331,8 +469,8 @@ func (m *TxCreator) tryConnect(tx types.Txi, parents []types.Txi, privateKey *cr
 
 	tx.GetBase().ParentsHash = parentHashes
 	// verify if the hash of the structure meet the standard.
-	hash := tx.CalcTxHash()
-	if m.NoVerifyMaxTxHash || hash.Cmp(m.MaxTxHash) < 0 {
+	hash := calcTxHash(tx, m.ChainConfig)
+	if m.NoVerifyMaxTxHash || hash.Cmp(m.GetMaxTxHash()) < 0 {
 		tx.GetBase().Hash = hash
 		logrus.WithField("hash", hash).WithField("parent", tx.Parents()).Trace("new tx connected")
 		// yes
@@ -352,8 +490,8 @@ func (m *TxCreator) tryConnect(tx types.Txi, parents []types.Txi, privateKey *cr
 		}).Trace("validate graph structure for tx being connected")
 
 		if tx.GetType() == types.TxBaseTypeSequencer {
-			tx.GetBase().Signature = crypto.Signer.Sign(*privateKey, tx.SignatureTargets()).Bytes
-			tx.GetBase().Hash = tx.CalcTxHash()
+			tx.GetBase().Signature = crypto.Signer.Sign(*privateKey, signatureTargets(tx, m.ChainConfig)).Bytes
+			tx.GetBase().Hash = calcTxHash(tx, m.ChainConfig)
 		}
 
 		return txRet, ok
@@ -403,7 +541,7 @@ func (m *TxCreator) SealTx(tx types.Txi, priveKey *crypto.PrivateKey) (ok bool)
 				connectionTries++
 				var txs types.Txis
 				var ancestor types.Txi
-				if tx.GetType() != types.TxBaseTypeArchive {
+				if !tx_types.AnonymousArchive(tx) {
 					ancestor = m.TipGenerator.GetByNonce(tx.Sender(), tx.GetNonce()-1)
 				}
 				if ancestor != nil && !ancestor.InValid() {
@@ -522,8 +660,8 @@ func (m *TxCreator) GenerateSequencer(issuer common.Address, Height uint64, acco
 				return nil, false
 			}
 			tx.StateRoot = root
-			tx.GetBase().Signature = crypto.Signer.Sign(*privateKey, tx.SignatureTargets()).Bytes
-			tx.GetBase().Hash = tx.CalcTxHash()
+			tx.GetBase().Signature = crypto.Signer.Sign(*privateKey, signatureTargets(tx, m.ChainConfig)).Bytes
+			tx.GetBase().Hash = calcTxHash(tx, m.ChainConfig)
 			tx.SetVerified(types.VerifiedGraph)
 			tx.SetVerified(types.VerifiedFormat)
 			break
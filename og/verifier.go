@@ -23,8 +23,10 @@ import (
 	"github.com/annchain/OG/status"
 	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/types/tx_types"
+	"github.com/annchain/OG/vm/eth/params"
 	"github.com/sirupsen/logrus"
 	"math/big"
+	"sync"
 )
 
 // GraphVerifier verifies if the tx meets the standards
@@ -42,6 +44,50 @@ type TxFormatVerifier struct {
 	NoVerifyMindHash  bool
 	NoVerifyMaxTxHash bool
 	NoVerifySignatrue bool
+	// ChainConfig gives the fork heights changing the hash and signature of
+	// the txs.
+	ChainConfig *params.ChainConfig
+
+	mu sync.RWMutex // guards MaxTxHash once the verifier runs
+}
+
+// timestampSigned tells whether the timestamp of the sequencer at height is
+// signed and hashed, which is from the block context fork height on.
+func timestampSigned(config *params.ChainConfig, height uint64) bool {
+	return config != nil && config.IsBlockContext(height)
+}
+
+// signatureTargets returns the signature targets of t at the fork heights
+// of config.
+func signatureTargets(t types.Txi, config *params.ChainConfig) []byte {
+	if seq, ok := t.(*tx_types.Sequencer); ok {
+		return seq.SignatureTargetsAt(timestampSigned(config, seq.Height))
+	}
+	return t.SignatureTargets()
+}
+
+// calcTxHash returns the hash of t at the fork heights of config.
+func calcTxHash(t types.Txi, config *params.ChainConfig) common.Hash {
+	if seq, ok := t.(*tx_types.Sequencer); ok {
+		return seq.CalcTxHashAt(timestampSigned(config, seq.Height))
+	}
+	return t.CalcTxHash()
+}
+
+// GetMaxTxHash returns the difficulty of TxHash.
+func (v *TxFormatVerifier) GetMaxTxHash() common.Hash {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.MaxTxHash
+}
+
+// SetMaxTxHash changes the difficulty of TxHash.
+func (v *TxFormatVerifier) SetMaxTxHash(hash common.Hash) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.MaxTxHash = hash
 }
 
 //consensus related verification
@@ -49,6 +95,7 @@ type ConsensusVerifier struct {
 	VerifyCampaign   func(cp *tx_types.Campaign) bool
 	VerifyTermChange func(cp *tx_types.TermChange) bool
 	VerifySequencer  func(cp *tx_types.Sequencer) bool
+	VerifyGovernance func(tx *tx_types.ActionTx) bool
 }
 
 func (c *ConsensusVerifier) Verify(t types.Txi) bool {
@@ -58,6 +105,9 @@ func (c *ConsensusVerifier) Verify(t types.Txi) bool {
 	case *tx_types.Archive:
 		return true
 	case *tx_types.ActionTx:
+		if tx.IsGovernance() && c.VerifyGovernance != nil {
+			return c.VerifyGovernance(tx)
+		}
 		return true
 	case *tx_types.Sequencer:
 		return c.VerifySequencer(tx)
@@ -110,9 +160,15 @@ func (v *TxFormatVerifier) Verify(t types.Txi) bool {
 		logrus.WithField("tx", t).Debug("Hash not valid")
 		return false
 	}
+	if archive, ok := t.(*tx_types.Archive); ok {
+		if _, err := archive.ContentHash(); err != nil {
+			logrus.WithField("tx", t).WithError(err).Debug("archive content not valid")
+			return false
+		}
+	}
 	if v.NoVerifySignatrue {
 		if !v.VerifySignature(t) {
-			logrus.WithField("sig targets ", hex.EncodeToString(t.SignatureTargets())).WithField("tx dump: ", t.Dump()).WithField("tx", t).Debug("Signature not valid")
+			logrus.WithField("sig targets ", hex.EncodeToString(signatureTargets(t, v.ChainConfig))).WithField("tx dump: ", t.Dump()).WithField("tx", t).Debug("Signature not valid")
 			return false
 		}
 	}
@@ -128,12 +184,12 @@ func (v *TxFormatVerifier) VerifyHash(t types.Txi) bool {
 			return false
 		}
 	}
-	if calcHash := t.CalcTxHash(); calcHash != t.GetTxHash() {
+	if calcHash := calcTxHash(t, v.ChainConfig); calcHash != t.GetTxHash() {
 		logrus.WithField("calcHash ", calcHash).WithField("tx", t).WithField("hash", t.GetTxHash()).Debug("TxHash is not aligned with content")
 		return false
 	}
 
-	if !v.NoVerifyMaxTxHash && !(t.GetTxHash().Cmp(v.MaxTxHash) < 0) {
+	if !v.NoVerifyMaxTxHash && !(t.GetTxHash().Cmp(v.GetMaxTxHash()) < 0) {
 		logrus.WithField("tx", t).WithField("hash", t.GetTxHash()).Debug("TxHash is not less than MaxTxHash")
 		return false
 	}
@@ -141,7 +197,8 @@ func (v *TxFormatVerifier) VerifyHash(t types.Txi) bool {
 }
 
 func (v *TxFormatVerifier) VerifySignature(t types.Txi) bool {
-	if t.GetType() == types.TxBaseTypeArchive {
+	// anonymous archives are not signed.
+	if archive, ok := t.(*tx_types.Archive); ok && !archive.Signed() {
 		return true
 	}
 	base := t.GetBase()
@@ -154,7 +211,7 @@ func (v *TxFormatVerifier) VerifySignature(t types.Txi) bool {
 		ok := crypto.Signer.Verify(
 			crypto.Signer.PublicKeyFromBytes(base.PublicKey),
 			crypto.Signature{Type: crypto.Signer.GetCryptoType(), Bytes: base.Signature},
-			t.SignatureTargets())
+			signatureTargets(t, v.ChainConfig))
 		return ok
 	}
 
@@ -178,7 +235,7 @@ func (v *TxFormatVerifier) VerifySignature(t types.Txi) bool {
 	copy(sig[32-len(r):32], r)
 	copy(sig[64-len(s):64], s)
 	sig[64] = V
-	sighash := Sha256(t.SignatureTargets())
+	sighash := Sha256(signatureTargets(t, v.ChainConfig))
 	// recover the public key from the signature
 	pub, err := crypto.Ecrecover(sighash[:], sig)
 	if err != nil {
@@ -227,7 +284,11 @@ func (v *TxFormatVerifier) VerifySourceAddress(t types.Txi) bool {
 	case *tx_types.TermChange:
 		return t.(*tx_types.TermChange).Issuer.Bytes == crypto.Signer.Address(crypto.Signer.PublicKeyFromBytes(t.GetBase().PublicKey)).Bytes
 	case *tx_types.Archive:
-		return true
+		from := t.(*tx_types.Archive).From
+		if from == nil {
+			return !t.(*tx_types.Archive).Signed()
+		}
+		return from.Bytes == crypto.Signer.Address(crypto.Signer.PublicKeyFromBytes(t.GetBase().PublicKey)).Bytes
 	default:
 		return true
 	}
@@ -310,7 +371,7 @@ func (v *GraphVerifier) getMyPreviousTx(currentTx types.Txi) (previousTx types.T
 		txi, archived := v.getTxFromAnywhere(head)
 		if txi != nil {
 			// found. verify nonce
-			if txi.GetType() != types.TxBaseTypeArchive {
+			if !tx_types.AnonymousArchive(txi) {
 				if txi.Sender() == currentTx.Sender() {
 					// verify if the nonce is larger
 					if txi.GetNonce() == currentTx.GetNonce()-1 {
@@ -456,11 +517,11 @@ func (v *GraphVerifier) verifyA3(txi types.Txi) bool {
 	// constantly check the ancestors until the same one issued by me is found.
 	// or nonce reaches 1
 
-	if txi.GetType() == types.TxBaseTypeArchive {
+	if tx_types.AnonymousArchive(txi) {
 		return true
 	}
 	if status.ArchiveMode {
-		if txi.GetType() != types.TxBaseTypeSequencer {
+		if txi.GetType() != types.TxBaseTypeSequencer && txi.GetType() != types.TxBaseTypeArchive {
 			logrus.Warn("archive mode , only process archive")
 			return false
 		}
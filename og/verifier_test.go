@@ -20,8 +20,10 @@ import (
 	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/types/tx_types"
+	"github.com/annchain/OG/vm/eth/params"
 	"github.com/magiconair/properties/assert"
 	"github.com/sirupsen/logrus"
+	"math/big"
 	"testing"
 )
 
@@ -272,3 +274,21 @@ type TestSigner struct {
 func (s *TestSigner) CanRecoverPubFromSig() bool {
 	return true
 }
+
+func TestTxFormatVerifier_SequencerTimestamp(t *testing.T) {
+	config := &params.ChainConfig{BlockContextHeight: big.NewInt(10)}
+	for _, height := range []uint64{9, 10} {
+		seq := tx_types.RandomSequencer()
+		seq.Height = height
+		seq.Hash = seq.CalcTxHashAt(height >= 10)
+
+		v := TxFormatVerifier{NoVerifyMindHash: true, NoVerifyMaxTxHash: true, ChainConfig: config}
+		if !v.VerifyHash(seq) {
+			t.Fatalf("hash of sequencer at height %d not verified", height)
+		}
+		seq.Timestamp++
+		if v.VerifyHash(seq) != (height < 10) {
+			t.Fatalf("timestamp of sequencer at height %d should be hashed from the fork height on", height)
+		}
+	}
+}
@@ -16,6 +16,8 @@
 
 package ogdb
 
+import "time"
+
 // Code using batches should try to add this much data to the batch.
 // The value was determined empirically.
 const IdealBatchSize = 100 * 1024
@@ -35,6 +37,11 @@ type Database interface {
 	NewBatch() Batch
 }
 
+// WriteErrorReporter is a Database reporting the last error of its writes.
+type WriteErrorReporter interface {
+	LastWriteError() (time.Time, error)
+}
+
 // Batch is a write-only database that commits changes to its host database
 // when Write is called. Batch cannot be used concurrently.
 type Batch interface {
@@ -57,6 +57,10 @@ type LevelDB struct {
 
 	quitLock sync.Mutex      // Mutex protecting the quit channel access
 	quitChan chan chan error // Quit channel to stop the metrics collection before closing the database
+
+	errLock        sync.RWMutex // Mutex protecting the last write error
+	lastWriteErr   error        // Last error of a write, for the health checks
+	lastWriteErrAt time.Time    // Time of the last error of a write
 }
 type LevelDBConfig struct {
 	Path    string
@@ -104,7 +108,7 @@ func (db *LevelDB) Path() string {
 
 // Put puts the given key / value to the queue
 func (db *LevelDB) Put(key []byte, value []byte) error {
-	return db.db.Put(key, value, nil)
+	return db.recordWriteError(db.db.Put(key, value, nil))
 }
 
 func (db *LevelDB) Has(key []byte) (bool, error) {
@@ -122,7 +126,26 @@ func (db *LevelDB) Get(key []byte) ([]byte, error) {
 
 // Delete deletes the key from the queue and database
 func (db *LevelDB) Delete(key []byte) error {
-	return db.db.Delete(key, nil)
+	return db.recordWriteError(db.db.Delete(key, nil))
+}
+
+// recordWriteError keeps err if it is not nil and returns it.
+func (db *LevelDB) recordWriteError(err error) error {
+	if err != nil {
+		db.errLock.Lock()
+		db.lastWriteErr = err
+		db.lastWriteErrAt = time.Now()
+		db.errLock.Unlock()
+	}
+	return err
+}
+
+// LastWriteError returns the time and the last error of a write, nil if
+// no write failed.
+func (db *LevelDB) LastWriteError() (time.Time, error) {
+	db.errLock.RLock()
+	defer db.errLock.RUnlock()
+	return db.lastWriteErrAt, db.lastWriteErr
 }
 
 func (db *LevelDB) NewIterator() iterator.Iterator {
@@ -379,11 +402,12 @@ func (db *LevelDB) meter(refresh time.Duration) {
 }
 
 func (db *LevelDB) NewBatch() Batch {
-	return &ldbBatch{db: db.db, b: new(leveldb.Batch)}
+	return &ldbBatch{db: db.db, ldb: db, b: new(leveldb.Batch)}
 }
 
 type ldbBatch struct {
 	db   *leveldb.DB
+	ldb  *LevelDB
 	b    *leveldb.Batch
 	size int
 }
@@ -395,7 +419,7 @@ func (b *ldbBatch) Put(key, value []byte) error {
 }
 
 func (b *ldbBatch) Write() error {
-	return b.db.Write(b.b, nil)
+	return b.ldb.recordWriteError(b.db.Write(b.b, nil))
 }
 
 func (b *ldbBatch) ValueSize() int {
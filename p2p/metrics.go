@@ -33,6 +33,8 @@ const (
 	MetricsInboundTraffic   = "p2p/InboundTraffic"   // Name for the registered inbound traffic meter
 	MetricsOutboundConnects = "p2p/OutboundConnects" // Name for the registered outbound connects meter
 	MetricsOutboundTraffic  = "p2p/OutboundTraffic"  // Name for the registered outbound traffic meter
+	MetricsPeers            = "p2p/Peers"            // Name for the registered peer count gauge
+	MetricsInboundPeers     = "p2p/InboundPeers"     // Name for the registered inbound peer count gauge
 
 	MeteredPeerLimit = 1024 // This amount of peers are individually metered
 )
@@ -42,6 +44,8 @@ var (
 	ingressTrafficMeter = metrics.NewRegisteredMeter(MetricsInboundTraffic, nil)   // Meter metering the cumulative ingress traffic
 	egressConnectMeter  = metrics.NewRegisteredMeter(MetricsOutboundConnects, nil) // Meter counting the egress connections
 	egressTrafficMeter  = metrics.NewRegisteredMeter(MetricsOutboundTraffic, nil)  // Meter metering the cumulative egress traffic
+	peersGauge          = metrics.NewRegisteredGauge(MetricsPeers, nil)            // Gauge of the connected peers
+	inboundPeersGauge   = metrics.NewRegisteredGauge(MetricsInboundPeers, nil)     // Gauge of the connected inbound peers
 
 	//PeerIngressRegistry = metrics.NewPrefixedChildRegistry(metrics.EphemeralRegistry, MetricsInboundTraffic+"/")  // Registry containing the peer ingress
 	//PeerEgressRegistry  = metrics.NewPrefixedChildRegistry(metrics.EphemeralRegistry, MetricsOutboundTraffic+"/") // Registry containing the peer egress
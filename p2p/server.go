@@ -779,6 +779,8 @@ running:
 				if p.Inbound() {
 					inboundCount++
 				}
+				peersGauge.Update(int64(len(peers)))
+				inboundPeersGauge.Update(int64(inboundCount))
 			}
 			// The dialer logic relies on the assumption that
 			// dial tasks complete after the peer has been added or
@@ -798,6 +800,8 @@ running:
 			if pd.Inbound() {
 				inboundCount--
 			}
+			peersGauge.Update(int64(len(peers)))
+			inboundPeersGauge.Update(int64(inboundCount))
 		}
 	}
 
@@ -25,6 +25,13 @@ type PerformanceReporter interface {
 	GetBenchmarks() map[string]interface{}
 }
 
+// CounterReporter is a PerformanceReporter some of whose benchmarks only
+// increase, they are exported as counters and the others as gauges.
+type CounterReporter interface {
+	PerformanceReporter
+	Counters() []string
+}
+
 type PerformanceMonitor struct {
 	reporters []PerformanceReporter
 	quit      bool
@@ -34,6 +41,10 @@ func (p *PerformanceMonitor) Register(holder PerformanceReporter) {
 	p.reporters = append(p.reporters, holder)
 }
 
+func (p *PerformanceMonitor) Reporters() []PerformanceReporter {
+	return p.reporters
+}
+
 func (p *PerformanceMonitor) Start() {
 	goroutine.New(func() {
 		p.quit = false
@@ -106,3 +106,7 @@ func (t *TxCounter) GetBenchmarks() map[string]interface{} {
 		"startupTime":        t.StartupTime.Unix(),
 	}
 }
+
+func (t *TxCounter) Counters() []string {
+	return []string{"txGenerated", "txReceived", "txConfirmed", "sequencerGenerated", "sequencerReceived", "sequencerConfirmed"}
+}
@@ -0,0 +1,187 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/annchain/OG/mylog"
+	"github.com/annchain/OG/p2p/onode"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AdminPeerRequest struct {
+	// Url is the onode url of the peer.
+	Url     string `json:"url"`
+	Trusted bool   `json:"trusted"`
+}
+
+type AdminLogLevelRequest struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+}
+
+func (r *RpcController) peerRequest(c *gin.Context) (*onode.Node, bool, error) {
+	var req AdminPeerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, false, fmt.Errorf("request format error: %v", err)
+	}
+	node, err := onode.ParseV4(req.Url)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid peer url: %v", err)
+	}
+	return node, req.Trusted, nil
+}
+
+// AdminAddPeer connects to a static peer, or marks a trusted peer which is
+// accepted beyond the peer limit.
+func (r *RpcController) AdminAddPeer(c *gin.Context) {
+	cors(c)
+	node, trusted, err := r.peerRequest(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	if trusted {
+		r.P2pServer.AddTrustedPeer(node)
+	} else {
+		r.P2pServer.AddPeer(node)
+	}
+	logrus.WithField("peer", node.String()).WithField("trusted", trusted).Info("admin added peer")
+	Response(c, http.StatusOK, nil, nil)
+}
+
+func (r *RpcController) AdminRemovePeer(c *gin.Context) {
+	cors(c)
+	node, trusted, err := r.peerRequest(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	if trusted {
+		r.P2pServer.RemoveTrustedPeer(node)
+	} else {
+		r.P2pServer.RemovePeer(node)
+	}
+	logrus.WithField("peer", node.String()).WithField("trusted", trusted).Info("admin removed peer")
+	Response(c, http.StatusOK, nil, nil)
+}
+
+func (r *RpcController) AdminPauseAutoTx(c *gin.Context) {
+	cors(c)
+	r.AutoTxCli.Pause()
+	logrus.Info("admin paused auto client")
+	Response(c, http.StatusOK, nil, nil)
+}
+
+func (r *RpcController) AdminResumeAutoTx(c *gin.Context) {
+	cors(c)
+	r.AutoTxCli.Resume()
+	logrus.Info("admin resumed auto client")
+	Response(c, http.StatusOK, nil, nil)
+}
+
+// AdminLogLevels returns the log level of each module.
+func (r *RpcController) AdminLogLevels(c *gin.Context) {
+	cors(c)
+	Response(c, http.StatusOK, nil, mylog.ModuleLevels())
+}
+
+func (r *RpcController) AdminSetLogLevel(c *gin.Context) {
+	var req AdminLogLevelRequest
+	cors(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	if req.Module == "" {
+		req.Module = mylog.MainModule
+	}
+	if err := mylog.SetModuleLevel(req.Module, req.Level); err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	logrus.WithField("module", req.Module).WithField("level", req.Level).Info("admin changed log level")
+	Response(c, http.StatusOK, nil, mylog.ModuleLevels())
+}
+
+// AdminClearPool drops all the txs in the pool.
+func (r *RpcController) AdminClearPool(c *gin.Context) {
+	cors(c)
+	r.Og.TxPool.ClearAll()
+	logrus.Warn("admin cleared tx pool")
+	Response(c, http.StatusOK, nil, nil)
+}
+
+// AdminResync forces the catchup syncer to sync to the best peer.
+func (r *RpcController) AdminResync(c *gin.Context) {
+	cors(c)
+	r.SyncerManager.CatchupSyncer.Resync()
+	Response(c, http.StatusOK, nil, nil)
+}
+
+// dumpFile writes data to a new file of the dump dir and returns its path.
+func (r *RpcController) dumpFile(name string, data []byte) (string, error) {
+	dir := r.DumpDir
+	if dir == "" {
+		dir = "dump"
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s", time.Now().Format("20060102_150405.000000"), name))
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	logrus.WithField("path", path).Info("admin dumped file")
+	return path, nil
+}
+
+// AdminDumpTxBuffer writes the txs waiting for their parents in the tx
+// buffer to a file.
+func (r *RpcController) AdminDumpTxBuffer(c *gin.Context) {
+	cors(c)
+	path, err := r.dumpFile("tx_buffer.txt", []byte(r.TxBuffer.Dump()))
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("dump tx buffer failed: %v", err), nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, path)
+}
+
+func (r *RpcController) AdminDumpBftStatus(c *gin.Context) {
+	cors(c)
+	if r.AnnSensus == nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("annsensus not enabled"), nil)
+		return
+	}
+	data, err := json.MarshalIndent(r.AnnSensus.GetBftStatus(), "", "  ")
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("marshal bft status failed: %v", err), nil)
+		return
+	}
+	path, err := r.dumpFile("bft_status.json", data)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("dump bft status failed: %v", err), nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, path)
+}
@@ -0,0 +1,76 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annchain/OG/mylog"
+	"github.com/sirupsen/logrus"
+)
+
+type testAutoTxClient struct {
+	paused bool
+}
+
+func (t *testAutoTxClient) SetTxIntervalUs(i int) {}
+func (t *testAutoTxClient) Pause()                { t.paused = true }
+func (t *testAutoTxClient) Resume()               { t.paused = false }
+
+func serveAdmin(t *testing.T, rpc *RpcController, method, path string, body interface{}) *httptest.ResponseRecorder {
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	// the admin routes are only served to local clients without auth.
+	req.RemoteAddr = "127.0.0.1:1234"
+	rpc.NewRouter().ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminLogLevel(t *testing.T) {
+	logger := logrus.New()
+	mylog.InitLogger(logger, "", "og_admintest")
+	rpc := &RpcController{}
+
+	w := serveAdmin(t, rpc, http.MethodPost, "/admin/log_level", AdminLogLevelRequest{Module: "admintest", Level: "trace"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	if mylog.ModuleLevels()["admintest"] != "trace" {
+		t.Fatalf("log level not changed: %v", mylog.ModuleLevels())
+	}
+	for _, req := range []AdminLogLevelRequest{{Module: "unknown", Level: "debug"}, {Module: "admintest", Level: "loud"}} {
+		if w := serveAdmin(t, rpc, http.MethodPost, "/admin/log_level", req); w.Code != http.StatusBadRequest {
+			t.Fatalf("%v: expected bad request, got %d", req, w.Code)
+		}
+	}
+}
+
+func TestAdminAutoTx(t *testing.T) {
+	cli := &testAutoTxClient{}
+	rpc := &RpcController{AutoTxCli: cli}
+	if serveAdmin(t, rpc, http.MethodPost, "/admin/pause_auto_tx", nil); !cli.paused {
+		t.Fatalf("auto tx not paused")
+	}
+	if serveAdmin(t, rpc, http.MethodPost, "/admin/resume_auto_tx", nil); cli.paused {
+		t.Fatalf("auto tx not resumed")
+	}
+}
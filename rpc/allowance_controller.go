@@ -0,0 +1,205 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/status"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/token"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// NewAllowanceRequest approves, revokes or spends an allowance. Spender is
+// only used to approve or revoke, Owner and To only to transfer. Value is
+// in units of 10^decimals of the token.
+type NewAllowanceRequest struct {
+	Nonce      uint64 `json:"nonce"`
+	From       string `json:"from"`
+	Spender    string `json:"spender"`
+	Owner      string `json:"owner"`
+	To         string `json:"to"`
+	TokenId    int32  `json:"token_id"`
+	Value      string `json:"value"`
+	CryptoType string `json:"crypto_type"`
+	Signature  string `json:"signature"`
+	Pubkey     string `json:"pubkey"`
+}
+
+type AllowanceResponse struct {
+	Owner   string `json:"owner"`
+	Spender string `json:"spender"`
+	TokenId int32  `json:"token_id"`
+	Value   string `json:"value"`
+}
+
+func (r *RpcController) Approve(c *gin.Context) {
+	r.newAllowanceTx(c, tx_types.ActionTxActionApprove)
+}
+
+func (r *RpcController) Revoke(c *gin.Context) {
+	r.newAllowanceTx(c, tx_types.ActionTxActionRevoke)
+}
+
+func (r *RpcController) TransferFrom(c *gin.Context) {
+	r.newAllowanceTx(c, tx_types.ActionTxActionTransferFrom)
+}
+
+func (r *RpcController) newAllowanceTx(c *gin.Context, action uint8) {
+	var (
+		txReq NewAllowanceRequest
+		pub   crypto.PublicKey
+		data  tx_types.ActionData
+	)
+	cors(c)
+	if status.ArchiveMode {
+		Response(c, http.StatusBadRequest, fmt.Errorf("archive mode"), nil)
+		return
+	}
+	err := c.ShouldBindJSON(&txReq)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	from, err := common.StringToAddress(txReq.From)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("from address format error: %v", err), nil)
+		return
+	}
+	if action == tx_types.ActionTxActionTransferFrom {
+		transfer := tx_types.NewTransferFrom()
+		transfer.TokenId = txReq.TokenId
+		transfer.Owner, err = common.StringToAddress(txReq.Owner)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("owner address format error: %v", err), nil)
+			return
+		}
+		transfer.To, err = common.StringToAddress(txReq.To)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("to address format error: %v", err), nil)
+			return
+		}
+		transfer.Value, err = r.tokenAmount(txReq.Value, txReq.TokenId)
+		if err != nil {
+			Response(c, http.StatusBadRequest, err, nil)
+			return
+		}
+		err = transfer.Validate()
+		data = transfer
+	} else {
+		approval := tx_types.NewApproval()
+		approval.TokenId = txReq.TokenId
+		approval.Spender, err = common.StringToAddress(txReq.Spender)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("spender address format error: %v", err), nil)
+			return
+		}
+		if action == tx_types.ActionTxActionApprove {
+			approval.Value, err = r.tokenAmount(txReq.Value, txReq.TokenId)
+			if err != nil {
+				Response(c, http.StatusBadRequest, err, nil)
+				return
+			}
+		}
+		err = approval.Validate(action)
+		data = approval
+	}
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	signature := common.FromHex(txReq.Signature)
+	if signature == nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("signature format error"), nil)
+		return
+	}
+	if txReq.CryptoType == "" {
+		pub, err = crypto.PublicKeyFromString(txReq.Pubkey)
+	} else {
+		pub, err = crypto.PublicKeyFromStringWithCryptoType(txReq.CryptoType, txReq.Pubkey)
+	}
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("pubkey format error %v", err), nil)
+		return
+	}
+	sig := crypto.SignatureFromBytes(pub.Type, signature)
+	if sig.Type != crypto.Signer.GetCryptoType() || pub.Type != crypto.Signer.GetCryptoType() {
+		Response(c, http.StatusOK, fmt.Errorf("crypto algorithm mismatch"), nil)
+		return
+	}
+	tx, err := r.TxCreator.NewAllowanceTxWithSeal(from, action, data, txReq.Nonce, pub, sig)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("new tx failed %v", err), nil)
+		return
+	}
+	if !r.FormatVerifier.VerifySignature(tx) {
+		logrus.WithField("request ", txReq).WithField("tx ", tx).Warn("signature invalid")
+		Response(c, http.StatusInternalServerError, fmt.Errorf("signature invalid"), nil)
+		return
+	}
+	if !r.FormatVerifier.VerifySourceAddress(tx) {
+		logrus.WithField("request ", txReq).WithField("tx ", tx).Warn("source address invalid")
+		Response(c, http.StatusInternalServerError, fmt.Errorf("source address invalid"), nil)
+		return
+	}
+	tx.SetVerified(types.VerifiedFormat)
+	logrus.WithField("tx", tx).Debugf("tx generated")
+	if !r.SyncerManager.IncrementalSyncer.Enabled {
+		Response(c, http.StatusOK, fmt.Errorf("tx is disabled when syncing"), nil)
+		return
+	}
+
+	r.TxBuffer.ReceivedNewTxChan <- tx
+
+	Response(c, http.StatusOK, nil, tx.GetTxHash().Hex())
+}
+
+// GetAllowance shows how much of owner's token spender is allowed to move,
+// in units of 10^decimals of the token.
+func (r *RpcController) GetAllowance(c *gin.Context) {
+	cors(c)
+	owner, err := common.StringToAddress(c.Query("owner"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("owner address format error: %v", err), nil)
+		return
+	}
+	spender, err := common.StringToAddress(c.Query("spender"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("spender address format error: %v", err), nil)
+		return
+	}
+	tokenID := token.OGTokenID
+	if tokenIDStr := c.Query("token_id"); tokenIDStr != "" {
+		t, err := strconv.Atoi(tokenIDStr)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("tokenID format err: %v", err), nil)
+			return
+		}
+		tokenID = int32(t)
+	}
+	allowance := r.Og.Dag.GetAllowance(owner, spender, tokenID)
+	Response(c, http.StatusOK, nil, AllowanceResponse{
+		Owner:   owner.Hex(),
+		Spender: spender.Hex(),
+		TokenId: tokenID,
+		Value:   allowance.DecimalString(r.tokenDecimals(tokenID)),
+	})
+}
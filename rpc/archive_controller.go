@@ -15,9 +15,11 @@
 package rpc
 
 import (
-	"bytes"
 	"fmt"
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
 	"github.com/annchain/OG/vm/eth/common/math"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -26,6 +28,8 @@ import (
 	"time"
 )
 
+const maxArchiveContentTypeLength = 255
+
 var archiveId uint32
 
 func getArchiveId() uint32 {
@@ -56,15 +60,47 @@ func (r *RpcController) NewArchive(c *gin.Context) {
 		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: no data "), nil)
 		return
 	}
-	var buf bytes.Buffer
-	buf.Write(txReq.Data)
-	//TODO compress data
+	if len(txReq.Data) > tx_types.MaxArchiveContentSize {
+		Response(c, http.StatusBadRequest, fmt.Errorf("data exceeds %d bytes", tx_types.MaxArchiveContentSize), nil)
+		return
+	}
+	if len(txReq.ContentType) > maxArchiveContentTypeLength {
+		Response(c, http.StatusBadRequest, fmt.Errorf("content type exceeds %d bytes", maxArchiveContentTypeLength), nil)
+		return
+	}
+	compression, err := archiveCompression(txReq.Compression)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	data, err := tx_types.CompressArchiveData(txReq.Data, compression)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("compress data failed: %v", err), nil)
+		return
+	}
 	logrus.WithField("id ", id).WithField("data  ", string(txReq.Data)).Trace("got archive request")
-	tx, err = r.TxCreator.NewArchiveWithSeal(buf.Bytes())
+	if txReq.Signature == "" {
+		tx, err = r.TxCreator.NewCompressedArchiveWithSeal(data, txReq.ContentType, compression)
+	} else {
+		tx, err = r.newSignedArchive(txReq, data, compression)
+	}
 	if err != nil {
 		Response(c, http.StatusInternalServerError, fmt.Errorf("new tx failed %v", err), nil)
 		return
 	}
+	if txReq.Signature != "" {
+		if !r.FormatVerifier.VerifySignature(tx) {
+			logrus.WithField("request ", txReq).WithField("tx ", tx).Warn("signature invalid")
+			Response(c, http.StatusInternalServerError, fmt.Errorf("signature invalid"), nil)
+			return
+		}
+		if !r.FormatVerifier.VerifySourceAddress(tx) {
+			logrus.WithField("request ", txReq).WithField("tx ", tx).Warn("source address invalid")
+			Response(c, http.StatusInternalServerError, fmt.Errorf("source address invalid"), nil)
+			return
+		}
+		tx.SetVerified(types.VerifiedFormat)
+	}
 	logrus.WithField("id ", id).WithField("tx", tx).Debugf("tx generated")
 	if !r.SyncerManager.IncrementalSyncer.Enabled {
 		Response(c, http.StatusOK, fmt.Errorf("tx is disabled when syncing"), nil)
@@ -79,3 +115,157 @@ func (r *RpcController) NewArchive(c *gin.Context) {
 	Response(c, http.StatusOK, nil, tx.GetTxHash().Hex())
 	return
 }
+
+// newSignedArchive seals the archive signed by the account of the request.
+func (r *RpcController) newSignedArchive(txReq NewArchiveRequest, data []byte, compression uint8) (types.Txi, error) {
+	var pub crypto.PublicKey
+	from, err := common.StringToAddress(txReq.From)
+	if err != nil {
+		return nil, fmt.Errorf("from address format error: %v", err)
+	}
+	signature := common.FromHex(txReq.Signature)
+	if signature == nil {
+		return nil, fmt.Errorf("signature format error")
+	}
+	if txReq.CryptoType == "" {
+		pub, err = crypto.PublicKeyFromString(txReq.Pubkey)
+	} else {
+		pub, err = crypto.PublicKeyFromStringWithCryptoType(txReq.CryptoType, txReq.Pubkey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pubkey format error %v", err)
+	}
+	sig := crypto.SignatureFromBytes(pub.Type, signature)
+	if sig.Type != crypto.Signer.GetCryptoType() || pub.Type != crypto.Signer.GetCryptoType() {
+		return nil, fmt.Errorf("crypto algorithm mismatch")
+	}
+	return r.TxCreator.NewSignedArchiveWithSeal(from, data, txReq.ContentType, compression, txReq.Nonce, pub, sig)
+}
+
+func archiveCompression(compression string) (uint8, error) {
+	switch compression {
+	case "", "none":
+		return tx_types.ArchiveCompressionNone, nil
+	case "gzip":
+		return tx_types.ArchiveCompressionGzip, nil
+	default:
+		return 0, fmt.Errorf("unknown compression %s", compression)
+	}
+}
+
+// ArchiveResponse is an archive with its decompressed data
+type ArchiveResponse struct {
+	Hash        string `json:"hash"`
+	From        string `json:"from"`
+	ContentType string `json:"content_type"`
+	ContentHash string `json:"content_hash"`
+	Data        []byte `json:"data"`
+	SeqHeight   uint64 `json:"seq_height"`
+}
+
+// ProofOfExistenceResponse tells when a content was first archived
+type ProofOfExistenceResponse struct {
+	ContentHash string `json:"content_hash"`
+	TxHash      string `json:"tx_hash"`
+	From        string `json:"from"`
+	SeqHash     string `json:"seq_hash"`
+	SeqHeight   uint64 `json:"seq_height"`
+	// Timestamp is the timestamp of the confirming sequencer in milliseconds.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// confirmedArchives returns the confirmed archives holding the content whose
+// sha256 is contentHash, ordered by the height of their confirming sequencer.
+func (r *RpcController) confirmedArchives(contentHash common.Hash) []*tx_types.Archive {
+	var archives []*tx_types.Archive
+	for _, hash := range r.Og.Dag.GetArchiveHashes(contentHash) {
+		archive, ok := r.Og.Dag.GetTx(hash).(*tx_types.Archive)
+		if !ok {
+			continue
+		}
+		archives = append(archives, archive)
+	}
+	return archives
+}
+
+func (r *RpcController) Archive(c *gin.Context) {
+	cors(c)
+	contentHash, err := common.HexStringToHash(c.Query("content_hash"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("content hash format error: %v", err), nil)
+		return
+	}
+	archives := r.confirmedArchives(contentHash)
+	if len(archives) == 0 {
+		Response(c, http.StatusNotFound, fmt.Errorf("archive not found"), nil)
+		return
+	}
+	var resp []ArchiveResponse
+	for _, archive := range archives {
+		content, err := archive.Content()
+		if err != nil {
+			Response(c, http.StatusInternalServerError, fmt.Errorf("decompress archive %s failed: %v", archive.GetTxHash().Hex(), err), nil)
+			return
+		}
+		a := ArchiveResponse{
+			Hash:        archive.GetTxHash().Hex(),
+			ContentType: archive.ContentType,
+			ContentHash: contentHash.Hex(),
+			Data:        content,
+			SeqHeight:   archive.Height,
+		}
+		if archive.From != nil {
+			a.From = archive.From.Hex()
+		}
+		resp = append(resp, a)
+	}
+	Response(c, http.StatusOK, nil, resp)
+}
+
+func (r *RpcController) ProofOfExistence(c *gin.Context) {
+	cors(c)
+	contentHash, err := common.HexStringToHash(c.Query("content_hash"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("content hash format error: %v", err), nil)
+		return
+	}
+	// only the archives signed by from prove the existence if it is given.
+	var from *common.Address
+	if fromStr := c.Query("from"); fromStr != "" {
+		addr, err := common.StringToAddress(fromStr)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("from address format error: %v", err), nil)
+			return
+		}
+		from = &addr
+	}
+	var first *tx_types.Archive
+	for _, archive := range r.confirmedArchives(contentHash) {
+		if from != nil && (archive.From == nil || archive.From.Bytes != from.Bytes) {
+			continue
+		}
+		if first == nil || archive.Height < first.Height {
+			first = archive
+		}
+	}
+	if first == nil {
+		Response(c, http.StatusNotFound, fmt.Errorf("archive not found"), nil)
+		return
+	}
+	seq := r.Og.Dag.GetSequencerByHeight(first.Height)
+	if seq == nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("sequencer %d not found", first.Height), nil)
+		return
+	}
+	resp := ProofOfExistenceResponse{
+		ContentHash: contentHash.Hex(),
+		TxHash:      first.GetTxHash().Hex(),
+		SeqHash:     seq.GetTxHash().Hex(),
+		SeqHeight:   seq.Height,
+		Timestamp:   seq.Timestamp,
+	}
+	if first.From != nil {
+		resp.From = first.From.Hex()
+	}
+	Response(c, http.StatusOK, nil, resp)
+}
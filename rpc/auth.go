@@ -0,0 +1,71 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"fmt"
+	"github.com/annchain/OG/auth"
+	"github.com/gin-gonic/gin"
+	"net"
+	"net/http"
+)
+
+// corsOriginKey holds in the context the origin allowed by the
+// authenticator, cors defaults to any origin without it.
+const corsOriginKey = "cors_origin"
+
+// authorize is the middleware checking that the client is granted group.
+// If no authenticator is set, it lets all requests through but the ones
+// of the admin group, which are only served to the local clients.
+func (r *RpcController) authorize(group auth.Group) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if r.Auth == nil {
+			if group == auth.GroupAdmin && !isLoopback(c.Request) {
+				Response(c, http.StatusForbidden, fmt.Errorf("admin api is only served to local clients without auth"), nil)
+				c.Abort()
+			}
+			return
+		}
+		c.Set(corsOriginKey, r.Auth.AllowedOrigin(c.GetHeader("Origin")))
+		if _, err := r.Auth.Authorize(c.Request, group); err != nil {
+			cors(c)
+			Response(c, auth.StatusCode(err), err, nil)
+			c.Abort()
+		}
+	}
+}
+
+// preflight answers the cors preflight requests, which browsers send
+// without the credential before the requests carrying it.
+func (r *RpcController) preflight(c *gin.Context) {
+	if c.Request.Method != http.MethodOptions {
+		return
+	}
+	if r.Auth != nil {
+		c.Set(corsOriginKey, r.Auth.AllowedOrigin(c.GetHeader("Origin")))
+	}
+	cors(c)
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// isLoopback tells if the request comes from the loopback address. The
+// forwarded headers are ignored since any client can set them.
+func isLoopback(req *http.Request) bool {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,121 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/annchain/OG/auth"
+)
+
+func TestAuthorize(t *testing.T) {
+	a, err := auth.NewAuthenticator(auth.Config{
+		Keys:            []auth.Key{{Name: "ops", Key: "ops-key", Groups: []auth.Group{auth.GroupAdmin}}},
+		AnonymousGroups: []auth.Group{auth.GroupPublic},
+		AllowedOrigins:  []string{"https://explorer.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc := &RpcController{Auth: a}
+	router := rpc.NewRouter()
+
+	for i, c := range []struct {
+		method, path, key string
+		status            int
+	}{
+		{http.MethodGet, "/ping", "", http.StatusOK},
+		{http.MethodGet, "/debug/pool_hashes", "", http.StatusForbidden},
+		{http.MethodPost, "/new_account", "", http.StatusForbidden},
+		{http.MethodPost, "/new_transaction", "", http.StatusForbidden},
+		{http.MethodGet, "/ping", "bad-key", http.StatusUnauthorized},
+		{http.MethodGet, "/auto_tx?interval_us=x", "ops-key", http.StatusBadRequest},
+	} {
+		r := httptest.NewRequest(c.method, c.path, nil)
+		r.Header.Set("Origin", "https://explorer.example")
+		if c.key != "" {
+			r.Header.Set("X-API-Key", c.key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != c.status {
+			t.Fatalf("case %d: expected status %d, got %d", i, c.status, w.Code)
+		}
+		if (c.status == http.StatusForbidden || c.status == http.StatusUnauthorized) && w.Header().Get("Access-Control-Allow-Origin") != "https://explorer.example" {
+			t.Fatalf("case %d: allowed origin not set", i)
+		}
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	a, err := auth.NewAuthenticator(auth.Config{
+		Keys:           []auth.Key{{Name: "wallet", Key: "wallet-key", Groups: []auth.Group{auth.GroupTx}}},
+		AllowedOrigins: []string{"https://explorer.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc := &RpcController{Auth: a}
+	router := rpc.NewRouter()
+
+	for i, c := range []struct {
+		origin, allowed string
+	}{
+		{"https://explorer.example", "https://explorer.example"},
+		{"https://evil.example", ""},
+	} {
+		r := httptest.NewRequest(http.MethodOptions, "/new_transaction", nil)
+		r.Header.Set("Origin", c.origin)
+		r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		r.Header.Set("Access-Control-Request-Headers", "X-API-Key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("case %d: expected status %d, got %d", i, http.StatusNoContent, w.Code)
+		}
+		if w.Header().Get("Access-Control-Allow-Origin") != c.allowed {
+			t.Fatalf("case %d: expected allowed origin %q, got %q", i, c.allowed, w.Header().Get("Access-Control-Allow-Origin"))
+		}
+		if c.allowed != "" && !strings.Contains(w.Header().Get("Access-Control-Allow-Headers"), "X-API-Key") {
+			t.Fatalf("case %d: X-API-Key header not allowed", i)
+		}
+	}
+}
+
+func TestAuthorizeWithoutAuthenticator(t *testing.T) {
+	rpc := &RpcController{}
+	router := rpc.NewRouter()
+
+	for i, c := range []struct {
+		method, path, remote string
+		status               int
+	}{
+		{http.MethodGet, "/ping", "192.0.2.1:1234", http.StatusOK},
+		{http.MethodGet, "/auto_tx?interval_us=x", "192.0.2.1:1234", http.StatusForbidden},
+		{http.MethodGet, "/auto_tx?interval_us=x", "127.0.0.1:1234", http.StatusBadRequest},
+		{http.MethodGet, "/auto_tx?interval_us=x", "[::1]:1234", http.StatusBadRequest},
+	} {
+		r := httptest.NewRequest(c.method, c.path, nil)
+		r.RemoteAddr = c.remote
+		r.Header.Set("X-Forwarded-For", "127.0.0.1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != c.status {
+			t.Fatalf("case %d: expected status %d, got %d", i, c.status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/annchain/OG/vm/abi"
+	vmtypes "github.com/annchain/OG/vm/types"
+	"github.com/gin-gonic/gin"
+)
+
+type RegisterABIRequest struct {
+	Address string `json:"address"`
+	// Abi is the json abi, either as is or in a string.
+	Abi json.RawMessage `json:"abi"`
+	// Nonce should be greater than the one of the last registration of the
+	// abi of the contract.
+	Nonce uint64 `json:"nonce"`
+	// Pubkey and Signature are of the creator of the contract, signing the
+	// address, the nonce and the abi.
+	Pubkey    string `json:"pubkey"`
+	Signature string `json:"signature"`
+}
+
+// LogResponse is a log emitted by a contract, decoded if the abi of the
+// contract is registered.
+type LogResponse struct {
+	Address string            `json:"address"`
+	Topics  []string          `json:"topics"`
+	Data    hexutil.Bytes     `json:"data"`
+	Index   uint              `json:"index"`
+	Event   *abi.DecodedEvent `json:"event,omitempty"`
+}
+
+// RegisterABI stores the abi of a contract, with which query_contract
+// takes a method and its arguments and the receipts and logs of the
+// contract are decoded. The request must be signed by the creator of the
+// contract.
+func (r *RpcController) RegisterABI(c *gin.Context) {
+	cors(c)
+	var req RegisterABIRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	addr, err := common.StringToAddress(req.Address)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+		return
+	}
+	data := unquoteABI(req.Abi)
+	pub, err := crypto.PublicKeyFromString(req.Pubkey)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("pubkey format error %v", err), nil)
+		return
+	}
+	signature := common.FromHex(req.Signature)
+	if len(signature) == 0 {
+		Response(c, http.StatusBadRequest, fmt.Errorf("signature format error"), nil)
+		return
+	}
+	sig := crypto.SignatureFromBytes(pub.Type, signature)
+	if !crypto.Signer.Verify(pub, sig, abiSignatureTargets(addr, req.Nonce, data)) {
+		Response(c, http.StatusBadRequest, fmt.Errorf("signature invalid"), nil)
+		return
+	}
+	err = r.Og.Dag.WriteContractABI(addr, pub.Address(), req.Nonce, data)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, addr.Hex())
+}
+
+// abiSignatureTargets returns the content signed to register the abi of
+// the contract at addr.
+func abiSignatureTargets(addr common.Address, nonce uint64, data []byte) []byte {
+	w := types.NewBinaryWriter()
+	w.Write(addr.Bytes, nonce, data)
+	return w.Bytes()
+}
+
+// unquoteABI returns the json abi which may be given in a json string.
+func unquoteABI(raw json.RawMessage) []byte {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []byte(s)
+	}
+	return raw
+}
+
+// contractABI returns the abi given in the request or, if empty, the one
+// registered for addr.
+func (r *RpcController) contractABI(addr common.Address, abiStr string) (*abi.ABI, error) {
+	if abiStr != "" {
+		return abi.JSON([]byte(abiStr))
+	}
+	contractABI := r.Og.Dag.GetContractABI(addr)
+	if contractABI == nil {
+		return nil, fmt.Errorf("abi of %s not registered", addr.Hex())
+	}
+	return contractABI, nil
+}
+
+func newReceiptResponse(receipt *core.Receipt) *ReceiptResponse {
+	return &ReceiptResponse{
+		TxHash:          receipt.TxHash.Hex(),
+		Status:          int(receipt.Status),
+		Result:          receipt.ProcessResult,
+		ContractAddress: receipt.ContractAddress.Hex(),
+		RevertReason:    receipt.RevertReason(),
+	}
+}
+
+// decodeReceipt fills the decoded return values and logs of the contract
+// call tx into rr.
+func (r *RpcController) decodeReceipt(rr *ReceiptResponse, receipt *core.Receipt) {
+	for _, l := range r.Og.Dag.GetTxLogs(receipt.TxHash) {
+		rr.Logs = append(rr.Logs, r.newLogResponse(l))
+	}
+
+	tx, ok := r.Og.Dag.GetTx(receipt.TxHash).(*tx_types.Tx)
+	if !ok || receipt.Status != core.ReceiptStatusSuccess || tx.To.Bytes == (common.Address{}).Bytes {
+		return
+	}
+	contractABI := r.Og.Dag.GetContractABI(tx.To)
+	if contractABI == nil {
+		return
+	}
+	method := contractABI.MethodByID(tx.Data)
+	if method == nil {
+		return
+	}
+	ret := common.FromHex(receipt.ProcessResult)
+	outputs, err := method.UnpackOutputs(ret)
+	if err != nil {
+		return
+	}
+	rr.Method = method.Name
+	rr.Outputs = outputs
+}
+
+func (r *RpcController) newLogResponse(l *vmtypes.Log) LogResponse {
+	resp := LogResponse{
+		Address: l.Address.Hex(),
+		Data:    l.Data,
+		Index:   l.Index,
+	}
+	for _, topic := range l.Topics {
+		resp.Topics = append(resp.Topics, topic.Hex())
+	}
+	if contractABI := r.Og.Dag.GetContractABI(l.Address); contractABI != nil {
+		resp.Event, _ = contractABI.DecodeLog(l)
+	}
+	return resp
+}
+
+type ContractInfoResponse struct {
+	Address   string `json:"address"`
+	Creator   string `json:"creator,omitempty"`
+	TxHash    string `json:"tx_hash,omitempty"`
+	SeqHeight uint64 `json:"seq_height,omitempty"`
+	CodeHash  string `json:"code_hash"`
+	CodeSize  int    `json:"code_size"`
+}
+
+// GetCode returns the code of a contract.
+func (r *RpcController) GetCode(c *gin.Context) {
+	cors(c)
+	addr, err := common.StringToAddress(c.Query("address"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, hexutil.Bytes(r.Og.Dag.GetCode(addr)))
+}
+
+// GetStorageAt returns a storage slot of a contract, at the latest
+// sequencer or at the given height.
+func (r *RpcController) GetStorageAt(c *gin.Context) {
+	cors(c)
+	addr, err := common.StringToAddress(c.Query("address"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+		return
+	}
+	keyBytes := common.FromHex(c.Query("key"))
+	if keyBytes == nil || len(keyBytes) > common.HashLength {
+		Response(c, http.StatusBadRequest, fmt.Errorf("key format error"), nil)
+		return
+	}
+	key := common.BytesToHash(common.LeftPadBytes(keyBytes, common.HashLength))
+
+	if heightStr := c.Query("height"); heightStr != "" {
+		height, err := strconv.ParseUint(heightStr, 10, 64)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("height format error: %v", err), nil)
+			return
+		}
+		value, err := r.Og.Dag.GetStateAt(addr, key, height)
+		if err != nil {
+			Response(c, http.StatusNotFound, err, nil)
+			return
+		}
+		Response(c, http.StatusOK, nil, value.Hex())
+		return
+	}
+	Response(c, http.StatusOK, nil, r.Og.Dag.GetState(addr, key).Hex())
+}
+
+// GetContractInfo returns the creator, creating tx and code hash and size
+// of a contract.
+func (r *RpcController) GetContractInfo(c *gin.Context) {
+	cors(c)
+	addr, err := common.StringToAddress(c.Query("address"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+		return
+	}
+	code := r.Og.Dag.GetCode(addr)
+	info := r.Og.Dag.GetContractInfo(addr)
+	if len(code) == 0 && info == nil {
+		Response(c, http.StatusNotFound, fmt.Errorf("contract not found"), nil)
+		return
+	}
+	resp := ContractInfoResponse{
+		Address:  addr.Hex(),
+		CodeHash: r.Og.Dag.GetCodeHash(addr).Hex(),
+		CodeSize: len(code),
+	}
+	if info != nil {
+		resp.Creator = info.Creator.Hex()
+		resp.TxHash = info.TxHash.Hex()
+		resp.SeqHeight = info.SeqHeight
+	}
+	Response(c, http.StatusOK, nil, resp)
+}
@@ -15,9 +15,12 @@ package rpc
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 
+	"github.com/annchain/OG/auth"
 	"github.com/annchain/OG/consensus/annsensus"
+	"github.com/annchain/OG/health"
 	"github.com/annchain/OG/types/token"
 	"github.com/annchain/OG/types/tx_types"
 
@@ -33,6 +36,8 @@ import (
 	"github.com/annchain/OG/p2p"
 	"github.com/annchain/OG/performance"
 	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/vm/abi"
+	vmtypes "github.com/annchain/OG/vm/types"
 	"github.com/gin-gonic/gin"
 )
 
@@ -47,10 +52,20 @@ type RpcController struct {
 	NewRequestChan     chan types.TxBaseType
 	AnnSensus          *annsensus.AnnSensus
 	FormatVerifier     *og.TxFormatVerifier
+	// Auth authorizes the requests by route group, all are allowed if nil.
+	Auth *auth.Authenticator
+	// DumpDir is where the admin dumps are written.
+	DumpDir string
+	// LiveChecker and ReadyChecker report the liveness and readiness of
+	// the node.
+	LiveChecker  *health.Checker
+	ReadyChecker *health.Checker
 }
 
 type AutoTxClient interface {
 	SetTxIntervalUs(i int)
+	Pause()
+	Resume()
 }
 
 //TxRequester
@@ -65,7 +80,16 @@ type SequenceRequester interface {
 
 //NewArchiveRequest for RPC request
 type NewArchiveRequest struct {
-	Data []byte `json:"data"`
+	Data        []byte `json:"data"`
+	ContentType string `json:"content_type"`
+	// Compression is the compression of the stored data, "none" or "gzip".
+	Compression string `json:"compression"`
+	// the archive is signed by From if Signature is set.
+	Nonce      uint64 `json:"nonce"`
+	From       string `json:"from"`
+	CryptoType string `json:"crypto_type"`
+	Signature  string `json:"signature"`
+	Pubkey     string `json:"pubkey"`
 }
 
 //NewAccountRequest for RPC request
@@ -73,8 +97,21 @@ type NewAccountRequest struct {
 	Algorithm string `json:"algorithm"`
 }
 
+// cors allows the browsers of the allowed origin to read the response and
+// to send the credential headers.
 func cors(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", "*")
+	origin := "*"
+	if allowed, ok := c.Get(corsOriginKey); ok {
+		origin = allowed.(string)
+	}
+	if origin != "" {
+		c.Header("Access-Control-Allow-Origin", origin)
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		if origin != "*" {
+			c.Header("Vary", "Origin")
+		}
+	}
 }
 
 //Query query
@@ -191,6 +228,31 @@ func (r *RpcController) Confirm(c *gin.Context) {
 
 }
 
+// Finality returns the finality certificate of a confirmed tx.
+func (r *RpcController) Finality(c *gin.Context) {
+	hashtr := c.Query("hash")
+	hash, err := common.HexStringToHash(hashtr)
+	cors(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("hash format error"), nil)
+		return
+	}
+	if r.AnnSensus == nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("annsensus not enabled"), nil)
+		return
+	}
+	if r.Og.Dag.GetTx(hash) == nil {
+		Response(c, http.StatusNotFound, fmt.Errorf("tx not found or not confirmed"), nil)
+		return
+	}
+	cert, err := r.AnnSensus.FinalityCertificate(hash)
+	if err != nil {
+		Response(c, http.StatusNotFound, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, cert)
+}
+
 type TxsResponse struct {
 	Total int               `json:"total"`
 	Txs   []TransactionResp `json:"txs"`
@@ -489,11 +551,16 @@ func (r *RpcController) QueryBalance(c *gin.Context) {
 		}
 		tokenID = int32(t)
 	}
+	// the balances are shown in units of 10^decimals of the tokens.
 	if all == "true" {
 		b := r.Og.Dag.GetAllTokenBalance(addr)
+		balances := make(map[int32]string, len(b))
+		for id, balance := range b {
+			balances[id] = balance.DecimalString(r.tokenDecimals(id))
+		}
 		Response(c, http.StatusOK, nil, gin.H{
 			"address": address,
-			"balance": b,
+			"balance": balances,
 		})
 		return
 	}
@@ -501,7 +568,7 @@ func (r *RpcController) QueryBalance(c *gin.Context) {
 	b := r.Og.Dag.GetBalance(addr, tokenID)
 	Response(c, http.StatusOK, nil, gin.H{
 		"address": address,
-		"balance": b,
+		"balance": b.DecimalString(r.tokenDecimals(tokenID)),
 	})
 	return
 }
@@ -511,9 +578,37 @@ func (r *RpcController) QueryShare(c *gin.Context) {
 	return
 }
 
+// ContractPayload encodes a call of method with args, a json array, by
+// the abi given in abistr or registered for the contract at address.
 func (r *RpcController) ContractPayload(c *gin.Context) {
-	Response(c, http.StatusOK, nil, "not implemented yet")
-	return
+	cors(c)
+	var addr common.Address
+	var err error
+	if address := c.Query("address"); address != "" {
+		addr, err = common.StringToAddress(address)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+			return
+		}
+	}
+	contractABI, err := r.contractABI(addr, c.Query("abistr"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	var args []json.RawMessage
+	if argsStr := c.Query("args"); argsStr != "" {
+		if err := json.Unmarshal([]byte(argsStr), &args); err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("args format error: %v", err), nil)
+			return
+		}
+	}
+	payload, err := contractABI.Pack(c.Query("method"), args)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, hex.EncodeToString(payload))
 }
 
 func (r *RpcController) ConStatus(c *gin.Context) {
@@ -536,6 +631,12 @@ type ReceiptResponse struct {
 	Status          int    `json:"status"`
 	Result          string `json:"result"`
 	ContractAddress string `json:"contract_address"`
+	RevertReason    string `json:"revert_reason,omitempty"`
+	// Method and Outputs are the called method and its decoded return
+	// values, given if the abi of the contract is registered.
+	Method  string        `json:"method,omitempty"`
+	Outputs []abi.Value   `json:"outputs,omitempty"`
+	Logs    []LogResponse `json:"logs,omitempty"`
 }
 
 func (r *RpcController) QueryReceipt(c *gin.Context) {
@@ -553,19 +654,26 @@ func (r *RpcController) QueryReceipt(c *gin.Context) {
 		return
 	}
 
-	rr := ReceiptResponse{}
-	rr.TxHash = receipt.TxHash.Hex()
-	rr.Status = int(receipt.Status)
-	rr.Result = receipt.ProcessResult
-	rr.ContractAddress = receipt.ContractAddress.Hex()
+	rr := newReceiptResponse(receipt)
+	r.decodeReceipt(rr, receipt)
 
 	Response(c, http.StatusOK, nil, rr)
 	return
 }
 
+// NewQueryContractReq calls the contract with the raw data or, if Method
+// is given, with the call of Method with Args encoded by the registered
+// abi of the contract.
 type NewQueryContractReq struct {
-	Address string `json:"address"`
-	Data    string `json:"data"`
+	Address string            `json:"address"`
+	Data    string            `json:"data"`
+	Method  string            `json:"method"`
+	Args    []json.RawMessage `json:"args"`
+}
+
+type QueryContractResponse struct {
+	Data    string      `json:"data"`
+	Outputs []abi.Value `json:"outputs"`
 }
 
 func (r *RpcController) QueryContract(c *gin.Context) {
@@ -584,6 +692,10 @@ func (r *RpcController) QueryContract(c *gin.Context) {
 		Response(c, http.StatusBadRequest, err, nil)
 		return
 	}
+	if reqdata.Method != "" {
+		r.queryContractMethod(c, addr, reqdata.Method, reqdata.Args)
+		return
+	}
 	query := common.FromHex(reqdata.Data)
 	if query == nil {
 		Response(c, http.StatusBadRequest, fmt.Errorf("data not hex"), nil)
@@ -600,6 +712,36 @@ func (r *RpcController) QueryContract(c *gin.Context) {
 	return
 }
 
+func (r *RpcController) queryContractMethod(c *gin.Context, addr common.Address, name string, args []json.RawMessage) {
+	contractABI, err := r.contractABI(addr, "")
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	query, err := contractABI.Pack(name, args)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	ret, err := r.Og.Dag.CallContract(addr, query)
+	if err != nil {
+		if reason, ok := vmtypes.UnpackRevertReason(ret); ok {
+			err = fmt.Errorf("%v: %s", err, reason)
+		}
+		Response(c, http.StatusNotFound, fmt.Errorf("query contract error: %v", err), nil)
+		return
+	}
+	outputs, err := contractABI.Methods[name].UnpackOutputs(ret)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("decode outputs error: %v", err), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, nil, QueryContractResponse{Data: hex.EncodeToString(ret), Outputs: outputs})
+	return
+}
+
 func (r *RpcController) GetLedgerSize(c *gin.Context) {
 	ledgerSize := r.Og.Dag.GetLedgerSize()
 	Response(c, http.StatusOK, nil, ledgerSize)
@@ -18,10 +18,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/hexutil"
 	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
 	"io"
 	"net/http"
 	"os"
@@ -36,7 +38,18 @@ const (
 	PATH_NONCE       = "/query_nonce"
 )
 
+// skipWithoutNode skips the tests of the rpcs of a running node if there
+// is none at ROOT.
+func skipWithoutNode(t *testing.T) {
+	resp, err := http.Get(ROOT)
+	if err != nil {
+		t.Skipf("no node at %s: %v", ROOT, err)
+	}
+	resp.Body.Close()
+}
+
 func TestNewAccount(t *testing.T) {
+	skipWithoutNode(t)
 	pri, pub, addr, err := newAccount("secp256k1")
 	if err != nil {
 		t.Error(err.Error())
@@ -85,6 +98,7 @@ func newAccount(algorithm string) (string, string, string, error) {
 	return a.Privkey, a.Pubkey, addr.String(), nil
 }
 func TestQueryNonce(t *testing.T) {
+	skipWithoutNode(t)
 	_, _, addr, err := newAccount("secp256k1")
 	if err != nil {
 		t.Error(err.Error())
@@ -121,6 +135,7 @@ func nonce(addr string) (int, error) {
 }
 
 func TestSendTx(t *testing.T) {
+	skipWithoutNode(t)
 	err := sendTx("secp256k1")
 	if err != nil {
 		t.Fatal(err)
@@ -161,11 +176,11 @@ func sendTx(algorithm string) error {
 	}
 
 	for nonce := 0; nonce < 10; nonce++ {
-		tx := types.Tx{
+		tx := tx_types.Tx{
 			TxBase: types.TxBase{
 				AccountNonce: uint64(nonce),
 			},
-			From:  fromAddr,
+			From:  &fromAddr,
 			To:    toAddr,
 			Value: math.NewBigInt(0),
 		}
@@ -0,0 +1,179 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/status"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type NewDomainRequest struct {
+	Nonce      uint64 `json:"nonce"`
+	From       string `json:"from"`
+	DomainName string `json:"domain_name"`
+	Period     uint64 `json:"period"`
+	To         string `json:"to"`
+	CryptoType string `json:"crypto_type"`
+	Signature  string `json:"signature"`
+	Pubkey     string `json:"pubkey"`
+}
+
+type DomainResponse struct {
+	Name    string `json:"name"`
+	Owner   string `json:"owner"`
+	Expiry  uint64 `json:"expiry"`
+	Expired bool   `json:"expired"`
+}
+
+type ResolveDomainResponse struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+func (r *RpcController) RegisterDomain(c *gin.Context) {
+	r.newDomainTx(c, tx_types.ActionRequestDomainName)
+}
+
+func (r *RpcController) RenewDomain(c *gin.Context) {
+	r.newDomainTx(c, tx_types.ActionRenewDomainName)
+}
+
+func (r *RpcController) TransferDomain(c *gin.Context) {
+	r.newDomainTx(c, tx_types.ActionTransferDomainName)
+}
+
+func (r *RpcController) newDomainTx(c *gin.Context, action uint8) {
+	var (
+		txReq NewDomainRequest
+		pub   crypto.PublicKey
+	)
+	cors(c)
+	if status.ArchiveMode {
+		Response(c, http.StatusBadRequest, fmt.Errorf("archive mode"), nil)
+		return
+	}
+	err := c.ShouldBindJSON(&txReq)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	from, err := common.StringToAddress(txReq.From)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("from address format error: %v", err), nil)
+		return
+	}
+	request := tx_types.RequestDomain{
+		DomainName: txReq.DomainName,
+		Period:     txReq.Period,
+	}
+	if action == tx_types.ActionTransferDomainName {
+		request.To, err = common.StringToAddress(txReq.To)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("to address format error: %v", err), nil)
+			return
+		}
+	}
+	if err = request.Validate(action); err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	signature := common.FromHex(txReq.Signature)
+	if signature == nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("signature format error"), nil)
+		return
+	}
+	if txReq.CryptoType == "" {
+		pub, err = crypto.PublicKeyFromString(txReq.Pubkey)
+	} else {
+		pub, err = crypto.PublicKeyFromStringWithCryptoType(txReq.CryptoType, txReq.Pubkey)
+	}
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("pubkey format error %v", err), nil)
+		return
+	}
+	sig := crypto.SignatureFromBytes(pub.Type, signature)
+	if sig.Type != crypto.Signer.GetCryptoType() || pub.Type != crypto.Signer.GetCryptoType() {
+		Response(c, http.StatusOK, fmt.Errorf("crypto algorithm mismatch"), nil)
+		return
+	}
+	tx, err := r.TxCreator.NewDomainTxWithSeal(from, action, request, txReq.Nonce, pub, sig)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("new tx failed %v", err), nil)
+		return
+	}
+	if !r.FormatVerifier.VerifySignature(tx) {
+		logrus.WithField("request ", txReq).WithField("tx ", tx).Warn("signature invalid")
+		Response(c, http.StatusInternalServerError, fmt.Errorf("signature invalid"), nil)
+		return
+	}
+	if !r.FormatVerifier.VerifySourceAddress(tx) {
+		logrus.WithField("request ", txReq).WithField("tx ", tx).Warn("source address invalid")
+		Response(c, http.StatusInternalServerError, fmt.Errorf("source address invalid"), nil)
+		return
+	}
+	tx.SetVerified(types.VerifiedFormat)
+	logrus.WithField("tx", tx).Debugf("tx generated")
+	if !r.SyncerManager.IncrementalSyncer.Enabled {
+		Response(c, http.StatusOK, fmt.Errorf("tx is disabled when syncing"), nil)
+		return
+	}
+
+	r.TxBuffer.ReceivedNewTxChan <- tx
+
+	Response(c, http.StatusOK, nil, tx.GetTxHash().Hex())
+}
+
+func (r *RpcController) GetDomain(c *gin.Context) {
+	cors(c)
+	name := c.Query("name")
+	if err := tx_types.ValidateDomainName(name); err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	domain := r.Og.Dag.GetDomain(name)
+	if domain == nil {
+		Response(c, http.StatusNotFound, fmt.Errorf("domain name not found"), nil)
+		return
+	}
+	domainResp := DomainResponse{
+		Name:    domain.Name,
+		Owner:   domain.Owner.Hex(),
+		Expiry:  domain.Expiry,
+		Expired: domain.Expired(r.Og.Dag.LatestSequencer().Height),
+	}
+	Response(c, http.StatusOK, nil, domainResp)
+}
+
+func (r *RpcController) ResolveDomain(c *gin.Context) {
+	cors(c)
+	name := c.Query("name")
+	if err := tx_types.ValidateDomainName(name); err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	addr, err := r.Og.Dag.ResolveDomain(name)
+	if err != nil {
+		Response(c, http.StatusNotFound, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, ResolveDomainResponse{Name: name, Address: addr.Hex()})
+}
@@ -0,0 +1,119 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/annchain/OG/common"
+	"github.com/gin-gonic/gin"
+)
+
+type GovernanceProposeRequest struct {
+	Parameter uint8  `json:"parameter"`
+	Value     uint64 `json:"value"`
+	HashValue string `json:"hash_value"`
+}
+
+type GovernanceVoteRequest struct {
+	ProposalHash string `json:"proposal_hash"`
+	Approve      bool   `json:"approve"`
+}
+
+func (r *RpcController) GovernanceParams(c *gin.Context) {
+	cors(c)
+	if r.AnnSensus == nil {
+		Response(c, http.StatusOK, nil, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, r.AnnSensus.GovernanceParams())
+}
+
+func (r *RpcController) GovernanceProposals(c *gin.Context) {
+	cors(c)
+	if r.AnnSensus == nil {
+		Response(c, http.StatusOK, nil, nil)
+		return
+	}
+	hashStr := c.Query("hash")
+	if hashStr == "" {
+		Response(c, http.StatusOK, nil, r.AnnSensus.GovernanceProposals())
+		return
+	}
+	hash, err := common.HexStringToHash(hashStr)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("hash format error: %v", err), nil)
+		return
+	}
+	ps := r.AnnSensus.GetGovernanceProposal(hash)
+	if ps == nil {
+		Response(c, http.StatusNotFound, fmt.Errorf("proposal not found"), nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, ps)
+}
+
+func (r *RpcController) GovernancePropose(c *gin.Context) {
+	var req GovernanceProposeRequest
+	cors(c)
+	if r.AnnSensus == nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("annsensus not enabled"), nil)
+		return
+	}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	var hashValue common.Hash
+	if req.HashValue != "" {
+		hashValue, err = common.HexStringToHash(req.HashValue)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("hash value format error: %v", err), nil)
+			return
+		}
+	}
+	err = r.AnnSensus.ProposeParamChange(req.Parameter, req.Value, hashValue)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, "proposal sent")
+}
+
+func (r *RpcController) GovernanceVote(c *gin.Context) {
+	var req GovernanceVoteRequest
+	cors(c)
+	if r.AnnSensus == nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("annsensus not enabled"), nil)
+		return
+	}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	hash, err := common.HexStringToHash(req.ProposalHash)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("proposal hash format error: %v", err), nil)
+		return
+	}
+	err = r.AnnSensus.VoteProposal(hash, req.Approve)
+	if err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, "vote sent")
+}
@@ -16,6 +16,7 @@ package rpc
 import (
 	"bytes"
 	"fmt"
+	"github.com/annchain/OG/auth"
 	"github.com/sirupsen/logrus"
 	"net/http"
 	"sort"
@@ -40,62 +41,120 @@ func (rpc *RpcController) NewRouter() *gin.Engine {
 }
 
 func (rpc *RpcController) addRouter(router *gin.Engine) *gin.Engine {
-	router.GET("/", rpc.writeListOfEndpoints)
+	// the preflights are answered before any credential is checked.
+	router.Use(rpc.preflight)
+	// routes are grouped by the permission required when an authenticator
+	// is set.
+	public := router.Group("", rpc.authorize(auth.GroupPublic))
+	txs := router.Group("", rpc.authorize(auth.GroupTx))
+	admin := router.Group("", rpc.authorize(auth.GroupAdmin))
+
+	// the probes of the orchestrators need no credential.
+	router.GET("health/live", rpc.HealthLive)
+	router.GET("health/ready", rpc.HealthReady)
+
+	public.GET("/", rpc.writeListOfEndpoints)
 	// init paths here
-	router.GET("/ping", func(c *gin.Context) {
+	public.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "pong",
 		})
 	})
-	router.GET("status", rpc.Status)
-	router.GET("net_info", rpc.NetInfo)
-	router.GET("peers_info", rpc.PeersInfo)
-	router.GET("og_peers_info", rpc.OgPeersInfo)
-	router.GET("transaction", rpc.Transaction)
-	router.GET("transaction_size", rpc.TransactionSize)
-	router.GET("confirm", rpc.Confirm)
-	router.GET("transactions", rpc.Transactions)
-	router.GET("transaction_hashes", rpc.TransactionHashes)
-	router.GET("validators", rpc.Validator)
-	router.GET("sequencer", rpc.Sequencer)
-	router.GET("/v1/sequencer", rpc.SequencerV1)
-	router.GET("genesis", rpc.Genesis)
+	public.GET("status", rpc.Status)
+	public.GET("net_info", rpc.NetInfo)
+	public.GET("peers_info", rpc.PeersInfo)
+	public.GET("og_peers_info", rpc.OgPeersInfo)
+	public.GET("banned_peers", rpc.BannedPeers)
+	public.GET("transaction", rpc.Transaction)
+	public.GET("transaction_size", rpc.TransactionSize)
+	public.GET("confirm", rpc.Confirm)
+	public.GET("finality", rpc.Finality)
+	public.GET("transactions", rpc.Transactions)
+	public.GET("transaction_hashes", rpc.TransactionHashes)
+	public.GET("validators", rpc.Validator)
+	public.GET("sequencer", rpc.Sequencer)
+	public.GET("/v1/sequencer", rpc.SequencerV1)
+	public.GET("genesis", rpc.Genesis)
 	// broadcast API
-	router.POST("new_transaction", rpc.NewTransaction)
-	router.GET("new_transaction", rpc.NewTransaction)
-	router.POST("new_transactions", rpc.NewTransactions)
-	router.POST("new_account", rpc.NewAccount)
-	router.POST("new_archive", rpc.NewArchive)
-	router.GET("auto_tx", rpc.AutoTx)
+	txs.POST("new_transaction", rpc.NewTransaction)
+	txs.GET("new_transaction", rpc.NewTransaction)
+	txs.POST("new_transactions", rpc.NewTransactions)
+	admin.POST("new_account", rpc.NewAccount)
+	txs.POST("new_archive", rpc.NewArchive)
+	public.GET("archive", rpc.Archive)
+	public.GET("proof_of_existence", rpc.ProofOfExistence)
+	admin.GET("auto_tx", rpc.AutoTx)
 
 	// query API
-	router.GET("query", rpc.Query)
-	router.GET("query_nonce", rpc.QueryNonce)
-	router.GET("query_balance", rpc.QueryBalance)
-	router.GET("query_share", rpc.QueryShare)
-	router.GET("contract_payload", rpc.ContractPayload)
-	router.GET("query_receipt", rpc.QueryReceipt)
-	router.POST("query_contract", rpc.QueryContract)
-	router.GET("query_contract", rpc.QueryContract)
-	router.GET("net_io", rpc.NetIo)
-
-	router.GET("debug", rpc.Debug)
-	router.GET("tps", rpc.Tps)
-	router.GET("monitor", rpc.Monitor)
-	router.GET("sync_status", rpc.SyncStatus)
-	router.GET("performance", rpc.Performance)
-	router.GET("consensus", rpc.ConStatus)
-	router.GET("confirm_status", rpc.ConfirmStatus)
-
-	router.GET("debug/bft_status", rpc.BftStatus)
-	router.GET("debug/pool_hashes", rpc.GetPoolHashes)
-	router.POST("token/second_offering", rpc.NewSecondOffering) //NewSecondOffering
-	router.POST("token/initial_offering", rpc.NewPublicOffering)
-	router.POST("token/destroy", rpc.TokenDestroy)
-	router.GET("token/latestId", rpc.LatestTokenId)
-	router.GET("token/list", rpc.Tokens)
-	router.GET("token", rpc.GetToken)
-	router.GET("ledger_size", rpc.GetLedgerSize)
+	public.GET("query", rpc.Query)
+	public.GET("query_nonce", rpc.QueryNonce)
+	public.GET("query_balance", rpc.QueryBalance)
+	public.GET("query_share", rpc.QueryShare)
+	public.GET("contract_payload", rpc.ContractPayload)
+	txs.POST("register_abi", rpc.RegisterABI)
+	public.GET("query_receipt", rpc.QueryReceipt)
+	public.POST("query_contract", rpc.QueryContract)
+	public.GET("query_contract", rpc.QueryContract)
+	public.GET("get_code", rpc.GetCode)
+	public.GET("get_storage_at", rpc.GetStorageAt)
+	public.GET("get_contract_info", rpc.GetContractInfo)
+	public.POST("simulate_tx", rpc.SimulateTx)
+	public.POST("estimate_gas", rpc.EstimateGas)
+	public.GET("net_io", rpc.NetIo)
+	public.GET("metrics", rpc.Metrics)
+
+	admin.GET("debug", rpc.Debug)
+	public.GET("tps", rpc.Tps)
+	public.GET("monitor", rpc.Monitor)
+	public.GET("sync_status", rpc.SyncStatus)
+	admin.GET("performance", rpc.Performance)
+	public.GET("consensus", rpc.ConStatus)
+	public.GET("confirm_status", rpc.ConfirmStatus)
+
+	admin.GET("debug/bft_status", rpc.BftStatus)
+	admin.GET("debug/pool_hashes", rpc.GetPoolHashes)
+	admin.GET("debug/trace_transaction", rpc.TraceTransaction)
+	txs.POST("token/second_offering", rpc.NewSecondOffering) //NewSecondOffering
+	txs.POST("token/initial_offering", rpc.NewPublicOffering)
+	txs.POST("token/destroy", rpc.TokenDestroy)
+	public.GET("token/latestId", rpc.LatestTokenId)
+	public.GET("token/list", rpc.Tokens)
+	public.GET("token", rpc.GetToken)
+	txs.POST("token/approve", rpc.Approve)
+	txs.POST("token/revoke", rpc.Revoke)
+	txs.POST("token/transfer_from", rpc.TransferFrom)
+	public.GET("token/allowance", rpc.GetAllowance)
+	txs.POST("token/freeze", rpc.TokenFreeze)
+	txs.POST("token/unfreeze", rpc.TokenUnfreeze)
+	txs.POST("token/whitelist/add", rpc.TokenWhitelistAdd)
+	txs.POST("token/whitelist/remove", rpc.TokenWhitelistRemove)
+	txs.POST("token/pause", rpc.TokenPause)
+	txs.POST("token/unpause", rpc.TokenUnpause)
+	public.GET("token/holder", rpc.GetTokenHolder)
+	public.GET("ledger_size", rpc.GetLedgerSize)
+
+	public.GET("governance/params", rpc.GovernanceParams)
+	public.GET("governance/proposals", rpc.GovernanceProposals)
+	txs.POST("governance/propose", rpc.GovernancePropose)
+	txs.POST("governance/vote", rpc.GovernanceVote)
+
+	txs.POST("domain/register", rpc.RegisterDomain)
+	txs.POST("domain/renew", rpc.RenewDomain)
+	txs.POST("domain/transfer", rpc.TransferDomain)
+	public.GET("domain", rpc.GetDomain)
+	public.GET("domain/resolve", rpc.ResolveDomain)
+
+	// admin API
+	admin.POST("admin/add_peer", rpc.AdminAddPeer)
+	admin.POST("admin/remove_peer", rpc.AdminRemovePeer)
+	admin.POST("admin/pause_auto_tx", rpc.AdminPauseAutoTx)
+	admin.POST("admin/resume_auto_tx", rpc.AdminResumeAutoTx)
+	admin.GET("admin/log_level", rpc.AdminLogLevels)
+	admin.POST("admin/log_level", rpc.AdminSetLogLevel)
+	admin.POST("admin/clear_pool", rpc.AdminClearPool)
+	admin.POST("admin/resync", rpc.AdminResync)
+	admin.POST("admin/dump_tx_buffer", rpc.AdminDumpTxBuffer)
+	admin.POST("admin/dump_bft_status", rpc.AdminDumpBftStatus)
 
 	return router
 
@@ -110,9 +169,11 @@ func (rpc *RpcController) writeListOfEndpoints(c *gin.Context) {
 		"net_info":           "",
 		"peers_info":         "",
 		"og_peers_info":      "",
+		"banned_peers":       "",
 		"transaction":        "hash",
 		"transaction_size":   "hash",
 		"confirm":            "hash",
+		"finality":           "hash",
 		"transactions":       "height,address",
 		"transaction_hashes": "height",
 		"validators":         "",
@@ -122,33 +183,54 @@ func (rpc *RpcController) writeListOfEndpoints(c *gin.Context) {
 
 		"new_transaction": "tx",
 		//"new_transaction":  "POSTBODY",
-		"new_transactions": "",
-		"new_account":      "POSTBODY",
-		"new_archive":      "tx",
-		"auto_tx":          "interval_us",
-
-		"query":            "query",
-		"query_nonce":      "address",
-		"query_balance":    "address",
-		"query_share":      "pubkey",
-		"contract_payload": "payload, abistr",
-		"query_receipt":    "hash",
-		"query_contract":   "address,data",
-		"net_io":           "",
-		"debug":            "f",
-		"tps":              "",
-		"monitor":          "",
-		"sync_status":      "",
-		"performance":      "",
-		"consensus":        "",
-		"confirm_status":   "",
-
-		"debug/bft_status":  "",
-		"debug/pool_hashes": "",
-		"token/latestId":    "",
-		"token/list":        "",
-		"token":             "id",
-		"ledger_size":       "",
+		"new_transactions":   "",
+		"new_account":        "POSTBODY",
+		"new_archive":        "tx",
+		"archive":            "content_hash",
+		"proof_of_existence": "content_hash,from",
+		"auto_tx":            "interval_us",
+
+		"query":             "query",
+		"query_nonce":       "address",
+		"query_balance":     "address",
+		"query_share":       "pubkey",
+		"contract_payload":  "address,abistr,method,args",
+		"query_receipt":     "hash",
+		"query_contract":    "address,data,method,args",
+		"get_code":          "address",
+		"get_storage_at":    "address,key,height",
+		"get_contract_info": "address",
+		"simulate_tx":       "from,to,value,data,token_id",
+		"estimate_gas":      "from,to,value,data,token_id",
+		"net_io":            "",
+		"metrics":           "",
+		"health/live":       "",
+		"health/ready":      "",
+		"debug":             "f",
+		"tps":               "",
+		"monitor":           "",
+		"sync_status":       "",
+		"performance":       "",
+		"consensus":         "",
+		"confirm_status":    "",
+
+		"debug/bft_status":        "",
+		"debug/pool_hashes":       "",
+		"debug/trace_transaction": "hash,tracer",
+		"token/latestId":          "",
+		"token/list":              "",
+		"token":                   "id",
+		"token/allowance":         "owner,spender,token_id",
+		"token/holder":            "id,address",
+		"ledger_size":             "",
+
+		"governance/params":    "",
+		"governance/proposals": "hash",
+
+		"domain":         "name",
+		"domain/resolve": "name",
+
+		"admin/log_level": "",
 	}
 	noArgNames := []string{}
 	argNames := []string{}
@@ -30,6 +30,10 @@ type RpcServer struct {
 	server *http.Server
 	port   string
 	C      *RpcController
+
+	// TLSCertFile and TLSKeyFile serve https when both set.
+	TLSCertFile string
+	TLSKeyFile  string
 }
 
 func NewRpcServer(port string) *RpcServer {
@@ -53,7 +57,13 @@ func (srv *RpcServer) Start() {
 	logrus.Infof("listening Http on %s", srv.port)
 	goroutine.New(func() {
 		// service connections
-		if err := srv.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if srv.TLSCertFile != "" && srv.TLSKeyFile != "" {
+			err = srv.server.ListenAndServeTLS(srv.TLSCertFile, srv.TLSKeyFile)
+		} else {
+			err = srv.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			logrus.WithError(err).Fatalf("error in Http server")
 		}
 	})
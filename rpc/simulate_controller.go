@@ -0,0 +1,140 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/gin-gonic/gin"
+)
+
+// SimulateTxRequest is an unsigned tx to be executed on a copy of the
+// state. Nonce defaults to the next nonce of From.
+type SimulateTxRequest struct {
+	Nonce   uint64 `json:"nonce"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Value   string `json:"value"`
+	Data    string `json:"data"`
+	TokenId int32  `json:"token_id"`
+}
+
+type SimulateTxResponse struct {
+	Success      bool             `json:"success"`
+	Error        string           `json:"error,omitempty"`
+	GasUsed      uint64           `json:"gas_used"`
+	ReturnData   hexutil.Bytes    `json:"return_data"`
+	RevertReason string           `json:"revert_reason,omitempty"`
+	Receipt      *ReceiptResponse `json:"receipt"`
+}
+
+// SimulateTx executes the tx on a throwaway copy of the latest state and
+// returns the would-be result.
+func (r *RpcController) SimulateTx(c *gin.Context) {
+	result, ok := r.simulateTx(c)
+	if !ok {
+		return
+	}
+	resp := SimulateTxResponse{
+		Success:      result.Err == nil,
+		GasUsed:      result.GasUsed,
+		ReturnData:   result.ReturnData,
+		RevertReason: result.RevertReason,
+	}
+	if result.Err != nil {
+		resp.Error = result.Err.Error()
+	}
+	if receipt := result.Receipt; receipt != nil {
+		resp.Receipt = newReceiptResponse(receipt)
+	}
+	Response(c, http.StatusOK, nil, resp)
+}
+
+// EstimateGas returns the gas used by the tx executed on the latest state.
+func (r *RpcController) EstimateGas(c *gin.Context) {
+	result, ok := r.simulateTx(c)
+	if !ok {
+		return
+	}
+	if result.Err != nil {
+		err := result.Err
+		if result.RevertReason != "" {
+			err = fmt.Errorf("%v: %s", err, result.RevertReason)
+		}
+		Response(c, http.StatusOK, err, nil)
+		return
+	}
+	Response(c, http.StatusOK, nil, result.GasUsed)
+}
+
+func (r *RpcController) simulateTx(c *gin.Context) (*core.SimulationResult, bool) {
+	var txReq SimulateTxRequest
+	err := c.ShouldBindJSON(&txReq)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return nil, false
+	}
+	from, err := common.StringToAddress(txReq.From)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("from address format error: %v", err), nil)
+		return nil, false
+	}
+	var to common.Address
+	if txReq.To != "" {
+		to, err = common.StringToAddress(txReq.To)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("to address format error: %v", err), nil)
+			return nil, false
+		}
+	}
+	value := math.NewBigInt(0)
+	if txReq.Value != "" {
+		value, err = r.tokenAmount(txReq.Value, txReq.TokenId)
+		if err != nil {
+			Response(c, http.StatusBadRequest, err, nil)
+			return nil, false
+		}
+	}
+	data := common.FromHex(txReq.Data)
+	if data == nil && txReq.Data != "" {
+		Response(c, http.StatusBadRequest, fmt.Errorf("data not hex"), nil)
+		return nil, false
+	}
+
+	tx := &tx_types.Tx{
+		TxBase: types.TxBase{
+			Type:         types.TxBaseTypeNormal,
+			AccountNonce: txReq.Nonce,
+		},
+		From:    &from,
+		To:      to,
+		Value:   value,
+		TokenId: txReq.TokenId,
+		Data:    data,
+	}
+
+	result, err := r.Og.Dag.SimulateTransaction(tx)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("simulate tx error: %v", err), nil)
+		return nil, false
+	}
+	return result, true
+}
@@ -18,6 +18,10 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/annchain/OG/health"
+	"github.com/annchain/OG/metrics"
+	"github.com/annchain/OG/metrics/prometheus"
+	"github.com/annchain/OG/og"
 	"github.com/annchain/OG/p2p"
 	"github.com/annchain/OG/p2p/ioperformance"
 	"github.com/annchain/OG/types"
@@ -85,8 +89,9 @@ func (r *RpcController) NetIo(c *gin.Context) {
 	cors(c)
 	type transportData struct {
 		*ioperformance.IoDataInfo `json:"transport_data"`
+		RateLimit                 *og.RateLimitStats `json:"rate_limit"`
 	}
-	data := transportData{ioperformance.GetNetPerformance()}
+	data := transportData{ioperformance.GetNetPerformance(), r.Og.Manager.Hub.RateLimitStats()}
 	Response(c, http.StatusOK, nil, data)
 	return
 }
@@ -98,6 +103,11 @@ func (r *RpcController) OgPeersInfo(c *gin.Context) {
 	return
 }
 
+func (r *RpcController) BannedPeers(c *gin.Context) {
+	Response(c, http.StatusOK, nil, r.Og.Manager.Hub.BannedPeers())
+	return
+}
+
 type Monitor struct {
 	Port    string     `json:"port"`
 	ShortId string     `json:"short_id"`
@@ -147,6 +157,36 @@ func (r *RpcController) Monitor(c *gin.Context) {
 	return
 }
 
+// HealthLive reports if the node is running, with 503 if not.
+func (r *RpcController) HealthLive(c *gin.Context) {
+	writeHealth(c, r.LiveChecker)
+}
+
+// HealthReady reports if the node is synced and connected so that it can
+// serve requests, with 503 if not.
+func (r *RpcController) HealthReady(c *gin.Context) {
+	writeHealth(c, r.ReadyChecker)
+}
+
+func writeHealth(c *gin.Context, checker *health.Checker) {
+	cors(c)
+	if checker == nil {
+		checker = health.NewChecker()
+	}
+	report := checker.Check()
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// Metrics exports the metrics registry and the benchmarks of the performance
+// reporters in the Prometheus text format.
+func (r *RpcController) Metrics(c *gin.Context) {
+	prometheus.Handler(metrics.DefaultRegistry, r.PerformanceMonitor).ServeHTTP(c.Writer, c.Request)
+}
+
 func (r *RpcController) BftStatus(c *gin.Context) {
 	cors(c)
 	if r.AnnSensus != nil {
@@ -0,0 +1,177 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/core/state"
+	"github.com/annchain/OG/status"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// NewTokenControlRequest freezes, whitelists or pauses a token. Holder is
+// not used to pause or unpause.
+type NewTokenControlRequest struct {
+	Nonce      uint64 `json:"nonce"`
+	From       string `json:"from"`
+	TokenId    int32  `json:"token_id"`
+	Holder     string `json:"holder"`
+	CryptoType string `json:"crypto_type"`
+	Signature  string `json:"signature"`
+	Pubkey     string `json:"pubkey"`
+}
+
+type TokenHolderResponse struct {
+	TokenId     int32  `json:"token_id"`
+	Address     string `json:"address"`
+	Frozen      bool   `json:"frozen"`
+	Whitelisted bool   `json:"whitelisted"`
+}
+
+func (r *RpcController) TokenFreeze(c *gin.Context) {
+	r.newTokenControlTx(c, tx_types.ActionTxActionFreeze)
+}
+
+func (r *RpcController) TokenUnfreeze(c *gin.Context) {
+	r.newTokenControlTx(c, tx_types.ActionTxActionUnfreeze)
+}
+
+func (r *RpcController) TokenWhitelistAdd(c *gin.Context) {
+	r.newTokenControlTx(c, tx_types.ActionTxActionWhitelistAdd)
+}
+
+func (r *RpcController) TokenWhitelistRemove(c *gin.Context) {
+	r.newTokenControlTx(c, tx_types.ActionTxActionWhitelistRemove)
+}
+
+func (r *RpcController) TokenPause(c *gin.Context) {
+	r.newTokenControlTx(c, tx_types.ActionTxActionPause)
+}
+
+func (r *RpcController) TokenUnpause(c *gin.Context) {
+	r.newTokenControlTx(c, tx_types.ActionTxActionUnpause)
+}
+
+func (r *RpcController) newTokenControlTx(c *gin.Context, action uint8) {
+	var (
+		txReq NewTokenControlRequest
+		pub   crypto.PublicKey
+	)
+	cors(c)
+	if status.ArchiveMode {
+		Response(c, http.StatusBadRequest, fmt.Errorf("archive mode"), nil)
+		return
+	}
+	err := c.ShouldBindJSON(&txReq)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("request format error: %v", err), nil)
+		return
+	}
+	from, err := common.StringToAddress(txReq.From)
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("from address format error: %v", err), nil)
+		return
+	}
+	control := tx_types.TokenControl{TokenId: txReq.TokenId}
+	if tx_types.TokenControlOfAction(action) != tx_types.TokenControlPause {
+		control.Holder, err = common.StringToAddress(txReq.Holder)
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("holder address format error: %v", err), nil)
+			return
+		}
+	}
+	if err = control.Validate(action); err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	signature := common.FromHex(txReq.Signature)
+	if signature == nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("signature format error"), nil)
+		return
+	}
+	if txReq.CryptoType == "" {
+		pub, err = crypto.PublicKeyFromString(txReq.Pubkey)
+	} else {
+		pub, err = crypto.PublicKeyFromStringWithCryptoType(txReq.CryptoType, txReq.Pubkey)
+	}
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("pubkey format error %v", err), nil)
+		return
+	}
+	sig := crypto.SignatureFromBytes(pub.Type, signature)
+	if sig.Type != crypto.Signer.GetCryptoType() || pub.Type != crypto.Signer.GetCryptoType() {
+		Response(c, http.StatusOK, fmt.Errorf("crypto algorithm mismatch"), nil)
+		return
+	}
+	tx, err := r.TxCreator.NewTokenControlTxWithSeal(from, action, control, txReq.Nonce, pub, sig)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, fmt.Errorf("new tx failed %v", err), nil)
+		return
+	}
+	if !r.FormatVerifier.VerifySignature(tx) {
+		logrus.WithField("request ", txReq).WithField("tx ", tx).Warn("signature invalid")
+		Response(c, http.StatusInternalServerError, fmt.Errorf("signature invalid"), nil)
+		return
+	}
+	if !r.FormatVerifier.VerifySourceAddress(tx) {
+		logrus.WithField("request ", txReq).WithField("tx ", tx).Warn("source address invalid")
+		Response(c, http.StatusInternalServerError, fmt.Errorf("source address invalid"), nil)
+		return
+	}
+	tx.SetVerified(types.VerifiedFormat)
+	logrus.WithField("tx", tx).Debugf("tx generated")
+	if !r.SyncerManager.IncrementalSyncer.Enabled {
+		Response(c, http.StatusOK, fmt.Errorf("tx is disabled when syncing"), nil)
+		return
+	}
+
+	r.TxBuffer.ReceivedNewTxChan <- tx
+
+	Response(c, http.StatusOK, nil, tx.GetTxHash().Hex())
+}
+
+// GetTokenHolder shows if the holder of the token is frozen or
+// whitelisted.
+func (r *RpcController) GetTokenHolder(c *gin.Context) {
+	cors(c)
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("id format error: %v", err), nil)
+		return
+	}
+	addr, err := common.StringToAddress(c.Query("address"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("address format error: %v", err), nil)
+		return
+	}
+	if r.Og.Dag.GetToken(int32(id)) == nil {
+		Response(c, http.StatusNotFound, fmt.Errorf("token not found"), nil)
+		return
+	}
+	flags := r.Og.Dag.GetTokenHolderFlags(int32(id), addr)
+	Response(c, http.StatusOK, nil, TokenHolderResponse{
+		TokenId:     int32(id),
+		Address:     addr.Hex(),
+		Frozen:      flags&state.TokenHolderFrozen != 0,
+		Whitelisted: flags&state.TokenHolderWhitelisted != 0,
+	})
+}
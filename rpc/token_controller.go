@@ -39,6 +39,11 @@ type NewPublicOfferingRequest struct {
 	Pubkey     string `json:"pubkey"`
 	TokenId    int32  `json:"token_id"`
 	TokenName  string `json:"token_name"`
+	// TokenSymbol, Decimals, MetadataURI and Controls are set by the IPO only.
+	TokenSymbol string `json:"token_symbol"`
+	Decimals    uint8  `json:"decimals"`
+	MetadataURI string `json:"metadata_uri"`
+	Controls    uint8  `json:"controls"`
 }
 
 //todo optimize later
@@ -106,7 +111,7 @@ func (r *RpcController) TokenDestroy(c *gin.Context) {
 
 	fmt.Println(fmt.Sprintf("tx req action: %x", txReq.Action))
 	tx, err = r.TxCreator.NewActionTxWithSeal(from, common.Address{}, math.NewBigInt(0), txReq.Action, txReq.Nonce,
-		txReq.EnableSPO, txReq.TokenId, txReq.TokenName, pub, sig)
+		txReq.EnableSPO, txReq.TokenId, txReq.TokenName, "", 0, "", 0, pub, sig)
 	if err != nil {
 		Response(c, http.StatusInternalServerError, fmt.Errorf("new tx failed %v", err), nil)
 		return
@@ -147,12 +152,23 @@ func (r *RpcController) NewPublicOffering(c *gin.Context) {
 		return
 	}
 
-	value, ok := math.NewBigIntFromString(txReq.Value, 10)
-	if !ok {
-		err = fmt.Errorf("new Big Int error")
+	offering := tx_types.PublicOffering{
+		TokenSymbol: txReq.TokenSymbol,
+		Decimals:    txReq.Decimals,
+		MetadataURI: txReq.MetadataURI,
+		Controls:    txReq.Controls,
 	}
-	if err != nil {
-		Response(c, http.StatusBadRequest, fmt.Errorf("value format error: %v", err), nil)
+	if err = offering.ValidateMetadata(); err != nil {
+		Response(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	if token := r.Og.Dag.GetTokenBySymbol(txReq.TokenSymbol); token != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("token symbol %s is used by token %d", txReq.TokenSymbol, token.TokenID), nil)
+		return
+	}
+	value, ok := math.NewBigIntFromDecimal(txReq.Value, txReq.Decimals)
+	if !ok {
+		Response(c, http.StatusBadRequest, fmt.Errorf("value format error: %s", txReq.Value), nil)
 		return
 	}
 
@@ -184,7 +200,8 @@ func (r *RpcController) NewPublicOffering(c *gin.Context) {
 	}
 
 	tx, err = r.TxCreator.NewActionTxWithSeal(from, common.Address{}, value, txReq.Action, txReq.Nonce,
-		txReq.EnableSPO, 0, txReq.TokenName, pub, sig)
+		txReq.EnableSPO, 0, txReq.TokenName, txReq.TokenSymbol, txReq.Decimals, txReq.MetadataURI, txReq.Controls,
+		pub, sig)
 	if err != nil {
 		Response(c, http.StatusInternalServerError, fmt.Errorf("new tx failed %v", err), nil)
 		return
@@ -225,12 +242,9 @@ func (r *RpcController) NewSecondOffering(c *gin.Context) {
 		return
 	}
 
-	value, ok := math.NewBigIntFromString(txReq.Value, 10)
-	if !ok {
-		err = fmt.Errorf("new Big Int error")
-	}
+	value, err := r.tokenAmount(txReq.Value, txReq.TokenId)
 	if err != nil {
-		Response(c, http.StatusBadRequest, fmt.Errorf("value format error: %v", err), nil)
+		Response(c, http.StatusBadRequest, err, nil)
 		return
 	}
 
@@ -261,7 +275,7 @@ func (r *RpcController) NewSecondOffering(c *gin.Context) {
 	}
 
 	tx, err = r.TxCreator.NewActionTxWithSeal(from, common.Address{}, value, tx_types.ActionTxActionSPO,
-		txReq.Nonce, txReq.EnableSPO, txReq.TokenId, txReq.TokenName, pub, sig)
+		txReq.Nonce, txReq.EnableSPO, txReq.TokenId, txReq.TokenName, "", 0, "", 0, pub, sig)
 	if err != nil {
 		Response(c, http.StatusInternalServerError, fmt.Errorf("new tx failed %v", err), nil)
 		return
@@ -285,17 +299,30 @@ func (r *RpcController) LatestTokenId(c *gin.Context) {
 
 func (r *RpcController) Tokens(c *gin.Context) {
 	tokens := r.Og.Dag.GetTokens()
-	Response(c, http.StatusOK, nil, tokens)
+	tokenResps := make([]TokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		if token == nil {
+			continue
+		}
+		tokenResps = append(tokenResps, newTokenRespFromTokenObj(token))
+	}
+	Response(c, http.StatusOK, nil, tokenResps)
 }
 
+// TokenResponse shows the amounts in units of 10^Decimals.
 type TokenResponse struct {
-	TokenID    int32    `json:"token_id"`
-	Name       string   `json:"name"`
-	Symbol     string   `json:"symbol"`
-	Issuer     string   `json:"issuer"`
-	ReIssuable bool     `json:"re_issuable"`
-	Issues     []string `json:"issues"`
-	Destroyed  bool     `json:"destroyed"`
+	TokenID     int32    `json:"token_id"`
+	Name        string   `json:"name"`
+	Symbol      string   `json:"symbol"`
+	Decimals    uint8    `json:"decimals"`
+	MetadataURI string   `json:"metadata_uri"`
+	Issuer      string   `json:"issuer"`
+	ReIssuable  bool     `json:"re_issuable"`
+	Issues      []string `json:"issues"`
+	TotalSupply string   `json:"total_supply"`
+	Destroyed   bool     `json:"destroyed"`
+	Controls    uint8    `json:"controls"`
+	Paused      bool     `json:"paused"`
 }
 
 func newTokenRespFromTokenObj(token *state.TokenObject) TokenResponse {
@@ -303,27 +330,63 @@ func newTokenRespFromTokenObj(token *state.TokenObject) TokenResponse {
 	tokenResp.TokenID = token.TokenID
 	tokenResp.Name = token.Name
 	tokenResp.Symbol = token.Symbol
+	tokenResp.Decimals = token.Decimals
+	tokenResp.MetadataURI = token.MetadataURI
 	tokenResp.Issuer = token.Issuer.Hex()
 	tokenResp.ReIssuable = token.ReIssuable
 	tokenResp.Destroyed = token.Destroyed
+	tokenResp.Controls = token.Controls
+	tokenResp.Paused = token.Paused
 
 	tokenResp.Issues = make([]string, 0)
+	totalSupply := math.NewBigInt(0)
 	for _, issue := range token.Issues {
-		tokenResp.Issues = append(tokenResp.Issues, issue.String())
+		tokenResp.Issues = append(tokenResp.Issues, issue.DecimalString(token.Decimals))
+		totalSupply = totalSupply.Add(issue)
 	}
+	tokenResp.TotalSupply = totalSupply.DecimalString(token.Decimals)
 
 	return tokenResp
 }
 
 func (r *RpcController) GetToken(c *gin.Context) {
-	str := c.Query("id")
-	tokenId, err := strconv.Atoi(str)
-	if err != nil {
-		Response(c, http.StatusBadRequest, err, nil)
+	var token *state.TokenObject
+	if symbol := c.Query("symbol"); symbol != "" {
+		token = r.Og.Dag.GetTokenBySymbol(symbol)
+	} else {
+		str := c.Query("id")
+		tokenId, err := strconv.Atoi(str)
+		if err != nil {
+			Response(c, http.StatusBadRequest, err, nil)
+			return
+		}
+		token = r.Og.Dag.GetToken(int32(tokenId))
+	}
+	if token == nil {
+		Response(c, http.StatusNotFound, fmt.Errorf("token not found"), nil)
 		return
 	}
-	token := r.Og.Dag.GetToken(int32(tokenId))
 	tokenResp := newTokenRespFromTokenObj(token)
 
 	Response(c, http.StatusOK, nil, tokenResp)
 }
+
+// tokenDecimals returns the decimals of the token, 0 for the og token and
+// the unknown tokens.
+func (r *RpcController) tokenDecimals(tokenID int32) uint8 {
+	token := r.Og.Dag.GetToken(tokenID)
+	if token == nil {
+		return 0
+	}
+	return token.Decimals
+}
+
+// tokenAmount parses the amount of the token given in units of
+// 10^decimals.
+func (r *RpcController) tokenAmount(amount string, tokenID int32) (*math.BigInt, error) {
+	value, ok := math.NewBigIntFromDecimal(amount, r.tokenDecimals(tokenID))
+	if !ok {
+		return nil, fmt.Errorf("value format error: %s", amount)
+	}
+	return value, nil
+}
@@ -0,0 +1,127 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/hexutil"
+	evm "github.com/annchain/OG/vm/eth/core/vm"
+	"github.com/annchain/OG/vm/ovm"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	tracerStruct = "struct"
+	tracerCall   = "call"
+)
+
+// StructLogResponse is a step of the OVM reported by the struct tracer.
+type StructLogResponse struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gas_cost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+type StructTraceResponse struct {
+	Failed      bool                `json:"failed"`
+	Error       string              `json:"error,omitempty"`
+	ReturnValue hexutil.Bytes       `json:"return_value"`
+	StructLogs  []StructLogResponse `json:"struct_logs"`
+}
+
+// TraceTransaction re-executes a confirmed tx and returns the opcode steps
+// or, with tracer=call, the tree of the internal calls.
+func (r *RpcController) TraceTransaction(c *gin.Context) {
+	cors(c)
+	hashBytes := common.FromHex(c.Query("hash"))
+	if hashBytes == nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("hash not hex"), nil)
+		return
+	}
+	hash := common.BytesToHash(hashBytes)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, fmt.Errorf("limit format error: %v", err), nil)
+		return
+	}
+
+	switch tracer := c.DefaultQuery("tracer", tracerStruct); tracer {
+	case tracerStruct:
+		logger := evm.NewStructLogger(&evm.LogConfig{
+			DisableMemory:  c.Query("disable_memory") == "true",
+			DisableStack:   c.Query("disable_stack") == "true",
+			DisableStorage: c.Query("disable_storage") == "true",
+			Limit:          limit,
+		})
+		if _, err := r.Og.Dag.TraceTransaction(hash, logger, nil); err != nil {
+			Response(c, http.StatusNotFound, fmt.Errorf("trace tx error: %v", err), nil)
+			return
+		}
+		Response(c, http.StatusOK, nil, formatStructTrace(logger))
+	case tracerCall:
+		callTracer := ovm.NewCallTreeTracer()
+		if _, err := r.Og.Dag.TraceTransaction(hash, nil, callTracer); err != nil {
+			Response(c, http.StatusNotFound, fmt.Errorf("trace tx error: %v", err), nil)
+			return
+		}
+		Response(c, http.StatusOK, nil, callTracer.Root())
+	default:
+		Response(c, http.StatusBadRequest, fmt.Errorf("unknown tracer: %s", tracer), nil)
+	}
+}
+
+func formatStructTrace(logger *evm.StructLogger) StructTraceResponse {
+	resp := StructTraceResponse{
+		Failed:      logger.Error() != nil,
+		ReturnValue: logger.Output(),
+		StructLogs:  make([]StructLogResponse, 0, len(logger.StructLogs())),
+	}
+	if logger.Error() != nil {
+		resp.Error = logger.Error().Error()
+	}
+	for _, structLog := range logger.StructLogs() {
+		step := StructLogResponse{
+			Pc:      structLog.Pc,
+			Op:      structLog.OpName(),
+			Gas:     structLog.Gas,
+			GasCost: structLog.GasCost,
+			Depth:   structLog.Depth,
+			Error:   structLog.ErrorString(),
+		}
+		for _, item := range structLog.Stack {
+			step.Stack = append(step.Stack, fmt.Sprintf("%x", common.LeftPadBytes(item.Bytes(), 32)))
+		}
+		for i := 0; i+32 <= len(structLog.Memory); i += 32 {
+			step.Memory = append(step.Memory, fmt.Sprintf("%x", structLog.Memory[i:i+32]))
+		}
+		if len(structLog.Storage) > 0 {
+			step.Storage = make(map[string]string, len(structLog.Storage))
+			for key, value := range structLog.Storage {
+				step.Storage[fmt.Sprintf("%x", key.ToBytes())] = fmt.Sprintf("%x", value.ToBytes())
+			}
+		}
+		resp.StructLogs = append(resp.StructLogs, step)
+	}
+	return resp
+}
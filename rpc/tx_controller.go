@@ -15,13 +15,13 @@ package rpc
 
 //go:generate msgp
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/crypto"
-	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/status"
 	"github.com/annchain/OG/types"
 	"github.com/gin-gonic/gin"
@@ -34,6 +34,7 @@ func (r *RpcController) NewTransaction(c *gin.Context) {
 		txReq NewTxRequest
 		sig   crypto.Signature
 		pub   crypto.PublicKey
+		ok    bool
 	)
 
 	//if status.ArchiveMode {
@@ -57,12 +58,9 @@ func (r *RpcController) NewTransaction(c *gin.Context) {
 		return
 	}
 
-	value, ok := math.NewBigIntFromString(txReq.Value, 10)
-	if !ok {
-		err = fmt.Errorf("new Big Int error")
-	}
+	value, err := r.tokenAmount(txReq.Value, txReq.TokenId)
 	if err != nil {
-		Response(c, http.StatusBadRequest, fmt.Errorf("value format error: %v", err), nil)
+		Response(c, http.StatusBadRequest, err, nil)
 		return
 	}
 
@@ -126,6 +124,14 @@ func (r *RpcController) NewTransaction(c *gin.Context) {
 		return
 	}
 
+	if len(txReq.Abi) != 0 && to.Bytes == (common.Address{}).Bytes {
+		err = r.Og.Dag.AddDeploymentABI(tx.GetTxHash(), unquoteABI(txReq.Abi))
+		if err != nil {
+			Response(c, http.StatusBadRequest, fmt.Errorf("abi format error: %v", err), nil)
+			return
+		}
+	}
+
 	r.TxBuffer.ReceivedNewTxChan <- tx
 
 	Response(c, http.StatusOK, nil, tx.GetTxHash().Hex())
@@ -144,6 +150,9 @@ type NewTxRequest struct {
 	Signature  string `json:"signature"`
 	Pubkey     string `json:"pubkey"`
 	TokenId    int32  `json:"token_id"`
+	// Abi is the json abi of the contract created by the tx, registered
+	// at the address of the contract.
+	Abi json.RawMessage `json:"abi" msg:"-"`
 }
 
 //msgp:tuple NewTxsRequests
@@ -158,6 +167,7 @@ func (r *RpcController) NewTransactions(c *gin.Context) {
 		sig        crypto.Signature
 		pub        crypto.PublicKey
 		hashes     common.Hashes
+		ok         bool
 	)
 
 	if status.ArchiveMode {
@@ -183,12 +193,9 @@ func (r *RpcController) NewTransactions(c *gin.Context) {
 			return
 		}
 
-		value, ok := math.NewBigIntFromString(txReq.Value, 10)
-		if !ok {
-			err = fmt.Errorf("new Big Int error")
-		}
+		value, err := r.tokenAmount(txReq.Value, txReq.TokenId)
 		if err != nil {
-			Response(c, http.StatusBadRequest, fmt.Errorf("value format error: %v", err), nil)
+			Response(c, http.StatusBadRequest, err, nil)
 			return
 		}
 
@@ -87,12 +87,18 @@ const (
 	NodeDataMsg
 	GetReceiptsMsg
 	MessageTypeOg02Length
+
+	// Protocol messages belonging to og/03
+
+	//for compact relay
+	MessageTypeNewTxHashes
+	MessageTypeOg03Length
 )
 
 type SendingType uint8
 
 func (mt MessageType) IsValid() bool {
-	if mt >= MessageTypeOg02Length {
+	if mt >= MessageTypeOg03Length {
 		return false
 	}
 	return true
@@ -187,6 +193,13 @@ func (mt MessageType) String() string {
 		return "GetReceiptsMsg"
 	case MessageTypeOg02Length:
 		return "MessageTypeOg02Length"
+
+		// Protocol messages belonging to og/03
+
+	case MessageTypeNewTxHashes:
+		return "MessageTypeNewTxHashes"
+	case MessageTypeOg03Length:
+		return "MessageTypeOg03Length"
 	default:
 		return fmt.Sprintf("unkown message type %d", mt)
 	}
@@ -320,6 +333,8 @@ func (m MessageType) GetMsg() Message {
 		message = &MessagePreCommit{}
 	case MessageTypeNewTxs:
 		message = &MessageNewTxs{}
+	case MessageTypeNewTxHashes:
+		message = &MessageNewTxHashes{}
 	case MessageTypeSequencerHeader:
 		message = &MessageSequencerHeader{}
 
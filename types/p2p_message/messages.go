@@ -264,6 +264,18 @@ func (m *MessageNewTxs) String() string {
 	return m.RawTxs.String()
 }
 
+//msgp:tuple MessageNewTxHashes
+type MessageNewTxHashes struct {
+	Hashes *common.Hashes
+}
+
+func (m *MessageNewTxHashes) String() string {
+	if m == nil || m.Hashes == nil {
+		return ""
+	}
+	return fmt.Sprintf("hashes: [%s]", m.Hashes.String())
+}
+
 //msgp:tuple MessageTxsRequest
 type MessageTxsRequest struct {
 	Hashes    *common.Hashes
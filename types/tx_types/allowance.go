@@ -0,0 +1,126 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"fmt"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/math"
+)
+
+//go:generate msgp
+
+// Approval sets how much of the sender's token Spender is allowed to move
+// with transfer-from txs. A revoke sets the allowance back to zero and
+// ignores Value.
+//msgp:tuple Approval
+type Approval struct {
+	Spender common.Address `json:"spender"`
+	TokenId int32          `json:"token_id"`
+	Value   *math.BigInt   `json:"value"`
+}
+
+// TransferFrom moves Value of Owner's token to To and spends the same
+// amount of the allowance Owner approved to the sender.
+//msgp:tuple TransferFrom
+type TransferFrom struct {
+	Owner   common.Address `json:"owner"`
+	To      common.Address `json:"to"`
+	TokenId int32          `json:"token_id"`
+	Value   *math.BigInt   `json:"value"`
+}
+
+func NewApproval() *Approval {
+	return &Approval{
+		Value: math.NewBigInt(0),
+	}
+}
+
+func NewTransferFrom() *TransferFrom {
+	return &TransferFrom{
+		Value: math.NewBigInt(0),
+	}
+}
+
+func (a Approval) String() string {
+	return fmt.Sprintf("spender %s, tokenid %d, value %v", a.Spender.TerminalString(), a.TokenId, a.Value)
+}
+
+func (t TransferFrom) String() string {
+	return fmt.Sprintf("owner %s, to %s, tokenid %d, value %v", t.Owner.TerminalString(), t.To.TerminalString(), t.TokenId, t.Value)
+}
+
+// Validate checks the approval without the state.
+func (a *Approval) Validate(action uint8) error {
+	if a.Spender == (common.Address{}) {
+		return fmt.Errorf("spender should not be empty")
+	}
+	if a.TokenId < 0 {
+		return fmt.Errorf("invalid token id %d", a.TokenId)
+	}
+	switch action {
+	case ActionTxActionApprove:
+		if a.Value == nil || a.Value.Sign() <= 0 {
+			return fmt.Errorf("allowance should be positive, use revoke to remove it")
+		}
+	case ActionTxActionRevoke:
+	default:
+		return fmt.Errorf("unknown approval action %d", action)
+	}
+	return nil
+}
+
+// Validate checks the transfer without the state.
+func (t *TransferFrom) Validate() error {
+	if t.Owner == (common.Address{}) {
+		return fmt.Errorf("owner should not be empty")
+	}
+	if t.To == (common.Address{}) {
+		return fmt.Errorf("transfer to an empty address")
+	}
+	if t.TokenId < 0 {
+		return fmt.Errorf("invalid token id %d", t.TokenId)
+	}
+	if t.Value == nil || t.Value.Sign() <= 0 {
+		return fmt.Errorf("value should be positive")
+	}
+	return nil
+}
+
+func (t *ActionTx) GetApproval() *Approval {
+	if t.Action == ActionTxActionApprove || t.Action == ActionTxActionRevoke {
+		v, ok := t.ActionData.(*Approval)
+		if ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (t *ActionTx) GetTransferFrom() *TransferFrom {
+	if t.Action == ActionTxActionTransferFrom {
+		v, ok := t.ActionData.(*TransferFrom)
+		if ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// IsAllowance returns true if the action tx approves, revokes or spends
+// an allowance.
+func (t *ActionTx) IsAllowance() bool {
+	return t.Action == ActionTxActionApprove || t.Action == ActionTxActionRevoke || t.Action == ActionTxActionTransferFrom
+}
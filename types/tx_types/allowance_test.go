@@ -0,0 +1,58 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/math"
+	"testing"
+)
+
+func TestApprovalValidate(t *testing.T) {
+	a := NewApproval()
+	if a.Validate(ActionTxActionRevoke) == nil {
+		t.Fatal("revoke without spender")
+	}
+	a.Spender = common.HexToAddress("0x889e0b36dc6f2c06eb68d9c5f53434e4c42c8d19")
+	if err := a.Validate(ActionTxActionRevoke); err != nil {
+		t.Fatalf("revoke should be valid: %v", err)
+	}
+	if a.Validate(ActionTxActionApprove) == nil {
+		t.Fatal("approve zero allowance")
+	}
+	a.Value = math.NewBigInt(100)
+	if err := a.Validate(ActionTxActionApprove); err != nil {
+		t.Fatalf("approve should be valid: %v", err)
+	}
+	if a.Validate(ActionTxActionTransferFrom) == nil {
+		t.Fatal("not an approval action")
+	}
+}
+
+func TestTransferFromValidate(t *testing.T) {
+	f := NewTransferFrom()
+	f.Owner = common.HexToAddress("0x889e0b36dc6f2c06eb68d9c5f53434e4c42c8d19")
+	f.Value = math.NewBigInt(10)
+	if f.Validate() == nil {
+		t.Fatal("transfer to empty address")
+	}
+	f.To = common.HexToAddress("0x0b5d53f433b7e4a4f853a01e987f977497dda262")
+	if err := f.Validate(); err != nil {
+		t.Fatalf("transfer should be valid: %v", err)
+	}
+	f.Value = math.NewBigInt(-1)
+	if f.Validate() == nil {
+		t.Fatal("negative value")
+	}
+}
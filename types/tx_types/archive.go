@@ -14,27 +14,152 @@
 package tx_types
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/types"
+	"github.com/tinylib/msgp/msgp"
 	"golang.org/x/crypto/sha3"
+	"io"
+	"io/ioutil"
 	"math/rand"
 	"strings"
 )
 
-//go:generate msgp
+//go:generate msgp -unexported
 
-//msgp:tuple Archive
+// compression of the archive data.
+const (
+	ArchiveCompressionNone uint8 = iota
+	ArchiveCompressionGzip
+)
+
+// MaxArchiveContentSize limits the size of the decompressed archive content.
+const MaxArchiveContentSize = 16 * 1024 * 1024
+
+//msgp:ignore Archive ArchiveMsg
+
+// Archive stores arbitrary data on the dag. An archive signed by an account
+// carries the account in From, an anonymous one has neither From nor
+// signature.
 type Archive struct {
 	types.TxBase
-	Data []byte `json:"data"`
+	Data        []byte          `json:"data"`
+	From        *common.Address `json:"from"`
+	ContentType string          `json:"content_type"`
+	Compression uint8           `json:"compression"`
+}
+
+// An Archive is encoded as the tuple of legacyArchive if it has neither
+// sender nor content metadata, so that the nodes from before the signed
+// archives still decode it, as the one of signedArchive otherwise.
+
+//msgp:tuple legacyArchive
+type legacyArchive struct {
+	types.TxBase
+	Data []byte
+}
+
+//msgp:tuple signedArchive
+type signedArchive struct {
+	types.TxBase
+	Data        []byte
+	From        *common.Address
+	ContentType string
+	Compression uint8
+}
+
+const legacyArchiveFields = 2
+
+// legacy tells if the archive is encoded in the layout of legacyArchive.
+func (a *Archive) legacy() bool {
+	return a.From == nil && a.ContentType == "" && a.Compression == ArchiveCompressionNone
+}
+
+// DecodeMsg implements msgp.Decodable
+func (a *Archive) DecodeMsg(dc *msgp.Reader) (err error) {
+	size, err := peekArrayHeader(dc)
+	if err != nil {
+		return
+	}
+	if size == legacyArchiveFields {
+		var l legacyArchive
+		err = l.DecodeMsg(dc)
+		if err != nil {
+			return
+		}
+		*a = Archive{TxBase: l.TxBase, Data: l.Data}
+		return
+	}
+	var s signedArchive
+	err = s.DecodeMsg(dc)
+	if err != nil {
+		return
+	}
+	*a = Archive(s)
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (a *Archive) EncodeMsg(en *msgp.Writer) (err error) {
+	if a.legacy() {
+		l := legacyArchive{TxBase: a.TxBase, Data: a.Data}
+		return l.EncodeMsg(en)
+	}
+	s := signedArchive(*a)
+	return s.EncodeMsg(en)
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (a *Archive) MarshalMsg(b []byte) (o []byte, err error) {
+	if a.legacy() {
+		l := legacyArchive{TxBase: a.TxBase, Data: a.Data}
+		return l.MarshalMsg(b)
+	}
+	s := signedArchive(*a)
+	return s.MarshalMsg(b)
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (a *Archive) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	size, _, err := msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if size == legacyArchiveFields {
+		var l legacyArchive
+		o, err = l.UnmarshalMsg(bts)
+		if err != nil {
+			return
+		}
+		*a = Archive{TxBase: l.TxBase, Data: l.Data}
+		return
+	}
+	var s signedArchive
+	o, err = s.UnmarshalMsg(bts)
+	if err != nil {
+		return
+	}
+	*a = Archive(s)
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (a *Archive) Msgsize() (s int) {
+	sa := signedArchive(*a)
+	return sa.Msgsize()
 }
 
 //msgp:tuple ArchiveJson
 type ArchiveJson struct {
 	types.TxBaseJson
-	Data []byte `json:"data"`
+	Data        []byte          `json:"data"`
+	From        *common.Address `json:"from"`
+	ContentType string          `json:"content_type"`
+	Compression uint8           `json:"compression"`
 }
 
 func (a *Archive) ToSmallCaseJson() ([]byte, error) {
@@ -42,8 +167,11 @@ func (a *Archive) ToSmallCaseJson() ([]byte, error) {
 		return nil, nil
 	}
 	j := ArchiveJson{
-		TxBaseJson: *a.TxBase.ToSmallCase(),
-		Data:       a.Data,
+		TxBaseJson:  *a.TxBase.ToSmallCase(),
+		Data:        a.Data,
+		From:        a.From,
+		ContentType: a.ContentType,
+		Compression: a.Compression,
 	}
 	return json.Marshal(&j)
 }
@@ -55,20 +183,104 @@ func (a *Archive) GetBase() *types.TxBase {
 	return &a.TxBase
 }
 
+// Sender returns the signer of the archive, an empty address if the
+// archive is anonymous.
 func (a *Archive) Sender() common.Address {
-	panic("not implemented")
-	return common.Address{}
-	//return  &Address{}
+	if a.From == nil {
+		return common.Address{}
+	}
+	return *a.From
+}
+
+func (a *Archive) GetSender() *common.Address {
+	return a.From
+}
+
+// Signed tells if the archive is signed by an account.
+func (a *Archive) Signed() bool {
+	return len(a.Signature) != 0
+}
+
+// Extended tells if the archive is signed or carries a content type or a
+// compression, which is encoded in the layout of signedArchive and takes
+// the nonce of its sender since the signed archive fork.
+func (a *Archive) Extended() bool {
+	return a.Signed() || !a.legacy()
+}
+
+// AnonymousArchive tells if txi is an archive signed by no account. Such an
+// archive has no sender whose nonce it would take, while a signed archive
+// takes the nonce of its sender like any other tx.
+func AnonymousArchive(txi types.Txi) bool {
+	a, ok := txi.(*Archive)
+	return ok && !a.Signed()
 }
 
-func (tc *Archive) GetSender() *common.Address {
-	panic("not implemented")
-	return nil
+// Content returns the decompressed archive data.
+func (a *Archive) Content() ([]byte, error) {
+	switch a.Compression {
+	case ArchiveCompressionNone:
+		return a.Data, nil
+	case ArchiveCompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(a.Data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		content, err := ioutil.ReadAll(io.LimitReader(r, MaxArchiveContentSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(content) > MaxArchiveContentSize {
+			return nil, fmt.Errorf("archive content exceeds %d bytes", MaxArchiveContentSize)
+		}
+		return content, nil
+	default:
+		return nil, fmt.Errorf("unknown compression %d", a.Compression)
+	}
+}
+
+// ContentHash returns the sha256 of the decompressed archive data.
+func (a *Archive) ContentHash() (hash common.Hash, err error) {
+	content, err := a.Content()
+	if err != nil {
+		return
+	}
+	hash = ArchiveContentHash(content)
+	return
+}
+
+// ArchiveContentHash returns the hash by which an archive holding content
+// is indexed.
+func ArchiveContentHash(content []byte) (hash common.Hash) {
+	result := sha256.Sum256(content)
+	hash.MustSetBytes(result[0:], common.PaddingNone)
+	return
+}
+
+// CompressArchiveData compresses data with the given compression.
+func CompressArchiveData(data []byte, compression uint8) ([]byte, error) {
+	switch compression {
+	case ArchiveCompressionNone:
+		return data, nil
+	case ArchiveCompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown compression %d", compression)
+	}
 }
 
 func (c *Archive) Compare(tx types.Txi) bool {
 	switch tx := tx.(type) {
-	case *Campaign:
+	case *Archive:
 		if c.GetTxHash().Cmp(tx.GetTxHash()) == 0 {
 			return true
 		}
@@ -87,13 +299,25 @@ func (c *Archive) Dump() string {
 		strings.Join(phashes, " ,"), c.AccountNonce, c.Data)
 }
 
+// SignatureTargets of an archive covers the content hash instead of the
+// data, so that the signer signs the document no matter how it is compressed.
 func (a *Archive) SignatureTargets() []byte {
-	// add parents infornmation.
-	panic("not inplemented")
+	// invalid content is rejected by the format verifier before the signature.
+	contentHash, _ := a.ContentHash()
+	w := types.NewBinaryWriter()
+	w.Write(a.AccountNonce)
+	if !types.CanRecoverPubFromSig && a.From != nil {
+		w.Write(a.From.Bytes)
+	}
+	w.Write([]byte(a.ContentType), contentHash.Bytes)
+	return w.Bytes()
 }
 
 func (a *Archive) String() string {
-	return fmt.Sprintf("%s-%d-Ac", a.TxBase.String(), a.AccountNonce)
+	if a.From == nil {
+		return fmt.Sprintf("%s-%d-Ac", a.TxBase.String(), a.AccountNonce)
+	}
+	return fmt.Sprintf("%s-[%.10s]-%d-Ac", a.TxBase.String(), a.From.String(), a.AccountNonce)
 }
 
 func (as Archives) String() string {
@@ -150,14 +374,19 @@ func (t *Archive) CalcTxHash() (hash common.Hash) {
 		w.Write(ancestor.Bytes)
 	}
 	// do not use Height to calculate tx hash.
-	w.Write(t.Weight, t.Data, t.CalcMinedHash().Bytes)
+	w.Write(t.Weight, t.Data)
+	// archives without metadata keep the hash they had before it existed.
+	if t.ContentType != "" || t.Compression != ArchiveCompressionNone {
+		w.Write(t.Compression, []byte(t.ContentType))
+	}
+	w.Write(t.CalcMinedHash().Bytes)
 	result := sha3.Sum256(w.Bytes())
 	hash.MustSetBytes(result[0:], common.PaddingNone)
 	return
 }
 
 func (t *Archive) SetSender(address common.Address) {
-	return
+	t.From = &address
 }
 
 type ArchiveMsg struct {
@@ -170,6 +399,9 @@ type ArchiveMsg struct {
 	Height       uint64   `json:"height"`
 	Data         []byte   `json:"data"`
 	Sign         string   `json:"sign"`
+	From         string   `json:"from"`
+	ContentType  string   `json:"content_type"`
+	Compression  uint8    `json:"compression"`
 }
 
 func (t *Archive) ToJsonMsg() ArchiveMsg {
@@ -187,5 +419,10 @@ func (t *Archive) ToJsonMsg() ArchiveMsg {
 		txMsg.Parents = append(txMsg.Parents, p.Hex())
 	}
 	txMsg.Data = t.Data
+	if t.From != nil {
+		txMsg.From = t.From.Hex()
+	}
+	txMsg.ContentType = t.ContentType
+	txMsg.Compression = t.Compression
 	return txMsg
 }
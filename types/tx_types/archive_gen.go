@@ -3,126 +3,99 @@ package tx_types
 // Code generated by github.com/tinylib/msgp DO NOT EDIT.
 
 import (
+	"github.com/annchain/OG/common"
 	"github.com/tinylib/msgp/msgp"
 )
 
 // DecodeMsg implements msgp.Decodable
-func (z *Archive) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *ArchiveJson) DecodeMsg(dc *msgp.Reader) (err error) {
 	var zb0001 uint32
 	zb0001, err = dc.ReadArrayHeader()
 	if err != nil {
+		err = msgp.WrapError(err)
 		return
 	}
-	if zb0001 != 2 {
-		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+	if zb0001 != 5 {
+		err = msgp.ArrayError{Wanted: 5, Got: zb0001}
 		return
 	}
-	err = z.TxBase.DecodeMsg(dc)
+	err = z.TxBaseJson.DecodeMsg(dc)
 	if err != nil {
+		err = msgp.WrapError(err, "TxBaseJson")
 		return
 	}
 	z.Data, err = dc.ReadBytes(z.Data)
 	if err != nil {
+		err = msgp.WrapError(err, "Data")
 		return
 	}
-	return
-}
-
-// EncodeMsg implements msgp.Encodable
-func (z *Archive) EncodeMsg(en *msgp.Writer) (err error) {
-	// array header, size 2
-	err = en.Append(0x92)
-	if err != nil {
-		return
-	}
-	err = z.TxBase.EncodeMsg(en)
-	if err != nil {
-		return
-	}
-	err = en.WriteBytes(z.Data)
-	if err != nil {
-		return
-	}
-	return
-}
-
-// MarshalMsg implements msgp.Marshaler
-func (z *Archive) MarshalMsg(b []byte) (o []byte, err error) {
-	o = msgp.Require(b, z.Msgsize())
-	// array header, size 2
-	o = append(o, 0x92)
-	o, err = z.TxBase.MarshalMsg(o)
-	if err != nil {
-		return
-	}
-	o = msgp.AppendBytes(o, z.Data)
-	return
-}
-
-// UnmarshalMsg implements msgp.Unmarshaler
-func (z *Archive) UnmarshalMsg(bts []byte) (o []byte, err error) {
-	var zb0001 uint32
-	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
-	if err != nil {
-		return
-	}
-	if zb0001 != 2 {
-		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
-		return
+	if dc.IsNil() {
+		err = dc.ReadNil()
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
+		z.From = nil
+	} else {
+		if z.From == nil {
+			z.From = new(common.Address)
+		}
+		err = z.From.DecodeMsg(dc)
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
 	}
-	bts, err = z.TxBase.UnmarshalMsg(bts)
+	z.ContentType, err = dc.ReadString()
 	if err != nil {
+		err = msgp.WrapError(err, "ContentType")
 		return
 	}
-	z.Data, bts, err = msgp.ReadBytesBytes(bts, z.Data)
+	z.Compression, err = dc.ReadUint8()
 	if err != nil {
+		err = msgp.WrapError(err, "Compression")
 		return
 	}
-	o = bts
-	return
-}
-
-// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *Archive) Msgsize() (s int) {
-	s = 1 + z.TxBase.Msgsize() + msgp.BytesPrefixSize + len(z.Data)
 	return
 }
 
-// DecodeMsg implements msgp.Decodable
-func (z *ArchiveJson) DecodeMsg(dc *msgp.Reader) (err error) {
-	var zb0001 uint32
-	zb0001, err = dc.ReadArrayHeader()
+// EncodeMsg implements msgp.Encodable
+func (z *ArchiveJson) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 5
+	err = en.Append(0x95)
 	if err != nil {
 		return
 	}
-	if zb0001 != 2 {
-		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
-		return
-	}
-	err = z.TxBaseJson.DecodeMsg(dc)
+	err = z.TxBaseJson.EncodeMsg(en)
 	if err != nil {
+		err = msgp.WrapError(err, "TxBaseJson")
 		return
 	}
-	z.Data, err = dc.ReadBytes(z.Data)
+	err = en.WriteBytes(z.Data)
 	if err != nil {
+		err = msgp.WrapError(err, "Data")
 		return
 	}
-	return
-}
-
-// EncodeMsg implements msgp.Encodable
-func (z *ArchiveJson) EncodeMsg(en *msgp.Writer) (err error) {
-	// array header, size 2
-	err = en.Append(0x92)
-	if err != nil {
-		return
+	if z.From == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.From.EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
 	}
-	err = z.TxBaseJson.EncodeMsg(en)
+	err = en.WriteString(z.ContentType)
 	if err != nil {
+		err = msgp.WrapError(err, "ContentType")
 		return
 	}
-	err = en.WriteBytes(z.Data)
+	err = en.WriteUint8(z.Compression)
 	if err != nil {
+		err = msgp.WrapError(err, "Compression")
 		return
 	}
 	return
@@ -131,13 +104,25 @@ func (z *ArchiveJson) EncodeMsg(en *msgp.Writer) (err error) {
 // MarshalMsg implements msgp.Marshaler
 func (z *ArchiveJson) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// array header, size 2
-	o = append(o, 0x92)
+	// array header, size 5
+	o = append(o, 0x95)
 	o, err = z.TxBaseJson.MarshalMsg(o)
 	if err != nil {
+		err = msgp.WrapError(err, "TxBaseJson")
 		return
 	}
 	o = msgp.AppendBytes(o, z.Data)
+	if z.From == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.From.MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
+	}
+	o = msgp.AppendString(o, z.ContentType)
+	o = msgp.AppendUint8(o, z.Compression)
 	return
 }
 
@@ -146,18 +131,47 @@ func (z *ArchiveJson) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var zb0001 uint32
 	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
 	if err != nil {
+		err = msgp.WrapError(err)
 		return
 	}
-	if zb0001 != 2 {
-		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+	if zb0001 != 5 {
+		err = msgp.ArrayError{Wanted: 5, Got: zb0001}
 		return
 	}
 	bts, err = z.TxBaseJson.UnmarshalMsg(bts)
 	if err != nil {
+		err = msgp.WrapError(err, "TxBaseJson")
 		return
 	}
 	z.Data, bts, err = msgp.ReadBytesBytes(bts, z.Data)
 	if err != nil {
+		err = msgp.WrapError(err, "Data")
+		return
+	}
+	if msgp.IsNil(bts) {
+		bts, err = msgp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.From = nil
+	} else {
+		if z.From == nil {
+			z.From = new(common.Address)
+		}
+		bts, err = z.From.UnmarshalMsg(bts)
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
+	}
+	z.ContentType, bts, err = msgp.ReadStringBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err, "ContentType")
+		return
+	}
+	z.Compression, bts, err = msgp.ReadUint8Bytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err, "Compression")
 		return
 	}
 	o = bts
@@ -167,6 +181,12 @@ func (z *ArchiveJson) UnmarshalMsg(bts []byte) (o []byte, err error) {
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *ArchiveJson) Msgsize() (s int) {
 	s = 1 + z.TxBaseJson.Msgsize() + msgp.BytesPrefixSize + len(z.Data)
+	if z.From == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.From.Msgsize()
+	}
+	s += msgp.StringPrefixSize + len(z.ContentType) + msgp.Uint8Size
 	return
 }
 
@@ -175,6 +195,7 @@ func (z *Archives) DecodeMsg(dc *msgp.Reader) (err error) {
 	var zb0002 uint32
 	zb0002, err = dc.ReadArrayHeader()
 	if err != nil {
+		err = msgp.WrapError(err)
 		return
 	}
 	if cap((*z)) >= int(zb0002) {
@@ -186,6 +207,7 @@ func (z *Archives) DecodeMsg(dc *msgp.Reader) (err error) {
 		if dc.IsNil() {
 			err = dc.ReadNil()
 			if err != nil {
+				err = msgp.WrapError(err, zb0001)
 				return
 			}
 			(*z)[zb0001] = nil
@@ -193,21 +215,9 @@ func (z *Archives) DecodeMsg(dc *msgp.Reader) (err error) {
 			if (*z)[zb0001] == nil {
 				(*z)[zb0001] = new(Archive)
 			}
-			var zb0003 uint32
-			zb0003, err = dc.ReadArrayHeader()
-			if err != nil {
-				return
-			}
-			if zb0003 != 2 {
-				err = msgp.ArrayError{Wanted: 2, Got: zb0003}
-				return
-			}
-			err = (*z)[zb0001].TxBase.DecodeMsg(dc)
-			if err != nil {
-				return
-			}
-			(*z)[zb0001].Data, err = dc.ReadBytes((*z)[zb0001].Data)
+			err = (*z)[zb0001].DecodeMsg(dc)
 			if err != nil {
+				err = msgp.WrapError(err, zb0001)
 				return
 			}
 		}
@@ -219,26 +229,19 @@ func (z *Archives) DecodeMsg(dc *msgp.Reader) (err error) {
 func (z Archives) EncodeMsg(en *msgp.Writer) (err error) {
 	err = en.WriteArrayHeader(uint32(len(z)))
 	if err != nil {
+		err = msgp.WrapError(err)
 		return
 	}
-	for zb0004 := range z {
-		if z[zb0004] == nil {
+	for zb0003 := range z {
+		if z[zb0003] == nil {
 			err = en.WriteNil()
 			if err != nil {
 				return
 			}
 		} else {
-			// array header, size 2
-			err = en.Append(0x92)
-			if err != nil {
-				return
-			}
-			err = z[zb0004].TxBase.EncodeMsg(en)
-			if err != nil {
-				return
-			}
-			err = en.WriteBytes(z[zb0004].Data)
+			err = z[zb0003].EncodeMsg(en)
 			if err != nil {
+				err = msgp.WrapError(err, zb0003)
 				return
 			}
 		}
@@ -250,17 +253,15 @@ func (z Archives) EncodeMsg(en *msgp.Writer) (err error) {
 func (z Archives) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	o = msgp.AppendArrayHeader(o, uint32(len(z)))
-	for zb0004 := range z {
-		if z[zb0004] == nil {
+	for zb0003 := range z {
+		if z[zb0003] == nil {
 			o = msgp.AppendNil(o)
 		} else {
-			// array header, size 2
-			o = append(o, 0x92)
-			o, err = z[zb0004].TxBase.MarshalMsg(o)
+			o, err = z[zb0003].MarshalMsg(o)
 			if err != nil {
+				err = msgp.WrapError(err, zb0003)
 				return
 			}
-			o = msgp.AppendBytes(o, z[zb0004].Data)
 		}
 	}
 	return
@@ -271,6 +272,7 @@ func (z *Archives) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var zb0002 uint32
 	zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
 	if err != nil {
+		err = msgp.WrapError(err)
 		return
 	}
 	if cap((*z)) >= int(zb0002) {
@@ -289,21 +291,9 @@ func (z *Archives) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if (*z)[zb0001] == nil {
 				(*z)[zb0001] = new(Archive)
 			}
-			var zb0003 uint32
-			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
-			if err != nil {
-				return
-			}
-			if zb0003 != 2 {
-				err = msgp.ArrayError{Wanted: 2, Got: zb0003}
-				return
-			}
-			bts, err = (*z)[zb0001].TxBase.UnmarshalMsg(bts)
-			if err != nil {
-				return
-			}
-			(*z)[zb0001].Data, bts, err = msgp.ReadBytesBytes(bts, (*z)[zb0001].Data)
+			bts, err = (*z)[zb0001].UnmarshalMsg(bts)
 			if err != nil {
+				err = msgp.WrapError(err, zb0001)
 				return
 			}
 		}
@@ -315,12 +305,286 @@ func (z *Archives) UnmarshalMsg(bts []byte) (o []byte, err error) {
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z Archives) Msgsize() (s int) {
 	s = msgp.ArrayHeaderSize
-	for zb0004 := range z {
-		if z[zb0004] == nil {
+	for zb0003 := range z {
+		if z[zb0003] == nil {
 			s += msgp.NilSize
 		} else {
-			s += 1 + z[zb0004].TxBase.Msgsize() + msgp.BytesPrefixSize + len(z[zb0004].Data)
+			s += z[zb0003].Msgsize()
 		}
 	}
 	return
 }
+
+// DecodeMsg implements msgp.Decodable
+func (z *legacyArchive) DecodeMsg(dc *msgp.Reader) (err error) {
+	var zb0001 uint32
+	zb0001, err = dc.ReadArrayHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	err = z.TxBase.DecodeMsg(dc)
+	if err != nil {
+		err = msgp.WrapError(err, "TxBase")
+		return
+	}
+	z.Data, err = dc.ReadBytes(z.Data)
+	if err != nil {
+		err = msgp.WrapError(err, "Data")
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *legacyArchive) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 2
+	err = en.Append(0x92)
+	if err != nil {
+		return
+	}
+	err = z.TxBase.EncodeMsg(en)
+	if err != nil {
+		err = msgp.WrapError(err, "TxBase")
+		return
+	}
+	err = en.WriteBytes(z.Data)
+	if err != nil {
+		err = msgp.WrapError(err, "Data")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *legacyArchive) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// array header, size 2
+	o = append(o, 0x92)
+	o, err = z.TxBase.MarshalMsg(o)
+	if err != nil {
+		err = msgp.WrapError(err, "TxBase")
+		return
+	}
+	o = msgp.AppendBytes(o, z.Data)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *legacyArchive) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	if zb0001 != 2 {
+		err = msgp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	bts, err = z.TxBase.UnmarshalMsg(bts)
+	if err != nil {
+		err = msgp.WrapError(err, "TxBase")
+		return
+	}
+	z.Data, bts, err = msgp.ReadBytesBytes(bts, z.Data)
+	if err != nil {
+		err = msgp.WrapError(err, "Data")
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *legacyArchive) Msgsize() (s int) {
+	s = 1 + z.TxBase.Msgsize() + msgp.BytesPrefixSize + len(z.Data)
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *signedArchive) DecodeMsg(dc *msgp.Reader) (err error) {
+	var zb0001 uint32
+	zb0001, err = dc.ReadArrayHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	if zb0001 != 5 {
+		err = msgp.ArrayError{Wanted: 5, Got: zb0001}
+		return
+	}
+	err = z.TxBase.DecodeMsg(dc)
+	if err != nil {
+		err = msgp.WrapError(err, "TxBase")
+		return
+	}
+	z.Data, err = dc.ReadBytes(z.Data)
+	if err != nil {
+		err = msgp.WrapError(err, "Data")
+		return
+	}
+	if dc.IsNil() {
+		err = dc.ReadNil()
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
+		z.From = nil
+	} else {
+		if z.From == nil {
+			z.From = new(common.Address)
+		}
+		err = z.From.DecodeMsg(dc)
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
+	}
+	z.ContentType, err = dc.ReadString()
+	if err != nil {
+		err = msgp.WrapError(err, "ContentType")
+		return
+	}
+	z.Compression, err = dc.ReadUint8()
+	if err != nil {
+		err = msgp.WrapError(err, "Compression")
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *signedArchive) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 5
+	err = en.Append(0x95)
+	if err != nil {
+		return
+	}
+	err = z.TxBase.EncodeMsg(en)
+	if err != nil {
+		err = msgp.WrapError(err, "TxBase")
+		return
+	}
+	err = en.WriteBytes(z.Data)
+	if err != nil {
+		err = msgp.WrapError(err, "Data")
+		return
+	}
+	if z.From == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.From.EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
+	}
+	err = en.WriteString(z.ContentType)
+	if err != nil {
+		err = msgp.WrapError(err, "ContentType")
+		return
+	}
+	err = en.WriteUint8(z.Compression)
+	if err != nil {
+		err = msgp.WrapError(err, "Compression")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *signedArchive) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// array header, size 5
+	o = append(o, 0x95)
+	o, err = z.TxBase.MarshalMsg(o)
+	if err != nil {
+		err = msgp.WrapError(err, "TxBase")
+		return
+	}
+	o = msgp.AppendBytes(o, z.Data)
+	if z.From == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.From.MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
+	}
+	o = msgp.AppendString(o, z.ContentType)
+	o = msgp.AppendUint8(o, z.Compression)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *signedArchive) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	if zb0001 != 5 {
+		err = msgp.ArrayError{Wanted: 5, Got: zb0001}
+		return
+	}
+	bts, err = z.TxBase.UnmarshalMsg(bts)
+	if err != nil {
+		err = msgp.WrapError(err, "TxBase")
+		return
+	}
+	z.Data, bts, err = msgp.ReadBytesBytes(bts, z.Data)
+	if err != nil {
+		err = msgp.WrapError(err, "Data")
+		return
+	}
+	if msgp.IsNil(bts) {
+		bts, err = msgp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.From = nil
+	} else {
+		if z.From == nil {
+			z.From = new(common.Address)
+		}
+		bts, err = z.From.UnmarshalMsg(bts)
+		if err != nil {
+			err = msgp.WrapError(err, "From")
+			return
+		}
+	}
+	z.ContentType, bts, err = msgp.ReadStringBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err, "ContentType")
+		return
+	}
+	z.Compression, bts, err = msgp.ReadUint8Bytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err, "Compression")
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *signedArchive) Msgsize() (s int) {
+	s = 1 + z.TxBase.Msgsize() + msgp.BytesPrefixSize + len(z.Data)
+	if z.From == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.From.Msgsize()
+	}
+	s += msgp.StringPrefixSize + len(z.ContentType) + msgp.Uint8Size
+	return
+}
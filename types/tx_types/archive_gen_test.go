@@ -9,8 +9,8 @@ import (
 	"github.com/tinylib/msgp/msgp"
 )
 
-func TestMarshalUnmarshalArchive(t *testing.T) {
-	v := Archive{}
+func TestMarshalUnmarshalArchiveJson(t *testing.T) {
+	v := ArchiveJson{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -32,8 +32,8 @@ func TestMarshalUnmarshalArchive(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgArchive(b *testing.B) {
-	v := Archive{}
+func BenchmarkMarshalMsgArchiveJson(b *testing.B) {
+	v := ArchiveJson{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -41,8 +41,8 @@ func BenchmarkMarshalMsgArchive(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgArchive(b *testing.B) {
-	v := Archive{}
+func BenchmarkAppendMsgArchiveJson(b *testing.B) {
+	v := ArchiveJson{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -53,8 +53,8 @@ func BenchmarkAppendMsgArchive(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalArchive(b *testing.B) {
-	v := Archive{}
+func BenchmarkUnmarshalArchiveJson(b *testing.B) {
+	v := ArchiveJson{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -67,8 +67,8 @@ func BenchmarkUnmarshalArchive(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeArchive(t *testing.T) {
-	v := Archive{}
+func TestEncodeDecodeArchiveJson(t *testing.T) {
+	v := ArchiveJson{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -77,7 +77,7 @@ func TestEncodeDecodeArchive(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := Archive{}
+	vn := ArchiveJson{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -91,8 +91,8 @@ func TestEncodeDecodeArchive(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeArchive(b *testing.B) {
-	v := Archive{}
+func BenchmarkEncodeArchiveJson(b *testing.B) {
+	v := ArchiveJson{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -105,8 +105,8 @@ func BenchmarkEncodeArchive(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeArchive(b *testing.B) {
-	v := Archive{}
+func BenchmarkDecodeArchiveJson(b *testing.B) {
+	v := ArchiveJson{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -122,8 +122,8 @@ func BenchmarkDecodeArchive(b *testing.B) {
 	}
 }
 
-func TestMarshalUnmarshalArchiveJson(t *testing.T) {
-	v := ArchiveJson{}
+func TestMarshalUnmarshalArchives(t *testing.T) {
+	v := Archives{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -145,8 +145,8 @@ func TestMarshalUnmarshalArchiveJson(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgArchiveJson(b *testing.B) {
-	v := ArchiveJson{}
+func BenchmarkMarshalMsgArchives(b *testing.B) {
+	v := Archives{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -154,8 +154,8 @@ func BenchmarkMarshalMsgArchiveJson(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgArchiveJson(b *testing.B) {
-	v := ArchiveJson{}
+func BenchmarkAppendMsgArchives(b *testing.B) {
+	v := Archives{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -166,8 +166,8 @@ func BenchmarkAppendMsgArchiveJson(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalArchiveJson(b *testing.B) {
-	v := ArchiveJson{}
+func BenchmarkUnmarshalArchives(b *testing.B) {
+	v := Archives{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -180,8 +180,8 @@ func BenchmarkUnmarshalArchiveJson(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeArchiveJson(t *testing.T) {
-	v := ArchiveJson{}
+func TestEncodeDecodeArchives(t *testing.T) {
+	v := Archives{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -190,7 +190,7 @@ func TestEncodeDecodeArchiveJson(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := ArchiveJson{}
+	vn := Archives{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -204,8 +204,8 @@ func TestEncodeDecodeArchiveJson(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeArchiveJson(b *testing.B) {
-	v := ArchiveJson{}
+func BenchmarkEncodeArchives(b *testing.B) {
+	v := Archives{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -218,8 +218,8 @@ func BenchmarkEncodeArchiveJson(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeArchiveJson(b *testing.B) {
-	v := ArchiveJson{}
+func BenchmarkDecodeArchives(b *testing.B) {
+	v := Archives{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -235,8 +235,8 @@ func BenchmarkDecodeArchiveJson(b *testing.B) {
 	}
 }
 
-func TestMarshalUnmarshalArchives(t *testing.T) {
-	v := Archives{}
+func TestMarshalUnmarshallegacyArchive(t *testing.T) {
+	v := legacyArchive{}
 	bts, err := v.MarshalMsg(nil)
 	if err != nil {
 		t.Fatal(err)
@@ -258,8 +258,8 @@ func TestMarshalUnmarshalArchives(t *testing.T) {
 	}
 }
 
-func BenchmarkMarshalMsgArchives(b *testing.B) {
-	v := Archives{}
+func BenchmarkMarshalMsglegacyArchive(b *testing.B) {
+	v := legacyArchive{}
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -267,8 +267,8 @@ func BenchmarkMarshalMsgArchives(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendMsgArchives(b *testing.B) {
-	v := Archives{}
+func BenchmarkAppendMsglegacyArchive(b *testing.B) {
+	v := legacyArchive{}
 	bts := make([]byte, 0, v.Msgsize())
 	bts, _ = v.MarshalMsg(bts[0:0])
 	b.SetBytes(int64(len(bts)))
@@ -279,8 +279,8 @@ func BenchmarkAppendMsgArchives(b *testing.B) {
 	}
 }
 
-func BenchmarkUnmarshalArchives(b *testing.B) {
-	v := Archives{}
+func BenchmarkUnmarshallegacyArchive(b *testing.B) {
+	v := legacyArchive{}
 	bts, _ := v.MarshalMsg(nil)
 	b.ReportAllocs()
 	b.SetBytes(int64(len(bts)))
@@ -293,8 +293,8 @@ func BenchmarkUnmarshalArchives(b *testing.B) {
 	}
 }
 
-func TestEncodeDecodeArchives(t *testing.T) {
-	v := Archives{}
+func TestEncodeDecodelegacyArchive(t *testing.T) {
+	v := legacyArchive{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 
@@ -303,7 +303,7 @@ func TestEncodeDecodeArchives(t *testing.T) {
 		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
 	}
 
-	vn := Archives{}
+	vn := legacyArchive{}
 	err := msgp.Decode(&buf, &vn)
 	if err != nil {
 		t.Error(err)
@@ -317,8 +317,8 @@ func TestEncodeDecodeArchives(t *testing.T) {
 	}
 }
 
-func BenchmarkEncodeArchives(b *testing.B) {
-	v := Archives{}
+func BenchmarkEncodelegacyArchive(b *testing.B) {
+	v := legacyArchive{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -331,8 +331,121 @@ func BenchmarkEncodeArchives(b *testing.B) {
 	en.Flush()
 }
 
-func BenchmarkDecodeArchives(b *testing.B) {
-	v := Archives{}
+func BenchmarkDecodelegacyArchive(b *testing.B) {
+	v := legacyArchive{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalUnmarshalsignedArchive(t *testing.T) {
+	v := signedArchive{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgsignedArchive(b *testing.B) {
+	v := signedArchive{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgsignedArchive(b *testing.B) {
+	v := signedArchive{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalsignedArchive(b *testing.B) {
+	v := signedArchive{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodesignedArchive(t *testing.T) {
+	v := signedArchive{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Logf("WARNING: Msgsize() for %v is inaccurate", v)
+	}
+
+	vn := signedArchive{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodesignedArchive(b *testing.B) {
+	v := signedArchive{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodesignedArchive(b *testing.B) {
+	v := signedArchive{}
 	var buf bytes.Buffer
 	msgp.Encode(&buf, &v)
 	b.SetBytes(int64(buf.Len()))
@@ -14,8 +14,11 @@
 package tx_types
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/annchain/OG/common"
+	"github.com/tinylib/msgp/msgp"
 	"testing"
 )
 
@@ -24,3 +27,104 @@ func TestRandomArchive(t *testing.T) {
 	d, _ := json.MarshalIndent(a, "", "\t")
 	fmt.Println(string(d))
 }
+
+func TestArchiveContent(t *testing.T) {
+	content := []byte("a document to be notarized")
+	data, err := CompressArchiveData(content, ArchiveCompressionGzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := RandomArchive()
+	a.Data = data
+	a.Compression = ArchiveCompressionGzip
+	got, err := a.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("content mismatch, got %s", got)
+	}
+	hash, err := a.ContentHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != ArchiveContentHash(content) {
+		t.Fatalf("content hash mismatch")
+	}
+	a.Compression = 9
+	if _, err := a.Content(); err == nil {
+		t.Fatal("unknown compression should fail")
+	}
+}
+
+func TestArchiveMarshal(t *testing.T) {
+	from := common.RandomAddress()
+	a := RandomArchive()
+	a.From = &from
+	a.ContentType = "text/plain"
+	a.Compression = ArchiveCompressionGzip
+	bts, err := a.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b Archive
+	_, err = b.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.From == nil || *b.From != from || b.ContentType != a.ContentType || b.Compression != a.Compression {
+		t.Fatalf("archive mismatch %v", b)
+	}
+
+	// archives written before the metadata existed have 2 fields.
+	legacy := RandomArchive()
+	bts = msgp.AppendArrayHeader(nil, 2)
+	bts, _ = legacy.TxBase.MarshalMsg(bts)
+	bts = msgp.AppendBytes(bts, legacy.Data)
+	var c Archive
+	_, err = c.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.From != nil || !bytes.Equal(c.Data, legacy.Data) {
+		t.Fatalf("legacy archive mismatch %v", c)
+	}
+	if c.CalcTxHash() != legacy.CalcTxHash() {
+		t.Fatal("legacy archive hash changed")
+	}
+	reencoded, err := c.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reencoded, bts) {
+		t.Fatal("legacy archive should keep its encoding")
+	}
+
+	var buf bytes.Buffer
+	err = msgp.Encode(&buf, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Archive
+	err = msgp.Decode(&buf, &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.From == nil || *d.From != from || d.ContentType != a.ContentType || d.Compression != a.Compression {
+		t.Fatalf("decoded archive mismatch %v", d)
+	}
+}
+
+func TestAnonymousArchive(t *testing.T) {
+	a := RandomArchive()
+	if !AnonymousArchive(a) {
+		t.Fatal("unsigned archive should be anonymous")
+	}
+	a.Signature = common.RandomHash().ToBytes()
+	if AnonymousArchive(a) {
+		t.Fatal("signed archive should take the nonce of its sender")
+	}
+	if AnonymousArchive(RandomTx()) {
+		t.Fatal("tx is not an archive")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/annchain/OG/common"
+)
+
+// limits of the domain names and of the number of sequencer heights a
+// domain name is registered or renewed for.
+const (
+	MinDomainNameLength = 3
+	MaxDomainNameLength = 63
+	MinDomainPeriod     = 1
+	MaxDomainPeriod     = 100000000
+)
+
+// ValidateDomainName checks that name is made of lower case letters, digits
+// and hyphens, doesn't start or end with a hyphen and can't be mistaken for
+// an address.
+func ValidateDomainName(name string) error {
+	if len(name) < MinDomainNameLength || len(name) > MaxDomainNameLength {
+		return fmt.Errorf("domain name should have %d to %d characters", MinDomainNameLength, MaxDomainNameLength)
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return fmt.Errorf("domain name should only contain lower case letters, digits and hyphens")
+		}
+	}
+	if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") {
+		return fmt.Errorf("domain name should not start or end with a hyphen")
+	}
+	if strings.HasPrefix(name, "0x") || isHexAddress(name) {
+		return fmt.Errorf("domain name should not look like an address")
+	}
+	return nil
+}
+
+// isHexAddress tells if name is an address without the 0x prefix.
+func isHexAddress(name string) bool {
+	if len(name) != 2*common.AddressLength {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'f' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the request of the domain action without the state.
+func (r *RequestDomain) Validate(action uint8) error {
+	if err := ValidateDomainName(r.DomainName); err != nil {
+		return err
+	}
+	switch action {
+	case ActionRequestDomainName, ActionRenewDomainName:
+		if r.Period < MinDomainPeriod || r.Period > MaxDomainPeriod {
+			return fmt.Errorf("period should be between %d and %d", MinDomainPeriod, MaxDomainPeriod)
+		}
+	case ActionTransferDomainName:
+		if r.To == (common.Address{}) {
+			return fmt.Errorf("transfer to an empty address")
+		}
+	default:
+		return fmt.Errorf("unknown domain action %d", action)
+	}
+	return nil
+}
+
+// IsDomain returns true if the action tx registers, renews or transfers a
+// domain name.
+func (t *ActionTx) IsDomain() bool {
+	return t.Action == ActionRequestDomainName || t.Action == ActionRenewDomainName || t.Action == ActionTransferDomainName
+}
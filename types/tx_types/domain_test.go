@@ -0,0 +1,55 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"github.com/annchain/OG/common"
+	"testing"
+)
+
+func TestValidateDomainName(t *testing.T) {
+	valid := []string{"alice", "og-1", "abc", "889e0b36dc6f2c06eb68d9c5f53434e4c42c8d1"}
+	invalid := []string{"", "ab", "Alice", "a.b", "-alice", "alice-", "0xalice",
+		"889e0b36dc6f2c06eb68d9c5f53434e4c42c8d19", string(make([]byte, MaxDomainNameLength+1))}
+	for _, name := range valid {
+		if err := ValidateDomainName(name); err != nil {
+			t.Errorf("%s should be valid: %v", name, err)
+		}
+	}
+	for _, name := range invalid {
+		if ValidateDomainName(name) == nil {
+			t.Errorf("%s should be invalid", name)
+		}
+	}
+}
+
+func TestRequestDomainValidate(t *testing.T) {
+	r := RequestDomain{DomainName: "alice"}
+	if r.Validate(ActionRequestDomainName) == nil {
+		t.Fatal("register without period")
+	}
+	if r.Validate(ActionTransferDomainName) == nil {
+		t.Fatal("transfer to empty address")
+	}
+	r.Period = MaxDomainPeriod
+	r.To = common.HexToAddress("0x889e0b36dc6f2c06eb68d9c5f53434e4c42c8d19")
+	for _, action := range []uint8{ActionRequestDomainName, ActionRenewDomainName, ActionTransferDomainName} {
+		if err := r.Validate(action); err != nil {
+			t.Fatalf("action %d should be valid: %v", action, err)
+		}
+	}
+	if r.Validate(ActionTxActionIPO) == nil {
+		t.Fatal("not a domain action")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"fmt"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/hexutil"
+)
+
+//go:generate msgp
+
+// consensus parameters that can be changed by governance proposals.
+const (
+	GovernanceParamPartnerNumber uint8 = iota
+	GovernanceParamThreshold
+	GovernanceParamTermChangeInterval
+	GovernanceParamSequencerInterval
+	GovernanceParamMaxTxHash
+)
+
+func GovernanceParamName(param uint8) string {
+	switch param {
+	case GovernanceParamPartnerNumber:
+		return "partner_number"
+	case GovernanceParamThreshold:
+		return "threshold"
+	case GovernanceParamTermChangeInterval:
+		return "term_change_interval"
+	case GovernanceParamSequencerInterval:
+		return "sequencer_interval"
+	case GovernanceParamMaxTxHash:
+		return "max_tx_hash"
+	default:
+		return "unknown"
+	}
+}
+
+// GovernanceProposal is raised by a senator to change one consensus
+// parameter. The change takes effect at the next term boundary once
+// enough senators voted for it.
+//msgp:tuple GovernanceProposal
+type GovernanceProposal struct {
+	TermId    uint64      `json:"term_id"`
+	Parameter uint8       `json:"parameter"`
+	Value     uint64      `json:"value"`
+	HashValue common.Hash `json:"hash_value"` // only used by GovernanceParamMaxTxHash
+}
+
+// GovernanceVote is a senator's vote on a proposal. BlsSig is the bls
+// signature of the proposal hash signed by the dkg key published in the
+// senator's campaign.
+//msgp:tuple GovernanceVote
+type GovernanceVote struct {
+	ProposalHash common.Hash   `json:"proposal_hash"`
+	Approve      bool          `json:"approve"`
+	BlsSig       hexutil.Bytes `json:"bls_sig"`
+}
+
+func (p GovernanceProposal) String() string {
+	if p.Parameter == GovernanceParamMaxTxHash {
+		return fmt.Sprintf("term %d, %s: %s", p.TermId, GovernanceParamName(p.Parameter), p.HashValue.Hex())
+	}
+	return fmt.Sprintf("term %d, %s: %d", p.TermId, GovernanceParamName(p.Parameter), p.Value)
+}
+
+// ValidateValue checks whether the proposed value is acceptable for
+// the parameter. It doesn't check anything related to current state.
+func (p *GovernanceProposal) ValidateValue() error {
+	switch p.Parameter {
+	case GovernanceParamPartnerNumber:
+		if p.Value < 2 {
+			return fmt.Errorf("BFT needs at least 2 nodes, got %d", p.Value)
+		}
+	case GovernanceParamThreshold:
+		if p.Value < 1 {
+			return fmt.Errorf("threshold should be positive")
+		}
+	case GovernanceParamTermChangeInterval, GovernanceParamSequencerInterval:
+		if p.Value == 0 {
+			return fmt.Errorf("%s should be positive", GovernanceParamName(p.Parameter))
+		}
+	case GovernanceParamMaxTxHash:
+		if p.HashValue.Empty() {
+			return fmt.Errorf("max tx hash should not be empty")
+		}
+	default:
+		return fmt.Errorf("unknown governance parameter %d", p.Parameter)
+	}
+	return nil
+}
+
+func (v GovernanceVote) String() string {
+	return fmt.Sprintf("proposal %s, approve %v", v.ProposalHash.TerminalString(), v.Approve)
+}
+
+func (t *ActionTx) GetGovernanceProposal() *GovernanceProposal {
+	if t.Action == ActionTxActionGovernanceProposal {
+		v, ok := t.ActionData.(*GovernanceProposal)
+		if ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (t *ActionTx) GetGovernanceVote() *GovernanceVote {
+	if t.Action == ActionTxActionGovernanceVote {
+		v, ok := t.ActionData.(*GovernanceVote)
+		if ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// IsGovernance returns true if the action tx is a governance proposal or vote.
+func (t *ActionTx) IsGovernance() bool {
+	return t.Action == ActionTxActionGovernanceProposal || t.Action == ActionTxActionGovernanceVote
+}
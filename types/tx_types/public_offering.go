@@ -0,0 +1,148 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"github.com/annchain/OG/common/math"
+	"github.com/tinylib/msgp/msgp"
+)
+
+//go:generate msgp -unexported
+
+// A PublicOffering is encoded as the tuple of legacyPublicOffering if it
+// has no metadata, so that the nodes from before the token metadata still
+// decode it, as the one of metadataPublicOffering otherwise.
+
+//msgp:tuple legacyPublicOffering
+type legacyPublicOffering struct {
+	TokenId   int32
+	Value     *math.BigInt
+	EnableSPO bool
+	TokenName string
+}
+
+//msgp:tuple metadataPublicOffering
+type metadataPublicOffering struct {
+	TokenId     int32
+	Value       *math.BigInt
+	EnableSPO   bool
+	TokenName   string
+	TokenSymbol string
+	Decimals    uint8
+	MetadataURI string
+	Controls    uint8
+}
+
+const legacyPublicOfferingFields = 4
+
+func (z *PublicOffering) legacy() *legacyPublicOffering {
+	return &legacyPublicOffering{
+		TokenId:   z.TokenId,
+		Value:     z.Value,
+		EnableSPO: z.EnableSPO,
+		TokenName: z.TokenName,
+	}
+}
+
+// peekArrayHeader returns the size of the array read next by dc without
+// consuming it.
+func peekArrayHeader(dc *msgp.Reader) (uint32, error) {
+	p, err := dc.R.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	switch p[0] {
+	case 0xdc:
+		p, err = dc.R.Peek(3)
+	case 0xdd:
+		p, err = dc.R.Peek(5)
+	}
+	if err != nil {
+		return 0, err
+	}
+	size, _, err := msgp.ReadArrayHeaderBytes(p)
+	return size, err
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *PublicOffering) DecodeMsg(dc *msgp.Reader) (err error) {
+	size, err := peekArrayHeader(dc)
+	if err != nil {
+		return
+	}
+	if size == legacyPublicOfferingFields {
+		var l legacyPublicOffering
+		err = l.DecodeMsg(dc)
+		if err != nil {
+			return
+		}
+		*z = PublicOffering{TokenId: l.TokenId, Value: l.Value, EnableSPO: l.EnableSPO, TokenName: l.TokenName}
+		return
+	}
+	var m metadataPublicOffering
+	err = m.DecodeMsg(dc)
+	if err != nil {
+		return
+	}
+	*z = PublicOffering(m)
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *PublicOffering) EncodeMsg(en *msgp.Writer) (err error) {
+	if z.HasMetadata() {
+		m := metadataPublicOffering(*z)
+		return m.EncodeMsg(en)
+	}
+	return z.legacy().EncodeMsg(en)
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *PublicOffering) MarshalMsg(b []byte) (o []byte, err error) {
+	if z.HasMetadata() {
+		m := metadataPublicOffering(*z)
+		return m.MarshalMsg(b)
+	}
+	return z.legacy().MarshalMsg(b)
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *PublicOffering) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	size, _, err := msgp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if size == legacyPublicOfferingFields {
+		var l legacyPublicOffering
+		o, err = l.UnmarshalMsg(bts)
+		if err != nil {
+			return
+		}
+		*z = PublicOffering{TokenId: l.TokenId, Value: l.Value, EnableSPO: l.EnableSPO, TokenName: l.TokenName}
+		return
+	}
+	var m metadataPublicOffering
+	o, err = m.UnmarshalMsg(bts)
+	if err != nil {
+		return
+	}
+	*z = PublicOffering(m)
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *PublicOffering) Msgsize() (s int) {
+	m := metadataPublicOffering(*z)
+	return m.Msgsize()
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/types"
 	"github.com/annchain/kyber/v3/util/random"
+	"golang.org/x/crypto/sha3"
 
 	"github.com/annchain/OG/common/hexutil"
 )
@@ -124,6 +125,14 @@ func RandomSequencer() *Sequencer {
 }
 
 func (t *Sequencer) SignatureTargets() []byte {
+	return t.SignatureTargetsAt(false)
+}
+
+// SignatureTargetsAt returns the signature targets of the sequencer, with
+// the timestamp if timestampSigned. The timestamp is signed and hashed from
+// the block context fork height on, as TIMESTAMP reads it from there on.
+// Sequencers before it keep the signature and hash they had without it.
+func (t *Sequencer) SignatureTargetsAt(timestampSigned bool) []byte {
 	w := types.NewBinaryWriter()
 
 	w.Write(t.BlsJointPubKey, t.AccountNonce)
@@ -134,9 +143,31 @@ func (t *Sequencer) SignatureTargets() []byte {
 	for _, parent := range t.Parents() {
 		w.Write(parent.Bytes)
 	}
+	if timestampSigned {
+		w.Write(t.Timestamp)
+	}
 	return w.Bytes()
 }
 
+// CalcTxHashAt returns the hash of the sequencer, with the timestamp if
+// timestampSigned. See SignatureTargetsAt.
+func (t *Sequencer) CalcTxHashAt(timestampSigned bool) (hash common.Hash) {
+	if !timestampSigned {
+		return t.TxBase.CalcTxHash()
+	}
+	w := types.NewBinaryWriter()
+
+	for _, ancestor := range t.ParentsHash {
+		w.Write(ancestor.Bytes)
+	}
+	// do not use Height to calculate tx hash.
+	w.Write(t.Weight, t.Timestamp)
+	w.Write(t.CalcMinedHash().Bytes)
+	result := sha3.Sum256(w.Bytes())
+	hash.MustSetBytes(result[0:], common.PaddingNone)
+	return
+}
+
 func (t *Sequencer) Sender() common.Address {
 	return *t.Issuer
 }
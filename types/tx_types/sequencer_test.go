@@ -14,6 +14,7 @@
 package tx_types
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/types"
@@ -98,3 +99,22 @@ func TestSequencer_Number(t *testing.T) {
 	o, err := newSeq.UnmarshalMsg(data)
 	fmt.Println(o, err)
 }
+
+func TestSequencerTimestampSigned(t *testing.T) {
+	addr := common.HexToAddress("0x1")
+	for _, signed := range []bool{false, true} {
+		seq := Sequencer{TxBase: types.TxBase{Height: 10, Signature: []byte{1}}, Issuer: &addr, Timestamp: 1000}
+		targets, hash := seq.SignatureTargetsAt(signed), seq.CalcTxHashAt(signed)
+		seq.Timestamp = 2000
+		if changed := !bytes.Equal(targets, seq.SignatureTargetsAt(signed)); changed != signed {
+			t.Errorf("timestamp signed %v, expected %v", changed, signed)
+		}
+		if changed := hash != seq.CalcTxHashAt(signed); changed != signed {
+			t.Errorf("timestamp hashed %v, expected %v", changed, signed)
+		}
+	}
+	seq := Sequencer{TxBase: types.TxBase{Height: 10, Signature: []byte{1}}, Issuer: &addr, Timestamp: 1000}
+	if seq.CalcTxHash() != seq.CalcTxHashAt(false) || !bytes.Equal(seq.SignatureTargets(), seq.SignatureTargetsAt(false)) {
+		t.Errorf("the timestamp should not be signed by default")
+	}
+}
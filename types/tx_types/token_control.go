@@ -0,0 +1,95 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"fmt"
+
+	"github.com/annchain/OG/common"
+)
+
+//go:generate msgp
+
+// controls the issuer can enable on a token at the IPO. They can't be
+// changed once the token is issued.
+const (
+	// TokenControlFreeze lets the issuer freeze the token of a holder.
+	TokenControlFreeze uint8 = 1 << iota
+	// TokenControlWhitelist allows transfers between whitelisted holders
+	// and the issuer only.
+	TokenControlWhitelist
+	// TokenControlPause lets the issuer pause all transfers of the token.
+	TokenControlPause
+)
+
+const TokenControlAll = TokenControlFreeze | TokenControlWhitelist | TokenControlPause
+
+// TokenControl is raised by the issuer to freeze, unfreeze, whitelist or
+// unlist a holder of the token, or to pause or unpause its transfers.
+// Holder is not used to pause or unpause.
+//msgp:tuple TokenControl
+type TokenControl struct {
+	TokenId int32          `json:"token_id"`
+	Holder  common.Address `json:"holder"`
+}
+
+func (c TokenControl) String() string {
+	return fmt.Sprintf("tokenid %d, holder %s", c.TokenId, c.Holder.TerminalString())
+}
+
+// TokenControlOfAction returns the control the token needs to accept the
+// action, 0 if the action is not a token control.
+func TokenControlOfAction(action uint8) uint8 {
+	switch action {
+	case ActionTxActionFreeze, ActionTxActionUnfreeze:
+		return TokenControlFreeze
+	case ActionTxActionWhitelistAdd, ActionTxActionWhitelistRemove:
+		return TokenControlWhitelist
+	case ActionTxActionPause, ActionTxActionUnpause:
+		return TokenControlPause
+	default:
+		return 0
+	}
+}
+
+// Validate checks the control action without the state.
+func (c *TokenControl) Validate(action uint8) error {
+	control := TokenControlOfAction(action)
+	if control == 0 {
+		return fmt.Errorf("unknown token control action %d", action)
+	}
+	if c.TokenId <= 0 {
+		return fmt.Errorf("og token has no controls")
+	}
+	if control != TokenControlPause && c.Holder == (common.Address{}) {
+		return fmt.Errorf("holder should not be empty")
+	}
+	return nil
+}
+
+func (t *ActionTx) GetTokenControl() *TokenControl {
+	if t.IsTokenControl() {
+		v, ok := t.ActionData.(*TokenControl)
+		if ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// IsTokenControl returns true if the action tx freezes, whitelists or
+// pauses a token.
+func (t *ActionTx) IsTokenControl() bool {
+	return TokenControlOfAction(t.Action) != 0
+}
@@ -0,0 +1,67 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/math"
+	"testing"
+)
+
+func TestTokenControlValidate(t *testing.T) {
+	c := TokenControl{TokenId: 1}
+	if err := c.Validate(ActionTxActionPause); err != nil {
+		t.Fatalf("pause should be valid: %v", err)
+	}
+	if c.Validate(ActionTxActionFreeze) == nil {
+		t.Fatal("freeze without holder")
+	}
+	c.Holder = common.HexToAddress("0x889e0b36dc6f2c06eb68d9c5f53434e4c42c8d19")
+	for _, action := range []uint8{ActionTxActionFreeze, ActionTxActionUnfreeze, ActionTxActionWhitelistAdd,
+		ActionTxActionWhitelistRemove, ActionTxActionPause, ActionTxActionUnpause} {
+		if err := c.Validate(action); err != nil {
+			t.Fatalf("action %d should be valid: %v", action, err)
+		}
+	}
+	if c.Validate(ActionTxActionTransferFrom) == nil {
+		t.Fatal("not a token control action")
+	}
+	c.TokenId = 0
+	if c.Validate(ActionTxActionFreeze) == nil {
+		t.Fatal("og token has no controls")
+	}
+}
+
+func TestPublicOfferingControls(t *testing.T) {
+	p := PublicOffering{TokenSymbol: "BTC", Controls: TokenControlFreeze | TokenControlPause, Value: math.NewBigInt(1)}
+	if err := p.ValidateMetadata(); err != nil {
+		t.Fatal(err)
+	}
+	bts, err := p.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p2 PublicOffering
+	if _, err = p2.UnmarshalMsg(bts); err != nil {
+		t.Fatal(err)
+	}
+	if p2.Controls != p.Controls {
+		t.Fatalf("controls lost: %v", p2)
+	}
+
+	p.Controls = 1 << 7
+	if p.ValidateMetadata() == nil {
+		t.Fatal("unknown controls")
+	}
+}
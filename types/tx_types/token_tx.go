@@ -32,6 +32,19 @@ const (
 	ActionTxActionDestroy
 	ActionTxActionSPO
 	ActionRequestDomainName
+	ActionTxActionGovernanceProposal
+	ActionTxActionGovernanceVote
+	ActionRenewDomainName
+	ActionTransferDomainName
+	ActionTxActionApprove
+	ActionTxActionRevoke
+	ActionTxActionTransferFrom
+	ActionTxActionFreeze
+	ActionTxActionUnfreeze
+	ActionTxActionWhitelistAdd
+	ActionTxActionWhitelistRemove
+	ActionTxActionPause
+	ActionTxActionUnpause
 )
 
 type ActionData interface {
@@ -39,6 +52,29 @@ type ActionData interface {
 	String() string
 }
 
+// NewActionData returns an empty action data which matches the action type.
+func NewActionData(action uint8) (ActionData, error) {
+	switch action {
+	case ActionTxActionIPO, ActionTxActionSPO, ActionTxActionDestroy:
+		return NewPublicOffering(), nil
+	case ActionRequestDomainName, ActionRenewDomainName, ActionTransferDomainName:
+		return &RequestDomain{}, nil
+	case ActionTxActionGovernanceProposal:
+		return &GovernanceProposal{}, nil
+	case ActionTxActionGovernanceVote:
+		return &GovernanceVote{}, nil
+	case ActionTxActionApprove, ActionTxActionRevoke:
+		return NewApproval(), nil
+	case ActionTxActionTransferFrom:
+		return NewTransferFrom(), nil
+	case ActionTxActionFreeze, ActionTxActionUnfreeze, ActionTxActionWhitelistAdd, ActionTxActionWhitelistRemove,
+		ActionTxActionPause, ActionTxActionUnpause:
+		return &TokenControl{}, nil
+	default:
+		return nil, fmt.Errorf("unkown action %d", action)
+	}
+}
+
 //msgp:tuple PublicOffering
 type PublicOffering struct {
 	TokenId int32        `json:"token_id"` //for Secondary Public Issues
@@ -46,6 +82,12 @@ type PublicOffering struct {
 	//To      Address       //when publish a token ,to equals from
 	EnableSPO bool   `json:"enable_spo"` //if enableSPO is false  , no Secondary Public Issues.
 	TokenName string `json:"token_name"`
+	// TokenSymbol, Decimals, MetadataURI and Controls are set by the IPO only.
+	TokenSymbol string `json:"token_symbol"`
+	Decimals    uint8  `json:"decimals"`
+	MetadataURI string `json:"metadata_uri"`
+	// Controls is the set of TokenControl flags enabled on the token.
+	Controls uint8 `json:"controls"`
 }
 
 func NewPublicOffering() *PublicOffering {
@@ -54,9 +96,65 @@ func NewPublicOffering() *PublicOffering {
 	}
 }
 
+// limits of the token metadata set by the IPO.
+const (
+	MaxTokenSymbolLength      = 5
+	MaxTokenDecimals          = 18
+	MaxTokenMetadataURILength = 256
+)
+
+// ValidateTokenSymbol checks that symbol is made of upper case letters and
+// digits and starts with a letter.
+func ValidateTokenSymbol(symbol string) error {
+	if len(symbol) == 0 || len(symbol) > MaxTokenSymbolLength {
+		return fmt.Errorf("token symbol should have 1 to %d characters", MaxTokenSymbolLength)
+	}
+	if symbol[0] < 'A' || symbol[0] > 'Z' {
+		return fmt.Errorf("token symbol should start with an upper case letter")
+	}
+	for _, c := range symbol {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return fmt.Errorf("token symbol should only contain upper case letters and digits")
+		}
+	}
+	return nil
+}
+
+// HasMetadata tells if the IPO sets the symbol, decimals, metadata uri or
+// controls of the token. The offerings without metadata are the legacy
+// ones, encoded and signed as before the metadata existed.
+func (p *PublicOffering) HasMetadata() bool {
+	return p.TokenSymbol != "" || p.Decimals != 0 || p.MetadataURI != "" || p.Controls != 0
+}
+
+// ValidateMetadata checks the symbol, decimals, metadata uri and controls of
+// the IPO. The symbol is mandatory once the IPO has any metadata.
+func (p *PublicOffering) ValidateMetadata() error {
+	if !p.HasMetadata() {
+		return nil
+	}
+	if err := ValidateTokenSymbol(p.TokenSymbol); err != nil {
+		return err
+	}
+	if p.Decimals > MaxTokenDecimals {
+		return fmt.Errorf("token decimals should not exceed %d", MaxTokenDecimals)
+	}
+	if len(p.MetadataURI) > MaxTokenMetadataURILength {
+		return fmt.Errorf("metadata uri exceeds %d bytes", MaxTokenMetadataURILength)
+	}
+	if p.Controls&^TokenControlAll != 0 {
+		return fmt.Errorf("unknown token controls %d", p.Controls)
+	}
+	return nil
+}
+
+// RequestDomain registers, renews or transfers a domain name. Period is
+// only used to register or renew, To only to transfer.
 //msgp:tuple RequestDomain
 type RequestDomain struct {
-	DomainName string
+	DomainName string         `json:"domain_name"`
+	Period     uint64         `json:"period"`
+	To         common.Address `json:"to"`
 }
 
 //msgp:tuple ActionTx
@@ -69,11 +167,11 @@ type ActionTx struct {
 }
 
 func (p PublicOffering) String() string {
-	return fmt.Sprintf("tokenid %d,value %v, EnableSPO %v", p.TokenId, p.Value, p.EnableSPO)
+	return fmt.Sprintf("tokenid %d,value %v, EnableSPO %v, symbol %s, decimals %d, controls %d", p.TokenId, p.Value, p.EnableSPO, p.TokenSymbol, p.Decimals, p.Controls)
 }
 
 func (r RequestDomain) String() string {
-	return r.DomainName
+	return fmt.Sprintf("%s, period %d, to %s", r.DomainName, r.Period, r.To.Hex())
 }
 
 func (t *ActionTx) GetConfirm() time.Duration {
@@ -133,7 +231,7 @@ func (t *ActionTx) GetPublicOffering() *PublicOffering {
 }
 
 func (t *ActionTx) GetDomainName() *RequestDomain {
-	if t.Action == ActionRequestDomainName {
+	if t.IsDomain() {
 		v, ok := t.ActionData.(*RequestDomain)
 		if ok {
 			return v
@@ -148,6 +246,19 @@ func (t *ActionTx) CheckActionIsValid() bool {
 	case ActionTxActionSPO:
 	case ActionTxActionDestroy:
 	case ActionRequestDomainName:
+	case ActionRenewDomainName:
+	case ActionTransferDomainName:
+	case ActionTxActionGovernanceProposal:
+	case ActionTxActionGovernanceVote:
+	case ActionTxActionApprove:
+	case ActionTxActionRevoke:
+	case ActionTxActionTransferFrom:
+	case ActionTxActionFreeze:
+	case ActionTxActionUnfreeze:
+	case ActionTxActionWhitelistAdd:
+	case ActionTxActionWhitelistRemove:
+	case ActionTxActionPause:
+	case ActionTxActionUnpause:
 	default:
 		return false
 	}
@@ -169,13 +280,34 @@ func (t *ActionTx) SignatureTargets() []byte {
 		w.Write(of.Value.GetSigBytes(), of.EnableSPO)
 		if t.Action == ActionTxActionIPO {
 			w.Write([]byte(of.TokenName))
+			if of.HasMetadata() {
+				w.Write([]byte(of.TokenSymbol), of.Decimals, []byte(of.MetadataURI), of.Controls)
+			}
 		} else {
 			w.Write(of.TokenId)
 		}
 
-	} else if t.Action == ActionRequestDomainName {
+	} else if t.IsDomain() {
 		r := t.GetDomainName()
-		w.Write(r.DomainName)
+		w.Write([]byte(r.DomainName), r.Period, r.To.Bytes)
+	} else if t.Action == ActionTxActionGovernanceProposal {
+		p := t.GetGovernanceProposal()
+		w.Write(p.TermId, p.Parameter, p.Value, p.HashValue.Bytes)
+	} else if t.Action == ActionTxActionGovernanceVote {
+		v := t.GetGovernanceVote()
+		w.Write(v.ProposalHash.Bytes, v.Approve, v.BlsSig)
+	} else if t.Action == ActionTxActionApprove || t.Action == ActionTxActionRevoke {
+		a := t.GetApproval()
+		w.Write(a.Spender.Bytes, a.TokenId)
+		if t.Action == ActionTxActionApprove {
+			w.Write(a.Value.GetSigBytes())
+		}
+	} else if t.Action == ActionTxActionTransferFrom {
+		f := t.GetTransferFrom()
+		w.Write(f.Owner.Bytes, f.To.Bytes, f.TokenId, f.Value.GetSigBytes())
+	} else if t.IsTokenControl() {
+		c := t.GetTokenControl()
+		w.Write(c.TokenId, c.Holder.Bytes)
 	}
 	return w.Bytes()
 }
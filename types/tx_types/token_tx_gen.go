@@ -3,9 +3,7 @@ package tx_types
 // Code generated by github.com/tinylib/msgp DO NOT EDIT.
 
 import (
-	"fmt"
 	"github.com/annchain/OG/common"
-	"github.com/annchain/OG/common/math"
 	"github.com/tinylib/msgp/msgp"
 )
 
@@ -43,6 +41,11 @@ func (z *ActionTx) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 	}
+	//this is edited by manuly
+	z.ActionData, err = NewActionData(z.Action)
+	if err != nil {
+		return
+	}
 	err = z.ActionData.DecodeMsg(dc)
 	if err != nil {
 		return
@@ -77,13 +80,11 @@ func (z *ActionTx) EncodeMsg(en *msgp.Writer) (err error) {
 		}
 	}
 	//this is edited by manuly
-	if z.Action == ActionTxActionDestroy || z.Action == ActionTxActionIPO || z.Action == ActionTxActionSPO {
-		z.ActionData = NewPublicOffering()
-	} else if z.Action == ActionRequestDomainName {
-		z.ActionData = NewPublicOffering()
-	} else {
-		err = fmt.Errorf("unkown action %d", z.Action)
-		return
+	if z.ActionData == nil {
+		z.ActionData, err = NewActionData(z.Action)
+		if err != nil {
+			return
+		}
 	}
 	err = z.ActionData.EncodeMsg(en)
 	if err != nil {
@@ -153,12 +154,8 @@ func (z *ActionTx) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	}
 
 	//this is edited by manuly
-	if z.Action == ActionTxActionDestroy || z.Action == ActionTxActionIPO || z.Action == ActionTxActionSPO {
-		z.ActionData = NewPublicOffering()
-	} else if z.Action == ActionRequestDomainName {
-		z.ActionData = NewPublicOffering()
-	} else {
-		err = fmt.Errorf("unkown action %d", z.Action)
+	z.ActionData, err = NewActionData(z.Action)
+	if err != nil {
 		return
 	}
 	bts, err = z.ActionData.UnmarshalMsg(bts)
@@ -299,40 +296,25 @@ func (z ActionTxs) Msgsize() (s int) {
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *PublicOffering) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *RequestDomain) DecodeMsg(dc *msgp.Reader) (err error) {
 	var zb0001 uint32
 	zb0001, err = dc.ReadArrayHeader()
 	if err != nil {
 		return
 	}
-	if zb0001 != 4 {
-		err = msgp.ArrayError{Wanted: 4, Got: zb0001}
+	if zb0001 != 3 {
+		err = msgp.ArrayError{Wanted: 3, Got: zb0001}
 		return
 	}
-	z.TokenId, err = dc.ReadInt32()
+	z.DomainName, err = dc.ReadString()
 	if err != nil {
 		return
 	}
-	if dc.IsNil() {
-		err = dc.ReadNil()
-		if err != nil {
-			return
-		}
-		z.Value = nil
-	} else {
-		if z.Value == nil {
-			z.Value = new(math.BigInt)
-		}
-		err = z.Value.DecodeMsg(dc)
-		if err != nil {
-			return
-		}
-	}
-	z.EnableSPO, err = dc.ReadBool()
+	z.Period, err = dc.ReadUint64()
 	if err != nil {
 		return
 	}
-	z.TokenName, err = dc.ReadString()
+	err = z.To.DecodeMsg(dc)
 	if err != nil {
 		return
 	}
@@ -340,32 +322,21 @@ func (z *PublicOffering) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *PublicOffering) EncodeMsg(en *msgp.Writer) (err error) {
-	// array header, size 4
-	err = en.Append(0x94)
+func (z *RequestDomain) EncodeMsg(en *msgp.Writer) (err error) {
+	// array header, size 3
+	err = en.Append(0x93)
 	if err != nil {
 		return
 	}
-	err = en.WriteInt32(z.TokenId)
+	err = en.WriteString(z.DomainName)
 	if err != nil {
 		return
 	}
-	if z.Value == nil {
-		err = en.WriteNil()
-		if err != nil {
-			return
-		}
-	} else {
-		err = z.Value.EncodeMsg(en)
-		if err != nil {
-			return
-		}
-	}
-	err = en.WriteBool(z.EnableSPO)
+	err = en.WriteUint64(z.Period)
 	if err != nil {
 		return
 	}
-	err = en.WriteString(z.TokenName)
+	err = z.To.EncodeMsg(en)
 	if err != nil {
 		return
 	}
@@ -373,131 +344,39 @@ func (z *PublicOffering) EncodeMsg(en *msgp.Writer) (err error) {
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *PublicOffering) MarshalMsg(b []byte) (o []byte, err error) {
+func (z *RequestDomain) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// array header, size 4
-	o = append(o, 0x94)
-	o = msgp.AppendInt32(o, z.TokenId)
-	if z.Value == nil {
-		o = msgp.AppendNil(o)
-	} else {
-		o, err = z.Value.MarshalMsg(o)
-		if err != nil {
-			return
-		}
-	}
-	o = msgp.AppendBool(o, z.EnableSPO)
-	o = msgp.AppendString(o, z.TokenName)
-	return
-}
-
-// UnmarshalMsg implements msgp.Unmarshaler
-func (z *PublicOffering) UnmarshalMsg(bts []byte) (o []byte, err error) {
-	var zb0001 uint32
-	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
-	if err != nil {
-		return
-	}
-	if zb0001 != 4 {
-		err = msgp.ArrayError{Wanted: 4, Got: zb0001}
-		return
-	}
-	z.TokenId, bts, err = msgp.ReadInt32Bytes(bts)
-	if err != nil {
-		return
-	}
-	if msgp.IsNil(bts) {
-		bts, err = msgp.ReadNilBytes(bts)
-		if err != nil {
-			return
-		}
-		z.Value = nil
-	} else {
-		if z.Value == nil {
-			z.Value = new(math.BigInt)
-		}
-		bts, err = z.Value.UnmarshalMsg(bts)
-		if err != nil {
-			return
-		}
-	}
-	z.EnableSPO, bts, err = msgp.ReadBoolBytes(bts)
-	if err != nil {
-		return
-	}
-	z.TokenName, bts, err = msgp.ReadStringBytes(bts)
+	// array header, size 3
+	o = append(o, 0x93)
+	o = msgp.AppendString(o, z.DomainName)
+	o = msgp.AppendUint64(o, z.Period)
+	o, err = z.To.MarshalMsg(o)
 	if err != nil {
 		return
 	}
-	o = bts
 	return
 }
 
-// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *PublicOffering) Msgsize() (s int) {
-	s = 1 + msgp.Int32Size
-	if z.Value == nil {
-		s += msgp.NilSize
-	} else {
-		s += z.Value.Msgsize()
-	}
-	s += msgp.BoolSize + msgp.StringPrefixSize + len(z.TokenName)
-	return
-}
-
-// DecodeMsg implements msgp.Decodable
-func (z *RequestDomain) DecodeMsg(dc *msgp.Reader) (err error) {
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *RequestDomain) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var zb0001 uint32
-	zb0001, err = dc.ReadArrayHeader()
+	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
 	if err != nil {
 		return
 	}
-	if zb0001 != 1 {
-		err = msgp.ArrayError{Wanted: 1, Got: zb0001}
-		return
-	}
-	z.DomainName, err = dc.ReadString()
-	if err != nil {
+	if zb0001 != 3 {
+		err = msgp.ArrayError{Wanted: 3, Got: zb0001}
 		return
 	}
-	return
-}
-
-// EncodeMsg implements msgp.Encodable
-func (z RequestDomain) EncodeMsg(en *msgp.Writer) (err error) {
-	// array header, size 1
-	err = en.Append(0x91)
-	if err != nil {
-		return
-	}
-	err = en.WriteString(z.DomainName)
+	z.DomainName, bts, err = msgp.ReadStringBytes(bts)
 	if err != nil {
 		return
 	}
-	return
-}
-
-// MarshalMsg implements msgp.Marshaler
-func (z RequestDomain) MarshalMsg(b []byte) (o []byte, err error) {
-	o = msgp.Require(b, z.Msgsize())
-	// array header, size 1
-	o = append(o, 0x91)
-	o = msgp.AppendString(o, z.DomainName)
-	return
-}
-
-// UnmarshalMsg implements msgp.Unmarshaler
-func (z *RequestDomain) UnmarshalMsg(bts []byte) (o []byte, err error) {
-	var zb0001 uint32
-	zb0001, bts, err = msgp.ReadArrayHeaderBytes(bts)
+	z.Period, bts, err = msgp.ReadUint64Bytes(bts)
 	if err != nil {
 		return
 	}
-	if zb0001 != 1 {
-		err = msgp.ArrayError{Wanted: 1, Got: zb0001}
-		return
-	}
-	z.DomainName, bts, err = msgp.ReadStringBytes(bts)
+	bts, err = z.To.UnmarshalMsg(bts)
 	if err != nil {
 		return
 	}
@@ -506,7 +385,7 @@ func (z *RequestDomain) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z RequestDomain) Msgsize() (s int) {
-	s = 1 + msgp.StringPrefixSize + len(z.DomainName)
+func (z *RequestDomain) Msgsize() (s int) {
+	s = 1 + msgp.StringPrefixSize + len(z.DomainName) + msgp.Uint64Size + z.To.Msgsize()
 	return
 }
@@ -0,0 +1,101 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tx_types
+
+import (
+	"bytes"
+	"github.com/annchain/OG/common/math"
+	"github.com/tinylib/msgp/msgp"
+	"testing"
+)
+
+func TestPublicOfferingMetadata(t *testing.T) {
+	p := PublicOffering{
+		TokenName:   "bitcoin",
+		TokenSymbol: "BTC",
+		Decimals:    8,
+		MetadataURI: "https://bitcoin.org",
+		Value:       math.NewBigInt(100),
+	}
+	if err := p.ValidateMetadata(); err != nil {
+		t.Fatal(err)
+	}
+	bts, err := p.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p2 PublicOffering
+	if _, err = p2.UnmarshalMsg(bts); err != nil {
+		t.Fatal(err)
+	}
+	if p2.TokenSymbol != p.TokenSymbol || p2.Decimals != p.Decimals || p2.MetadataURI != p.MetadataURI {
+		t.Fatalf("metadata lost: %v", p2)
+	}
+
+	// the offerings written before the metadata existed have 4 fields.
+	legacy := msgp.AppendArrayHeader(nil, 4)
+	legacy = msgp.AppendInt32(legacy, 1)
+	legacy, _ = math.NewBigInt(100).MarshalMsg(legacy)
+	legacy = msgp.AppendBool(legacy, true)
+	legacy = msgp.AppendString(legacy, "bitcoin")
+	if _, err = p2.UnmarshalMsg(legacy); err != nil {
+		t.Fatal(err)
+	}
+	if p2.TokenName != "bitcoin" || p2.TokenSymbol != "" || p2.Decimals != 0 || p2.MetadataURI != "" {
+		t.Fatalf("legacy offering decoded wrong: %v", p2)
+	}
+	if err = p2.DecodeMsg(msgp.NewReader(bytes.NewReader(legacy))); err != nil {
+		t.Fatal(err)
+	}
+	// and the ones without metadata are still written so.
+	if bts, err = p2.MarshalMsg(nil); err != nil || !bytes.Equal(bts, legacy) {
+		t.Fatalf("legacy offering encoded as %x, %v", bts, err)
+	}
+
+	p2 = PublicOffering{}
+	var buf bytes.Buffer
+	w := msgp.NewWriter(&buf)
+	if err = p.EncodeMsg(w); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+	if err = p2.DecodeMsg(msgp.NewReader(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	if p2.TokenSymbol != p.TokenSymbol || p2.MetadataURI != p.MetadataURI {
+		t.Fatalf("metadata lost: %v", p2)
+	}
+}
+
+func TestValidateTokenMetadata(t *testing.T) {
+	for _, symbol := range []string{"BTC", "A", "OG2", "ABCDE"} {
+		if err := ValidateTokenSymbol(symbol); err != nil {
+			t.Errorf("%s should be valid: %v", symbol, err)
+		}
+	}
+	for _, symbol := range []string{"", "btc", "2OG", "B-T", "ABCDEF"} {
+		if ValidateTokenSymbol(symbol) == nil {
+			t.Errorf("%s should be invalid", symbol)
+		}
+	}
+	p := PublicOffering{TokenSymbol: "BTC", Decimals: MaxTokenDecimals + 1}
+	if p.ValidateMetadata() == nil {
+		t.Fatal("too many decimals")
+	}
+	p.Decimals = MaxTokenDecimals
+	p.MetadataURI = string(make([]byte, MaxTokenMetadataURILength+1))
+	if p.ValidateMetadata() == nil {
+		t.Fatal("metadata uri too long")
+	}
+}
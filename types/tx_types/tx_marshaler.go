@@ -61,13 +61,11 @@ func (t *RawTxMarshaler) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	case types.TxBaseAction:
 		rawTx := &RawActionTx{TxBase: types.TxBase{Type: types.TxBaseAction}}
 		action := bts[3]
-		if action == ActionRequestDomainName {
-			rawTx.ActionData = &RequestDomain{}
-		} else if action == ActionTxActionIPO || action == ActionTxActionSPO || action == ActionTxActionDestroy {
-			rawTx.ActionData = &PublicOffering{}
-		} else {
-			return bts, fmt.Errorf("unkown action %d", action)
+		actionData, err := NewActionData(action)
+		if err != nil {
+			return bts, err
 		}
+		rawTx.ActionData = actionData
 		t.RawTxi = rawTx
 		return t.RawTxi.UnmarshalMsg(bts[3:])
 	default:
@@ -115,13 +113,11 @@ func (t *RawTxMarshaler) DecodeMsg(dc *msgp.Reader) (err error) {
 			return fmt.Errorf("size mismatch")
 		}
 		action := head[0]
-		if action == ActionRequestDomainName {
-			rawTx.ActionData = &RequestDomain{}
-		} else if action == ActionTxActionIPO || action == ActionTxActionSPO || action == ActionTxActionDestroy {
-			rawTx.ActionData = &PublicOffering{}
-		} else {
-			return fmt.Errorf("unkown action %d", action)
+		actionData, err := NewActionData(action)
+		if err != nil {
+			return err
 		}
+		rawTx.ActionData = actionData
 		t.RawTxi = rawTx
 		return t.RawTxi.DecodeMsg(dc)
 	default:
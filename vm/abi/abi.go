@@ -0,0 +1,217 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package abi encodes contract calls and decodes their results and events
+// as described by a solidity json abi.
+//
+// The values are taken from and given back as json: integers as decimal
+// strings (numbers and 0x prefixed strings are accepted as input),
+// addresses, bytes and fixed bytes as 0x prefixed hex strings, arrays as
+// json arrays.
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+)
+
+// Argument is an input or output of a method or an event.
+type Argument struct {
+	Name    string
+	Type    Type
+	Indexed bool
+}
+
+// Method is a function of a contract.
+type Method struct {
+	Name string
+	// RawName is the name in the abi, Name differs from it for overloaded
+	// functions.
+	RawName  string
+	Inputs   []Argument
+	Outputs  []Argument
+	Constant bool
+	// ID is the 4 bytes selector of the method.
+	ID []byte
+}
+
+// Event is an event a contract may log.
+type Event struct {
+	Name      string
+	RawName   string
+	Inputs    []Argument
+	Anonymous bool
+	// ID is the hash of the signature, the first topic of the logs.
+	ID common.Hash
+}
+
+// ABI holds the methods and events of a contract.
+type ABI struct {
+	Methods map[string]Method
+	Events  map[string]Event
+}
+
+type jsonArgument struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Indexed    bool           `json:"indexed"`
+	Components []jsonArgument `json:"components"`
+}
+
+type jsonEntry struct {
+	Type            string         `json:"type"`
+	Name            string         `json:"name"`
+	Inputs          []jsonArgument `json:"inputs"`
+	Outputs         []jsonArgument `json:"outputs"`
+	Anonymous       bool           `json:"anonymous"`
+	Constant        bool           `json:"constant"`
+	StateMutability string         `json:"stateMutability"`
+}
+
+// JSON parses a json abi.
+func JSON(data []byte) (*ABI, error) {
+	var entries []jsonEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid abi: %v", err)
+	}
+	abi := &ABI{
+		Methods: make(map[string]Method),
+		Events:  make(map[string]Event),
+	}
+	for _, entry := range entries {
+		switch entry.Type {
+		case "function", "":
+			inputs, err := newArguments(entry.Inputs)
+			if err != nil {
+				return nil, err
+			}
+			outputs, err := newArguments(entry.Outputs)
+			if err != nil {
+				return nil, err
+			}
+			method := Method{
+				Name:     uniqueName(entry.Name, func(name string) bool { _, ok := abi.Methods[name]; return ok }),
+				RawName:  entry.Name,
+				Inputs:   inputs,
+				Outputs:  outputs,
+				Constant: entry.Constant || entry.StateMutability == "view" || entry.StateMutability == "pure",
+			}
+			method.ID = crypto.Keccak256([]byte(method.Sig()))[:4]
+			abi.Methods[method.Name] = method
+		case "event":
+			inputs, err := newArguments(entry.Inputs)
+			if err != nil {
+				return nil, err
+			}
+			event := Event{
+				Name:      uniqueName(entry.Name, func(name string) bool { _, ok := abi.Events[name]; return ok }),
+				RawName:   entry.Name,
+				Inputs:    inputs,
+				Anonymous: entry.Anonymous,
+			}
+			event.ID = common.BytesToHash(crypto.Keccak256([]byte(event.Sig())))
+			abi.Events[event.Name] = event
+		}
+	}
+	return abi, nil
+}
+
+func newArguments(args []jsonArgument) ([]Argument, error) {
+	arguments := make([]Argument, len(args))
+	for i, arg := range args {
+		if len(arg.Components) != 0 {
+			return nil, fmt.Errorf("%v: tuple argument %q", ErrUnsupportedType, arg.Name)
+		}
+		t, err := NewType(arg.Type)
+		if err != nil {
+			return nil, err
+		}
+		arguments[i] = Argument{Name: arg.Name, Type: t, Indexed: arg.Indexed}
+	}
+	return arguments, nil
+}
+
+// uniqueName returns name, suffixed by a number if it is taken, so that
+// overloaded functions are called name, name0, name1...
+func uniqueName(name string, taken func(string) bool) string {
+	unique := name
+	for i := 0; taken(unique); i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	return unique
+}
+
+func signature(name string, args []Argument) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type.String()
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(types, ","))
+}
+
+// Sig returns the signature of the method, e.g. transfer(address,uint256).
+func (m Method) Sig() string {
+	return signature(m.RawName, m.Inputs)
+}
+
+// Sig returns the signature of the event, e.g. Transfer(address,address,uint256).
+func (e Event) Sig() string {
+	return signature(e.RawName, e.Inputs)
+}
+
+// Pack encodes a call of the method name with args, the json values of
+// its inputs.
+func (abi *ABI) Pack(name string, args []json.RawMessage) ([]byte, error) {
+	method, ok := abi.Methods[name]
+	if !ok {
+		return nil, fmt.Errorf("method %q not found", name)
+	}
+	if len(args) != len(method.Inputs) {
+		return nil, fmt.Errorf("method %s takes %d arguments, got %d", name, len(method.Inputs), len(args))
+	}
+	data, err := packArguments(method.Inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("pack %s: %v", name, err)
+	}
+	return append(append([]byte{}, method.ID...), data...), nil
+}
+
+// MethodByID returns the method called by the input data, nil if there
+// is none.
+func (abi *ABI) MethodByID(data []byte) *Method {
+	if len(data) < 4 {
+		return nil
+	}
+	for _, method := range abi.Methods {
+		if bytes.Equal(method.ID, data[:4]) {
+			return &method
+		}
+	}
+	return nil
+}
+
+// EventByID returns the event with the given id, nil if there is none.
+func (abi *ABI) EventByID(id common.Hash) *Event {
+	for _, event := range abi.Events {
+		if !event.Anonymous && event.ID == id {
+			return &event
+		}
+	}
+	return nil
+}
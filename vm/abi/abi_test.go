@@ -0,0 +1,150 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/annchain/OG/common"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+const testABI = `[
+	{"type":"function","name":"f","inputs":[{"name":"a","type":"uint"},{"name":"b","type":"uint32[]"},{"name":"c","type":"bytes10"},{"name":"d","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"get","constant":true,"inputs":[],"outputs":[{"name":"n","type":"int8"},{"name":"s","type":"string"},{"name":"l","type":"address[2]"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+func raws(args ...string) []json.RawMessage {
+	msgs := make([]json.RawMessage, len(args))
+	for i, arg := range args {
+		msgs[i] = json.RawMessage(arg)
+	}
+	return msgs
+}
+
+func TestPack(t *testing.T) {
+	abi, err := JSON([]byte(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// example of the solidity abi specification.
+	expected := "8be65246" +
+		"0000000000000000000000000000000000000000000000000000000000000123" +
+		"0000000000000000000000000000000000000000000000000000000000000080" +
+		"3132333435363738393000000000000000000000000000000000000000000000" +
+		"00000000000000000000000000000000000000000000000000000000000000e0" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000456" +
+		"0000000000000000000000000000000000000000000000000000000000000789" +
+		"000000000000000000000000000000000000000000000000000000000000000d" +
+		"48656c6c6f2c20776f726c642100000000000000000000000000000000000000"
+	data, err := abi.Pack("f", raws(`"0x123"`, `[1110, "1929"]`, `"0x31323334353637383930"`, `"0x48656c6c6f2c20776f726c6421"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(data) != expected {
+		t.Fatalf("unexpected encoding %x", data)
+	}
+
+	method := abi.MethodByID(data)
+	if method == nil || method.Name != "f" {
+		t.Fatalf("method not found by id")
+	}
+	values, err := method.UnpackInputs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(values); got != "[{a uint256 291} {b uint32[] [1110 1929]} {c bytes10 0x31323334353637383930} {d bytes 0x48656c6c6f2c20776f726c6421}]" {
+		t.Fatalf("unexpected inputs %s", got)
+	}
+
+	data, err = abi.Pack("transfer", raws(`"0x0000000000000000000000000000000000000001"`, `1`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hex.EncodeToString(data), "a9059cbb") {
+		t.Fatalf("unexpected selector %x", data[:4])
+	}
+	for _, args := range [][]json.RawMessage{
+		raws(`"0x01"`, `1`),
+		raws(`"0x0000000000000000000000000000000000000001"`, `-1`),
+		raws(`"0x0000000000000000000000000000000000000001"`),
+	} {
+		if _, err := abi.Pack("transfer", args); err == nil {
+			t.Fatalf("invalid arguments %s packed", args)
+		}
+	}
+}
+
+func TestUnpackOutputs(t *testing.T) {
+	abi, err := JSON([]byte(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := hex.DecodeString(
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe" +
+			"0000000000000000000000000000000000000000000000000000000000000080" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"6f67000000000000000000000000000000000000000000000000000000000000")
+	values, err := abi.Methods["get"].UnpackOutputs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[{n int8 -2} {s string og} {l address[2] [0x0000000000000000000000000000000000000001 0x0000000000000000000000000000000000000002]}]"
+	if got := fmt.Sprint(values); got != expected {
+		t.Fatalf("unexpected outputs %s", got)
+	}
+	if _, err := abi.Methods["get"].UnpackOutputs(data[:100]); err == nil {
+		t.Fatalf("short data unpacked")
+	}
+}
+
+func TestDecodeLog(t *testing.T) {
+	abi, err := JSON([]byte(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := abi.Events["Transfer"]
+	if event.ID.Hex() != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Fatalf("unexpected event id %s", event.ID.Hex())
+	}
+	l := &vmtypes.Log{
+		Topics: common.Hashes{
+			event.ID,
+			common.BytesToHash(common.LeftPadBytes([]byte{1}, 32)),
+			common.BytesToHash(common.LeftPadBytes([]byte{2}, 32)),
+		},
+		Data: common.LeftPadBytes([]byte{100}, 32),
+	}
+	decoded, err := abi.DecodeLog(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Transfer [{from address 0x0000000000000000000000000000000000000001} {to address 0x0000000000000000000000000000000000000002} {value uint256 100}]"
+	if got := fmt.Sprint(decoded.Name, " ", decoded.Args); got != expected {
+		t.Fatalf("unexpected event %s", got)
+	}
+	l.Topics = l.Topics[:2]
+	if _, err := abi.DecodeLog(l); err == nil {
+		t.Fatalf("log with missing topic decoded")
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/vm/eth/common/math"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+var errShortData = errors.New("abi: data too short")
+
+// Value is a decoded argument.
+type Value struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// DecodedEvent is a log decoded by the abi of its contract.
+type DecodedEvent struct {
+	Name string  `json:"name"`
+	Args []Value `json:"args"`
+}
+
+// UnpackOutputs decodes the return data of a call of the method.
+func (m Method) UnpackOutputs(data []byte) ([]Value, error) {
+	return unpackArguments(m.Outputs, data)
+}
+
+// UnpackInputs decodes the input data of a call of the method, the
+// selector included.
+func (m Method) UnpackInputs(data []byte) ([]Value, error) {
+	if len(data) < 4 || !bytes.Equal(data[:4], m.ID) {
+		return nil, fmt.Errorf("input is not a call of %s", m.Name)
+	}
+	return unpackArguments(m.Inputs, data[4:])
+}
+
+// DecodeLog decodes a log emitted by a contract of the abi. The indexed
+// arguments of dynamic types are given as the hash stored in the topic.
+func (abi *ABI) DecodeLog(l *vmtypes.Log) (*DecodedEvent, error) {
+	if len(l.Topics) == 0 {
+		return nil, fmt.Errorf("anonymous log")
+	}
+	event := abi.EventByID(l.Topics[0])
+	if event == nil {
+		return nil, fmt.Errorf("event %s not found", l.Topics[0].Hex())
+	}
+
+	var indexed, plain []Argument
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		} else {
+			plain = append(plain, arg)
+		}
+	}
+	if len(l.Topics) != len(indexed)+1 {
+		return nil, fmt.Errorf("event %s has %d indexed arguments, got %d topics", event.Name, len(indexed), len(l.Topics)-1)
+	}
+	plainValues, err := unpackArguments(plain, l.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := &DecodedEvent{Name: event.Name}
+	topics := l.Topics[1:]
+	for _, arg := range event.Inputs {
+		if !arg.Indexed {
+			decoded.Args = append(decoded.Args, plainValues[0])
+			plainValues = plainValues[1:]
+			continue
+		}
+		topic := topics[0].ToBytes()
+		topics = topics[1:]
+		var value interface{} = hexutil.Bytes(topic)
+		if !arg.Type.dynamic() {
+			if value, err = unpackValue(arg.Type, topic); err != nil {
+				return nil, err
+			}
+		}
+		decoded.Args = append(decoded.Args, Value{Name: arg.Name, Type: arg.Type.String(), Value: value})
+	}
+	return decoded, nil
+}
+
+func packArguments(args []Argument, values []json.RawMessage) ([]byte, error) {
+	types := make([]Type, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type
+	}
+	return packTuple(types, values)
+}
+
+func unpackArguments(args []Argument, data []byte) ([]Value, error) {
+	types := make([]Type, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type
+	}
+	values, err := unpackTuple(types, data)
+	if err != nil {
+		return nil, err
+	}
+	decoded := make([]Value, len(args))
+	for i, arg := range args {
+		decoded[i] = Value{Name: arg.Name, Type: arg.Type.String(), Value: values[i]}
+	}
+	return decoded, nil
+}
+
+func word(n int) []byte {
+	return math.PaddedBigBytes(big.NewInt(int64(n)), 32)
+}
+
+func rightPad(b []byte) []byte {
+	if r := len(b) % 32; r != 0 {
+		b = append(b, make([]byte, 32-r)...)
+	}
+	return b
+}
+
+// packTuple encodes values, the heads of the dynamic ones being the
+// offsets of their encodings appended after the heads.
+func packTuple(types []Type, values []json.RawMessage) ([]byte, error) {
+	headSize := 0
+	for _, t := range types {
+		headSize += t.headSize()
+	}
+	var head, tail []byte
+	for i, t := range types {
+		enc, err := packValue(t, values[i])
+		if err != nil {
+			return nil, err
+		}
+		if t.dynamic() {
+			head = append(head, word(headSize+len(tail))...)
+			tail = append(tail, enc...)
+		} else {
+			head = append(head, enc...)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+func packValue(t Type, raw json.RawMessage) ([]byte, error) {
+	switch t.Kind {
+	case UintTy, IntTy:
+		v, err := parseInt(raw)
+		if err != nil {
+			return nil, err
+		}
+		if !fitsInt(t, v) {
+			return nil, fmt.Errorf("%s out of range for %s", v, t)
+		}
+		return math.PaddedBigBytes(math.U256(v), 32), nil
+	case BoolTy:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return nil, fmt.Errorf("invalid bool %s", raw)
+		}
+		if b {
+			return word(1), nil
+		}
+		return word(0), nil
+	case AddressTy:
+		b, err := parseHex(raw)
+		if err != nil || len(b) != common.AddressLength {
+			return nil, fmt.Errorf("invalid address %s", raw)
+		}
+		return common.LeftPadBytes(b, 32), nil
+	case FixedBytesTy:
+		b, err := parseHex(raw)
+		if err != nil || len(b) != t.Size {
+			return nil, fmt.Errorf("invalid %s %s", t, raw)
+		}
+		return rightPad(b), nil
+	case BytesTy:
+		b, err := parseHex(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytes %s", raw)
+		}
+		return append(word(len(b)), rightPad(b)...), nil
+	case StringTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("invalid string %s", raw)
+		}
+		return append(word(len(s)), rightPad([]byte(s))...), nil
+	case SliceTy, ArrayTy:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return nil, fmt.Errorf("invalid %s %s", t, raw)
+		}
+		if t.Kind == ArrayTy && len(elems) != t.Size {
+			return nil, fmt.Errorf("%s takes %d elements, got %d", t, t.Size, len(elems))
+		}
+		enc, err := packTuple(repeat(*t.Elem, len(elems)), elems)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == SliceTy {
+			enc = append(word(len(elems)), enc...)
+		}
+		return enc, nil
+	}
+	return nil, ErrUnsupportedType
+}
+
+// unpackTuple decodes the values of types encoded at the start of data.
+func unpackTuple(types []Type, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(types))
+	pos := 0
+	for i, t := range types {
+		if pos+32 > len(data) {
+			return nil, errShortData
+		}
+		enc := data[pos:]
+		if t.dynamic() {
+			offset := new(big.Int).SetBytes(data[pos : pos+32])
+			if !offset.IsUint64() || offset.Uint64() > uint64(len(data)) {
+				return nil, fmt.Errorf("abi: invalid offset %s", offset)
+			}
+			enc = data[offset.Uint64():]
+		}
+		value, err := unpackValue(t, enc)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		pos += t.headSize()
+	}
+	return values, nil
+}
+
+// unpackValue decodes the value of t encoded at the start of data.
+func unpackValue(t Type, data []byte) (interface{}, error) {
+	if len(data) < 32 {
+		return nil, errShortData
+	}
+	w := data[:32]
+	switch t.Kind {
+	case UintTy, IntTy:
+		v := new(big.Int).SetBytes(w)
+		if t.Kind == IntTy {
+			v = math.S256(v)
+		}
+		if !fitsInt(t, v) {
+			return nil, fmt.Errorf("abi: %s out of range for %s", v, t)
+		}
+		return v.String(), nil
+	case BoolTy:
+		v := new(big.Int).SetBytes(w)
+		if v.Cmp(big.NewInt(1)) > 0 {
+			return nil, fmt.Errorf("abi: invalid bool %x", w)
+		}
+		return v.Sign() == 1, nil
+	case AddressTy:
+		return common.BytesToAddress(w[12:]).Hex(), nil
+	case FixedBytesTy:
+		return hexutil.Bytes(common.CopyBytes(w[:t.Size])), nil
+	case BytesTy, StringTy:
+		length := new(big.Int).SetBytes(w)
+		if !length.IsUint64() || length.Uint64() > uint64(len(data)-32) {
+			return nil, errShortData
+		}
+		content := data[32 : 32+length.Uint64()]
+		if t.Kind == StringTy {
+			return string(content), nil
+		}
+		return hexutil.Bytes(common.CopyBytes(content)), nil
+	case SliceTy, ArrayTy:
+		n := t.Size
+		if t.Kind == SliceTy {
+			length := new(big.Int).SetBytes(w)
+			// every element takes a word at least.
+			if !length.IsUint64() || length.Uint64() > uint64(len(data)-32)/32 {
+				return nil, errShortData
+			}
+			n = int(length.Uint64())
+			data = data[32:]
+		}
+		elems, err := unpackTuple(repeat(*t.Elem, n), data)
+		if err != nil {
+			return nil, err
+		}
+		return elems, nil
+	}
+	return nil, ErrUnsupportedType
+}
+
+func repeat(t Type, n int) []Type {
+	types := make([]Type, n)
+	for i := range types {
+		types[i] = t
+	}
+	return types
+}
+
+func fitsInt(t Type, v *big.Int) bool {
+	if t.Kind == UintTy {
+		return v.Sign() >= 0 && v.BitLen() <= t.Size
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	return v.Cmp(limit) < 0 && v.Cmp(new(big.Int).Neg(limit)) >= 0
+}
+
+// parseInt reads a json number or a decimal or 0x prefixed hex string.
+func parseInt(raw json.RawMessage) (*big.Int, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, fmt.Errorf("invalid integer %s", raw)
+		}
+		s = n.String()
+	}
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s, base = s[2:], 16
+	}
+	v, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s", raw)
+	}
+	return v, nil
+}
+
+func parseHex(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	return hexutil.Decode(s)
+}
@@ -0,0 +1,131 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of an abi type.
+type Kind int
+
+const (
+	UintTy Kind = iota
+	IntTy
+	BoolTy
+	AddressTy
+	FixedBytesTy
+	BytesTy
+	StringTy
+	SliceTy
+	ArrayTy
+)
+
+var ErrUnsupportedType = errors.New("unsupported abi type")
+
+// Type is a solidity abi type. Tuples are not supported.
+type Type struct {
+	Kind Kind
+	// Size is the bit size of the integer types, the byte size of the
+	// fixed bytes types and the length of the arrays.
+	Size int
+	// Elem is the element type of the arrays and slices.
+	Elem *Type
+
+	str string
+}
+
+// NewType parses a type such as uint256, bytes32, address[] or bool[2][].
+func NewType(s string) (Type, error) {
+	if strings.HasSuffix(s, "]") {
+		i := strings.LastIndex(s, "[")
+		if i <= 0 {
+			return Type{}, fmt.Errorf("invalid abi type %q", s)
+		}
+		elem, err := NewType(s[:i])
+		if err != nil {
+			return Type{}, err
+		}
+		t := Type{Kind: SliceTy, Elem: &elem, str: s}
+		if n := s[i+1 : len(s)-1]; n != "" {
+			size, err := strconv.Atoi(n)
+			if err != nil || size <= 0 {
+				return Type{}, fmt.Errorf("invalid array length in abi type %q", s)
+			}
+			t.Kind, t.Size = ArrayTy, size
+		}
+		return t, nil
+	}
+
+	switch {
+	case s == "bool":
+		return Type{Kind: BoolTy, str: s}, nil
+	case s == "address":
+		return Type{Kind: AddressTy, str: s}, nil
+	case s == "string":
+		return Type{Kind: StringTy, str: s}, nil
+	case s == "bytes":
+		return Type{Kind: BytesTy, str: s}, nil
+	case strings.HasPrefix(s, "bytes"):
+		size, err := strconv.Atoi(s[len("bytes"):])
+		if err != nil || size < 1 || size > 32 {
+			return Type{}, fmt.Errorf("invalid abi type %q", s)
+		}
+		return Type{Kind: FixedBytesTy, Size: size, str: s}, nil
+	case strings.HasPrefix(s, "uint"):
+		return newIntType(UintTy, s, s[len("uint"):])
+	case strings.HasPrefix(s, "int"):
+		return newIntType(IntTy, s, s[len("int"):])
+	}
+	return Type{}, fmt.Errorf("%v: %s", ErrUnsupportedType, s)
+}
+
+func newIntType(kind Kind, s string, bits string) (Type, error) {
+	if bits == "" {
+		// uint and int are aliases of uint256 and int256.
+		return Type{Kind: kind, Size: 256, str: s + "256"}, nil
+	}
+	size, err := strconv.Atoi(bits)
+	if err != nil || size < 8 || size > 256 || size%8 != 0 {
+		return Type{}, fmt.Errorf("invalid abi type %q", s)
+	}
+	return Type{Kind: kind, Size: size, str: s}, nil
+}
+
+// String returns the canonical name of the type used in signatures.
+func (t Type) String() string {
+	return t.str
+}
+
+// dynamic tells if the value is encoded in the tail of its tuple.
+func (t Type) dynamic() bool {
+	switch t.Kind {
+	case BytesTy, StringTy, SliceTy:
+		return true
+	case ArrayTy:
+		return t.Elem.dynamic()
+	}
+	return false
+}
+
+// headSize is the size taken by the type in the head of its tuple.
+func (t Type) headSize() int {
+	if t.Kind == ArrayTy && !t.dynamic() {
+		return t.Size * t.Elem.headSize()
+	}
+	return 32
+}
@@ -1,5 +1,7 @@
 package vm
 
+import "github.com/annchain/OG/vm/eth/params"
+
 // InterpreterConfig are the configuration options for the Interpreter
 type InterpreterConfig struct {
 	// Debug enabled debugging Interpreter options
@@ -8,4 +10,6 @@ type InterpreterConfig struct {
 	Tracer Tracer
 	// Enable recording of SHA3/keccak preimages
 	EnablePreimageRecording bool
+	// ChainConfig decides the instruction set by height, params.DefaultChainConfig if nil
+	ChainConfig *params.ChainConfig
 }
@@ -17,6 +17,8 @@
 package vm
 
 import (
+	"errors"
+
 	common2 "github.com/annchain/OG/common"
 	"github.com/annchain/OG/vm/eth/common"
 	"github.com/annchain/OG/vm/eth/common/math"
@@ -117,6 +119,8 @@ func gasReturnDataCopy(gt params.GasTable, ctx *vmtypes.Context, contract *vmtyp
 	return gas, nil
 }
 
+// gasSStore is the legacy SSTORE gas metering used before constantinople
+// and again since petersburg.
 func gasSStore(gt params.GasTable, ctx *vmtypes.Context, contract *vmtypes.Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
 	var (
 		y, x    = stack.Back(1), stack.Back(0)
@@ -136,6 +140,14 @@ func gasSStore(gt params.GasTable, ctx *vmtypes.Context, contract *vmtypes.Contr
 	default: // non 0 => non 0 (or 0 => 0)
 		return params.SstoreResetGas, nil
 	}
+}
+
+// gasSStoreEIP1283 is the constantinople net gas metering.
+func gasSStoreEIP1283(gt params.GasTable, ctx *vmtypes.Context, contract *vmtypes.Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	var (
+		y, x    = stack.Back(1), stack.Back(0)
+		current = ctx.StateDB.GetState(contract.Address(), common2.BigToHash(x))
+	)
 	// The new gas metering is based on net gas costs (EIP-1283):
 	//
 	// 1. If current value equals new value (this is a no-op), 200 gas is deducted.
@@ -181,6 +193,63 @@ func gasSStore(gt params.GasTable, ctx *vmtypes.Context, contract *vmtypes.Contr
 	return params.NetSstoreDirtyGas, nil
 }
 
+// gasSStoreEIP2200 is the istanbul net gas metering.
+//
+// 0. If *gasleft* is less than or equal to 2300, fail the current call.
+// 1. If current value equals new value (this is a no-op), SLOAD_GAS is deducted.
+// 2. If current value does not equal new value:
+//   2.1. If original value equals current value (this storage slot has not been changed by the current execution context):
+//     2.1.1. If original value is 0, SSTORE_SET_GAS (20K) gas is deducted.
+//     2.1.2. Otherwise, SSTORE_RESET_GAS gas is deducted. If new value is 0, add SSTORE_CLEARS_SCHEDULE to refund counter.
+//   2.2. If original value does not equal current value (this storage slot is dirty), SLOAD_GAS gas is deducted. Apply both of the following clauses:
+//     2.2.1. If original value is not 0:
+//       2.2.1.1. If current value is 0 (also means that new value is not 0), subtract SSTORE_CLEARS_SCHEDULE gas from refund counter.
+//       2.2.1.2. If new value is 0 (also means that current value is not 0), add SSTORE_CLEARS_SCHEDULE gas to refund counter.
+//     2.2.2. If original value equals new value (this storage slot is reset):
+//       2.2.2.1. If original value is 0, add SSTORE_SET_GAS - SLOAD_GAS to refund counter.
+//       2.2.2.2. Otherwise, add SSTORE_RESET_GAS - SLOAD_GAS gas to refund counter.
+func gasSStoreEIP2200(gt params.GasTable, ctx *vmtypes.Context, contract *vmtypes.Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	// If we fail the minimum gas availability invariant, fail (0)
+	if contract.Gas <= params.SstoreSentryGasEIP2200 {
+		return 0, errors.New("not enough gas for reentrancy sentry")
+	}
+	// Gas sentry honoured, do the actual gas calculation based on the stored value
+	var (
+		y, x    = stack.Back(1), stack.Back(0)
+		current = ctx.StateDB.GetState(contract.Address(), common2.BigToHash(x))
+	)
+	value := common2.BigToHash(y)
+
+	if current == value { // noop (1)
+		return params.SstoreNoopGasEIP2200, nil
+	}
+	original := ctx.StateDB.GetCommittedState(contract.Address(), common2.BigToHash(x))
+	if original == current {
+		if original == (common2.Hash{}) { // create slot (2.1.1)
+			return params.SstoreInitGasEIP2200, nil
+		}
+		if value == (common2.Hash{}) { // delete slot (2.1.2b)
+			ctx.StateDB.AddRefund(params.SstoreClearRefundEIP2200)
+		}
+		return params.SstoreCleanGasEIP2200, nil // write existing slot (2.1.2)
+	}
+	if original != (common2.Hash{}) {
+		if current == (common2.Hash{}) { // recreate slot (2.2.1.1)
+			ctx.StateDB.SubRefund(params.SstoreClearRefundEIP2200)
+		} else if value == (common2.Hash{}) { // delete slot (2.2.1.2)
+			ctx.StateDB.AddRefund(params.SstoreClearRefundEIP2200)
+		}
+	}
+	if original == value {
+		if original == (common2.Hash{}) { // reset to original inexistent slot (2.2.2.1)
+			ctx.StateDB.AddRefund(params.SstoreInitRefundEIP2200)
+		} else { // reset to original existing slot (2.2.2.2)
+			ctx.StateDB.AddRefund(params.SstoreCleanRefundEIP2200)
+		}
+	}
+	return params.SstoreDirtyGasEIP2200, nil // dirty update (2.2)
+}
+
 func makeGasLog(n uint64) gasFunc {
 	return func(gt params.GasTable, ctx *vmtypes.Context, contract *vmtypes.Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
 		requestedSize, overflow := common.BigUint64(stack.Back(1))
@@ -556,43 +556,62 @@ func opGasprice(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contr
 	return nil, nil
 }
 
-//func opBlockhash(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
-//	num := stack.pop()
-//
-//	n := interpreter.intPool.get().Sub(interpreter.vmContext.SequenceID, common.Big257)
-//	if num.Cmp(n) > 0 && num.Cmp(interpreter.vmContext.SequenceID) < 0 {
-//		stack.push(interpreter.vmContext.GetHash(num.Uint64()).Big())
-//	} else {
-//		stack.push(interpreter.intPool.getZero())
-//	}
-//	interpreter.intPool.put(num, n)
-//	return nil, nil
-//}
-//
-//func opCoinbase(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
-//	stack.push(interpreter.vmContext.Coinbase.Big())
-//	return nil, nil
-//}
-//
-//func opTimestamp(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
-//	stack.push(math.U256(interpreter.intPool.get().Set(interpreter.vmContext.Time)))
-//	return nil, nil
-//}
-//
-//func opNumber(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
-//	stack.push(math.U256(interpreter.intPool.get().Set(interpreter.vmContext.SequenceID)))
-//	return nil, nil
-//}
-//
-//func opDifficulty(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
-//	stack.push(math.U256(interpreter.intPool.get().Set(interpreter.vmContext.Difficulty)))
-//	return nil, nil
-//}
-//
-//func opGasLimit(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
-//	stack.push(math.U256(interpreter.intPool.get().SetUint64(interpreter.vmContext.GasLimit)))
-//	return nil, nil
-//}
+func opBlockhash(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	num := stack.pop()
+
+	current := interpreter.intPool.get().SetUint64(interpreter.txContext.SequenceID)
+	n := interpreter.intPool.get().Sub(current, common.Big257)
+	if num.Cmp(n) > 0 && num.Cmp(current) < 0 && interpreter.vmContext.GetHash != nil {
+		stack.push(interpreter.vmContext.GetHash(num.Uint64()).Big())
+	} else {
+		stack.push(interpreter.intPool.getZero())
+	}
+	interpreter.intPool.put(num, n, current)
+	return nil, nil
+}
+
+func opCoinbase(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	stack.push(interpreter.txContext.Coinbase.Big())
+	return nil, nil
+}
+
+func opTimestamp(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	if interpreter.txContext.Time == nil {
+		stack.push(interpreter.intPool.getZero())
+		return nil, nil
+	}
+	stack.push(math.U256(interpreter.intPool.get().Set(interpreter.txContext.Time.Value)))
+	return nil, nil
+}
+
+func opNumber(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	stack.push(math.U256(interpreter.intPool.get().SetUint64(interpreter.txContext.SequenceID)))
+	return nil, nil
+}
+
+// opDifficulty always pushes zero since sequencers are not mined against a
+// difficulty.
+func opDifficulty(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	stack.push(interpreter.intPool.getZero())
+	return nil, nil
+}
+
+func opGasLimit(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	stack.push(math.U256(interpreter.intPool.get().SetUint64(interpreter.txContext.GasLimit)))
+	return nil, nil
+}
+
+func opChainID(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	chainId := interpreter.intPool.get().Set(interpreter.chainConfig.ChainID)
+	stack.push(chainId)
+	return nil, nil
+}
+
+func opSelfBalance(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	balance := interpreter.intPool.get().Set(interpreter.vmContext.StateDB.GetBalance(contract.Address()).Value)
+	stack.push(balance)
+	return nil, nil
+}
 
 func opPop(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	interpreter.intPool.put(stack.pop())
@@ -23,7 +23,10 @@ import (
 	"testing"
 
 	"github.com/annchain/OG/common/crypto"
+	math2 "github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/vm/eth/common"
+	"github.com/annchain/OG/vm/eth/params"
+	"github.com/annchain/OG/vm/instruction"
 
 	"github.com/annchain/OG/vm/ovm"
 	vmtypes "github.com/annchain/OG/vm/types"
@@ -35,12 +38,20 @@ type twoOperandTest struct {
 	expected string
 }
 
+// newTestInterpreter returns an interpreter of the default chain config on
+// an empty context.
+func newTestInterpreter() (*ovm.OVM, *EVMInterpreter) {
+	vmContext := &vmtypes.Context{}
+	evmInterpreter := NewEVMInterpreter(vmContext, &ovm.TxContext{}, &InterpreterConfig{})
+	env := ovm.NewOVM(vmContext, []ovm.Interpreter{evmInterpreter}, &ovm.OVMConfig{})
+	return env, evmInterpreter
+}
+
 func testTwoOperandOp(t *testing.T, tests []twoOperandTest, opFn func(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error)) {
 	var (
-		env            = ovm.NewOVM(vmtypes.Context{}, nil, &ovm.OVMConfig{})
-		stack          = newstack()
-		pc             = uint64(0)
-		evmInterpreter = NewEVMInterpreter(&env.Context, &InterpreterConfig{})
+		env, evmInterpreter = newTestInterpreter()
+		stack               = newstack()
+		pc                  = uint64(0)
 	)
 
 	env.Interpreter = evmInterpreter
@@ -78,9 +89,8 @@ func testTwoOperandOp(t *testing.T, tests []twoOperandTest, opFn func(pc *uint64
 
 func TestByteOp(t *testing.T) {
 	var (
-		env            = ovm.NewOVM(vmtypes.Context{}, nil, &ovm.OVMConfig{})
-		stack          = newstack()
-		evmInterpreter = NewEVMInterpreter(&env.Context, &InterpreterConfig{})
+		env, evmInterpreter = newTestInterpreter()
+		stack               = newstack()
 	)
 
 	env.Interpreter = evmInterpreter
@@ -213,9 +223,8 @@ func TestSLT(t *testing.T) {
 
 func opBenchmark(bench *testing.B, op func(pc *uint64, interpreter *EVMInterpreter, contract *vmtypes.Contract, memory *Memory, stack *Stack) ([]byte, error), args ...string) {
 	var (
-		env            = ovm.NewOVM(vmtypes.Context{}, nil, &ovm.OVMConfig{})
-		stack          = newstack()
-		evmInterpreter = NewEVMInterpreter(&env.Context, &InterpreterConfig{})
+		env, evmInterpreter = newTestInterpreter()
+		stack               = newstack()
 	)
 
 	env.Interpreter = evmInterpreter
@@ -448,10 +457,9 @@ func BenchmarkOpIsZero(b *testing.B) {
 
 func TestOpMstore(t *testing.T) {
 	var (
-		env            = ovm.NewOVM(vmtypes.Context{}, nil, &ovm.OVMConfig{})
-		stack          = newstack()
-		mem            = NewMemory()
-		evmInterpreter = NewEVMInterpreter(&env.Context, &InterpreterConfig{})
+		env, evmInterpreter = newTestInterpreter()
+		stack               = newstack()
+		mem                 = NewMemory()
 	)
 
 	env.Interpreter = evmInterpreter
@@ -474,9 +482,9 @@ func TestOpMstore(t *testing.T) {
 
 func BenchmarkOpMstore(bench *testing.B) {
 	var (
-		env   = ovm.NewOVM(vmtypes.Context{}, nil, &ovm.OVMConfig{})
-		stack = newstack()
-		mem   = NewMemory()
+		_, evmInterpreter = newTestInterpreter()
+		stack             = newstack()
+		mem               = NewMemory()
 	)
 
 	mem.Resize(64)
@@ -484,7 +492,7 @@ func BenchmarkOpMstore(bench *testing.B) {
 	memStart := big.NewInt(0)
 	value := big.NewInt(0x1337)
 
-	evmInterpreter := env.Interpreter.(*EVMInterpreter)
+	evmInterpreter.intPool = poolOfIntPools.get()
 
 	bench.ResetTimer()
 	for i := 0; i < bench.N; i++ {
@@ -496,15 +504,15 @@ func BenchmarkOpMstore(bench *testing.B) {
 
 func BenchmarkOpSHA3(bench *testing.B) {
 	var (
-		env   = ovm.NewOVM(vmtypes.Context{}, nil, &ovm.OVMConfig{})
-		stack = newstack()
-		mem   = NewMemory()
+		_, evmInterpreter = newTestInterpreter()
+		stack             = newstack()
+		mem               = NewMemory()
 	)
 	mem.Resize(32)
 	pc := uint64(0)
 	start := big.NewInt(0)
 
-	evmInterpreter := env.Interpreter.(*EVMInterpreter)
+	evmInterpreter.intPool = poolOfIntPools.get()
 
 	bench.ResetTimer()
 	for i := 0; i < bench.N; i++ {
@@ -588,3 +596,110 @@ func TestCreate2Addreses(t *testing.T) {
 
 	}
 }
+
+type testChainContext struct{}
+
+func (c *testChainContext) GetSequencerHash(height uint64) common2.Hash {
+	return common2.BigToHash(new(big.Int).SetUint64(height + 1000))
+}
+
+func (c *testChainContext) GetSequencer(height uint64) (common2.Hash, []byte, []byte, bool) {
+	return c.GetSequencerHash(height), nil, nil, true
+}
+
+func TestBlockContextOps(t *testing.T) {
+	coinbase := common2.HexToAddress("0x1234")
+	txContext := &ovm.TxContext{
+		GasLimit:   500000,
+		Coinbase:   coinbase,
+		SequenceID: 300,
+		Time:       math2.NewBigInt(1560000000),
+	}
+	vmContext := ovm.NewOVMContext(&testChainContext{}, &coinbase, nil)
+	evmInterpreter := NewEVMInterpreter(vmContext, txContext, &InterpreterConfig{})
+	evmInterpreter.intPool = poolOfIntPools.get()
+	defer poolOfIntPools.put(evmInterpreter.intPool)
+
+	tests := []struct {
+		name     string
+		op       executionFunc
+		arg      *big.Int
+		expected *big.Int
+	}{
+		{"number", opNumber, nil, big.NewInt(300)},
+		{"timestamp", opTimestamp, nil, big.NewInt(1560000000)},
+		{"coinbase", opCoinbase, nil, coinbase.Big()},
+		{"difficulty", opDifficulty, nil, big.NewInt(0)},
+		{"gaslimit", opGasLimit, nil, big.NewInt(500000)},
+		{"blockhash", opBlockhash, big.NewInt(299), big.NewInt(1299)},
+		{"blockhash oldest", opBlockhash, big.NewInt(44), big.NewInt(1044)},
+		{"blockhash current", opBlockhash, big.NewInt(300), big.NewInt(0)},
+		{"blockhash too old", opBlockhash, big.NewInt(43), big.NewInt(0)},
+	}
+	for _, test := range tests {
+		stack := newstack()
+		pc := uint64(0)
+		if test.arg != nil {
+			stack.push(new(big.Int).Set(test.arg))
+		}
+		if _, err := test.op(&pc, evmInterpreter, nil, nil, stack); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if actual := stack.pop(); actual.Cmp(test.expected) != 0 {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestForkInstructionSets(t *testing.T) {
+	chainConfig := &params.ChainConfig{
+		ChainID:              big.NewInt(7),
+		ConstantinopleHeight: big.NewInt(10),
+		IstanbulHeight:       big.NewInt(20),
+	}
+	tests := []struct {
+		height  uint64
+		op      instruction.OpCode
+		enabled bool
+	}{
+		{9, instruction.SHL, false},
+		{10, instruction.SHL, true},
+		{10, instruction.CHAINID, false},
+		{19, instruction.SELFBALANCE, false},
+		{20, instruction.CHAINID, true},
+		{20, instruction.SELFBALANCE, true},
+	}
+	for _, test := range tests {
+		txContext := &ovm.TxContext{SequenceID: test.height}
+		evmInterpreter := NewEVMInterpreter(nil, txContext, &InterpreterConfig{ChainConfig: chainConfig})
+		if valid := evmInterpreter.jumpTable[test.op].valid; valid != test.enabled {
+			t.Errorf("%v at height %d: expected valid %v, got %v", test.op, test.height, test.enabled, valid)
+		}
+	}
+	if !chainConfig.IsPetersburg(10) {
+		t.Errorf("petersburg should follow constantinople when not set")
+	}
+}
+
+func TestBlockContextFork(t *testing.T) {
+	chainConfig := &params.ChainConfig{BlockContextHeight: big.NewInt(10)}
+	for _, test := range []struct {
+		height uint64
+		valid  bool
+	}{
+		{9, false},
+		{10, true},
+		{11, true},
+	} {
+		in := NewEVMInterpreter(&vmtypes.Context{}, &ovm.TxContext{SequenceID: test.height}, &InterpreterConfig{ChainConfig: chainConfig})
+		for _, op := range blockContextOps {
+			if in.jumpTable[op].valid != test.valid {
+				t.Errorf("height %d: %v valid %v, expected %v", test.height, op, in.jumpTable[op].valid, test.valid)
+			}
+		}
+	}
+	in := NewEVMInterpreter(&vmtypes.Context{}, &ovm.TxContext{SequenceID: 100}, &InterpreterConfig{})
+	if in.jumpTable[instruction.TIMESTAMP].valid {
+		t.Error("block context ops should never be valid without the fork")
+	}
+}
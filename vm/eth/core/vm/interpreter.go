@@ -44,17 +44,18 @@ type keccakState interface {
 //
 type EVMInterpreter struct {
 	//ovm      *ovm.OVM
-	vmContext  *vmtypes.Context
-	txContext  *ovm.TxContext
-	Cfg        *InterpreterConfig
-	gasTable   params.GasTable
-	intPool    *intPool
-	hasher     keccakState    // Keccak256 hasher instance shared across opcodes
-	hasherBuf  common2.Hash   // Keccak256 hasher result array shared aross opcodes
-	jumpTable  [256]operation // JumpTable contains the OVM instruction table.
-	readOnly   bool           // Whether to throw on stateful modifications
-	returnData []byte         // Last CALL's return data for subsequent reuse
-	Caller     vmtypes.Caller
+	vmContext   *vmtypes.Context
+	txContext   *ovm.TxContext
+	Cfg         *InterpreterConfig
+	chainConfig *params.ChainConfig
+	gasTable    params.GasTable
+	intPool     *intPool
+	hasher      keccakState    // Keccak256 hasher instance shared across opcodes
+	hasherBuf   common2.Hash   // Keccak256 hasher result array shared aross opcodes
+	jumpTable   [256]operation // JumpTable contains the OVM instruction table.
+	readOnly    bool           // Whether to throw on stateful modifications
+	returnData  []byte         // Last CALL's return data for subsequent reuse
+	Caller      vmtypes.Caller
 }
 
 func (in *EVMInterpreter) SetCaller(caller vmtypes.Caller) {
@@ -63,12 +64,35 @@ func (in *EVMInterpreter) SetCaller(caller vmtypes.Caller) {
 
 // NewEVMInterpreter returns a new instance of the Interpreter.
 func NewEVMInterpreter(vmContext *vmtypes.Context, txContext *ovm.TxContext, cfg *InterpreterConfig) *EVMInterpreter {
+	chainConfig := cfg.ChainConfig
+	if chainConfig == nil {
+		chainConfig = params.DefaultChainConfig
+	}
+	// the instruction set is picked by the height of the sequencer
+	// confirming the tx so that forks activate at the same point on
+	// every node.
+	height := txContext.SequenceID
+	var jumpTable [256]operation
+	switch {
+	case chainConfig.IsIstanbul(height):
+		jumpTable = istanbulInstructionSet
+	case chainConfig.IsPetersburg(height):
+		jumpTable = petersburgInstructionSet
+	case chainConfig.IsConstantinople(height):
+		jumpTable = constantinopleInstructionSet
+	default:
+		jumpTable = byzantiumInstructionSet
+	}
+	if !chainConfig.IsBlockContext(height) {
+		jumpTable = withoutBlockContext(jumpTable)
+	}
 	return &EVMInterpreter{
-		vmContext: vmContext,
-		txContext: txContext,
-		Cfg:       cfg,
-		gasTable:  params.GetGasTable(txContext.SequenceID),
-		jumpTable: byzantiumInstructionSet,
+		vmContext:   vmContext,
+		txContext:   txContext,
+		Cfg:         cfg,
+		chainConfig: chainConfig,
+		gasTable:    chainConfig.GasTable(height),
+		jumpTable:   jumpTable,
 	}
 }
 
@@ -53,9 +53,100 @@ type operation struct {
 }
 
 var (
-	byzantiumInstructionSet = newByzantiumInstructionSet()
+	byzantiumInstructionSet      = newByzantiumInstructionSet()
+	constantinopleInstructionSet = newConstantinopleInstructionSet()
+	petersburgInstructionSet     = newPetersburgInstructionSet()
+	istanbulInstructionSet       = newIstanbulInstructionSet()
 )
 
+// blockContextOps read the confirming sequencer. They are invalid before
+// the block context fork as the sequencer timestamp wasn't signed.
+var blockContextOps = []instruction.OpCode{
+	instruction.BLOCKHASH, instruction.COINBASE, instruction.TIMESTAMP,
+	instruction.NUMBER, instruction.DIFFICULTY, instruction.GASLIMIT,
+}
+
+// withoutBlockContext returns a copy of instructionSet without the block
+// context instructions.
+func withoutBlockContext(instructionSet [256]operation) [256]operation {
+	for _, op := range blockContextOps {
+		instructionSet[op] = operation{}
+	}
+	return instructionSet
+}
+
+// newIstanbulInstructionSet returns the frontier, homestead, byzantium,
+// constantinople, petersburg and istanbul instructions.
+func newIstanbulInstructionSet() [256]operation {
+	instructionSet := newPetersburgInstructionSet()
+	// EIP-1344
+	instructionSet[instruction.CHAINID] = operation{
+		execute:       opChainID,
+		gasCost:       constGasFunc(GasQuickStep),
+		validateStack: makeStackFunc(0, 1),
+		valid:         true,
+	}
+	// EIP-1884
+	instructionSet[instruction.SELFBALANCE] = operation{
+		execute:       opSelfBalance,
+		gasCost:       constGasFunc(GasFastStep),
+		validateStack: makeStackFunc(0, 1),
+		valid:         true,
+	}
+	// EIP-2200
+	instructionSet[instruction.SSTORE].gasCost = gasSStoreEIP2200
+	return instructionSet
+}
+
+// newPetersburgInstructionSet returns the constantinople instructions
+// without the EIP-1283 net gas metering.
+func newPetersburgInstructionSet() [256]operation {
+	instructionSet := newConstantinopleInstructionSet()
+	instructionSet[instruction.SSTORE].gasCost = gasSStore
+	return instructionSet
+}
+
+// newConstantinopleInstructionSet returns the frontier, homestead,
+// byzantium and constantinople instructions.
+func newConstantinopleInstructionSet() [256]operation {
+	instructionSet := newByzantiumInstructionSet()
+	instructionSet[instruction.SHL] = operation{
+		execute:       opSHL,
+		gasCost:       constGasFunc(GasFastestStep),
+		validateStack: makeStackFunc(2, 1),
+		valid:         true,
+	}
+	instructionSet[instruction.SHR] = operation{
+		execute:       opSHR,
+		gasCost:       constGasFunc(GasFastestStep),
+		validateStack: makeStackFunc(2, 1),
+		valid:         true,
+	}
+	instructionSet[instruction.SAR] = operation{
+		execute:       opSAR,
+		gasCost:       constGasFunc(GasFastestStep),
+		validateStack: makeStackFunc(2, 1),
+		valid:         true,
+	}
+	instructionSet[instruction.EXTCODEHASH] = operation{
+		execute:       opExtCodeHash,
+		gasCost:       gasExtCodeHash,
+		validateStack: makeStackFunc(1, 1),
+		valid:         true,
+	}
+	instructionSet[instruction.CREATE2] = operation{
+		execute:       opCreate2,
+		gasCost:       gasCreate2,
+		validateStack: makeStackFunc(4, 1),
+		memorySize:    memoryCreate2,
+		valid:         true,
+		writes:        true,
+		returns:       true,
+	}
+	instructionSet[instruction.SSTORE].gasCost = gasSStoreEIP1283
+	return instructionSet
+}
+
 // NewByzantiumInstructionSet returns the frontier, homestead and
 // byzantium instructions.
 func newByzantiumInstructionSet() [256]operation {
@@ -340,42 +431,42 @@ func newFrontierInstructionSet() [256]operation {
 			memorySize:    memoryExtCodeCopy,
 			valid:         true,
 		},
-		//instruction.BLOCKHASH: {
-		//	execute:       opBlockhash,
-		//	gasCost:       constGasFunc(GasExtStep),
-		//	validateStack: makeStackFunc(1, 1),
-		//	valid:         true,
-		//},
-		//instruction.COINBASE: {
-		//	execute:       opCoinbase,
-		//	gasCost:       constGasFunc(GasQuickStep),
-		//	validateStack: makeStackFunc(0, 1),
-		//	valid:         true,
-		//},
-		//instruction.TIMESTAMP: {
-		//	execute:       opTimestamp,
-		//	gasCost:       constGasFunc(GasQuickStep),
-		//	validateStack: makeStackFunc(0, 1),
-		//	valid:         true,
-		//},
-		//instruction.NUMBER: {
-		//	execute:       opNumber,
-		//	gasCost:       constGasFunc(GasQuickStep),
-		//	validateStack: makeStackFunc(0, 1),
-		//	valid:         true,
-		//},
-		//instruction.DIFFICULTY: {
-		//	execute:       opDifficulty,
-		//	gasCost:       constGasFunc(GasQuickStep),
-		//	validateStack: makeStackFunc(0, 1),
-		//	valid:         true,
-		//},
-		//instruction.GASLIMIT: {
-		//	execute:       opGasLimit,
-		//	gasCost:       constGasFunc(GasQuickStep),
-		//	validateStack: makeStackFunc(0, 1),
-		//	valid:         true,
-		//},
+		instruction.BLOCKHASH: {
+			execute:       opBlockhash,
+			gasCost:       constGasFunc(GasExtStep),
+			validateStack: makeStackFunc(1, 1),
+			valid:         true,
+		},
+		instruction.COINBASE: {
+			execute:       opCoinbase,
+			gasCost:       constGasFunc(GasQuickStep),
+			validateStack: makeStackFunc(0, 1),
+			valid:         true,
+		},
+		instruction.TIMESTAMP: {
+			execute:       opTimestamp,
+			gasCost:       constGasFunc(GasQuickStep),
+			validateStack: makeStackFunc(0, 1),
+			valid:         true,
+		},
+		instruction.NUMBER: {
+			execute:       opNumber,
+			gasCost:       constGasFunc(GasQuickStep),
+			validateStack: makeStackFunc(0, 1),
+			valid:         true,
+		},
+		instruction.DIFFICULTY: {
+			execute:       opDifficulty,
+			gasCost:       constGasFunc(GasQuickStep),
+			validateStack: makeStackFunc(0, 1),
+			valid:         true,
+		},
+		instruction.GASLIMIT: {
+			execute:       opGasLimit,
+			gasCost:       constGasFunc(GasQuickStep),
+			validateStack: makeStackFunc(0, 1),
+			valid:         true,
+		},
 		instruction.POP: {
 			execute:       opPop,
 			gasCost:       constGasFunc(GasQuickStep),
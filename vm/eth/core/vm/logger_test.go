@@ -21,7 +21,6 @@ import (
 	"math/big"
 	"testing"
 
-	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/vm/instruction"
 	vmtypes "github.com/annchain/OG/vm/types"
 )
@@ -51,7 +50,7 @@ func (*dummyStatedb) GetRefund() uint64 { return 1337 }
 
 func TestStoreCapture(t *testing.T) {
 	var (
-		env      = &vmtypes.Context{}
+		env      = &vmtypes.Context{StateDB: &dummyStatedb{}}
 		logger   = NewStructLogger(nil)
 		mem      = NewMemory()
 		stack    = newstack()
@@ -64,7 +63,7 @@ func TestStoreCapture(t *testing.T) {
 	if len(logger.changedValues[contract.Address()]) == 0 {
 		t.Fatalf("expected exactly 1 changed value on address %x, got %d", contract.Address(), len(logger.changedValues[contract.Address()]))
 	}
-	exp := types.BigToHash(big.NewInt(1))
+	exp := common.BigToHash(big.NewInt(1))
 	if logger.changedValues[contract.Address()][index] != exp {
 		t.Errorf("expected %x, got %x", exp, logger.changedValues[contract.Address()][index])
 	}
@@ -0,0 +1,117 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import "math/big"
+
+// DefaultChainConfig runs byzantium rules forever, which is what every
+// existing network has been running since genesis.
+var DefaultChainConfig = &ChainConfig{ChainID: big.NewInt(0)}
+
+// ChainConfig is the core config which determines the instruction set and
+// gas rules of the OVM. Forks are activated at a sequencer height, nil
+// means the fork is never activated.
+type ChainConfig struct {
+	ChainID *big.Int // returned by CHAINID since istanbul
+
+	ConstantinopleHeight *big.Int // CREATE2, SHL/SHR/SAR, EXTCODEHASH and net gas metering (EIP-1283)
+	PetersburgHeight     *big.Int // removes EIP-1283
+	IstanbulHeight       *big.Int // CHAINID, SELFBALANCE, EIP-1884 repricing and EIP-2200 net gas metering
+
+	BlockContextHeight  *big.Int // BLOCKHASH, COINBASE, TIMESTAMP, NUMBER, DIFFICULTY and GASLIMIT, signed sequencer timestamps
+	TokenContractHeight *big.Int // native token contract at 0x100, contract tx value moved by the OVM in the tx token
+	WASMHeight          *big.Int // contracts compiled to WebAssembly
+	OGPrecompilesHeight *big.Int // ed25519 and BLS verification and sequencer query precompiled contracts at 0x101-0x103
+	TokenMetadataHeight *big.Int // token symbol, decimals, metadata uri and controls set by the IPOs
+	SignedArchiveHeight *big.Int // archives signed by their sender or with a content type and compression
+	DomainNameHeight    *big.Int // domain names registered, renewed and transferred by action txs
+	AllowanceHeight     *big.Int // token allowances approved, revoked and spent by transfer-from action txs
+}
+
+// IsConstantinople returns whether height is either equal to the constantinople fork height or greater.
+func (c *ChainConfig) IsConstantinople(height uint64) bool {
+	return isForked(c.ConstantinopleHeight, height)
+}
+
+// IsPetersburg returns whether height is either equal to the petersburg fork
+// height or greater, or the constantinople height if petersburg is not set.
+func (c *ChainConfig) IsPetersburg(height uint64) bool {
+	return isForked(c.PetersburgHeight, height) || c.PetersburgHeight == nil && isForked(c.ConstantinopleHeight, height)
+}
+
+// IsIstanbul returns whether height is either equal to the istanbul fork height or greater.
+func (c *ChainConfig) IsIstanbul(height uint64) bool {
+	return isForked(c.IstanbulHeight, height)
+}
+
+// IsBlockContext returns whether height is either equal to the block context fork height or greater.
+func (c *ChainConfig) IsBlockContext(height uint64) bool {
+	return isForked(c.BlockContextHeight, height)
+}
+
+// IsTokenContract returns whether height is either equal to the token contract fork height or greater.
+func (c *ChainConfig) IsTokenContract(height uint64) bool {
+	return isForked(c.TokenContractHeight, height)
+}
+
+// IsWASM returns whether height is either equal to the wasm fork height or greater.
+func (c *ChainConfig) IsWASM(height uint64) bool {
+	return isForked(c.WASMHeight, height)
+}
+
+// IsOGPrecompiles returns whether height is either equal to the OG precompiles fork height or greater.
+func (c *ChainConfig) IsOGPrecompiles(height uint64) bool {
+	return isForked(c.OGPrecompilesHeight, height)
+}
+
+// IsTokenMetadata returns whether height is either equal to the token metadata fork height or greater.
+func (c *ChainConfig) IsTokenMetadata(height uint64) bool {
+	return isForked(c.TokenMetadataHeight, height)
+}
+
+// IsSignedArchive returns whether height is either equal to the signed archive fork height or greater.
+func (c *ChainConfig) IsSignedArchive(height uint64) bool {
+	return isForked(c.SignedArchiveHeight, height)
+}
+
+// IsDomainName returns whether height is either equal to the domain name fork height or greater.
+func (c *ChainConfig) IsDomainName(height uint64) bool {
+	return isForked(c.DomainNameHeight, height)
+}
+
+// IsAllowance returns whether height is either equal to the allowance fork height or greater.
+func (c *ChainConfig) IsAllowance(height uint64) bool {
+	return isForked(c.AllowanceHeight, height)
+}
+
+// GasTable returns the gas table corresponding to the fork active at height.
+//
+// The returned GasTable's fields shouldn't, under any circumstances, be changed.
+func (c *ChainConfig) GasTable(height uint64) GasTable {
+	if c.IsIstanbul(height) {
+		return GasTableIstanbul
+	}
+	return GasTableConstantinople
+}
+
+// isForked returns whether a fork scheduled at fork height is active at height.
+func isForked(fork *big.Int, height uint64) bool {
+	if fork == nil {
+		return false
+	}
+	return fork.Cmp(new(big.Int).SetUint64(height)) <= 0
+}
@@ -52,6 +52,21 @@ var (
 
 		CreateBySuicide: 25000,
 	}
+
+	// GasTableIstanbul contain the gas re-prices for
+	// the istanbul phase (EIP-1884).
+	GasTableIstanbul = GasTable{
+		ExtcodeSize: 700,
+		ExtcodeCopy: 700,
+		ExtcodeHash: 700,
+		Balance:     700,
+		SLoad:       800,
+		Calls:       700,
+		Suicide:     5000,
+		ExpByte:     50,
+
+		CreateBySuicide: 25000,
+	}
 )
 
 var (
@@ -67,5 +82,5 @@ var (
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
 func GetGasTable(num uint64) GasTable {
-	return GasTableConstantinople
+	return DefaultChainConfig.GasTable(num)
 }
@@ -0,0 +1,102 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ovm
+
+import (
+	"math/big"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/hexutil"
+)
+
+// Types of the call frames reported to a CallTracer.
+const (
+	CallTypeCall         = "CALL"
+	CallTypeCallCode     = "CALLCODE"
+	CallTypeDelegateCall = "DELEGATECALL"
+	CallTypeStaticCall   = "STATICCALL"
+	CallTypeCreate       = "CREATE"
+	CallTypeCreate2      = "CREATE2"
+)
+
+// CallTracer is notified by the OVM each time a call frame is entered
+// and left, including the frame of the tx itself.
+type CallTracer interface {
+	CaptureEnter(typ string, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int)
+	CaptureExit(output []byte, gasUsed uint64, err error)
+}
+
+// CallFrame is a call made during an OVM execution together with the
+// calls it made.
+type CallFrame struct {
+	Type    string         `json:"type"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *big.Int       `json:"value,omitempty"`
+	Gas     uint64         `json:"gas"`
+	GasUsed uint64         `json:"gas_used"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Calls   []*CallFrame   `json:"calls,omitempty"`
+}
+
+// CallTreeTracer implements CallTracer and builds the tree of the calls
+// made by a single tx.
+type CallTreeTracer struct {
+	root  *CallFrame
+	stack []*CallFrame
+}
+
+func NewCallTreeTracer() *CallTreeTracer {
+	return &CallTreeTracer{}
+}
+
+func (t *CallTreeTracer) CaptureEnter(typ string, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	frame := &CallFrame{
+		Type:  typ,
+		From:  from,
+		To:    to,
+		Gas:   gas,
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = new(big.Int).Set(value)
+	}
+	if len(t.stack) == 0 {
+		t.root = frame
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+func (t *CallTreeTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	frame.Output = common.CopyBytes(output)
+	frame.GasUsed = gasUsed
+	if err != nil {
+		frame.Error = err.Error()
+	}
+}
+
+// Root returns the frame of the tx, nil if nothing is traced.
+func (t *CallTreeTracer) Root() *CallFrame {
+	return t.root
+}
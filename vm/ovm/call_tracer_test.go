@@ -0,0 +1,64 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ovm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/annchain/OG/common"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+func TestCallTreeTracer(t *testing.T) {
+	caller := common.HexToAddress("0x1234")
+	db := newTokenMemoryStateDB()
+	db.CreateAccount(caller)
+
+	tracer := NewCallTreeTracer()
+	ctx := NewOVMContext(&DefaultChainContext{}, &caller, db)
+	ovm := NewOVM(ctx, nil, &OVMConfig{Tracer: tracer, TokenContract: true})
+	ret, _, err := ovm.Call(vmtypes.AccountRef(caller), TokenContractAddress, tokenIdSelector[:], 100000, big.NewInt(0), true)
+	if err != nil {
+		t.Fatalf("call token contract: %v", err)
+	}
+
+	root := tracer.Root()
+	if root == nil || root.Type != CallTypeCall || root.To != TokenContractAddress {
+		t.Fatalf("unexpected root frame: %+v", root)
+	}
+	if !bytes.Equal(root.Output, ret) || root.GasUsed != tokenIdGas || root.Error != "" {
+		t.Fatalf("unexpected root frame result: %+v", root)
+	}
+}
+
+func TestCallTreeTracerNested(t *testing.T) {
+	a, b, c := common.HexToAddress("0x0a"), common.HexToAddress("0x0b"), common.HexToAddress("0x0c")
+	tracer := NewCallTreeTracer()
+	tracer.CaptureEnter(CallTypeCall, a, b, nil, 100, big.NewInt(1))
+	tracer.CaptureEnter(CallTypeStaticCall, b, c, []byte{1}, 50, nil)
+	tracer.CaptureExit(nil, 50, vmtypes.ErrOutOfGas)
+	tracer.CaptureEnter(CallTypeCreate, b, c, nil, 40, big.NewInt(0))
+	tracer.CaptureExit([]byte{2}, 10, nil)
+	tracer.CaptureExit([]byte{3}, 90, nil)
+
+	root := tracer.Root()
+	if len(root.Calls) != 2 || root.GasUsed != 90 {
+		t.Fatalf("unexpected root frame: %+v", root)
+	}
+	if root.Calls[0].Error != vmtypes.ErrOutOfGas.Error() || root.Calls[1].Type != CallTypeCreate {
+		t.Fatalf("unexpected inner frames: %+v %+v", root.Calls[0], root.Calls[1])
+	}
+}
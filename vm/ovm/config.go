@@ -57,4 +57,16 @@ type OVMConfig struct {
 	// NoRecursion disabled Interpreter call, callcode,
 	// delegate call and create.
 	NoRecursion bool
+	// Tracer is notified of the call frames, nil disables call tracing.
+	Tracer CallTracer
+	// TokenContract enables the native token contract and moves the value
+	// of the tx in the tx token. Without it the value of the tx is moved
+	// before the OVM runs.
+	TokenContract bool
+	// OGPrecompiles enables the OG precompiled contracts, see
+	// PrecompiledContractsOG and SequencerAddress.
+	OGPrecompiles bool
+	// SequenceID is the height of the confirming sequencer. The sequencer
+	// contract only returns the sequencers before it, like BLOCKHASH.
+	SequenceID uint64
 }
@@ -2,9 +2,10 @@ package ovm
 
 import (
 	"fmt"
-	"github.com/annchain/OG/types"
-	"math/big"
 	"testing"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/math"
 )
 
 func TestLayers(t *testing.T) {
@@ -18,16 +19,16 @@ func TestLayers(t *testing.T) {
 	ldb.CreateAccount(addr1)
 	ldb.CreateAccount(addr2)
 
-	ldb.AddBalance(addr1, big.NewInt(100))
+	ldb.AddBalance(addr1, math.NewBigInt(100))
 
 	ldb.NewLayer()
 	ldb.CreateAccount(addr3)
-	ldb.AddBalance(addr1, big.NewInt(50))
-	ldb.AddBalance(addr2, big.NewInt(30))
+	ldb.AddBalance(addr1, math.NewBigInt(50))
+	ldb.AddBalance(addr2, math.NewBigInt(30))
 
 	ldb.NewLayer()
 	ldb.SetNonce(addr3, 1)
-	ldb.SubBalance(addr2, big.NewInt(10000))
+	ldb.SubBalance(addr2, math.NewBigInt(10000))
 
 	fmt.Println(ldb.String())
 	fmt.Println(ldb.GetBalance(addr1))
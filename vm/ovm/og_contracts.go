@@ -0,0 +1,161 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ovm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/annchain/OG/common"
+	vmtypes "github.com/annchain/OG/vm/types"
+	"github.com/annchain/kyber/v3/pairing/bn256"
+	"github.com/annchain/kyber/v3/sign/bls"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Addresses of the OG precompiled contracts, placed after the native token
+// contract.
+//
+//	ed25519Verify(pubKey[32] ++ sig[64] ++ msg) returns (bool)
+//	    verifies an ed25519 signature of msg as made by crypto.SignerEd25519.
+//	blsVerify(pubKey[128] ++ sig[64] ++ msg) returns (bool)
+//	    verifies a BLS signature on bn256 of msg against a public key on G2,
+//	    such as the joint public key of a term.
+//	sequencer(uint256 height) returns (bytes32 hash, bytes sig[64], bytes pubKey[128])
+//	    the hash and BLS joint signature and public key of the confirmed
+//	    sequencer at height, all zero if there is none.
+//
+// The arguments are packed, not abi encoded, the bool results are words.
+var (
+	Ed25519VerifyAddress = common.HexToAddress("0x0000000000000000000000000000000000000101")
+	BlsVerifyAddress     = common.HexToAddress("0x0000000000000000000000000000000000000102")
+	SequencerAddress     = common.HexToAddress("0x0000000000000000000000000000000000000103")
+)
+
+const (
+	ed25519VerifyGas        uint64 = 2000
+	ed25519VerifyPerWordGas uint64 = 12
+	blsVerifyGas            uint64 = 113000
+	blsVerifyPerWordGas     uint64 = 12
+	sequencerGas            uint64 = 800
+
+	blsPubKeyLength    = 128
+	blsSignatureLength = 64
+	sequencerOutLength = common.HashLength + blsSignatureLength + blsPubKeyLength
+)
+
+var errSequencerInputLength = errors.New("sequencer contract: invalid input length")
+
+// PrecompiledContractsOG contains the stateless precompiled contracts added
+// by OG on top of the Ethereum ones.
+var PrecompiledContractsOG = map[common.Address]PrecompiledContract{
+	Ed25519VerifyAddress: &ed25519Verify{},
+	BlsVerifyAddress:     &blsVerify{},
+}
+
+// precompile returns the precompiled contract at addr, nil if there is none.
+func (ovm *OVM) precompile(addr common.Address) PrecompiledContract {
+	if p := PrecompiledContractsByzantium[addr]; p != nil {
+		return p
+	}
+	if !ovm.OVMConfigs.OGPrecompiles {
+		return nil
+	}
+	if p := PrecompiledContractsOG[addr]; p != nil {
+		return p
+	}
+	if addr == SequencerAddress {
+		return &sequencerQuery{getSequencer: ovm.VMContext.GetSequencer, current: ovm.OVMConfigs.SequenceID}
+	}
+	return nil
+}
+
+func words(input []byte) uint64 {
+	return uint64(len(input)+31) / 32
+}
+
+// ed25519Verify implements the ed25519 signature verification.
+type ed25519Verify struct{}
+
+func (c *ed25519Verify) RequiredGas(input []byte) uint64 {
+	return ed25519VerifyGas + words(input)*ed25519VerifyPerWordGas
+}
+
+func (c *ed25519Verify) Run(input []byte) ([]byte, error) {
+	if len(input) < ed25519.PublicKeySize+ed25519.SignatureSize {
+		return false32Byte, nil
+	}
+	pubKey := input[:ed25519.PublicKeySize]
+	sig := input[ed25519.PublicKeySize : ed25519.PublicKeySize+ed25519.SignatureSize]
+	msg := input[ed25519.PublicKeySize+ed25519.SignatureSize:]
+	if ed25519.Verify(pubKey, msg, sig) {
+		return true32Byte, nil
+	}
+	return false32Byte, nil
+}
+
+// blsVerify implements the BLS signature verification used by the
+// sequencers.
+type blsVerify struct{}
+
+func (c *blsVerify) RequiredGas(input []byte) uint64 {
+	return blsVerifyGas + words(input)*blsVerifyPerWordGas
+}
+
+func (c *blsVerify) Run(input []byte) ([]byte, error) {
+	if len(input) < blsPubKeyLength+blsSignatureLength {
+		return false32Byte, nil
+	}
+	pubKey, err := bn256.UnmarshalBinaryPointG2(input[:blsPubKeyLength])
+	if err != nil {
+		return false32Byte, nil
+	}
+	sig := input[blsPubKeyLength : blsPubKeyLength+blsSignatureLength]
+	msg := input[blsPubKeyLength+blsSignatureLength:]
+	if err := bls.Verify(bn256.NewSuiteG2(), pubKey, msg, sig); err != nil {
+		return false32Byte, nil
+	}
+	return true32Byte, nil
+}
+
+// sequencerQuery returns the data of a sequencer confirmed before the
+// current one. The current and later ones give zero values whether they
+// are known or not, so that the result doesn't depend on where the tx runs.
+type sequencerQuery struct {
+	getSequencer vmtypes.GetSequencerFunc
+	current      uint64
+}
+
+func (c *sequencerQuery) RequiredGas(input []byte) uint64 {
+	return sequencerGas
+}
+
+func (c *sequencerQuery) Run(input []byte) ([]byte, error) {
+	if len(input) != 32 {
+		return nil, errSequencerInputLength
+	}
+	out := make([]byte, sequencerOutLength)
+	height := new(big.Int).SetBytes(input)
+	if !height.IsUint64() || height.Uint64() >= c.current || c.getSequencer == nil {
+		return out, nil
+	}
+	hash, sig, pubKey, ok := c.getSequencer(height.Uint64())
+	if !ok {
+		return out, nil
+	}
+	copy(out, hash.ToBytes())
+	copy(out[common.HashLength:common.HashLength+blsSignatureLength], sig)
+	copy(out[common.HashLength+blsSignatureLength:], pubKey)
+	return out, nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ovm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/annchain/OG/common"
+	vmtypes "github.com/annchain/OG/vm/types"
+	"github.com/annchain/kyber/v3/pairing/bn256"
+	"github.com/annchain/kyber/v3/sign/bls"
+	"golang.org/x/crypto/ed25519"
+)
+
+type sequencerChainContext struct {
+	DefaultChainContext
+	hash   common.Hash
+	sig    []byte
+	pubKey []byte
+}
+
+func (c *sequencerChainContext) GetSequencer(height uint64) (common.Hash, []byte, []byte, bool) {
+	if height != 7 {
+		return common.Hash{}, nil, nil, false
+	}
+	return c.hash, c.sig, c.pubKey, true
+}
+
+func TestEd25519Verify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("og")
+	sig := ed25519.Sign(priv, msg)
+	p := &ed25519Verify{}
+
+	if ret, _ := p.Run(append(append(append([]byte{}, pub...), sig...), msg...)); !bytes.Equal(ret, true32Byte) {
+		t.Fatalf("valid signature rejected: %x", ret)
+	}
+	if ret, _ := p.Run(append(append(append([]byte{}, pub...), sig...), []byte("go")...)); !bytes.Equal(ret, false32Byte) {
+		t.Fatalf("invalid signature accepted: %x", ret)
+	}
+	if ret, _ := p.Run(pub); !bytes.Equal(ret, false32Byte) {
+		t.Fatalf("short input accepted: %x", ret)
+	}
+}
+
+func TestBlsVerify(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	priv, pub := bls.NewKeyPair(suite, suite.RandomStream())
+	msg := []byte("sequencer hash")
+	sig, err := bls.Sign(suite, priv, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubBytes, err := pub.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &blsVerify{}
+
+	if ret, _ := p.Run(append(append(append([]byte{}, pubBytes...), sig...), msg...)); !bytes.Equal(ret, true32Byte) {
+		t.Fatalf("valid signature rejected: %x", ret)
+	}
+	if ret, _ := p.Run(append(append(append([]byte{}, pubBytes...), sig...), []byte("other")...)); !bytes.Equal(ret, false32Byte) {
+		t.Fatalf("invalid signature accepted: %x", ret)
+	}
+}
+
+func TestSequencerQuery(t *testing.T) {
+	chain := &sequencerChainContext{
+		hash:   common.HexToHash("0x0102030405060708091011121314151617181920212223242526272829303132"),
+		sig:    bytes.Repeat([]byte{0xaa}, blsSignatureLength),
+		pubKey: bytes.Repeat([]byte{0xbb}, blsPubKeyLength),
+	}
+	caller := common.HexToAddress("0x1234")
+	db := NewMemoryStateDB()
+	db.CreateAccount(caller)
+	ctx := NewOVMContext(chain, &caller, db)
+
+	// before the fork the address is an empty account.
+	legacy := NewOVM(ctx, nil, &OVMConfig{})
+	ret, _, err := legacy.Call(vmtypes.AccountRef(caller), SequencerAddress, common.LeftPadBytes(big.NewInt(7).Bytes(), 32), 100000, big.NewInt(0), false)
+	if err != nil || len(ret) != 0 {
+		t.Fatalf("sequencer contract before the fork: got %x, %v", ret, err)
+	}
+
+	ovm := NewOVM(ctx, nil, &OVMConfig{OGPrecompiles: true, SequenceID: 8})
+	query := func(height int64) []byte {
+		ret, _, err := ovm.StaticCall(vmtypes.AccountRef(caller), SequencerAddress, common.LeftPadBytes(big.NewInt(height).Bytes(), 32), 100000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ret
+	}
+
+	ret = query(7)
+	expected := append(append(chain.hash.ToBytes(), chain.sig...), chain.pubKey...)
+	if !bytes.Equal(ret, expected) {
+		t.Fatalf("sequencer 7: got %x", ret)
+	}
+	if ret := query(8); !bytes.Equal(ret, make([]byte, sequencerOutLength)) {
+		t.Fatalf("unknown sequencer: got %x", ret)
+	}
+
+	// the confirming sequencer is not queried even if it is known.
+	ovm = NewOVM(ctx, nil, &OVMConfig{OGPrecompiles: true, SequenceID: 7})
+	if ret := query(7); !bytes.Equal(ret, make([]byte, sequencerOutLength)) {
+		t.Fatalf("confirming sequencer: got %x", ret)
+	}
+}
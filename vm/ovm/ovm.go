@@ -23,6 +23,7 @@ import (
 
 	"github.com/annchain/OG/common/crypto"
 	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/types/token"
 	"github.com/annchain/OG/vm/eth/params"
 	vmtypes "github.com/annchain/OG/vm/types"
 	"github.com/sirupsen/logrus"
@@ -35,10 +36,12 @@ var emptyCodeHash = crypto.Keccak256Hash(nil)
 // run runs the given contract and takes care of running precompiles with a fallback to the byte Code interpreter.
 func run(ovm *OVM, contract *vmtypes.Contract, input []byte, readOnly bool) ([]byte, error) {
 	if contract.CodeAddr != nil {
-		precompiles := PrecompiledContractsByzantium
-		if p := precompiles[*contract.CodeAddr]; p != nil {
+		if p := ovm.precompile(*contract.CodeAddr); p != nil {
 			return RunPrecompiledContract(p, input, contract)
 		}
+		if ovm.isTokenContract(*contract.CodeAddr) {
+			return runTokenContract(ovm, contract, input, readOnly)
+		}
 	}
 	for _, interpreter := range ovm.Interpreters {
 		if interpreter.CanRun(contract.Code) {
@@ -117,6 +120,19 @@ func (ovm *OVM) Cancel() {
 	atomic.StoreInt32(&ovm.VMContext.Abort, 1)
 }
 
+func (ovm *OVM) isTokenContract(addr common.Address) bool {
+	return ovm.OVMConfigs.TokenContract && addr == TokenContractAddress
+}
+
+// valueTokenID returns the token of the value moved by a call. Only the
+// tx itself carries a token other than the default one.
+func (ovm *OVM) valueTokenID(txCall bool) int32 {
+	if txCall && ovm.OVMConfigs.TokenContract {
+		return ovm.VMContext.TokenID
+	}
+	return token.OGTokenID
+}
+
 // Call executes the contract associated with the addr with the given input as
 // parameters. It also handles any necessary value transfer required and takes
 // the necessary steps to create accounts and reverses the state in case of an
@@ -133,14 +149,19 @@ func (ovm *OVM) Call(caller vmtypes.ContractRef, addr common.Address, input []by
 	if ovm.OVMConfigs.NoRecursion && ctx.Depth > 0 {
 		return nil, gas, nil
 	}
+	if tracer := ovm.OVMConfigs.Tracer; tracer != nil {
+		tracer.CaptureEnter(CallTypeCall, caller.Address(), addr, input, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if ctx.Depth > int(params.CallCreateDepth) {
 		return nil, gas, vmtypes.ErrDepth
 	}
 	// Fail if we're trying to transfer more than the available Balance
-	if !ctx.CanTransfer(ctx.StateDB, caller.Address(), value) {
-		return nil, gas, vmtypes.ErrInsufficientBalance
+	tokenID := ovm.valueTokenID(txCall)
+	if err := ctx.CanTransfer(ctx.StateDB, caller.Address(), addr, tokenID, value); err != nil {
+		return nil, gas, err
 	}
 
 	var (
@@ -148,8 +169,7 @@ func (ovm *OVM) Call(caller vmtypes.ContractRef, addr common.Address, input []by
 		snapshot = ctx.StateDB.Snapshot()
 	)
 	if !ctx.StateDB.Exist(addr) {
-		precompiles := PrecompiledContractsByzantium
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		if ovm.precompile(addr) == nil && !ovm.isTokenContract(addr) && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			//if ovm.InterpreterConfig.Debug && ovm.Depth == 0 {
 			//	ovm.InterpreterConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -159,9 +179,9 @@ func (ovm *OVM) Call(caller vmtypes.ContractRef, addr common.Address, input []by
 		}
 		ctx.StateDB.CreateAccount(addr)
 	}
-	if value.Sign() != 0 && !txCall {
-
-		ctx.Transfer(ctx.StateDB, caller.Address(), to.Address(), value)
+	// the value of the tx was already moved without the token contract.
+	if value.Sign() != 0 && (!txCall || ovm.OVMConfigs.TokenContract) {
+		ctx.Transfer(ctx.StateDB, caller.Address(), to.Address(), tokenID, value)
 	}
 
 	// Initialise a new contract and set the Code that is to be used by the OVM.
@@ -214,14 +234,18 @@ func (ovm *OVM) CallCode(caller vmtypes.ContractRef, addr common.Address, input
 	if ovm.OVMConfigs.NoRecursion && ctx.Depth > 0 {
 		return nil, gas, nil
 	}
+	if tracer := ovm.OVMConfigs.Tracer; tracer != nil {
+		tracer.CaptureEnter(CallTypeCallCode, caller.Address(), addr, input, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if ctx.Depth > int(params.CallCreateDepth) {
 		return nil, gas, vmtypes.ErrDepth
 	}
 	// Fail if we're trying to transfer more than the available Balance
-	if !ctx.CanTransfer(ctx.StateDB, caller.Address(), value) {
-		return nil, gas, vmtypes.ErrInsufficientBalance
+	if err := ctx.CanTransfer(ctx.StateDB, caller.Address(), caller.Address(), token.OGTokenID, value); err != nil {
+		return nil, gas, err
 	}
 
 	var (
@@ -261,6 +285,10 @@ func (ovm *OVM) DelegateCall(caller vmtypes.ContractRef, addr common.Address, in
 	if ovm.OVMConfigs.NoRecursion && ctx.Depth > 0 {
 		return nil, gas, nil
 	}
+	if tracer := ovm.OVMConfigs.Tracer; tracer != nil {
+		tracer.CaptureEnter(CallTypeDelegateCall, caller.Address(), addr, input, gas, nil)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	// Fail if we're trying to execute above the call depth limit
 	if ctx.Depth > int(params.CallCreateDepth) {
 		return nil, gas, vmtypes.ErrDepth
@@ -301,6 +329,10 @@ func (ovm *OVM) StaticCall(caller vmtypes.ContractRef, addr common.Address, inpu
 	if ovm.OVMConfigs.NoRecursion && ctx.Depth > 0 {
 		return nil, gas, nil
 	}
+	if tracer := ovm.OVMConfigs.Tracer; tracer != nil {
+		tracer.CaptureEnter(CallTypeStaticCall, caller.Address(), addr, input, gas, new(big.Int))
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	// Fail if we're trying to execute above the call depth limit
 	if ctx.Depth > int(params.CallCreateDepth) {
 		return nil, gas, vmtypes.ErrDepth
@@ -337,8 +369,9 @@ func (ovm *OVM) create(caller vmtypes.ContractRef, codeAndHash *vmtypes.CodeAndH
 	if ctx.Depth > int(params.CallCreateDepth) {
 		return nil, common.Address{}, gas, vmtypes.ErrDepth
 	}
-	if !ctx.CanTransfer(ctx.StateDB, caller.Address(), value) {
-		return nil, common.Address{}, gas, vmtypes.ErrInsufficientBalance
+	tokenID := ovm.valueTokenID(txCall)
+	if err := ctx.CanTransfer(ctx.StateDB, caller.Address(), address, tokenID, value); err != nil {
+		return nil, common.Address{}, gas, err
 	}
 	nonce := ctx.StateDB.GetNonce(caller.Address())
 	if !txCall {
@@ -355,8 +388,8 @@ func (ovm *OVM) create(caller vmtypes.ContractRef, codeAndHash *vmtypes.CodeAndH
 	ctx.StateDB.CreateAccount(address)
 	ctx.StateDB.SetNonce(address, 1)
 
-	if value.Sign() != 0 && !txCall {
-		ctx.Transfer(ctx.StateDB, caller.Address(), address, value)
+	if value.Sign() != 0 && (!txCall || ovm.OVMConfigs.TokenContract) {
+		ctx.Transfer(ctx.StateDB, caller.Address(), address, tokenID, value)
 	}
 
 	// initialise a new contract and set the Code that is to be used by the
@@ -415,6 +448,10 @@ func (ovm *OVM) create(caller vmtypes.ContractRef, codeAndHash *vmtypes.CodeAndH
 func (ovm *OVM) Create(caller vmtypes.ContractRef, code []byte, gas uint64, value *big.Int, txCall bool) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
 
 	contractAddr = crypto.CreateAddress(caller.Address(), ovm.VMContext.StateDB.GetNonce(caller.Address()))
+	if tracer := ovm.OVMConfigs.Tracer; tracer != nil {
+		tracer.CaptureEnter(CallTypeCreate, caller.Address(), contractAddr, code, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	return ovm.create(caller, &vmtypes.CodeAndHash{Code: code}, gas, value, contractAddr, txCall)
 }
 
@@ -425,5 +462,9 @@ func (ovm *OVM) Create(caller vmtypes.ContractRef, code []byte, gas uint64, valu
 func (ovm *OVM) Create2(caller vmtypes.ContractRef, code []byte, gas uint64, endowment *big.Int, salt *big.Int, txCall bool) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
 	codeAndHash := &vmtypes.CodeAndHash{Code: code}
 	contractAddr = crypto.CreateAddress2(caller.Address(), common.BigToHash(salt).Bytes, codeAndHash.Hash().ToBytes())
+	if tracer := ovm.OVMConfigs.Tracer; tracer != nil {
+		tracer.CaptureEnter(CallTypeCreate2, caller.Address(), contractAddr, code, gas, endowment)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	return ovm.create(caller, codeAndHash, gas, endowment, contractAddr, txCall)
 }
@@ -0,0 +1,144 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ovm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/crypto"
+	"github.com/annchain/OG/types/token"
+	"github.com/annchain/OG/vm/eth/common/math"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+// TokenContractAddress is the reserved address of the native token contract.
+// It exposes OG native tokens to the contracts using the solidity call
+// convention:
+//
+//	tokenId() returns (int32)
+//	    the token of the value carried by the tx being processed.
+//	balanceOf(address owner, int32 tokenId) returns (uint256)
+//	    the balance of owner in the given token.
+//	transfer(address to, int32 tokenId, uint256 amount) returns (bool)
+//	    moves amount of the token from the calling contract to to.
+var TokenContractAddress = common.HexToAddress("0x0000000000000000000000000000000000000100")
+
+const (
+	tokenIdGas       uint64 = 2
+	tokenBalanceGas  uint64 = 400
+	tokenTransferGas uint64 = 9000
+)
+
+var (
+	tokenIdSelector       = methodSelector("tokenId()")
+	tokenBalanceSelector  = methodSelector("balanceOf(address,int32)")
+	tokenTransferSelector = methodSelector("transfer(address,int32,uint256)")
+)
+
+var (
+	errTokenMethodUnknown  = errors.New("token contract: unknown method")
+	errTokenInputLength    = errors.New("token contract: invalid input length")
+	errTokenIdOutOfRange   = errors.New("token contract: token id out of range")
+	errTokenValueNotZero   = errors.New("token contract: value not accepted")
+	errTokenTransferCaller = errors.New("token contract: transfer must be called directly")
+)
+
+func methodSelector(signature string) [4]byte {
+	var selector [4]byte
+	copy(selector[:], crypto.Keccak256([]byte(signature))[:4])
+	return selector
+}
+
+// runTokenContract runs the native token contract. Unlike the other
+// precompiled contracts it works on the state, so it is run by the OVM
+// instead of RunPrecompiledContract.
+func runTokenContract(ovm *OVM, contract *vmtypes.Contract, input []byte, readOnly bool) ([]byte, error) {
+	if contract.Value().Sign() != 0 {
+		return nil, errTokenValueNotZero
+	}
+	if len(input) < 4 {
+		return nil, errTokenMethodUnknown
+	}
+	var selector [4]byte
+	copy(selector[:], input[:4])
+	args := input[4:]
+	ctx := ovm.VMContext
+
+	switch selector {
+	case tokenIdSelector:
+		if !contract.UseGas(tokenIdGas) {
+			return nil, vmtypes.ErrOutOfGas
+		}
+		return math.PaddedBigBytes(math.U256(big.NewInt(int64(ctx.TokenID))), 32), nil
+
+	case tokenBalanceSelector:
+		if !contract.UseGas(tokenBalanceGas) {
+			return nil, vmtypes.ErrOutOfGas
+		}
+		if len(args) != 64 {
+			return nil, errTokenInputLength
+		}
+		owner := common.BytesToAddress(args[12:32])
+		tokenID, err := decodeTokenID(args[32:64])
+		if err != nil {
+			return nil, err
+		}
+		balance := ctx.StateDB.GetBalance(owner)
+		if tokenDB, ok := ctx.StateDB.(vmtypes.TokenStateDB); ok {
+			balance = tokenDB.GetTokenBalance(owner, tokenID)
+		} else if tokenID != token.OGTokenID {
+			return make([]byte, 32), nil
+		}
+		return math.PaddedBigBytes(balance.Value, 32), nil
+
+	case tokenTransferSelector:
+		if !contract.UseGas(tokenTransferGas) {
+			return nil, vmtypes.ErrOutOfGas
+		}
+		if readOnly {
+			return nil, vmtypes.ErrWriteProtection
+		}
+		// called by DELEGATECALL or CALLCODE the caller is not the
+		// contract the tokens belong to.
+		if contract.Address() != TokenContractAddress {
+			return nil, errTokenTransferCaller
+		}
+		if len(args) != 96 {
+			return nil, errTokenInputLength
+		}
+		to := common.BytesToAddress(args[12:32])
+		tokenID, err := decodeTokenID(args[32:64])
+		if err != nil {
+			return nil, err
+		}
+		amount := new(big.Int).SetBytes(args[64:96])
+		if err := ctx.CanTransfer(ctx.StateDB, contract.Caller(), to, tokenID, amount); err != nil {
+			return nil, err
+		}
+		ctx.Transfer(ctx.StateDB, contract.Caller(), to, tokenID, amount)
+		return common.LeftPadBytes([]byte{1}, 32), nil
+	}
+	return nil, errTokenMethodUnknown
+}
+
+// decodeTokenID decodes an abi encoded int32.
+func decodeTokenID(word []byte) (int32, error) {
+	v := math.S256(new(big.Int).SetBytes(word))
+	if !v.IsInt64() || v.Int64() < 0 || v.Int64() > int64(^uint32(0)>>1) {
+		return 0, errTokenIdOutOfRange
+	}
+	return int32(v.Int64()), nil
+}
@@ -0,0 +1,141 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ovm
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/math"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+type tokenMemoryStateDB struct {
+	*MemoryStateDB
+	tokens map[common.Address]map[int32]*big.Int
+	frozen map[common.Address]bool
+}
+
+func newTokenMemoryStateDB() *tokenMemoryStateDB {
+	return &tokenMemoryStateDB{NewMemoryStateDB(), make(map[common.Address]map[int32]*big.Int), make(map[common.Address]bool)}
+}
+
+var errFrozen = errors.New("frozen")
+
+func (m *tokenMemoryStateDB) CheckTokenTransfer(tokenID int32, from, to common.Address) error {
+	if m.frozen[from] || m.frozen[to] {
+		return errFrozen
+	}
+	return nil
+}
+
+func (m *tokenMemoryStateDB) GetTokenBalance(addr common.Address, tokenID int32) *math.BigInt {
+	if v, ok := m.tokens[addr][tokenID]; ok {
+		return math.NewBigIntFromBigInt(v)
+	}
+	return math.NewBigInt(0)
+}
+
+func (m *tokenMemoryStateDB) setTokenBalance(addr common.Address, tokenID int32, v *big.Int) {
+	if m.tokens[addr] == nil {
+		m.tokens[addr] = make(map[int32]*big.Int)
+	}
+	m.tokens[addr][tokenID] = v
+}
+
+func (m *tokenMemoryStateDB) AddTokenBalance(addr common.Address, tokenID int32, v *math.BigInt) {
+	m.setTokenBalance(addr, tokenID, new(big.Int).Add(m.GetTokenBalance(addr, tokenID).Value, v.Value))
+}
+
+func (m *tokenMemoryStateDB) SubTokenBalance(addr common.Address, tokenID int32, v *math.BigInt) {
+	m.setTokenBalance(addr, tokenID, new(big.Int).Sub(m.GetTokenBalance(addr, tokenID).Value, v.Value))
+}
+
+func (m *tokenMemoryStateDB) RevertToSnapshot(int) {}
+
+func word(v int64) []byte {
+	return common.LeftPadBytes(big.NewInt(v).Bytes(), 32)
+}
+
+func TestTokenContract(t *testing.T) {
+	caller := common.HexToAddress("0x1234")
+	receiver := common.HexToAddress("0x5678")
+	db := newTokenMemoryStateDB()
+	db.CreateAccount(caller)
+	db.AddTokenBalance(caller, 3, math.NewBigInt(100))
+
+	ctx := NewOVMContext(&DefaultChainContext{}, &caller, db)
+	ctx.TokenID = 3
+	// before the fork the token contract is an empty account.
+	legacy := NewOVM(ctx, nil, &OVMConfig{})
+	ret, _, err := legacy.Call(vmtypes.AccountRef(caller), TokenContractAddress, tokenIdSelector[:], 100000, big.NewInt(0), false)
+	if err != nil || len(ret) != 0 {
+		t.Fatalf("token contract before the fork: got %x, %v", ret, err)
+	}
+
+	ovm := NewOVM(ctx, nil, &OVMConfig{TokenContract: true})
+	call := func(selector [4]byte, args ...[]byte) ([]byte, error) {
+		input := selector[:]
+		for _, arg := range args {
+			input = append(input, arg...)
+		}
+		ret, _, err := ovm.Call(vmtypes.AccountRef(caller), TokenContractAddress, input, 100000, big.NewInt(0), false)
+		return ret, err
+	}
+
+	ret, err = call(tokenIdSelector)
+	if err != nil || new(big.Int).SetBytes(ret).Int64() != 3 {
+		t.Fatalf("tokenId: got %x, %v", ret, err)
+	}
+	if _, err := call(tokenTransferSelector, common.LeftPadBytes(receiver.ToBytes(), 32), word(3), word(40)); err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if _, err := call(tokenTransferSelector, common.LeftPadBytes(receiver.ToBytes(), 32), word(3), word(61)); err != vmtypes.ErrInsufficientBalance {
+		t.Fatalf("transfer over balance: expected %v, got %v", vmtypes.ErrInsufficientBalance, err)
+	}
+	ret, err = call(tokenBalanceSelector, common.LeftPadBytes(receiver.ToBytes(), 32), word(3))
+	if err != nil || new(big.Int).SetBytes(ret).Int64() != 40 {
+		t.Fatalf("balanceOf: got %x, %v", ret, err)
+	}
+	if balance := db.GetTokenBalance(caller, 3).Value.Int64(); balance != 60 {
+		t.Fatalf("caller balance: expected 60, got %d", balance)
+	}
+}
+
+func TestTokenControls(t *testing.T) {
+	caller := common.HexToAddress("0x1234")
+	receiver := common.HexToAddress("0x5678")
+	db := newTokenMemoryStateDB()
+	db.CreateAccount(caller)
+	db.AddTokenBalance(caller, 3, math.NewBigInt(100))
+	db.frozen[receiver] = true
+
+	ctx := NewOVMContext(&DefaultChainContext{}, &caller, db)
+	ctx.TokenID = 3
+	ovm := NewOVM(ctx, nil, &OVMConfig{TokenContract: true})
+
+	input := append(tokenTransferSelector[:], common.LeftPadBytes(receiver.ToBytes(), 32)...)
+	input = append(append(input, word(3)...), word(40)...)
+	if _, _, err := ovm.Call(vmtypes.AccountRef(caller), TokenContractAddress, input, 100000, big.NewInt(0), false); err != errFrozen {
+		t.Fatalf("transfer to a frozen holder: expected %v, got %v", errFrozen, err)
+	}
+	if _, _, err := ovm.Call(vmtypes.AccountRef(caller), receiver, nil, 100000, big.NewInt(40), true); err != errFrozen {
+		t.Fatalf("tx value to a frozen holder: expected %v, got %v", errFrozen, err)
+	}
+	if balance := db.GetTokenBalance(caller, 3).Value.Int64(); balance != 100 {
+		t.Fatalf("caller balance: expected 100, got %d", balance)
+	}
+}
@@ -19,6 +19,7 @@ package ovm
 import (
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/types/token"
 	vmtypes "github.com/annchain/OG/vm/types"
 	"math/big"
 )
@@ -33,38 +34,77 @@ type TxContext struct {
 	// Temporarily keep using gas as resource billing
 	GasLimit   uint64
 	GasPrice   *math.BigInt
-	Coinbase   common.Address // Provides information for COINBASE
-	SequenceID uint64         // Provides information for SequenceID
-	//Time        *math.BigInt      // Provides information for TIME
+	Coinbase   common.Address // Provides information for COINBASE, the issuer of the confirming sequencer
+	SequenceID uint64         // Provides information for NUMBER, the height of the confirming sequencer
+	Time       *math.BigInt   // Provides information for TIMESTAMP, the confirming sequencer's timestamp in seconds
 	//Difficulty  *math.BigInt      // Provides information for DIFFICULTY
 }
 
 // ChainContext supports retrieving headers and consensus parameters from the
 // current blockchain to be used during transaction processing.
 type ChainContext interface {
+	// GetSequencerHash returns the hash of the confirmed sequencer at the
+	// given height, empty hash if not found.
+	GetSequencerHash(height uint64) common.Hash
+	// GetSequencer returns the hash, the BLS joint signature and the
+	// joint public key of the confirmed sequencer at the given height.
+	GetSequencer(height uint64) (hash common.Hash, jointSig []byte, jointPubKey []byte, ok bool)
 }
 
 type DefaultChainContext struct {
 }
 
+func (c *DefaultChainContext) GetSequencerHash(height uint64) common.Hash {
+	return common.Hash{}
+}
+
+func (c *DefaultChainContext) GetSequencer(height uint64) (common.Hash, []byte, []byte, bool) {
+	return common.Hash{}, nil, nil, false
+}
+
 // NewOVMContext creates a new context for use in the OVM.
 func NewOVMContext(chainContext ChainContext, coinBase *common.Address, stateDB vmtypes.StateDB) *vmtypes.Context {
 	return &vmtypes.Context{
-		CanTransfer: CanTransfer,
-		Transfer:    Transfer,
-		StateDB:     stateDB,
+		CanTransfer:  CanTransfer,
+		Transfer:     Transfer,
+		GetHash:      chainContext.GetSequencerHash,
+		GetSequencer: chainContext.GetSequencer,
+		StateDB:      stateDB,
 	}
 }
 
-// CanTransfer checks whether there are enough funds in the address' account to make a transfer.
+// CanTransfer checks whether there are enough funds of the token in the sender's account to make a transfer,
+// and whether the controls of the token allow moving it to recipient.
 // This does not take the necessary gas in to account to make the transfer valid.
-func CanTransfer(db vmtypes.StateDB, addr common.Address, amount *big.Int) bool {
-	return db.GetBalance(addr).Value.Cmp(amount) >= 0
+func CanTransfer(db vmtypes.StateDB, sender, recipient common.Address, tokenID int32, amount *big.Int) error {
+	tokenDB, ok := db.(vmtypes.TokenStateDB)
+	var balance *math.BigInt
+	switch {
+	case tokenID == token.OGTokenID:
+		balance = db.GetBalance(sender)
+	case ok:
+		balance = tokenDB.GetTokenBalance(sender, tokenID)
+	default:
+		return vmtypes.ErrInsufficientBalance
+	}
+	if balance.Value.Cmp(amount) < 0 {
+		return vmtypes.ErrInsufficientBalance
+	}
+	if ok && amount.Sign() != 0 {
+		return tokenDB.CheckTokenTransfer(tokenID, sender, recipient)
+	}
+	return nil
 }
 
-// Transfer subtracts amount from sender and adds amount to recipient using the given Db
-func Transfer(db vmtypes.StateDB, sender, recipient common.Address, amount *big.Int) {
+// Transfer subtracts amount of the token from sender and adds amount to recipient using the given Db
+func Transfer(db vmtypes.StateDB, sender, recipient common.Address, tokenID int32, amount *big.Int) {
 	a := math.NewBigIntFromBigInt(amount)
-	db.SubBalance(sender, a)
-	db.AddBalance(recipient, a)
+	if tokenID == token.OGTokenID {
+		db.SubBalance(sender, a)
+		db.AddBalance(recipient, a)
+		return
+	}
+	tokenDB := db.(vmtypes.TokenStateDB)
+	tokenDB.SubTokenBalance(sender, tokenID, a)
+	tokenDB.AddTokenBalance(recipient, tokenID, a)
 }
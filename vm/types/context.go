@@ -7,24 +7,33 @@ import (
 
 type (
 	// CanTransferFunc is the signature of a transfer guard function
-	CanTransferFunc func(StateDB, common.Address, *big.Int) bool
+	CanTransferFunc func(StateDB, common.Address, common.Address, int32, *big.Int) error
 	// TransferFunc is the signature of a transfer function
-	TransferFunc func(StateDB, common.Address, common.Address, *big.Int)
+	TransferFunc func(StateDB, common.Address, common.Address, int32, *big.Int)
 	// GetHashFunc returns the nth block hash in the blockchain
 	// and is used by the BLOCKHASH OVM op code.
 	GetHashFunc func(uint64) common.Hash
+	// GetSequencerFunc returns the hash, the BLS joint signature and the
+	// joint public key of the nth sequencer, false if not found.
+	GetSequencerFunc func(uint64) (common.Hash, []byte, []byte, bool)
 )
 
 // Context provides the OVM with auxiliary information. Once provided
 // it shouldn't be modified.
 type Context struct {
 	// CanTransfer returns whether the account contains
-	// sufficient ether to transfer the value
+	// sufficient ether to transfer the value and the token
+	// may be moved to the recipient
 	CanTransfer CanTransferFunc
 	// Transfer transfers ether from one account to the other
 	Transfer TransferFunc
-	// GetHash returns the hash corresponding to n
-	//GetHash GetHashFunc
+	// GetHash returns the hash of the sequencer at height n
+	GetHash GetHashFunc
+	// GetSequencer returns the nth sequencer and its joint signature
+	GetSequencer GetSequencerFunc
+	// TokenID is the token of the value carried by the tx. Value moved
+	// by calls inside the contracts is always in the default token.
+	TokenID     int32
 	StateDB     StateDB
 	CallGasTemp uint64
 	// Depth is the current call stack
@@ -0,0 +1,36 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestUnpackRevertReason(t *testing.T) {
+	// revert("not owner")
+	data, _ := hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000009" +
+		"6e6f74206f776e65720000000000000000000000000000000000000000000000")
+	if reason, ok := UnpackRevertReason(data); !ok || reason != "not owner" {
+		t.Fatalf("expected not owner, got %q %v", reason, ok)
+	}
+	if _, ok := UnpackRevertReason(data[:40]); ok {
+		t.Fatalf("expected short data to fail")
+	}
+	if _, ok := UnpackRevertReason(append([]byte{0, 0, 0, 0}, data[4:]...)); ok {
+		t.Fatalf("expected unknown selector to fail")
+	}
+}
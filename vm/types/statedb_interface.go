@@ -66,6 +66,18 @@ type StateDB interface {
 	// SetStateObject(addr common.Address, stateObject StateObjectInterface)
 }
 
+// TokenStateDB is implemented by the state dbs that hold native tokens
+// other than the default one.
+type TokenStateDB interface {
+	SubTokenBalance(common.Address, int32, *math.BigInt)
+	AddTokenBalance(common.Address, int32, *math.BigInt)
+	// Retrieve the token balance from the given address or 0 if object not found
+	GetTokenBalance(common.Address, int32) *math.BigInt
+	// CheckTokenTransfer tells if the controls of the token issuer, such
+	// as freezes and pauses, allow moving the token between the addresses.
+	CheckTokenTransfer(tokenID int32, from, to common.Address) error
+}
+
 // StateDBDebug is a temp inferface for layerdb debug.
 type StateDBDebug interface {
 	CreateAccount(common.Address)
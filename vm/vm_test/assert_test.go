@@ -1,8 +1,8 @@
 package vm_test
 
 import (
+	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/math"
-	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/vm/eth/core/vm"
 	"github.com/annchain/OG/vm/ovm"
 	"github.com/stretchr/testify/assert"
@@ -4,13 +4,12 @@ import (
 	"fmt"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/math"
-	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/vm/eth/core/vm"
 	"github.com/annchain/OG/vm/ovm"
 	vmtypes "github.com/annchain/OG/vm/types"
 )
 
-func ExampleExecute() {
+func Example_execute() {
 
 	txContext := &ovm.TxContext{
 		From:       common.HexToAddress("0x01"),
@@ -36,10 +35,10 @@ func ExampleExecute() {
 
 	ovm := ovm.NewOVM(context, []ovm.Interpreter{evmInterpreter}, &ovm.OVMConfig{NoRecursion: false})
 
-	ret, contractAddr, leftOverGas, err := ovm.Create(vmtypes.AccountRef(txContext.From), txContext.Data, txContext.GasLimit, txContext.Value.Value)
+	ret, contractAddr, leftOverGas, err := ovm.Create(vmtypes.AccountRef(txContext.From), txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
 	fmt.Println(common.Bytes2Hex(ret), contractAddr.String(), leftOverGas, err)
 
-	ret, leftOverGas, err = ovm.Call(vmtypes.AccountRef(txContext.From), contractAddr, txContext.Data, txContext.GasLimit, txContext.Value.Value)
+	ret, leftOverGas, err = ovm.Call(vmtypes.AccountRef(txContext.From), contractAddr, txContext.Data, txContext.GasLimit, txContext.Value.Value, true)
 	fmt.Println(common.Bytes2Hex(ret), contractAddr.String(), leftOverGas, err)
 
 	fmt.Println(db.String())
@@ -0,0 +1,369 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package vm_test
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/vm/eth/core/vm"
+	"github.com/annchain/OG/vm/ovm"
+	vmtypes "github.com/annchain/OG/vm/types"
+	"github.com/annchain/OG/vm/wasm"
+	"github.com/stretchr/testify/assert"
+)
+
+// helpers assembling wasm binary modules.
+
+func wasmCat(parts ...[]byte) []byte {
+	var b []byte
+	for _, part := range parts {
+		b = append(b, part...)
+	}
+	return b
+}
+
+func wasmUleb(v uint64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b = append(b, c|0x80)
+			continue
+		}
+		return append(b, c)
+	}
+}
+
+func wasmSleb(v int64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func wasmVec(items ...[]byte) []byte {
+	return wasmCat(wasmUleb(uint64(len(items))), wasmCat(items...))
+}
+
+func wasmString(s string) []byte {
+	return wasmCat(wasmUleb(uint64(len(s))), []byte(s))
+}
+
+func wasmSection(id byte, items ...[]byte) []byte {
+	payload := wasmVec(items...)
+	return wasmCat([]byte{id}, wasmUleb(uint64(len(payload))), payload)
+}
+
+func wasmFuncType(params []byte, results []byte) []byte {
+	return wasmCat([]byte{0x60}, wasmUleb(uint64(len(params))), params, wasmUleb(uint64(len(results))), results)
+}
+
+func wasmI32(v int32) []byte {
+	return wasmCat([]byte{0x41}, wasmSleb(int64(v)))
+}
+
+func wasmI64(v int64) []byte {
+	return wasmCat([]byte{0x42}, wasmSleb(v))
+}
+
+func wasmCall(idx uint32) []byte {
+	return wasmCat([]byte{0x10}, wasmUleb(uint64(idx)))
+}
+
+// wasmContract assembles a module with one page of memory. Functions
+// index space starts with the imports, which are host functions.
+type wasmContract struct {
+	types   [][]byte
+	imports [][]byte
+	funcs   [][]byte
+	codes   [][]byte
+	exports [][]byte
+	data    [][]byte
+}
+
+func (c *wasmContract) typ(params []byte, results []byte) uint32 {
+	c.types = append(c.types, wasmFuncType(params, results))
+	return uint32(len(c.types) - 1)
+}
+
+func (c *wasmContract) importHost(name string, typ uint32) uint32 {
+	c.imports = append(c.imports, wasmCat(wasmString(wasm.HostModule), wasmString(name), []byte{0x00}, wasmUleb(uint64(typ))))
+	return uint32(len(c.imports) - 1)
+}
+
+// function adds a function without locals, body must not hold the end
+// of the function.
+func (c *wasmContract) function(typ uint32, export string, body ...[]byte) uint32 {
+	return c.functionWithLocals(typ, export, 0, body...)
+}
+
+// functionWithLocals adds a function with locals i64 locals.
+func (c *wasmContract) functionWithLocals(typ uint32, export string, locals uint32, body ...[]byte) uint32 {
+	idx := uint32(len(c.imports) + len(c.funcs))
+	c.funcs = append(c.funcs, wasmUleb(uint64(typ)))
+	decls := []byte{0x00}
+	if locals > 0 {
+		decls = wasmCat(wasmUleb(1), wasmUleb(uint64(locals)), []byte{wasmTypeI64})
+	}
+	code := wasmCat(decls, wasmCat(body...), []byte{0x0b})
+	c.codes = append(c.codes, wasmCat(wasmUleb(uint64(len(code))), code))
+	if export != "" {
+		c.exports = append(c.exports, wasmCat(wasmString(export), []byte{0x00}, wasmUleb(uint64(idx))))
+	}
+	return idx
+}
+
+func (c *wasmContract) dataAt(offset int32, data []byte) {
+	c.data = append(c.data, wasmCat([]byte{0x00}, wasmI32(offset), []byte{0x0b}, wasmUleb(uint64(len(data))), data))
+}
+
+func (c *wasmContract) bytes() []byte {
+	module := wasmCat([]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00},
+		wasmSection(1, c.types...),
+		wasmSection(2, c.imports...),
+		wasmSection(3, c.funcs...),
+		wasmSection(5, []byte{0x00, 0x01}),
+		wasmSection(7, c.exports...),
+		wasmSection(10, c.codes...))
+	if len(c.data) > 0 {
+		module = append(module, wasmSection(11, c.data...)...)
+	}
+	return module
+}
+
+const (
+	wasmTypeI32 = 0x7f
+	wasmTypeI64 = 0x7e
+)
+
+var (
+	wasmI32Pair = []byte{wasmTypeI32, wasmTypeI32}
+	wasmI32Trio = []byte{wasmTypeI32, wasmTypeI32, wasmTypeI32}
+)
+
+// storeContract stores 7 at key 0 when deployed. Its main stores the 32
+// bytes input at key 0, if any, and returns the value at key 0.
+func storeContract() []byte {
+	c := &wasmContract{}
+	pair := c.typ(wasmI32Pair, nil)
+	void := c.typ(nil, nil)
+	size := c.typ(nil, []byte{wasmTypeI32})
+	trio := c.typ(wasmI32Trio, nil)
+	storageLoad := c.importHost("storage_load", pair)
+	storageStore := c.importHost("storage_store", pair)
+	inputSize := c.importHost("get_input_size", size)
+	copyInput := c.importHost("copy_input", trio)
+	finish := c.importHost("finish", pair)
+
+	c.function(void, "deploy",
+		wasmI32(63), wasmI32(7), []byte{0x3a, 0x00, 0x00},
+		wasmI32(0), wasmI32(32), wasmCall(storageStore))
+	c.function(void, "main",
+		wasmCall(inputSize), wasmI32(32), []byte{0x46}, []byte{0x04, 0x40},
+		wasmI32(32), wasmI32(0), wasmI32(32), wasmCall(copyInput),
+		wasmI32(0), wasmI32(32), wasmCall(storageStore),
+		[]byte{0x0b},
+		wasmI32(0), wasmI32(64), wasmCall(storageLoad),
+		wasmI32(64), wasmI32(32), wasmCall(finish))
+	return c.bytes()
+}
+
+// factorialContract returns the little endian factorial of 20 computed
+// recursively.
+func factorialContract() []byte {
+	c := &wasmContract{}
+	pair := c.typ(wasmI32Pair, nil)
+	void := c.typ(nil, nil)
+	fact := c.typ([]byte{wasmTypeI32}, []byte{wasmTypeI64})
+	finish := c.importHost("finish", pair)
+
+	factIdx := uint32(len(c.imports) + 1)
+	c.function(void, "main",
+		wasmI32(0), wasmI32(20), wasmCall(factIdx), []byte{0x37, 0x03, 0x00},
+		wasmI32(0), wasmI32(8), wasmCall(finish))
+	c.function(fact, "",
+		[]byte{0x20, 0x00, 0x45, 0x04, wasmTypeI64},
+		wasmI64(1),
+		[]byte{0x05},
+		[]byte{0x20, 0x00, 0xad},
+		[]byte{0x20, 0x00}, wasmI32(1), []byte{0x6b}, wasmCall(factIdx),
+		[]byte{0x7e},
+		[]byte{0x0b})
+	return c.bytes()
+}
+
+func revertContract() []byte {
+	c := &wasmContract{}
+	pair := c.typ(wasmI32Pair, nil)
+	void := c.typ(nil, nil)
+	revert := c.importHost("revert", pair)
+	c.function(void, "main", wasmI32(0), wasmI32(4), wasmCall(revert))
+	c.dataAt(0, []byte("nope"))
+	return c.bytes()
+}
+
+func loopContract() []byte {
+	c := &wasmContract{}
+	void := c.typ(nil, nil)
+	c.function(void, "main", []byte{0x03, 0x40, 0x0c, 0x00, 0x0b})
+	return c.bytes()
+}
+
+// framesContract recurses with 4000 locals in each frame.
+func framesContract() []byte {
+	c := &wasmContract{}
+	void := c.typ(nil, nil)
+	c.functionWithLocals(void, "main", 4000, wasmCall(0))
+	return c.bytes()
+}
+
+// proxyContract calls target and returns what it returned.
+func proxyContract(target common.Address) []byte {
+	c := &wasmContract{}
+	pair := c.typ(wasmI32Pair, nil)
+	void := c.typ(nil, nil)
+	size := c.typ(nil, []byte{wasmTypeI32})
+	trio := c.typ(wasmI32Trio, nil)
+	callType := c.typ([]byte{wasmTypeI64, wasmTypeI32, wasmTypeI32, wasmTypeI32, wasmTypeI32}, []byte{wasmTypeI32})
+	call := c.importHost("call", callType)
+	returnSize := c.importHost("get_return_data_size", size)
+	copyReturn := c.importHost("copy_return_data", trio)
+	finish := c.importHost("finish", pair)
+
+	c.function(void, "main",
+		wasmI64(100000), wasmI32(0), wasmI32(32), wasmI32(0), wasmI32(0), wasmCall(call), []byte{0x1a},
+		wasmI32(64), wasmI32(0), wasmCall(returnSize), wasmCall(copyReturn),
+		wasmI32(64), wasmCall(returnSize), wasmCall(finish))
+	c.dataAt(0, target.ToBytes())
+	return c.bytes()
+}
+
+func wasmTestOVM(from common.Address) (*ovm.OVM, *ovm.LayerStateDB) {
+	coinBase := common.HexToAddress("0x1234567812345678AABBCCDDEEFF998877665544")
+	ldb := ovm.NewLayerDB(ovm.NewMemoryStateDB())
+	ldb.NewLayer()
+	ldb.CreateAccount(from)
+	ldb.AddBalance(from, math.NewBigInt(10000000))
+	ctx := ovm.NewOVMContext(&ovm.DefaultChainContext{}, &coinBase, ldb)
+	txContext := &ovm.TxContext{
+		From:     from,
+		Value:    math.NewBigInt(0),
+		GasPrice: math.NewBigInt(1),
+		GasLimit: 8000000,
+		Coinbase: coinBase,
+	}
+	interpreters := []ovm.Interpreter{
+		wasm.NewWASMInterpreter(ctx, txContext),
+		vm.NewEVMInterpreter(ctx, txContext, &vm.InterpreterConfig{}),
+	}
+	return ovm.NewOVM(ctx, interpreters, &ovm.OVMConfig{}), ldb
+}
+
+func wasmWord(v int64) []byte {
+	return common.LeftPadBytes(big.NewInt(v).Bytes(), 32)
+}
+
+func TestWASMContractStorage(t *testing.T) {
+	from := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	o, ldb := wasmTestOVM(from)
+	code := storeContract()
+	caller := vmtypes.AccountRef(from)
+
+	ret, addr, _, err := o.Create(caller, code, 1000000, big.NewInt(0), true)
+	assert.NoError(t, err)
+	assert.Equal(t, code, ret)
+	assert.Equal(t, code, ldb.GetCode(addr))
+	assert.Equal(t, wasmWord(7), ldb.GetState(addr, common.BytesToHash(make([]byte, 32))).ToBytes())
+
+	ret, _, err = o.Call(caller, addr, nil, 1000000, big.NewInt(0), true)
+	assert.NoError(t, err)
+	assert.Equal(t, wasmWord(7), ret)
+
+	ret, _, err = o.Call(caller, addr, wasmWord(42), 1000000, big.NewInt(0), true)
+	assert.NoError(t, err)
+	assert.Equal(t, wasmWord(42), ret)
+
+	_, _, err = o.StaticCall(caller, addr, wasmWord(43), 1000000)
+	assert.Equal(t, vmtypes.ErrWriteProtection, err)
+	ret, _, err = o.StaticCall(caller, addr, nil, 1000000)
+	assert.NoError(t, err)
+	assert.Equal(t, wasmWord(42), ret)
+}
+
+func TestWASMContractExecution(t *testing.T) {
+	from := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	o, _ := wasmTestOVM(from)
+	caller := vmtypes.AccountRef(from)
+	deploy := func(code []byte) common.Address {
+		_, addr, _, err := o.Create(caller, code, 1000000, big.NewInt(0), false)
+		assert.NoError(t, err)
+		return addr
+	}
+
+	// deployed first as the layer db doesn't restore its active layer
+	// when a failed call is reverted.
+	factorial := deploy(factorialContract())
+	store := deploy(storeContract())
+	proxy := deploy(proxyContract(store))
+	reverting := deploy(revertContract())
+	looping := deploy(loopContract())
+	frames := deploy(framesContract())
+
+	ret, _, err := o.Call(caller, factorial, nil, 1000000, big.NewInt(0), true)
+	assert.NoError(t, err)
+	expected := make([]byte, 8)
+	binary.LittleEndian.PutUint64(expected, 2432902008176640000)
+	assert.Equal(t, expected, ret)
+
+	ret, _, err = o.Call(caller, proxy, nil, 1000000, big.NewInt(0), true)
+	assert.NoError(t, err)
+	assert.Equal(t, wasmWord(7), ret)
+
+	ret, _, err = o.Call(caller, reverting, nil, 1000000, big.NewInt(0), true)
+	assert.Equal(t, vmtypes.ErrExecutionReverted, err)
+	assert.Equal(t, []byte("nope"), ret)
+
+	_, leftOverGas, err := o.Call(caller, looping, nil, 1000000, big.NewInt(0), true)
+	assert.Equal(t, vmtypes.ErrOutOfGas, err)
+	assert.Equal(t, uint64(0), leftOverGas)
+
+	// the values of all the frames are bounded before the call depth.
+	_, _, err = o.Call(caller, frames, nil, 1000000, big.NewInt(0), true)
+	assert.Equal(t, wasm.ErrStackOverflow, err)
+
+	ret, _, err = o.Call(caller, store, nil, 1000000, big.NewInt(0), true)
+	assert.NoError(t, err)
+	assert.Equal(t, wasmWord(7), ret)
+}
+
+func TestWASMRejectsFloats(t *testing.T) {
+	c := &wasmContract{}
+	void := c.typ([]byte{0x7d}, nil)
+	c.function(void, "main")
+	_, err := wasm.DecodeModule(c.bytes())
+	assert.Equal(t, wasm.ErrFloatNotAllowed, err)
+
+	assert.True(t, wasm.IsWASM(c.bytes()))
+	assert.False(t, wasm.IsWASM([]byte{0x60, 0x80, 0x60, 0x40}))
+}
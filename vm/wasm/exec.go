@@ -0,0 +1,744 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package wasm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+const (
+	GasInstruction uint64 = 1
+	GasMemoryPage  uint64 = 512
+	GasCopyWord    uint64 = 3
+
+	maxCallDepth = 1024
+	// maxValues bounds the values held by the stacks and the locals of all
+	// the running functions, those of nested contract calls included.
+	maxValues = 65536
+)
+
+var (
+	ErrUnreachable         = errors.New("wasm: unreachable executed")
+	ErrStackUnderflow      = errors.New("wasm: stack underflow")
+	ErrStackOverflow       = errors.New("wasm: stack overflow")
+	ErrCallStackExhausted  = errors.New("wasm: call stack exhausted")
+	ErrMemoryOutOfBounds   = errors.New("wasm: memory access out of bounds")
+	ErrIntegerDivideByZero = errors.New("wasm: integer divide by zero")
+	ErrIntegerOverflow     = errors.New("wasm: integer overflow")
+	ErrUndefinedElement    = errors.New("wasm: undefined table element")
+	ErrIndirectCallType    = errors.New("wasm: indirect call type mismatch")
+	ErrInvalidIndex        = errors.New("wasm: index out of range")
+	ErrImmutableGlobal     = errors.New("wasm: global is immutable")
+	ErrExportNotFound      = errors.New("wasm: export not found")
+	ErrImportNotFound      = errors.New("wasm: import not found")
+)
+
+// GasMeter is charged for each executed instruction. vmtypes.Contract
+// implements it.
+type GasMeter interface {
+	UseGas(gas uint64) bool
+}
+
+// HostFunction is a function the host provides to a module. Call traps
+// the execution by calling Instance.Trap.
+type HostFunction struct {
+	Type FuncType
+	Call func(inst *Instance, args []uint64) []uint64
+}
+
+// Resolver returns the host function imported by a module, nil if there is
+// no such function.
+type Resolver func(module string, name string) *HostFunction
+
+// Frames counts the values held by the stacks and the locals of the
+// running functions. The instances of nested contract calls share it so
+// their memory is bounded together, not per function.
+type Frames struct {
+	values int
+}
+
+func (f *Frames) grow(inst *Instance, n int) {
+	if f.values+n > maxValues {
+		inst.Trap(ErrStackOverflow)
+	}
+	f.values += n
+}
+
+// trap aborts the execution of an instance. It is raised as a panic so
+// the interpreter loop doesn't check an error after each instruction.
+type trap struct {
+	err error
+}
+
+// Instance is a module instantiated with its memory, globals and table.
+type Instance struct {
+	module    *Module
+	hosts     []*HostFunction
+	memory    []byte
+	memoryMax uint32
+	globals   []uint64
+	table     []int64
+	meter     GasMeter
+	frames    *Frames
+	depth     int
+}
+
+// Instantiate links the imports of the module to host functions, charges
+// its initial memory and runs its start function. frames is shared with
+// the instances running below in the call stack, a new one is used if nil.
+func Instantiate(m *Module, resolve Resolver, meter GasMeter, frames *Frames) (inst *Instance, err error) {
+	if frames == nil {
+		frames = &Frames{}
+	}
+	inst = &Instance{module: m, meter: meter, frames: frames}
+	for _, imp := range m.Imports {
+		host := resolve(imp.Module, imp.Name)
+		if host == nil {
+			return nil, fmt.Errorf("%v: %s.%s", ErrImportNotFound, imp.Module, imp.Name)
+		}
+		if !host.Type.equal(&m.Types[imp.TypeIndex]) {
+			return nil, fmt.Errorf("%v: %s.%s signature mismatch", ErrInvalidModule, imp.Module, imp.Name)
+		}
+		inst.hosts = append(inst.hosts, host)
+	}
+	defer inst.recoverTrap(&err)
+
+	if m.HasMemory {
+		inst.useGas(GasMemoryPage * uint64(m.MemoryMin))
+		inst.memory = make([]byte, int(m.MemoryMin)*pageSize)
+		inst.memoryMax = m.MemoryMax
+	}
+	for _, seg := range m.Data {
+		copy(inst.Memory(seg.Offset, uint32(len(seg.Data))), seg.Data)
+	}
+	for _, g := range m.Globals {
+		inst.globals = append(inst.globals, g.Init)
+	}
+	if m.HasTable {
+		inst.table = make([]int64, m.TableSize)
+		for i := range inst.table {
+			inst.table[i] = -1
+		}
+	}
+	for _, seg := range m.Elements {
+		if uint64(seg.Offset)+uint64(len(seg.Indices)) > uint64(len(inst.table)) {
+			inst.Trap(ErrUndefinedElement)
+		}
+		for i, idx := range seg.Indices {
+			if _, ok := m.funcType(idx); !ok {
+				inst.Trap(ErrInvalidIndex)
+			}
+			inst.table[int(seg.Offset)+i] = int64(idx)
+		}
+	}
+	if m.HasStart {
+		inst.call(m.StartIndex, nil)
+	}
+	return inst, nil
+}
+
+// HasExport tells if the module exports a function of the name.
+func (inst *Instance) HasExport(name string) bool {
+	export, ok := inst.module.Exports[name]
+	return ok && export.Kind == externalFunction
+}
+
+// Invoke calls the exported function of the name.
+func (inst *Instance) Invoke(name string, args ...uint64) (results []uint64, err error) {
+	if !inst.HasExport(name) {
+		return nil, fmt.Errorf("%v: %s", ErrExportNotFound, name)
+	}
+	idx := inst.module.Exports[name].Index
+	typ, _ := inst.module.funcType(idx)
+	if len(args) != len(typ.Params) {
+		return nil, fmt.Errorf("%v: %s expects %d arguments", ErrInvalidModule, name, len(typ.Params))
+	}
+	defer inst.recoverTrap(&err)
+	return inst.call(idx, args), nil
+}
+
+func (inst *Instance) recoverTrap(err *error) {
+	if r := recover(); r != nil {
+		t, ok := r.(trap)
+		if !ok {
+			panic(r)
+		}
+		*err = t.err
+	}
+}
+
+// Trap aborts the execution with err.
+func (inst *Instance) Trap(err error) {
+	panic(trap{err})
+}
+
+// Memory returns the size bytes of the memory at offset.
+func (inst *Instance) Memory(offset uint32, size uint32) []byte {
+	end := uint64(offset) + uint64(size)
+	if end > uint64(len(inst.memory)) {
+		inst.Trap(ErrMemoryOutOfBounds)
+	}
+	return inst.memory[offset:end]
+}
+
+// UseGas charges gas to the meter of the instance.
+func (inst *Instance) UseGas(gas uint64) {
+	inst.useGas(gas)
+}
+
+func (inst *Instance) useGas(gas uint64) {
+	if !inst.meter.UseGas(gas) {
+		inst.Trap(vmtypes.ErrOutOfGas)
+	}
+}
+
+func (inst *Instance) call(idx uint32, args []uint64) []uint64 {
+	if int(idx) < len(inst.hosts) {
+		return inst.hosts[idx].Call(inst, args)
+	}
+	fidx := int(idx) - len(inst.hosts)
+	if fidx >= len(inst.module.Functions) {
+		inst.Trap(ErrInvalidIndex)
+	}
+	if inst.depth >= maxCallDepth {
+		inst.Trap(ErrCallStackExhausted)
+	}
+	inst.depth++
+	base := inst.frames.values
+	defer func() {
+		inst.depth--
+		inst.frames.values = base
+	}()
+
+	f := inst.module.Functions[fidx]
+	typ := &inst.module.Types[f.TypeIndex]
+	inst.frames.grow(inst, len(typ.Params)+len(f.Locals))
+	locals := make([]uint64, len(typ.Params)+len(f.Locals))
+	copy(locals, args)
+	return inst.execute(f, typ, locals)
+}
+
+// label is the target of a branch.
+type label struct {
+	// pc is where the execution continues after a branch, the loop
+	// instruction itself for loops, past the end of the block otherwise.
+	pc     int
+	height int
+	arity  int
+}
+
+type stack struct {
+	inst   *Instance
+	values []uint64
+}
+
+func (s *stack) push(v uint64) {
+	s.inst.frames.grow(s.inst, 1)
+	s.values = append(s.values, v)
+}
+
+func (s *stack) pop() uint64 {
+	if len(s.values) == 0 {
+		s.inst.Trap(ErrStackUnderflow)
+	}
+	v := s.values[len(s.values)-1]
+	s.values = s.values[:len(s.values)-1]
+	s.inst.frames.values--
+	return v
+}
+
+func (s *stack) pop32() uint32 {
+	return uint32(s.pop())
+}
+
+// popN pops the n top values, the first of them being the deepest.
+func (s *stack) popN(n int) []uint64 {
+	if len(s.values) < n {
+		s.inst.Trap(ErrStackUnderflow)
+	}
+	values := make([]uint64, n)
+	copy(values, s.values[len(s.values)-n:])
+	s.values = s.values[:len(s.values)-n]
+	s.inst.frames.values -= n
+	return values
+}
+
+// unwind keeps the arity top values and drops the values above height.
+func (s *stack) unwind(height int, arity int) {
+	if height < 0 || len(s.values) < height+arity {
+		s.inst.Trap(ErrStackUnderflow)
+	}
+	copy(s.values[height:], s.values[len(s.values)-arity:])
+	s.inst.frames.values -= len(s.values) - height - arity
+	s.values = s.values[:height+arity]
+}
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (inst *Instance) execute(f *Function, typ *FuncType, locals []uint64) []uint64 {
+	code := f.Code
+	r := &reader{data: code}
+	s := &stack{inst: inst}
+	labels := []label{{pc: len(code), arity: len(typ.Results)}}
+
+	// immediates have been checked when the module was decoded.
+	u32 := func() uint32 {
+		v, _ := r.u32()
+		return v
+	}
+	branch := func(depth uint32) {
+		if int(depth) >= len(labels) {
+			inst.Trap(ErrInvalidIndex)
+		}
+		l := labels[len(labels)-1-int(depth)]
+		s.unwind(l.height, l.arity)
+		labels = labels[:len(labels)-1-int(depth)]
+		r.pos = l.pc
+	}
+	memarg := func() uint64 {
+		r.u32()
+		offset := u32()
+		return uint64(s.pop32()) + uint64(offset)
+	}
+	load := func(size uint64) []byte {
+		addr := memarg()
+		if addr+size > uint64(len(inst.memory)) {
+			inst.Trap(ErrMemoryOutOfBounds)
+		}
+		return inst.memory[addr : addr+size]
+	}
+	store := func(size uint64) ([]byte, uint64) {
+		v := s.pop()
+		addr := memarg()
+		if addr+size > uint64(len(inst.memory)) {
+			inst.Trap(ErrMemoryOutOfBounds)
+		}
+		return inst.memory[addr : addr+size], v
+	}
+	callFunc := func(idx uint32) {
+		ftyp, ok := inst.module.funcType(idx)
+		if !ok {
+			inst.Trap(ErrInvalidIndex)
+		}
+		for _, v := range inst.call(idx, s.popN(len(ftyp.Params))) {
+			s.push(v)
+		}
+	}
+
+	for r.pos < len(code) {
+		inst.useGas(GasInstruction)
+		pc := r.pos
+		op := code[pc]
+		r.pos++
+
+		switch op {
+		case opUnreachable:
+			inst.Trap(ErrUnreachable)
+		case opNop:
+		case opBlock, opLoop, opIf:
+			b := f.blocks[pc]
+			r.blockType(inst.module)
+			var cond uint32
+			if op == opIf {
+				cond = s.pop32()
+			}
+			l := label{pc: b.endPc + 1, height: len(s.values) - b.params, arity: b.results}
+			if op == opLoop {
+				l.pc, l.arity = pc, b.params
+			}
+			if l.height < 0 {
+				inst.Trap(ErrStackUnderflow)
+			}
+			labels = append(labels, l)
+			if op == opIf && cond == 0 {
+				if b.elsePc >= 0 {
+					r.pos = b.elsePc + 1
+				} else {
+					r.pos = b.endPc
+				}
+			}
+		case opElse:
+			// end of the then branch, the end instruction is right
+			// before the continuation of the if.
+			r.pos = labels[len(labels)-1].pc - 1
+		case opEnd:
+			labels = labels[:len(labels)-1]
+		case opBr:
+			branch(u32())
+		case opBrIf:
+			depth := u32()
+			if s.pop32() != 0 {
+				branch(depth)
+			}
+		case opBrTable:
+			n := u32()
+			i := s.pop32()
+			if i > n {
+				i = n
+			}
+			for j := uint32(0); j < i; j++ {
+				r.u32()
+			}
+			branch(u32())
+		case opReturn:
+			branch(uint32(len(labels) - 1))
+		case opCall:
+			callFunc(u32())
+		case opCallIndirect:
+			typeIdx := u32()
+			r.pos++
+			i := s.pop32()
+			if int(i) >= len(inst.table) || inst.table[i] < 0 {
+				inst.Trap(ErrUndefinedElement)
+			}
+			idx := uint32(inst.table[i])
+			ftyp, _ := inst.module.funcType(idx)
+			if int(typeIdx) >= len(inst.module.Types) || !ftyp.equal(&inst.module.Types[typeIdx]) {
+				inst.Trap(ErrIndirectCallType)
+			}
+			callFunc(idx)
+
+		case opDrop:
+			s.pop()
+		case opSelect, opSelectTyped:
+			if op == opSelectTyped {
+				r.valueTypes()
+			}
+			c, b, a := s.pop32(), s.pop(), s.pop()
+			if c != 0 {
+				s.push(a)
+			} else {
+				s.push(b)
+			}
+
+		case opLocalGet, opLocalSet, opLocalTee:
+			i := u32()
+			if int(i) >= len(locals) {
+				inst.Trap(ErrInvalidIndex)
+			}
+			switch op {
+			case opLocalGet:
+				s.push(locals[i])
+			case opLocalSet:
+				locals[i] = s.pop()
+			case opLocalTee:
+				v := s.pop()
+				locals[i] = v
+				s.push(v)
+			}
+		case opGlobalGet, opGlobalSet:
+			i := u32()
+			if int(i) >= len(inst.globals) {
+				inst.Trap(ErrInvalidIndex)
+			}
+			if op == opGlobalGet {
+				s.push(inst.globals[i])
+			} else {
+				if !inst.module.Globals[i].Mutable {
+					inst.Trap(ErrImmutableGlobal)
+				}
+				inst.globals[i] = s.pop()
+			}
+
+		case opI32Load:
+			s.push(uint64(binary.LittleEndian.Uint32(load(4))))
+		case opI64Load:
+			s.push(binary.LittleEndian.Uint64(load(8)))
+		case opI32Load8S:
+			s.push(uint64(uint32(int32(int8(load(1)[0])))))
+		case opI32Load8U:
+			s.push(uint64(load(1)[0]))
+		case opI32Load16S:
+			s.push(uint64(uint32(int32(int16(binary.LittleEndian.Uint16(load(2)))))))
+		case opI32Load16U:
+			s.push(uint64(binary.LittleEndian.Uint16(load(2))))
+		case opI64Load8S:
+			s.push(uint64(int64(int8(load(1)[0]))))
+		case opI64Load8U:
+			s.push(uint64(load(1)[0]))
+		case opI64Load16S:
+			s.push(uint64(int64(int16(binary.LittleEndian.Uint16(load(2))))))
+		case opI64Load16U:
+			s.push(uint64(binary.LittleEndian.Uint16(load(2))))
+		case opI64Load32S:
+			s.push(uint64(int64(int32(binary.LittleEndian.Uint32(load(4))))))
+		case opI64Load32U:
+			s.push(uint64(binary.LittleEndian.Uint32(load(4))))
+		case opI32Store, opI64Store32:
+			m, v := store(4)
+			binary.LittleEndian.PutUint32(m, uint32(v))
+		case opI64Store:
+			m, v := store(8)
+			binary.LittleEndian.PutUint64(m, v)
+		case opI32Store8, opI64Store8:
+			m, v := store(1)
+			m[0] = byte(v)
+		case opI32Store16, opI64Store16:
+			m, v := store(2)
+			binary.LittleEndian.PutUint16(m, uint16(v))
+		case opMemorySize:
+			r.pos++
+			s.push(uint64(len(inst.memory) / pageSize))
+		case opMemoryGrow:
+			r.pos++
+			delta := s.pop32()
+			pages := uint32(len(inst.memory) / pageSize)
+			if !inst.module.HasMemory || uint64(pages)+uint64(delta) > uint64(inst.memoryMax) {
+				s.push(uint64(^uint32(0)))
+				break
+			}
+			inst.useGas(GasMemoryPage * uint64(delta))
+			inst.memory = append(inst.memory, make([]byte, int(delta)*pageSize)...)
+			s.push(uint64(pages))
+
+		case opI32Const:
+			v, _ := r.i32()
+			s.push(uint64(uint32(v)))
+		case opI64Const:
+			v, _ := r.i64()
+			s.push(uint64(v))
+
+		case opI32Eqz:
+			s.push(b2u(s.pop32() == 0))
+		case opI64Eqz:
+			s.push(b2u(s.pop() == 0))
+		case opI32Eq, opI32Ne, opI32LtS, opI32LtU, opI32GtS, opI32GtU, opI32LeS, opI32LeU, opI32GeS, opI32GeU:
+			b, a := s.pop32(), s.pop32()
+			s.push(b2u(compare32(op, a, b)))
+		case opI64Eq, opI64Ne, opI64LtS, opI64LtU, opI64GtS, opI64GtU, opI64LeS, opI64LeU, opI64GeS, opI64GeU:
+			b, a := s.pop(), s.pop()
+			s.push(b2u(compare64(op, a, b)))
+
+		case opI32Clz:
+			s.push(uint64(bits.LeadingZeros32(s.pop32())))
+		case opI32Ctz:
+			s.push(uint64(bits.TrailingZeros32(s.pop32())))
+		case opI32Popcnt:
+			s.push(uint64(bits.OnesCount32(s.pop32())))
+		case opI64Clz:
+			s.push(uint64(bits.LeadingZeros64(s.pop())))
+		case opI64Ctz:
+			s.push(uint64(bits.TrailingZeros64(s.pop())))
+		case opI64Popcnt:
+			s.push(uint64(bits.OnesCount64(s.pop())))
+		case opI32Add, opI32Sub, opI32Mul, opI32DivS, opI32DivU, opI32RemS, opI32RemU,
+			opI32And, opI32Or, opI32Xor, opI32Shl, opI32ShrS, opI32ShrU, opI32Rotl, opI32Rotr:
+			b, a := s.pop32(), s.pop32()
+			v, err := binary32(op, a, b)
+			if err != nil {
+				inst.Trap(err)
+			}
+			s.push(uint64(v))
+		case opI64Add, opI64Sub, opI64Mul, opI64DivS, opI64DivU, opI64RemS, opI64RemU,
+			opI64And, opI64Or, opI64Xor, opI64Shl, opI64ShrS, opI64ShrU, opI64Rotl, opI64Rotr:
+			b, a := s.pop(), s.pop()
+			v, err := binary64(op, a, b)
+			if err != nil {
+				inst.Trap(err)
+			}
+			s.push(v)
+
+		case opI32WrapI64, opI64ExtendI32U:
+			s.push(uint64(s.pop32()))
+		case opI64ExtendI32S:
+			s.push(uint64(int64(int32(s.pop32()))))
+		case opI32Extend8S:
+			s.push(uint64(uint32(int32(int8(s.pop())))))
+		case opI32Extend16S:
+			s.push(uint64(uint32(int32(int16(s.pop())))))
+		case opI64Extend8S:
+			s.push(uint64(int64(int8(s.pop()))))
+		case opI64Extend16S:
+			s.push(uint64(int64(int16(s.pop()))))
+		case opI64Extend32S:
+			s.push(uint64(int64(int32(s.pop()))))
+
+		case opPrefixFC:
+			switch u32() {
+			case opMemoryCopy:
+				r.pos += 2
+				n, src, dst := s.pop32(), s.pop32(), s.pop32()
+				inst.useGas(GasCopyWord * ((uint64(n) + 31) / 32))
+				copy(inst.Memory(dst, n), inst.Memory(src, n))
+			case opMemoryFill:
+				r.pos++
+				n, v, dst := s.pop32(), s.pop32(), s.pop32()
+				inst.useGas(GasCopyWord * ((uint64(n) + 31) / 32))
+				m := inst.Memory(dst, n)
+				for i := range m {
+					m[i] = byte(v)
+				}
+			default:
+				inst.Trap(ErrUnsupported)
+			}
+		default:
+			inst.Trap(ErrUnsupported)
+		}
+	}
+	return s.popN(len(typ.Results))
+}
+
+func compare32(op byte, a uint32, b uint32) bool {
+	switch op {
+	case opI32Eq:
+		return a == b
+	case opI32Ne:
+		return a != b
+	case opI32LtS:
+		return int32(a) < int32(b)
+	case opI32LtU:
+		return a < b
+	case opI32GtS:
+		return int32(a) > int32(b)
+	case opI32GtU:
+		return a > b
+	case opI32LeS:
+		return int32(a) <= int32(b)
+	case opI32LeU:
+		return a <= b
+	case opI32GeS:
+		return int32(a) >= int32(b)
+	}
+	return a >= b
+}
+
+func compare64(op byte, a uint64, b uint64) bool {
+	switch op {
+	case opI64Eq:
+		return a == b
+	case opI64Ne:
+		return a != b
+	case opI64LtS:
+		return int64(a) < int64(b)
+	case opI64LtU:
+		return a < b
+	case opI64GtS:
+		return int64(a) > int64(b)
+	case opI64GtU:
+		return a > b
+	case opI64LeS:
+		return int64(a) <= int64(b)
+	case opI64LeU:
+		return a <= b
+	case opI64GeS:
+		return int64(a) >= int64(b)
+	}
+	return a >= b
+}
+
+func binary32(op byte, a uint32, b uint32) (uint32, error) {
+	switch op {
+	case opI32Add:
+		return a + b, nil
+	case opI32Sub:
+		return a - b, nil
+	case opI32Mul:
+		return a * b, nil
+	case opI32DivS, opI32RemS:
+		if b == 0 {
+			return 0, ErrIntegerDivideByZero
+		}
+		if int32(a) == -1<<31 && int32(b) == -1 {
+			if op == opI32DivS {
+				return 0, ErrIntegerOverflow
+			}
+			return 0, nil
+		}
+		if op == opI32DivS {
+			return uint32(int32(a) / int32(b)), nil
+		}
+		return uint32(int32(a) % int32(b)), nil
+	case opI32DivU, opI32RemU:
+		if b == 0 {
+			return 0, ErrIntegerDivideByZero
+		}
+		if op == opI32DivU {
+			return a / b, nil
+		}
+		return a % b, nil
+	case opI32And:
+		return a & b, nil
+	case opI32Or:
+		return a | b, nil
+	case opI32Xor:
+		return a ^ b, nil
+	case opI32Shl:
+		return a << (b % 32), nil
+	case opI32ShrS:
+		return uint32(int32(a) >> (b % 32)), nil
+	case opI32ShrU:
+		return a >> (b % 32), nil
+	case opI32Rotl:
+		return bits.RotateLeft32(a, int(b%32)), nil
+	}
+	return bits.RotateLeft32(a, -int(b%32)), nil
+}
+
+func binary64(op byte, a uint64, b uint64) (uint64, error) {
+	switch op {
+	case opI64Add:
+		return a + b, nil
+	case opI64Sub:
+		return a - b, nil
+	case opI64Mul:
+		return a * b, nil
+	case opI64DivS, opI64RemS:
+		if b == 0 {
+			return 0, ErrIntegerDivideByZero
+		}
+		if int64(a) == -1<<63 && int64(b) == -1 {
+			if op == opI64DivS {
+				return 0, ErrIntegerOverflow
+			}
+			return 0, nil
+		}
+		if op == opI64DivS {
+			return uint64(int64(a) / int64(b)), nil
+		}
+		return uint64(int64(a) % int64(b)), nil
+	case opI64DivU, opI64RemU:
+		if b == 0 {
+			return 0, ErrIntegerDivideByZero
+		}
+		if op == opI64DivU {
+			return a / b, nil
+		}
+		return a % b, nil
+	case opI64And:
+		return a & b, nil
+	case opI64Or:
+		return a | b, nil
+	case opI64Xor:
+		return a ^ b, nil
+	case opI64Shl:
+		return a << (b % 64), nil
+	case opI64ShrS:
+		return uint64(int64(a) >> (b % 64)), nil
+	case opI64ShrU:
+		return a >> (b % 64), nil
+	case opI64Rotl:
+		return bits.RotateLeft64(a, int(b%64)), nil
+	}
+	return bits.RotateLeft64(a, -int(b%64)), nil
+}
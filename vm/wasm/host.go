@@ -0,0 +1,327 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package wasm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/annchain/OG/common"
+	ogmath "github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/vm/eth/common/math"
+	"github.com/annchain/OG/vm/eth/params"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+// HostModule is the module name the contracts import the host functions
+// from. Addresses take 20 bytes of memory, keys, values and amounts 32
+// bytes, the amounts being big endian:
+//
+//	storage_load(key_ptr i32, result_ptr i32)
+//	storage_store(key_ptr i32, value_ptr i32)
+//	get_balance(addr_ptr i32, result_ptr i32)
+//	get_caller(result_ptr i32)
+//	get_address(result_ptr i32)
+//	get_call_value(result_ptr i32)
+//	get_input_size() i32
+//	copy_input(result_ptr i32, offset i32, length i32)
+//	get_return_data_size() i32
+//	copy_return_data(result_ptr i32, offset i32, length i32)
+//	call(gas i64, addr_ptr i32, value_ptr i32, input_ptr i32, input_len i32) i32
+//	    returns 0 on success, 1 on failure and 2 if the callee reverted.
+//	log(data_ptr i32, data_len i32, topic_count i32, topics_ptr i32)
+//	finish(data_ptr i32, data_len i32)
+//	revert(data_ptr i32, data_len i32)
+//	get_gas_left() i64
+//	get_sequencer_height() i64
+//	get_timestamp() i64
+const HostModule = "env"
+
+const (
+	GasHostBase    uint64 = 2
+	GasStorageLoad uint64 = 200
+	GasBalance     uint64 = 400
+	GasCall        uint64 = 700
+	maxLogTopics          = 4
+)
+
+const (
+	callSuccess  = 0
+	callFailure  = 1
+	callReverted = 2
+)
+
+var (
+	// errStop is raised by finish and revert to end the execution.
+	errStop          = errors.New("wasm: stop")
+	errTooManyTopics = errors.New("wasm: too many log topics")
+)
+
+type hostFunc struct {
+	typ FuncType
+	fn  func(env *hostEnv, inst *Instance, args []uint64) []uint64
+}
+
+var (
+	i32 = ValueTypeI32
+	i64 = ValueTypeI64
+)
+
+var hostFuncs = map[string]hostFunc{
+	"storage_load":         {FuncType{Params: []ValueType{i32, i32}}, (*hostEnv).storageLoad},
+	"storage_store":        {FuncType{Params: []ValueType{i32, i32}}, (*hostEnv).storageStore},
+	"get_balance":          {FuncType{Params: []ValueType{i32, i32}}, (*hostEnv).getBalance},
+	"get_caller":           {FuncType{Params: []ValueType{i32}}, (*hostEnv).getCaller},
+	"get_address":          {FuncType{Params: []ValueType{i32}}, (*hostEnv).getAddress},
+	"get_call_value":       {FuncType{Params: []ValueType{i32}}, (*hostEnv).getCallValue},
+	"get_input_size":       {FuncType{Results: []ValueType{i32}}, (*hostEnv).getInputSize},
+	"copy_input":           {FuncType{Params: []ValueType{i32, i32, i32}}, (*hostEnv).copyInput},
+	"get_return_data_size": {FuncType{Results: []ValueType{i32}}, (*hostEnv).getReturnDataSize},
+	"copy_return_data":     {FuncType{Params: []ValueType{i32, i32, i32}}, (*hostEnv).copyReturnData},
+	"call":                 {FuncType{Params: []ValueType{i64, i32, i32, i32, i32}, Results: []ValueType{i32}}, (*hostEnv).call},
+	"log":                  {FuncType{Params: []ValueType{i32, i32, i32, i32}}, (*hostEnv).log},
+	"finish":               {FuncType{Params: []ValueType{i32, i32}}, (*hostEnv).finish},
+	"revert":               {FuncType{Params: []ValueType{i32, i32}}, (*hostEnv).revert},
+	"get_gas_left":         {FuncType{Results: []ValueType{i64}}, (*hostEnv).getGasLeft},
+	"get_sequencer_height": {FuncType{Results: []ValueType{i64}}, (*hostEnv).getSequencerHeight},
+	"get_timestamp":        {FuncType{Results: []ValueType{i64}}, (*hostEnv).getTimestamp},
+}
+
+// hostEnv is the environment of a contract execution the host functions
+// work on.
+type hostEnv struct {
+	in       *WASMInterpreter
+	contract *vmtypes.Contract
+	input    []byte
+	output   []byte
+	reverted bool
+}
+
+func (env *hostEnv) resolve(module string, name string) *HostFunction {
+	if module != HostModule {
+		return nil
+	}
+	f, ok := hostFuncs[name]
+	if !ok {
+		return nil
+	}
+	return &HostFunction{
+		Type: f.typ,
+		Call: func(inst *Instance, args []uint64) []uint64 {
+			return f.fn(env, inst, args)
+		},
+	}
+}
+
+func (env *hostEnv) stateDB() vmtypes.StateDB {
+	return env.in.vmContext.StateDB
+}
+
+func (env *hostEnv) checkWrite(inst *Instance) {
+	if env.in.readOnly {
+		inst.Trap(vmtypes.ErrWriteProtection)
+	}
+}
+
+// copyOut charges the copy of length bytes of data at offset to the memory
+// at ptr.
+func copyOut(inst *Instance, ptr uint32, data []byte, offset uint32, length uint32) bool {
+	inst.UseGas(GasHostBase + GasCopyWord*((uint64(length)+31)/32))
+	end := uint64(offset) + uint64(length)
+	if end > uint64(len(data)) {
+		return false
+	}
+	copy(inst.Memory(ptr, length), data[offset:end])
+	return true
+}
+
+func (env *hostEnv) storageLoad(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasStorageLoad)
+	key := common.BytesToHash(inst.Memory(uint32(args[0]), 32))
+	value := env.stateDB().GetState(env.contract.Address(), key)
+	copy(inst.Memory(uint32(args[1]), 32), value.ToBytes())
+	return nil
+}
+
+func (env *hostEnv) storageStore(inst *Instance, args []uint64) []uint64 {
+	env.checkWrite(inst)
+	key := common.BytesToHash(inst.Memory(uint32(args[0]), 32))
+	value := common.BytesToHash(inst.Memory(uint32(args[1]), 32))
+	db := env.stateDB()
+	current := db.GetState(env.contract.Address(), key)
+	switch {
+	case current == (common.Hash{}) && value != (common.Hash{}):
+		inst.UseGas(params.SstoreSetGas)
+	case current != (common.Hash{}) && value == (common.Hash{}):
+		inst.UseGas(params.SstoreClearGas)
+		db.AddRefund(params.SstoreRefundGas)
+	default:
+		inst.UseGas(params.SstoreResetGas)
+	}
+	db.SetState(env.contract.Address(), key, value)
+	return nil
+}
+
+func (env *hostEnv) getBalance(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasBalance)
+	addr := common.BytesToAddress(inst.Memory(uint32(args[0]), common.AddressLength))
+	balance := env.stateDB().GetBalance(addr)
+	copy(inst.Memory(uint32(args[1]), 32), math.PaddedBigBytes(balance.Value, 32))
+	return nil
+}
+
+func (env *hostEnv) getCaller(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	copy(inst.Memory(uint32(args[0]), common.AddressLength), env.contract.Caller().ToBytes())
+	return nil
+}
+
+func (env *hostEnv) getAddress(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	copy(inst.Memory(uint32(args[0]), common.AddressLength), env.contract.Address().ToBytes())
+	return nil
+}
+
+func (env *hostEnv) getCallValue(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	copy(inst.Memory(uint32(args[0]), 32), math.PaddedBigBytes(env.contract.Value(), 32))
+	return nil
+}
+
+func (env *hostEnv) getInputSize(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	return []uint64{uint64(len(env.input))}
+}
+
+func (env *hostEnv) copyInput(inst *Instance, args []uint64) []uint64 {
+	if !copyOut(inst, uint32(args[0]), env.input, uint32(args[1]), uint32(args[2])) {
+		inst.Trap(ErrMemoryOutOfBounds)
+	}
+	return nil
+}
+
+func (env *hostEnv) getReturnDataSize(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	return []uint64{uint64(len(env.in.returnData))}
+}
+
+func (env *hostEnv) copyReturnData(inst *Instance, args []uint64) []uint64 {
+	if !copyOut(inst, uint32(args[0]), env.in.returnData, uint32(args[1]), uint32(args[2])) {
+		inst.Trap(vmtypes.ErrReturnDataOutOfBounds)
+	}
+	return nil
+}
+
+func (env *hostEnv) call(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasCall)
+	gas := args[0]
+	addr := common.BytesToAddress(inst.Memory(uint32(args[1]), common.AddressLength))
+	value := new(big.Int).SetBytes(inst.Memory(uint32(args[2]), 32))
+	input := common.CopyBytes(inst.Memory(uint32(args[3]), uint32(args[4])))
+	if value.Sign() != 0 {
+		env.checkWrite(inst)
+		inst.UseGas(params.CallValueTransferGas)
+	}
+	// all but one 64th of the gas left, as in EIP150.
+	contract := env.contract
+	if available := contract.Gas - contract.Gas/64; gas > available {
+		gas = available
+	}
+	inst.UseGas(gas)
+	if value.Sign() != 0 {
+		gas += params.CallStipend
+	}
+
+	var (
+		ret         []byte
+		leftOverGas uint64
+		err         error
+	)
+	// the write protection of a static call has to be passed on to the
+	// callee whatever interpreter runs it.
+	if env.in.readOnly {
+		ret, leftOverGas, err = env.in.caller.StaticCall(contract, addr, input, gas)
+	} else {
+		ret, leftOverGas, err = env.in.caller.Call(contract, addr, input, gas, value, false)
+	}
+	contract.Gas += leftOverGas
+	env.in.returnData = ret
+
+	switch err {
+	case nil:
+		return []uint64{callSuccess}
+	case vmtypes.ErrExecutionReverted:
+		return []uint64{callReverted}
+	}
+	return []uint64{callFailure}
+}
+
+func (env *hostEnv) log(inst *Instance, args []uint64) []uint64 {
+	env.checkWrite(inst)
+	data := inst.Memory(uint32(args[0]), uint32(args[1]))
+	count := uint32(args[2])
+	if count > maxLogTopics {
+		inst.Trap(errTooManyTopics)
+	}
+	inst.UseGas(params.LogGas + params.LogTopicGas*uint64(count) + params.LogDataGas*uint64(len(data)))
+	topicBytes := inst.Memory(uint32(args[3]), count*32)
+	topics := make(common.Hashes, count)
+	for i := range topics {
+		topics[i] = common.BytesToHash(topicBytes[i*32 : (i+1)*32])
+	}
+	env.stateDB().AddLog(&vmtypes.Log{
+		Address:    env.contract.Address(),
+		Topics:     topics,
+		Data:       common.CopyBytes(data),
+		SequenceID: env.in.txContext.SequenceID,
+	})
+	return nil
+}
+
+func (env *hostEnv) finish(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	env.output = common.CopyBytes(inst.Memory(uint32(args[0]), uint32(args[1])))
+	inst.Trap(errStop)
+	return nil
+}
+
+func (env *hostEnv) revert(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	env.output = common.CopyBytes(inst.Memory(uint32(args[0]), uint32(args[1])))
+	env.reverted = true
+	inst.Trap(errStop)
+	return nil
+}
+
+func (env *hostEnv) getGasLeft(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	return []uint64{env.contract.Gas}
+}
+
+func (env *hostEnv) getSequencerHeight(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	return []uint64{env.in.txContext.SequenceID}
+}
+
+func (env *hostEnv) getTimestamp(inst *Instance, args []uint64) []uint64 {
+	inst.UseGas(GasHostBase)
+	return []uint64{timestamp(env.in.txContext.Time)}
+}
+
+func timestamp(t *ogmath.BigInt) uint64 {
+	if t == nil || t.Value == nil || !t.Value.IsUint64() {
+		return 0
+	}
+	return t.Value.Uint64()
+}
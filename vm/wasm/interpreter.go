@@ -0,0 +1,101 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package wasm
+
+import (
+	"github.com/annchain/OG/vm/ovm"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+const (
+	// exported function run when a contract is created, optional.
+	deployEntry = "deploy"
+	// exported function run when a contract is called.
+	mainEntry = "main"
+)
+
+// WASMInterpreter runs the contracts compiled to WebAssembly. It has to be
+// placed before the EVM interpreter, which runs any code, in the
+// interpreters of the OVM.
+//
+// A wasm contract is created by a tx carrying the module itself, the
+// module is stored as the code of the contract once its deploy function
+// returned. Calls run its main function, which reads the input and sets
+// the output with the host functions described at HostModule.
+type WASMInterpreter struct {
+	vmContext *vmtypes.Context
+	txContext *ovm.TxContext
+	caller    vmtypes.Caller
+	frames    Frames
+
+	readOnly   bool
+	returnData []byte
+}
+
+func NewWASMInterpreter(vmContext *vmtypes.Context, txContext *ovm.TxContext) *WASMInterpreter {
+	return &WASMInterpreter{
+		vmContext: vmContext,
+		txContext: txContext,
+	}
+}
+
+func (in *WASMInterpreter) SetCaller(caller vmtypes.Caller) {
+	in.caller = caller
+}
+
+// CanRun tells if the code is a wasm module.
+func (in *WASMInterpreter) CanRun(code []byte) bool {
+	return IsWASM(code)
+}
+
+func (in *WASMInterpreter) Run(contract *vmtypes.Contract, input []byte, readOnly bool) ([]byte, error) {
+	in.vmContext.Depth++
+	defer func() { in.vmContext.Depth-- }()
+
+	if readOnly && !in.readOnly {
+		in.readOnly = true
+		defer func() { in.readOnly = false }()
+	}
+	in.returnData = nil
+
+	module, err := DecodeModule(contract.Code)
+	if err != nil {
+		return nil, err
+	}
+	env := &hostEnv{in: in, contract: contract, input: input}
+	inst, err := Instantiate(module, env.resolve, contract, &in.frames)
+	if err != nil {
+		return nil, err
+	}
+
+	// the code of a contract being created is not stored yet.
+	creating := in.vmContext.StateDB.GetCodeSize(contract.Address()) == 0
+	entry := mainEntry
+	if creating {
+		if !inst.HasExport(deployEntry) {
+			return contract.Code, nil
+		}
+		entry = deployEntry
+	}
+	if _, err = inst.Invoke(entry); err != nil && err != errStop {
+		return nil, err
+	}
+	if env.reverted {
+		return env.output, vmtypes.ErrExecutionReverted
+	}
+	if creating {
+		return contract.Code, nil
+	}
+	return env.output, nil
+}
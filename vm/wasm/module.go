@@ -0,0 +1,860 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var (
+	wasmMagic   = []byte{0x00, 0x61, 0x73, 0x6d}
+	wasmVersion = []byte{0x01, 0x00, 0x00, 0x00}
+)
+
+// IsWASM tells if the code is a WebAssembly binary module.
+func IsWASM(code []byte) bool {
+	return len(code) >= 8 && bytes.Equal(code[:4], wasmMagic) && bytes.Equal(code[4:8], wasmVersion)
+}
+
+// ValueType is the type of a wasm value. Only the integer types are
+// supported, floats are rejected as their results are not deterministic
+// across platforms.
+type ValueType byte
+
+const (
+	ValueTypeI32 ValueType = 0x7f
+	ValueTypeI64 ValueType = 0x7e
+)
+
+const (
+	sectionCustom    = 0
+	sectionType      = 1
+	sectionImport    = 2
+	sectionFunction  = 3
+	sectionTable     = 4
+	sectionMemory    = 5
+	sectionGlobal    = 6
+	sectionExport    = 7
+	sectionStart     = 8
+	sectionElement   = 9
+	sectionCode      = 10
+	sectionData      = 11
+	sectionDataCount = 12
+)
+
+const (
+	externalFunction = 0x00
+	externalTable    = 0x01
+	externalMemory   = 0x02
+	externalGlobal   = 0x03
+)
+
+const (
+	pageSize = 65536
+	// MaxMemoryPages caps the memory of a contract instance to 16MB.
+	MaxMemoryPages = 256
+	maxTableSize   = 65536
+	maxLocals      = 4096
+)
+
+var (
+	ErrInvalidModule   = errors.New("wasm: invalid module")
+	ErrUnsupported     = errors.New("wasm: unsupported feature")
+	ErrFloatNotAllowed = errors.New("wasm: floating point not allowed")
+)
+
+type FuncType struct {
+	Params  []ValueType
+	Results []ValueType
+}
+
+func (t *FuncType) equal(o *FuncType) bool {
+	return bytes.Equal(valueTypeBytes(t.Params), valueTypeBytes(o.Params)) &&
+		bytes.Equal(valueTypeBytes(t.Results), valueTypeBytes(o.Results))
+}
+
+func valueTypeBytes(types []ValueType) []byte {
+	b := make([]byte, len(types))
+	for i, t := range types {
+		b[i] = byte(t)
+	}
+	return b
+}
+
+// Import is an imported function. Imports of tables, memories and globals
+// are not supported.
+type Import struct {
+	Module    string
+	Name      string
+	TypeIndex uint32
+}
+
+type Global struct {
+	Type    ValueType
+	Mutable bool
+	Init    uint64
+}
+
+type Export struct {
+	Kind  byte
+	Index uint32
+}
+
+// Function is a function defined in the module.
+type Function struct {
+	TypeIndex uint32
+	Locals    []ValueType
+	Code      []byte
+
+	// blocks maps the position of each block, loop and if instruction to
+	// its else, end and block type.
+	blocks map[int]*blockInfo
+}
+
+type blockInfo struct {
+	elsePc  int
+	endPc   int
+	params  int
+	results int
+}
+
+type ElementSegment struct {
+	Offset  uint32
+	Indices []uint32
+}
+
+type DataSegment struct {
+	Offset uint32
+	Data   []byte
+}
+
+// Module is a decoded wasm binary module.
+type Module struct {
+	Types     []FuncType
+	Imports   []Import
+	Functions []*Function
+	Globals   []Global
+	Exports   map[string]Export
+	Elements  []ElementSegment
+	Data      []DataSegment
+
+	HasTable  bool
+	TableSize uint32
+
+	HasMemory   bool
+	MemoryMin   uint32
+	MemoryMax   uint32
+	HasStart    bool
+	StartIndex  uint32
+	funcTypeIdx []uint32
+}
+
+// funcType returns the type of the function at idx of the function index
+// space, which holds the imports first.
+func (m *Module) funcType(idx uint32) (*FuncType, bool) {
+	if int(idx) < len(m.Imports) {
+		return &m.Types[m.Imports[idx].TypeIndex], true
+	}
+	idx -= uint32(len(m.Imports))
+	if int(idx) >= len(m.Functions) {
+		return nil, false
+	}
+	return &m.Types[m.Functions[idx].TypeIndex], true
+}
+
+// DecodeModule decodes and checks a wasm binary module.
+func DecodeModule(code []byte) (*Module, error) {
+	if !IsWASM(code) {
+		return nil, ErrInvalidModule
+	}
+	m := &Module{Exports: make(map[string]Export)}
+	r := &reader{data: code, pos: 8}
+	lastID := byte(0)
+	for r.pos < len(r.data) {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		if id != sectionCustom {
+			// sections other than the custom ones must appear once and
+			// in order, the data count section sits before the code.
+			order := sectionOrder(id)
+			if order <= sectionOrder(lastID) && lastID != sectionCustom {
+				return nil, fmt.Errorf("%v: section %d out of order", ErrInvalidModule, id)
+			}
+			lastID = id
+		}
+		if err := m.decodeSection(id, &reader{data: payload}); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.funcTypeIdx) != len(m.Functions) {
+		return nil, fmt.Errorf("%v: function and code sections mismatch", ErrInvalidModule)
+	}
+	for _, f := range m.Functions {
+		if err := m.analyze(f); err != nil {
+			return nil, err
+		}
+	}
+	if m.HasStart {
+		typ, ok := m.funcType(m.StartIndex)
+		if !ok || len(typ.Params) != 0 || len(typ.Results) != 0 {
+			return nil, fmt.Errorf("%v: invalid start function", ErrInvalidModule)
+		}
+	}
+	for name, export := range m.Exports {
+		if export.Kind == externalFunction {
+			if _, ok := m.funcType(export.Index); !ok {
+				return nil, fmt.Errorf("%v: export %s out of range", ErrInvalidModule, name)
+			}
+		}
+	}
+	return m, nil
+}
+
+// sectionOrder returns the rank of a section, the data count section is
+// placed between the element and the code sections.
+func sectionOrder(id byte) int {
+	if id == sectionDataCount {
+		return sectionCode*2 - 1
+	}
+	return int(id) * 2
+}
+
+func (m *Module) decodeSection(id byte, r *reader) error {
+	var err error
+	switch id {
+	case sectionCustom:
+		return nil
+	case sectionType:
+		err = m.decodeTypes(r)
+	case sectionImport:
+		err = m.decodeImports(r)
+	case sectionFunction:
+		err = r.vec(func() error {
+			idx, err := r.u32()
+			if err != nil {
+				return err
+			}
+			if int(idx) >= len(m.Types) {
+				return fmt.Errorf("%v: type index out of range", ErrInvalidModule)
+			}
+			m.funcTypeIdx = append(m.funcTypeIdx, idx)
+			return nil
+		})
+	case sectionTable:
+		err = m.decodeTable(r)
+	case sectionMemory:
+		err = m.decodeMemory(r)
+	case sectionGlobal:
+		err = r.vec(func() error {
+			typ, err := r.valueType()
+			if err != nil {
+				return err
+			}
+			mut, err := r.byte()
+			if err != nil {
+				return err
+			}
+			if mut > 1 {
+				return ErrInvalidModule
+			}
+			init, err := r.constExpr(typ)
+			if err != nil {
+				return err
+			}
+			m.Globals = append(m.Globals, Global{Type: typ, Mutable: mut == 1, Init: init})
+			return nil
+		})
+	case sectionExport:
+		err = r.vec(func() error {
+			name, err := r.name()
+			if err != nil {
+				return err
+			}
+			kind, err := r.byte()
+			if err != nil {
+				return err
+			}
+			idx, err := r.u32()
+			if err != nil {
+				return err
+			}
+			if _, ok := m.Exports[name]; ok {
+				return fmt.Errorf("%v: duplicate export %s", ErrInvalidModule, name)
+			}
+			m.Exports[name] = Export{Kind: kind, Index: idx}
+			return nil
+		})
+	case sectionStart:
+		m.HasStart = true
+		m.StartIndex, err = r.u32()
+	case sectionElement:
+		err = m.decodeElements(r)
+	case sectionCode:
+		err = m.decodeCode(r)
+	case sectionData:
+		err = m.decodeData(r)
+	case sectionDataCount:
+		_, err = r.u32()
+	default:
+		return fmt.Errorf("%v: unknown section %d", ErrInvalidModule, id)
+	}
+	if err != nil {
+		return err
+	}
+	if r.pos != len(r.data) {
+		return fmt.Errorf("%v: section %d size mismatch", ErrInvalidModule, id)
+	}
+	return nil
+}
+
+func (m *Module) decodeTypes(r *reader) error {
+	return r.vec(func() error {
+		form, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if form != 0x60 {
+			return fmt.Errorf("%v: invalid func type", ErrInvalidModule)
+		}
+		var typ FuncType
+		if typ.Params, err = r.valueTypes(); err != nil {
+			return err
+		}
+		if typ.Results, err = r.valueTypes(); err != nil {
+			return err
+		}
+		m.Types = append(m.Types, typ)
+		return nil
+	})
+}
+
+func (m *Module) decodeImports(r *reader) error {
+	return r.vec(func() error {
+		var imp Import
+		var err error
+		if imp.Module, err = r.name(); err != nil {
+			return err
+		}
+		if imp.Name, err = r.name(); err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if kind != externalFunction {
+			return fmt.Errorf("%v: import of %s.%s is not a function", ErrUnsupported, imp.Module, imp.Name)
+		}
+		if imp.TypeIndex, err = r.u32(); err != nil {
+			return err
+		}
+		if int(imp.TypeIndex) >= len(m.Types) {
+			return fmt.Errorf("%v: type index out of range", ErrInvalidModule)
+		}
+		m.Imports = append(m.Imports, imp)
+		return nil
+	})
+}
+
+func (m *Module) decodeTable(r *reader) error {
+	return r.vec(func() error {
+		if m.HasTable {
+			return fmt.Errorf("%v: multiple tables", ErrUnsupported)
+		}
+		elemType, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if elemType != 0x70 {
+			return fmt.Errorf("%v: table element type", ErrUnsupported)
+		}
+		min, _, _, err := r.limits()
+		if err != nil {
+			return err
+		}
+		if min > maxTableSize {
+			return fmt.Errorf("%v: table too large", ErrUnsupported)
+		}
+		m.HasTable = true
+		m.TableSize = min
+		return nil
+	})
+}
+
+func (m *Module) decodeMemory(r *reader) error {
+	return r.vec(func() error {
+		if m.HasMemory {
+			return fmt.Errorf("%v: multiple memories", ErrUnsupported)
+		}
+		min, max, hasMax, err := r.limits()
+		if err != nil {
+			return err
+		}
+		if !hasMax || max > MaxMemoryPages {
+			max = MaxMemoryPages
+		}
+		if min > max {
+			return fmt.Errorf("%v: memory too large", ErrUnsupported)
+		}
+		m.HasMemory = true
+		m.MemoryMin = min
+		m.MemoryMax = max
+		return nil
+	})
+}
+
+func (m *Module) decodeElements(r *reader) error {
+	return r.vec(func() error {
+		flags, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flags != 0 {
+			return fmt.Errorf("%v: element segment kind %d", ErrUnsupported, flags)
+		}
+		offset, err := r.constExpr(ValueTypeI32)
+		if err != nil {
+			return err
+		}
+		seg := ElementSegment{Offset: uint32(offset)}
+		err = r.vec(func() error {
+			idx, err := r.u32()
+			if err != nil {
+				return err
+			}
+			seg.Indices = append(seg.Indices, idx)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		m.Elements = append(m.Elements, seg)
+		return nil
+	})
+}
+
+func (m *Module) decodeCode(r *reader) error {
+	i := 0
+	return r.vec(func() error {
+		if i >= len(m.funcTypeIdx) {
+			return fmt.Errorf("%v: function and code sections mismatch", ErrInvalidModule)
+		}
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		body, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+		br := &reader{data: body}
+		f := &Function{TypeIndex: m.funcTypeIdx[i]}
+		err = br.vec(func() error {
+			n, err := br.u32()
+			if err != nil {
+				return err
+			}
+			typ, err := br.valueType()
+			if err != nil {
+				return err
+			}
+			if uint64(len(f.Locals))+uint64(n) > maxLocals {
+				return fmt.Errorf("%v: too many locals", ErrUnsupported)
+			}
+			for j := uint32(0); j < n; j++ {
+				f.Locals = append(f.Locals, typ)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		f.Code = body[br.pos:]
+		m.Functions = append(m.Functions, f)
+		i++
+		return nil
+	})
+}
+
+func (m *Module) decodeData(r *reader) error {
+	return r.vec(func() error {
+		flags, err := r.u32()
+		if err != nil {
+			return err
+		}
+		switch flags {
+		case 0:
+		case 2:
+			memIdx, err := r.u32()
+			if err != nil {
+				return err
+			}
+			if memIdx != 0 {
+				return fmt.Errorf("%v: memory index out of range", ErrInvalidModule)
+			}
+		default:
+			return fmt.Errorf("%v: data segment kind %d", ErrUnsupported, flags)
+		}
+		offset, err := r.constExpr(ValueTypeI32)
+		if err != nil {
+			return err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		data, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+		m.Data = append(m.Data, DataSegment{Offset: uint32(offset), Data: data})
+		return nil
+	})
+}
+
+// analyze walks the code of a function, rejects the unsupported
+// instructions and records where each block ends.
+func (m *Module) analyze(f *Function) error {
+	r := &reader{data: f.Code}
+	f.blocks = make(map[int]*blockInfo)
+	var open []int
+	for r.pos < len(r.data) {
+		pc := r.pos
+		op, err := r.byte()
+		if err != nil {
+			return err
+		}
+		switch op {
+		case opBlock, opLoop, opIf:
+			params, results, err := r.blockType(m)
+			if err != nil {
+				return err
+			}
+			f.blocks[pc] = &blockInfo{elsePc: -1, params: params, results: results}
+			open = append(open, pc)
+		case opElse:
+			if len(open) == 0 || f.Code[open[len(open)-1]] != opIf || f.blocks[open[len(open)-1]].elsePc >= 0 {
+				return fmt.Errorf("%v: unexpected else", ErrInvalidModule)
+			}
+			f.blocks[open[len(open)-1]].elsePc = pc
+		case opEnd:
+			if len(open) == 0 {
+				if r.pos != len(r.data) {
+					return fmt.Errorf("%v: code after function end", ErrInvalidModule)
+				}
+				return nil
+			}
+			f.blocks[open[len(open)-1]].endPc = pc
+			open = open[:len(open)-1]
+		default:
+			if err := r.skipImmediates(op); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("%v: function without end", ErrInvalidModule)
+}
+
+type reader struct {
+	data []byte
+	pos  int
+}
+
+var errUnexpectedEnd = fmt.Errorf("%v: unexpected end", ErrInvalidModule)
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, errUnexpectedEnd
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.pos {
+		return nil, errUnexpectedEnd
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *reader) uleb(bits uint) (uint64, error) {
+	var v uint64
+	var shift uint
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift >= bits || (shift+7 > bits && uint64(b&0x7f)>>(bits-shift) != 0) {
+			return 0, fmt.Errorf("%v: integer too large", ErrInvalidModule)
+		}
+		v |= uint64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+}
+
+func (r *reader) sleb(bits uint) (int64, error) {
+	var v int64
+	var shift uint
+	var b byte
+	var err error
+	for {
+		b, err = r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift >= bits {
+			return 0, fmt.Errorf("%v: integer too large", ErrInvalidModule)
+		}
+		v |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if shift < 64 && b&0x40 != 0 {
+		v |= -1 << shift
+	}
+	return v, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	v, err := r.uleb(32)
+	return uint32(v), err
+}
+
+func (r *reader) i32() (int32, error) {
+	v, err := r.sleb(32)
+	return int32(v), err
+}
+
+func (r *reader) i64() (int64, error) {
+	return r.sleb(64)
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(n))
+	return string(b), err
+}
+
+// vec reads the length of a vector then calls read for each element.
+func (r *reader) vec(read func() error) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	// each element takes at least a byte.
+	if int(n) > len(r.data)-r.pos {
+		return errUnexpectedEnd
+	}
+	for i := uint32(0); i < n; i++ {
+		if err := read(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reader) valueType() (ValueType, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch ValueType(b) {
+	case ValueTypeI32, ValueTypeI64:
+		return ValueType(b), nil
+	case 0x7d, 0x7c:
+		return 0, ErrFloatNotAllowed
+	}
+	return 0, fmt.Errorf("%v: value type 0x%x", ErrUnsupported, b)
+}
+
+func (r *reader) valueTypes() ([]ValueType, error) {
+	var types []ValueType
+	err := r.vec(func() error {
+		t, err := r.valueType()
+		types = append(types, t)
+		return err
+	})
+	return types, err
+}
+
+func (r *reader) limits() (min uint32, max uint32, hasMax bool, err error) {
+	flag, err := r.byte()
+	if err != nil {
+		return
+	}
+	if flag > 1 {
+		err = fmt.Errorf("%v: limits flag %d", ErrUnsupported, flag)
+		return
+	}
+	if min, err = r.u32(); err != nil {
+		return
+	}
+	if flag == 1 {
+		hasMax = true
+		if max, err = r.u32(); err != nil {
+			return
+		}
+		if max < min {
+			err = fmt.Errorf("%v: limits max below min", ErrInvalidModule)
+		}
+	}
+	return
+}
+
+// constExpr reads a constant initializer expression.
+func (r *reader) constExpr(typ ValueType) (uint64, error) {
+	op, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	switch {
+	case op == opI32Const && typ == ValueTypeI32:
+		n, err := r.i32()
+		if err != nil {
+			return 0, err
+		}
+		v = uint64(uint32(n))
+	case op == opI64Const && typ == ValueTypeI64:
+		n, err := r.i64()
+		if err != nil {
+			return 0, err
+		}
+		v = uint64(n)
+	default:
+		return 0, fmt.Errorf("%v: constant expression", ErrUnsupported)
+	}
+	if end, err := r.byte(); err != nil || end != opEnd {
+		return 0, fmt.Errorf("%v: constant expression", ErrInvalidModule)
+	}
+	return v, nil
+}
+
+// blockType reads the type of a block and returns the number of its
+// params and results.
+func (r *reader) blockType(m *Module) (int, int, error) {
+	if r.pos >= len(r.data) {
+		return 0, 0, errUnexpectedEnd
+	}
+	switch b := r.data[r.pos]; {
+	case b == 0x40:
+		r.pos++
+		return 0, 0, nil
+	case b&0xc0 == 0x40:
+		// a single byte negative leb, the type of the result.
+		if _, err := r.valueType(); err != nil {
+			return 0, 0, err
+		}
+		return 0, 1, nil
+	}
+	idx, err := r.sleb(33)
+	if err != nil {
+		return 0, 0, err
+	}
+	if idx < 0 || idx >= int64(len(m.Types)) {
+		return 0, 0, fmt.Errorf("%v: block type out of range", ErrInvalidModule)
+	}
+	typ := m.Types[idx]
+	return len(typ.Params), len(typ.Results), nil
+}
+
+func (r *reader) skipImmediates(op byte) error {
+	var err error
+	switch {
+	case op == opBr || op == opBrIf || op == opCall || (op >= opLocalGet && op <= opGlobalSet):
+		_, err = r.u32()
+	case op == opBrTable:
+		if err = r.vec(func() error { _, err := r.u32(); return err }); err == nil {
+			_, err = r.u32()
+		}
+	case op == opCallIndirect:
+		if _, err = r.u32(); err == nil {
+			err = r.zeroByte()
+		}
+	case op == opSelectTyped:
+		_, err = r.valueTypes()
+	case op >= opI32Load && op <= opI64Store32:
+		if op == opF32Load || op == opF64Load || op == opF32Store || op == opF64Store {
+			return ErrFloatNotAllowed
+		}
+		if _, err = r.u32(); err == nil {
+			_, err = r.u32()
+		}
+	case op == opMemorySize || op == opMemoryGrow:
+		err = r.zeroByte()
+	case op == opI32Const:
+		_, err = r.i32()
+	case op == opI64Const:
+		_, err = r.i64()
+	case op == opPrefixFC:
+		var sub uint32
+		if sub, err = r.u32(); err != nil {
+			return err
+		}
+		switch sub {
+		case opMemoryCopy:
+			if err = r.zeroByte(); err == nil {
+				err = r.zeroByte()
+			}
+		case opMemoryFill:
+			err = r.zeroByte()
+		default:
+			if sub < 8 {
+				return ErrFloatNotAllowed
+			}
+			return fmt.Errorf("%v: instruction 0xfc %d", ErrUnsupported, sub)
+		}
+	case isFloatOp(op):
+		return ErrFloatNotAllowed
+	case isPlainOp(op):
+	default:
+		return fmt.Errorf("%v: instruction 0x%x", ErrUnsupported, op)
+	}
+	return err
+}
+
+func (r *reader) zeroByte() error {
+	b, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if b != 0 {
+		return fmt.Errorf("%v: expected zero byte", ErrInvalidModule)
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"fmt"
+	"github.com/annchain/OG/common/goroutine"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
@@ -33,11 +34,25 @@ type Conn struct {
 	AfterReadFunc   func(messageType int, r io.Reader)
 	BeforeCloseFunc func()
 
-	once   sync.Once
-	id     string
-	stopCh chan struct{}
+	once      sync.Once
+	id        string
+	stopCh    chan struct{}
+	closeOnce sync.Once
+
+	// sendCh queues the messages written by writeLoop.
+	sendCh chan []byte
+
+	mu sync.RWMutex
+	// filters maps the events subscribed to their filters.
+	filters map[string]*txFilter
 }
 
+// connSendQueueSize is the number of messages queued for a connection, a
+// connection falling further behind is closed.
+const connSendQueueSize = 1024
+
+var errSlowConn = errors.New("Conn is too slow, closed")
+
 // Write write p to the websocket connection. The error returned will always
 // be nil if success.
 func (c *Conn) Write(p []byte) (n int, err error) {
@@ -53,6 +68,69 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	}
 }
 
+// Send queues p to be written to the websocket connection. It never
+// blocks: the connection is closed if its queue is full, so that a slow
+// client does not hold the other ones.
+func (c *Conn) Send(p []byte) error {
+	select {
+	case <-c.stopCh:
+		return errors.New("Conn is closed, can't be written")
+	default:
+	}
+	select {
+	case c.sendCh <- p:
+		return nil
+	default:
+		c.Close()
+		return errSlowConn
+	}
+}
+
+// writeLoop writes the queued messages until the connection is closed.
+func (c *Conn) writeLoop() {
+	for {
+		select {
+		case p := <-c.sendCh:
+			if _, err := c.Write(p); err != nil {
+				c.Close()
+				return
+			}
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+func (c *Conn) subscribe(event string, filter *txFilter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.filters[event] = filter
+}
+
+func (c *Conn) unsubscribe(event string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.filters, event)
+}
+
+// filter returns the filter of the subscribed event, nil if none.
+func (c *Conn) filter(event string) *txFilter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.filters[event]
+}
+
+// events returns the events subscribed.
+func (c *Conn) events() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var events []string
+	for event := range c.filters {
+		events = append(events, event)
+	}
+	return events
+}
+
 // GetID returns the id generated using UUID algorithm.
 func (c *Conn) GetID() string {
 	c.once.Do(func() {
@@ -66,6 +144,8 @@ func (c *Conn) GetID() string {
 // Listen listens for receive data from websocket connection. It blocks
 // until websocket connection is closed.
 func (c *Conn) Listen() {
+	goroutine.New(c.writeLoop)
+
 	c.Conn.SetCloseHandler(func(code int, text string) error {
 		if c.BeforeCloseFunc != nil {
 			c.BeforeCloseFunc()
@@ -102,21 +182,22 @@ ReadLoop:
 
 // Close close the connection.
 func (c *Conn) Close() error {
-	select {
-	case <-c.stopCh:
-		return errors.New("Conn already been closed")
-	default:
+	err := errors.New("Conn already been closed")
+	c.closeOnce.Do(func() {
 		c.Conn.Close()
 		close(c.stopCh)
-		return nil
-	}
+		err = nil
+	})
+	return err
 }
 
 // NewConn wraps conn.
 func NewConn(conn *websocket.Conn) *Conn {
 	return &Conn{
-		Conn:   conn,
-		stopCh: make(chan struct{}),
+		Conn:    conn,
+		stopCh:  make(chan struct{}),
+		sendCh:  make(chan []byte, connSendQueueSize),
+		filters: make(map[string]*txFilter),
 	}
 }
 
@@ -132,58 +213,49 @@ type event2Cons struct {
 	mu    sync.RWMutex
 }
 
-func (e *event2Cons) getFromMap(key string) (v map[string]*Conn, ok bool) {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
-	v, ok = e.conns[key]
-	return
-}
-
-func (e *event2Cons) setMap(key string, v map[string]*Conn) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.conns[key] = v
-}
-
 func NewEvent2Cons() *event2Cons {
 	return &event2Cons{
 		conns: make(map[string]map[string]*Conn),
 	}
 }
 func (e *event2Cons) Add(eventType string, conn *Conn) error {
-	conns, ok := e.getFromMap(eventType)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	conns, ok := e.conns[eventType]
 	if !ok {
 		conns = make(map[string]*Conn)
-		e.setMap(eventType, conns)
+		e.conns[eventType] = conns
 	}
 	thisID := conn.GetID()
-	if _, ok := e.getFromMap(thisID); !ok {
-		//not exist,add it
-		v, _ := e.getFromMap(eventType)
-		v[thisID] = conn
-		e.setMap(eventType, v)
-	} else {
+	if _, ok := conns[thisID]; ok {
 		return fmt.Errorf("Conn with ID: %s already exist!", thisID)
 	}
+	conns[thisID] = conn
 	return nil
 }
 
 func (e *event2Cons) Remove(eventType string, conn *Conn) error {
-	conns, ok := e.getFromMap(eventType)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	conns, ok := e.conns[eventType]
 	if !ok {
 		return fmt.Errorf("No Connection with eventType: %s\n", eventType)
 	}
 	thisID := conn.GetID()
-	if _, ok := e.getFromMap(thisID); !ok {
+	if _, ok := conns[thisID]; !ok {
 		return fmt.Errorf("No connection with ID: %s\n", thisID)
-	} else {
-		delete(conns, thisID)
 	}
+	delete(conns, thisID)
 	return nil
 }
 
 func (e *event2Cons) Get(eventType string) ([]*Conn, error) {
-	conns, ok := e.getFromMap(eventType)
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	conns, ok := e.conns[eventType]
 	if !ok {
 		return nil, fmt.Errorf("No Connection with eventType: %s\n", eventType)
 	}
@@ -194,6 +266,13 @@ func (e *event2Cons) Get(eventType string) ([]*Conn, error) {
 	return ret, nil
 }
 
+// Has tells if any connection subscribed eventType.
+func (e *event2Cons) Has(eventType string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.conns[eventType]) > 0
+}
+
 func (e *event2Cons) GetWithID(eventType string, ID string) (*Conn, error) {
 	conns, err := e.Get(eventType)
 	if err != nil {
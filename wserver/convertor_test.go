@@ -14,20 +14,28 @@
 package wserver
 
 import (
-	"fmt"
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/math"
 	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
 	"testing"
 )
 
 func TestConvertor(t *testing.T) {
-	tx := types.Tx{
+	tx := &tx_types.Tx{
 		TxBase: types.TxBase{
 			Hash:        common.BytesToHash([]byte{1, 2, 3, 4, 5}),
 			ParentsHash: common.Hashes{common.BytesToHash([]byte{1, 1, 2, 2, 3, 3})},
 		},
-		From:  common.HexToAddress("0x12345"),
 		To:    common.HexToAddress("0x56789"),
-		Value: nil,
+		Value: math.NewBigInt(0),
+	}
+	var uidata UIData
+	uidata.AddToBatch(tx, true)
+	if len(uidata.Nodes) != 1 || uidata.Nodes[0].Data.Unit != tx.GetTxHash().Hex() {
+		t.Fatalf("tx not converted to a node: %v", uidata.Nodes)
+	}
+	if len(uidata.Edges) != 1 || uidata.Edges[0].Target != tx.ParentsHash[0].Hex() {
+		t.Fatalf("parent not converted to an edge: %v", uidata.Edges)
 	}
-	fmt.Println(tx2UIData(tx))
 }
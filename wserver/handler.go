@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/annchain/OG/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
@@ -33,6 +34,8 @@ type websocketHandler struct {
 
 	event2Cons *event2Cons
 	baseConns  []*Conn
+	// auth authorizes the clients if set.
+	auth *auth.Authenticator
 }
 
 // RegisterMessage defines message struct client send after connect
@@ -40,6 +43,11 @@ type websocketHandler struct {
 type RegisterMessage struct {
 	//Token string
 	Event string `json:"event"`
+	// Filter restricts the txs, confirmations and logs pushed for the
+	// event.
+	Filter *Filter `json:"filter,omitempty"`
+	// Unsubscribe stops the event.
+	Unsubscribe bool `json:"unsubscribe,omitempty"`
 }
 
 func (wh *websocketHandler) Handle(ctx *gin.Context) {
@@ -49,6 +57,12 @@ func (wh *websocketHandler) Handle(ctx *gin.Context) {
 // First try to upgrade connection to websocket. If success, connection will
 // be kept until client send close message or server drop them.
 func (wh *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if wh.auth != nil {
+		if _, err := wh.auth.AuthorizeWebsocket(r, auth.GroupPublic); err != nil {
+			http.Error(w, err.Error(), auth.StatusCode(err))
+			return
+		}
+	}
 	wsConn, err := wh.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		return
@@ -57,7 +71,6 @@ func (wh *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// handle Websocket request
 	conn := NewConn(wsConn)
-	var eventType string
 	conn.AfterReadFunc = func(messageType int, r io.Reader) {
 		var rm RegisterMessage
 		decoder := json.NewDecoder(r)
@@ -65,18 +78,41 @@ func (wh *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			logrus.WithError(err).Debug("Failed to serve request")
 			return
 		}
-
-		wh.event2Cons.Add(rm.Event, conn)
-		eventType = rm.Event
+		wh.register(conn, &rm)
 	}
-	conn.BeforeCloseFunc = func() {
-		wh.event2Cons.Remove(eventType, conn)
+	removeConn := func() {
+		for _, event := range conn.events() {
+			wh.event2Cons.Remove(event, conn)
+		}
 	}
+	conn.BeforeCloseFunc = removeConn
+	conn.subscribe(messageTypeBaseWs, nil)
 	wh.event2Cons.Add(messageTypeBaseWs, conn)
 
-	logrus.WithField("event_type",eventType).WithField("client ip ",r.RemoteAddr).Info("serve websocket client")
+	logrus.WithField("client ip ", r.RemoteAddr).Info("serve websocket client")
 
 	conn.Listen()
+	removeConn()
+	conn.Close()
+}
+
+// register subscribes or unsubscribes conn to the event of rm. An invalid
+// filter is answered with an error event.
+func (wh *websocketHandler) register(conn *Conn, rm *RegisterMessage) {
+	if rm.Unsubscribe {
+		conn.unsubscribe(rm.Event)
+		wh.event2Cons.Remove(rm.Event, conn)
+		return
+	}
+	filter, err := newTxFilter(rm.Filter)
+	if err != nil {
+		if msg, err := marshalEvent(messageTypeError, err.Error()); err == nil {
+			conn.Send(msg)
+		}
+		return
+	}
+	conn.subscribe(rm.Event, filter)
+	wh.event2Cons.Add(rm.Event, conn)
 }
 
 // ErrRequestIllegal describes error when data of the request is unaccepted.
@@ -149,7 +185,7 @@ func (s *pushHandler) push(event, message string) (int, error) {
 	}
 	cnt := 0
 	for i := range conns {
-		_, err := conns[i].Write([]byte(message))
+		err := conns[i].Send([]byte(message))
 		if err != nil {
 			s.event2Cons.Remove(event, conns[i])
 			continue
@@ -19,11 +19,14 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"github.com/annchain/OG/auth"
 	"github.com/annchain/OG/common"
 	"github.com/annchain/OG/common/goroutine"
+	"github.com/annchain/OG/core"
 	"github.com/annchain/OG/status"
 	"github.com/annchain/OG/types"
 	"github.com/annchain/OG/types/tx_types"
+	vmtypes "github.com/annchain/OG/vm/types"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
@@ -39,6 +42,13 @@ const (
 	messageTypeConfirmed = "confirmed"
 	messageTypeNewTx     = "new_tx"
 	messageTypeBaseWs    = "base_ws"
+
+	// events subscribed with filters.
+	messageTypeTxs         = "txs"
+	messageTypeTxConfirmed = "tx_confirmed"
+	messageTypeLogs        = "logs"
+	messageTypeNewHead     = "new_head"
+	messageTypeError       = "error"
 )
 
 var defaultUpgrader = &websocket.Upgrader{
@@ -76,8 +86,15 @@ type Server struct {
 	// To receive new tx events
 	NewTxReceivedChan chan types.Txi
 
-	// to receive confirmation events
-	BatchConfirmedChan chan map[common.Hash]types.Txi
+	// to receive the confirmed sequencers with their txs
+	SeqConfirmedChan chan *core.ConfirmBatch
+
+	// GetTxLogs returns the logs of a confirmed tx for the logs event.
+	GetTxLogs func(hash common.Hash) []*vmtypes.Log
+
+	// TLSCertFile and TLSKeyFile serve wss when both set.
+	TLSCertFile string
+	TLSKeyFile  string
 
 	wh     *websocketHandler
 	ph     *pushHandler
@@ -89,14 +106,20 @@ type Server struct {
 func (s *Server) GetBenchmarks() map[string]interface{} {
 	return map[string]interface{}{
 		"newtx":   len(s.NewTxReceivedChan),
-		"batchtx": len(s.BatchConfirmedChan),
+		"batchtx": len(s.SeqConfirmedChan),
 	}
 }
 
 // ListenAndServe listens on the TCP network address and handle websocket
 // request.
 func (s *Server) Serve() {
-	if err := s.server.ListenAndServe(); err != nil {
+	var err error
+	if s.TLSCertFile != "" && s.TLSKeyFile != "" {
+		err = s.server.ListenAndServeTLS(s.TLSCertFile, s.TLSKeyFile)
+	} else {
+		err = s.server.ListenAndServe()
+	}
+	if err != nil {
 		// cannot panic, because this probably is an intentional close
 		logrus.WithError(err).Info("websocket server")
 	}
@@ -110,12 +133,12 @@ func (s *Server) Push(event, message string) (int, error) {
 // NewServer creates a new Server.
 func NewServer(addr string) *Server {
 	s := &Server{
-		Addr:               addr,
-		WSPath:             serverDefaultWSPath,
-		PushPath:           serverDefaultPushPath,
-		NewTxReceivedChan:  make(chan types.Txi, 10000),
-		BatchConfirmedChan: make(chan map[common.Hash]types.Txi, 1000),
-		quit:               make(chan bool),
+		Addr:              addr,
+		WSPath:            serverDefaultWSPath,
+		PushPath:          serverDefaultPushPath,
+		NewTxReceivedChan: make(chan types.Txi, 10000),
+		SeqConfirmedChan:  make(chan *core.ConfirmBatch, 1000),
+		quit:              make(chan bool),
 	}
 
 	e2c := NewEvent2Cons()
@@ -150,6 +173,20 @@ func NewServer(addr string) *Server {
 	return s
 }
 
+// SetAuthenticator requires the websocket clients to be granted the public
+// group and the push requests the admin one, and accepts the websocket
+// requests of the allowed origins only. It is called before Start.
+func (s *Server) SetAuthenticator(a *auth.Authenticator) {
+	upgrader := *s.wh.upgrader
+	upgrader.CheckOrigin = a.CheckOrigin
+	s.wh.upgrader = &upgrader
+	s.wh.auth = a
+	s.ph.authFunc = func(r *http.Request) bool {
+		_, err := a.Authorize(r, auth.GroupAdmin)
+		return err == nil
+	}
+}
+
 func (s *Server) Start() {
 	goroutine.New(s.Serve)
 	goroutine.New(s.WatchNewTxs)
@@ -199,6 +236,7 @@ func (s *Server) WatchNewTxs() {
 
 				s.publishTxi(tx)
 			}
+			s.publishTx(tx)
 
 			//if ac,ok := tx.(*tx_types.Archive);ok {
 			//	data := base64.StdEncoding.EncodeToString(ac.Data)
@@ -220,31 +258,75 @@ func (s *Server) WatchNewTxs() {
 
 			//uidata.AddToBatch(tx, true)
 
-		case <-s.BatchConfirmedChan:
-		//case batch := <-s.BatchConfirmedChan:
-		// first publish all pending txs
-		//if status.ArchiveMode {
-		//	s.publishNewTxs(blockdbData)
-		//	blockdbData = nil
-		//}
-		//s.publishTxs(uidata)
-		//uidata = nil
-		//
-		//// then publish batch
-		//s.publishBatch(batch)
-
-		//case <-ticker.C:
-		//	if status.ArchiveMode {
-		//		s.publishNewTxs(blockdbData)
-		//		blockdbData = nil
-		//	}
-		//	s.publishTxs(uidata)
-		//	uidata = nil
+		case batch := <-s.SeqConfirmedChan:
+			s.publishBatch(batch.Txs)
+			s.publishConfirmation(batch)
 
 		case <-s.quit:
-			break
+			return
+		}
+	}
+}
+
+// publishEvent pushes the event to the connections subscribed to it whose
+// filter is matched. The message is only marshaled if pushed.
+func (s *Server) publishEvent(event string, data interface{}, match func(f *txFilter) bool) {
+	if !s.wh.event2Cons.Has(event) {
+		return
+	}
+	conns, err := s.wh.event2Cons.Get(event)
+	if err != nil {
+		return
+	}
+	var msg []byte
+	for _, conn := range conns {
+		if f := conn.filter(event); f != nil && match != nil && !match(f) {
+			continue
 		}
+		if msg == nil {
+			msg, err = marshalEvent(event, data)
+			if err != nil {
+				logrus.WithError(err).Error("Failed to marshal ws message")
+				return
+			}
+		}
+		if err := conn.Send(msg); err != nil {
+			logrus.WithError(err).WithField("conn", conn.GetID()).Debug("websocket send error")
+			s.wh.event2Cons.Remove(event, conn)
+		}
+	}
+}
+
+// publishTx pushes a received tx to the txs subscriptions.
+func (s *Server) publishTx(txi types.Txi) {
+	if !s.wh.event2Cons.Has(messageTypeTxs) {
+		return
+	}
+	data, err := txData(txi)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to marshal ws message")
+		return
 	}
+	s.publishEvent(messageTypeTxs, data, func(f *txFilter) bool { return f.matchTx(txi) })
+}
+
+// publishConfirmation pushes the txs confirmed by a sequencer and their
+// logs, then the sequencer as the new head.
+func (s *Server) publishConfirmation(batch *core.ConfirmBatch) {
+	publishLogs := s.GetTxLogs != nil && s.wh.event2Cons.Has(messageTypeLogs)
+	for _, txi := range batch.Txs {
+		txi := txi
+		s.publishEvent(messageTypeTxConfirmed, newTxConfirmedEvent(txi, batch.Seq),
+			func(f *txFilter) bool { return f.matchTx(txi) })
+		if !publishLogs {
+			continue
+		}
+		for _, l := range s.GetTxLogs(txi.GetTxHash()) {
+			l := l
+			s.publishEvent(messageTypeLogs, newLogEvent(l), func(f *txFilter) bool { return f.matchLog(l) })
+		}
+	}
+	s.publishEvent(messageTypeNewHead, newHeadEvent(batch.Seq, len(batch.Txs)), nil)
 }
 
 // works only for seq and tx.
@@ -281,7 +363,10 @@ func (s *Server) publishTxs(uidata *UIData) {
 	logrus.WithField("len ", len(bs)).WithField("nodeCount", len(uidata.Nodes)).Trace("push to ws")
 	s.Push(messageTypeNewUnit, string(bs))
 }
-func (s *Server) publishBatch(elders map[common.Hash]types.Txi) {
+func (s *Server) publishBatch(elders types.Txis) {
+	if !s.wh.event2Cons.Has(messageTypeConfirmed) {
+		return
+	}
 	logrus.WithFields(logrus.Fields{
 		"len": len(elders),
 	}).Trace("push confirmation to ws")
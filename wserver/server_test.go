@@ -20,6 +20,7 @@ import (
 )
 
 func TestServer(t *testing.T) {
+	t.Skip("manual test, serving the pushed events to the clients for a minute")
 	addr := ":12345"
 	srv := NewServer(addr)
 	go func() {
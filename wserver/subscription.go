@@ -0,0 +1,253 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package wserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/hexutil"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	vmtypes "github.com/annchain/OG/vm/types"
+)
+
+// Filter restricts the events pushed to a subscription. Empty fields match
+// everything.
+type Filter struct {
+	// Addresses match the sender or receiver of txs and the contract
+	// emitting logs.
+	Addresses []string `json:"addresses"`
+	// TokenIDs match the token of txs, for action txs the token offered.
+	TokenIDs []int32 `json:"token_ids"`
+	// TxTypes are among tx, sequencer, campaign, term_change, archive and
+	// action.
+	TxTypes []string `json:"tx_types"`
+	// Topics match the topics of logs by position, an empty position
+	// matches any topic.
+	Topics [][]string `json:"topics"`
+}
+
+var txTypeNames = map[string]types.TxBaseType{
+	"tx":          types.TxBaseTypeNormal,
+	"sequencer":   types.TxBaseTypeSequencer,
+	"campaign":    types.TxBaseTypeCampaign,
+	"term_change": types.TxBaseTypeTermChange,
+	"archive":     types.TxBaseTypeArchive,
+	"action":      types.TxBaseAction,
+}
+
+// txFilter is a parsed Filter.
+type txFilter struct {
+	addresses map[common.Address]bool
+	tokenIDs  map[int32]bool
+	txTypes   map[types.TxBaseType]bool
+	topics    []map[common.Hash]bool
+}
+
+func newTxFilter(f *Filter) (*txFilter, error) {
+	tf := &txFilter{}
+	if f == nil {
+		return tf, nil
+	}
+	if len(f.Addresses) > 0 {
+		tf.addresses = make(map[common.Address]bool)
+		for _, s := range f.Addresses {
+			addr, err := common.StringToAddress(s)
+			if err != nil {
+				return nil, fmt.Errorf("address format error: %v", err)
+			}
+			tf.addresses[addr] = true
+		}
+	}
+	if len(f.TokenIDs) > 0 {
+		tf.tokenIDs = make(map[int32]bool)
+		for _, id := range f.TokenIDs {
+			tf.tokenIDs[id] = true
+		}
+	}
+	if len(f.TxTypes) > 0 {
+		tf.txTypes = make(map[types.TxBaseType]bool)
+		for _, name := range f.TxTypes {
+			txType, ok := txTypeNames[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("unknown tx type %q", name)
+			}
+			tf.txTypes[txType] = true
+		}
+	}
+	for _, position := range f.Topics {
+		var topics map[common.Hash]bool
+		if len(position) > 0 {
+			topics = make(map[common.Hash]bool)
+			for _, s := range position {
+				b, err := hexutil.Decode(s)
+				if err != nil || len(b) != common.HashLength {
+					return nil, fmt.Errorf("topic format error: %s", s)
+				}
+				topics[common.BytesToHash(b)] = true
+			}
+		}
+		tf.topics = append(tf.topics, topics)
+	}
+	return tf, nil
+}
+
+func txTokenID(txi types.Txi) (int32, bool) {
+	switch tx := txi.(type) {
+	case *tx_types.Tx:
+		return tx.TokenId, true
+	case *tx_types.ActionTx:
+		if offer := tx.GetPublicOffering(); offer != nil {
+			return offer.TokenId, true
+		}
+	}
+	return 0, false
+}
+
+// matchTx tells if the tx matches the addresses, token ids and tx types.
+func (tf *txFilter) matchTx(txi types.Txi) bool {
+	if tf.txTypes != nil && !tf.txTypes[txi.GetType()] {
+		return false
+	}
+	if tf.tokenIDs != nil {
+		id, ok := txTokenID(txi)
+		if !ok || !tf.tokenIDs[id] {
+			return false
+		}
+	}
+	if tf.addresses != nil {
+		from := txi.GetSender()
+		tx, isTx := txi.(*tx_types.Tx)
+		if !(from != nil && tf.addresses[*from]) && !(isTx && tf.addresses[tx.To]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLog tells if the log matches the addresses and topics.
+func (tf *txFilter) matchLog(l *vmtypes.Log) bool {
+	if tf.addresses != nil && !tf.addresses[l.Address] {
+		return false
+	}
+	if len(tf.topics) > len(l.Topics) {
+		return false
+	}
+	for i, topics := range tf.topics {
+		if topics != nil && !topics[l.Topics[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+// EventMessage is the message pushed for the subscribed events.
+type EventMessage struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// TxConfirmedEvent is pushed once a tx is confirmed by a sequencer.
+type TxConfirmedEvent struct {
+	TxHash    string `json:"tx_hash"`
+	Type      string `json:"type"`
+	Sender    string `json:"sender,omitempty"`
+	SeqHeight uint64 `json:"seq_height"`
+	SeqHash   string `json:"seq_hash"`
+}
+
+// HeadEvent is pushed for every new sequencer.
+type HeadEvent struct {
+	Height    uint64 `json:"height"`
+	Hash      string `json:"hash"`
+	Timestamp int64  `json:"timestamp"`
+	StateRoot string `json:"state_root"`
+	TxCount   int    `json:"tx_count"`
+}
+
+// LogEvent is pushed for the logs emitted by the confirmed txs.
+type LogEvent struct {
+	Address   string        `json:"address"`
+	Topics    []string      `json:"topics"`
+	Data      hexutil.Bytes `json:"data"`
+	TxHash    string        `json:"tx_hash"`
+	SeqHeight uint64        `json:"seq_height"`
+	Index     uint          `json:"index"`
+}
+
+func txTypeName(t types.TxBaseType) string {
+	for name, txType := range txTypeNames {
+		if txType == t {
+			return name
+		}
+	}
+	return t.String()
+}
+
+func newTxConfirmedEvent(txi types.Txi, seq *tx_types.Sequencer) *TxConfirmedEvent {
+	event := &TxConfirmedEvent{
+		TxHash:    txi.GetTxHash().Hex(),
+		Type:      txTypeName(txi.GetType()),
+		SeqHeight: seq.Height,
+		SeqHash:   seq.GetTxHash().Hex(),
+	}
+	if sender := txi.GetSender(); sender != nil {
+		event.Sender = sender.Hex()
+	}
+	return event
+}
+
+func newHeadEvent(seq *tx_types.Sequencer, txCount int) *HeadEvent {
+	return &HeadEvent{
+		Height:    seq.Height,
+		Hash:      seq.GetTxHash().Hex(),
+		Timestamp: seq.Timestamp,
+		StateRoot: seq.StateRoot.Hex(),
+		TxCount:   txCount,
+	}
+}
+
+func newLogEvent(l *vmtypes.Log) *LogEvent {
+	event := &LogEvent{
+		Address:   l.Address.Hex(),
+		Data:      l.Data,
+		TxHash:    l.TxHash.Hex(),
+		SeqHeight: l.SequenceID,
+		Index:     l.Index,
+	}
+	for _, topic := range l.Topics {
+		event.Topics = append(event.Topics, topic.Hex())
+	}
+	return event
+}
+
+// txData returns the json of the tx pushed in the tx events.
+func txData(txi types.Txi) (json.RawMessage, error) {
+	switch t := txi.(type) {
+	case *tx_types.Tx:
+		txMsg := t.ToJsonMsg()
+		return json.Marshal(&txMsg)
+	case *tx_types.Sequencer:
+		txMsg := t.ToJsonMsg()
+		return json.Marshal(&txMsg)
+	}
+	return txi.ToSmallCaseJson()
+}
+
+func marshalEvent(event string, data interface{}) ([]byte, error) {
+	return json.Marshal(&EventMessage{Event: event, Data: data})
+}
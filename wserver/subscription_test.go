@@ -0,0 +1,182 @@
+// Copyright © 2019 Annchain Authors <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package wserver
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annchain/OG/common"
+	"github.com/annchain/OG/common/math"
+	"github.com/annchain/OG/core"
+	"github.com/annchain/OG/types"
+	"github.com/annchain/OG/types/tx_types"
+	vmtypes "github.com/annchain/OG/vm/types"
+	"github.com/gorilla/websocket"
+)
+
+func newSubscriptionTestTx(from, to string, tokenID int32) *tx_types.Tx {
+	fromAddr := common.HexToAddress(from)
+	tx := &tx_types.Tx{
+		TxBase:  types.TxBase{Type: types.TxBaseTypeNormal},
+		From:    &fromAddr,
+		To:      common.HexToAddress(to),
+		Value:   math.NewBigInt(0),
+		TokenId: tokenID,
+	}
+	tx.SetHash(tx.CalcTxHash())
+	return tx
+}
+
+func TestTxFilter(t *testing.T) {
+	tx := newSubscriptionTestTx("0x01", "0x02", 3)
+	seq := &tx_types.Sequencer{TxBase: types.TxBase{Type: types.TxBaseTypeSequencer}}
+
+	for i, c := range []struct {
+		filter  Filter
+		tx      types.Txi
+		matched bool
+	}{
+		{Filter{}, tx, true},
+		{Filter{Addresses: []string{"0x01"}}, tx, true},
+		{Filter{Addresses: []string{"0x02"}}, tx, true},
+		{Filter{Addresses: []string{"0x03"}}, tx, false},
+		{Filter{TokenIDs: []int32{3, 4}}, tx, true},
+		{Filter{TokenIDs: []int32{0}}, tx, false},
+		{Filter{TxTypes: []string{"tx"}}, tx, true},
+		{Filter{TxTypes: []string{"sequencer"}}, tx, false},
+		{Filter{TxTypes: []string{"Sequencer"}}, seq, true},
+		{Filter{TokenIDs: []int32{0}}, seq, false},
+	} {
+		f, err := newTxFilter(&c.filter)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if f.matchTx(c.tx) != c.matched {
+			t.Fatalf("case %d: expected matched %v", i, c.matched)
+		}
+	}
+
+	topic := common.HexToHash("0xaa").Hex()
+	l := &vmtypes.Log{
+		Address: common.HexToAddress("0x05"),
+		Topics:  common.Hashes{common.HexToHash("0xaa"), common.HexToHash("0xbb")},
+	}
+	for i, c := range []struct {
+		filter  Filter
+		matched bool
+	}{
+		{Filter{}, true},
+		{Filter{Addresses: []string{"0x05"}, Topics: [][]string{{topic}}}, true},
+		{Filter{Topics: [][]string{nil, {topic}}}, false},
+		{Filter{Topics: [][]string{{}, {common.HexToHash("0xbb").Hex()}}}, true},
+		{Filter{Topics: [][]string{nil, nil, nil}}, false},
+		{Filter{Addresses: []string{"0x06"}}, false},
+	} {
+		f, err := newTxFilter(&c.filter)
+		if err != nil {
+			t.Fatalf("log case %d: %v", i, err)
+		}
+		if f.matchLog(l) != c.matched {
+			t.Fatalf("log case %d: expected matched %v", i, c.matched)
+		}
+	}
+
+	for _, filter := range []Filter{
+		{Addresses: []string{"0x" + strings.Repeat("01", common.AddressLength+1)}},
+		{TxTypes: []string{"block"}},
+		{Topics: [][]string{{"0x01"}}},
+	} {
+		if _, err := newTxFilter(&filter); err == nil {
+			t.Fatalf("invalid filter %+v accepted", filter)
+		}
+	}
+}
+
+func TestSubscription(t *testing.T) {
+	s := NewServer(":0")
+	server := httptest.NewServer(s.server.Handler)
+	defer server.Close()
+	done := make(chan struct{})
+	go func() {
+		s.WatchNewTxs()
+		close(done)
+	}()
+	defer func() {
+		close(s.quit)
+		<-done
+	}()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + serverDefaultWSPath
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, rm := range []RegisterMessage{
+		{Event: messageTypeTxConfirmed, Filter: &Filter{Addresses: []string{"0x01"}}},
+		{Event: messageTypeNewHead},
+	} {
+		if err := conn.WriteJSON(&rm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// wait for the subscriptions to be registered.
+	for !s.wh.event2Cons.Has(messageTypeNewHead) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	matched := newSubscriptionTestTx("0x01", "0x02", 0)
+	seq := &tx_types.Sequencer{TxBase: types.TxBase{Type: types.TxBaseTypeSequencer, Height: 7}}
+	s.SeqConfirmedChan <- &core.ConfirmBatch{
+		Seq: seq,
+		Txs: types.Txis{newSubscriptionTestTx("0x03", "0x04", 0), matched},
+	}
+
+	var confirmed struct {
+		Event string           `json:"event"`
+		Data  TxConfirmedEvent `json:"data"`
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(&confirmed); err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Event != messageTypeTxConfirmed || confirmed.Data.TxHash != matched.GetTxHash().Hex() || confirmed.Data.SeqHeight != 7 {
+		t.Fatalf("unexpected confirmation %+v", confirmed)
+	}
+	var head struct {
+		Event string    `json:"event"`
+		Data  HeadEvent `json:"data"`
+	}
+	if err := conn.ReadJSON(&head); err != nil {
+		t.Fatal(err)
+	}
+	if head.Event != messageTypeNewHead || head.Data.Height != 7 || head.Data.TxCount != 2 {
+		t.Fatalf("unexpected head %+v", head)
+	}
+
+	if err := conn.WriteJSON(&RegisterMessage{Event: messageTypeTxs, Filter: &Filter{TxTypes: []string{"block"}}}); err != nil {
+		t.Fatal(err)
+	}
+	var reply map[string]json.RawMessage
+	if err := conn.ReadJSON(&reply); err != nil {
+		t.Fatal(err)
+	}
+	if string(reply["event"]) != `"error"` {
+		t.Fatalf("invalid filter not rejected: %v", reply)
+	}
+}